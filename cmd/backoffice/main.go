@@ -13,8 +13,12 @@ import (
 
 	"github.com/evetabi/prediction/internal/backoffice"
 	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/ledger"
+	"github.com/evetabi/prediction/internal/payment"
+	"github.com/evetabi/prediction/internal/payout"
 	"github.com/evetabi/prediction/internal/repository"
 	"github.com/evetabi/prediction/internal/service"
+	"github.com/evetabi/prediction/internal/storage"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 )
@@ -53,36 +57,118 @@ func main() {
 
 	// ── Repositories ──────────────────────────────────────────────────────────
 	userRepo := repository.NewUserRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	walletRepo := repository.NewWalletRepository(db)
 	marketRepo := repository.NewMarketRepository(db)
 	betRepo := repository.NewBetRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	priceSourceRepo := repository.NewPriceSourceRepository(db)
+	mmConfigRepo := repository.NewMMConfigRepository(db)
+	mmSwitchRepo := repository.NewMMSwitchRepository(db)
+	treasuryRepo := repository.NewTreasuryRepository(db)
+	reconRepo := repository.NewReconciliationRepository(db)
+	depositRepo := repository.NewDepositRepository(db)
+	payoutBatchRepo := repository.NewPayoutBatchRepository(db)
+	bankAccountRepo := repository.NewBankAccountRepository(db)
+	bonusRepo := repository.NewBonusRepository(db)
+	promoRepo := repository.NewPromotionRepository(db)
+	referralRepo := repository.NewReferralRepository(db)
+	rgRepo := repository.NewResponsibleRepository(db)
+	kycRepo := repository.NewKYCRepository(db)
+	amlRepo := repository.NewAMLRepository(db)
+	statementRepo := repository.NewStatementRepository(db)
+	adjustmentRepo := repository.NewAdjustmentRepository(db)
+	ledgerSvc := ledger.New(db)
 
 	// ── Services ──────────────────────────────────────────────────────────────
 	priceSvc := service.NewPriceService(cfg)
+	priceSourceSvc := service.NewPriceSourceService(db, priceSourceRepo, auditRepo, priceSvc, cfg)
+	if err = priceSourceSvc.Init(context.Background()); err != nil {
+		logger.Error("price source init failed", "err", err)
+		os.Exit(1)
+	}
 	marketSvc := service.NewMarketService(marketRepo, priceSvc, cfg)
-	authSvc := service.NewAuthService(db, userRepo, walletRepo, cfg)
-	mmSvc := service.NewMMService(db, betRepo, marketRepo, walletRepo, cfg)
+	bonusSvc := service.NewBonusService(db, bonusRepo, walletRepo, ledgerSvc, cfg)
+	rgSvc := service.NewResponsibleService(db, rgRepo, auditRepo, cfg)
+	amlSvc := service.NewAMLService(db, amlRepo, auditRepo, cfg)
+	referralSvc := service.NewReferralService(db, referralRepo, userRepo, ledgerSvc, auditRepo, cfg)
+	authSvc := service.NewAuthService(db, userRepo, refreshTokenRepo, bonusSvc, referralSvc, rgSvc, cfg)
+	mmConfigSvc := service.NewMMConfigService(db, mmConfigRepo, marketRepo, auditRepo, cfg)
+	mmSvc := service.NewMMService(db, betRepo, marketRepo, walletRepo, ledgerSvc, mmSwitchRepo, auditRepo, mmConfigSvc, cfg)
+	if err = mmSvc.LoadSwitches(context.Background()); err != nil {
+		logger.Error("mm kill-switch load failed", "err", err)
+		os.Exit(1)
+	}
 
 	// ResolutionService needed for CancelMarket refunds
-	resolutionSvc := service.NewResolutionService(db, marketRepo, betRepo, walletRepo, priceSvc, cfg)
+	resolutionSvc := service.NewResolutionService(db, marketRepo, betRepo, ledgerSvc, treasuryRepo, priceSvc, bonusSvc, promoRepo, cfg)
 	marketSvc.SetRefunder(resolutionSvc)
 
+	reconSvc := service.NewReconciliationService(db, reconRepo, auditRepo, cfg)
+
+	paymentProvider, err := payment.New(cfg.Payment.Provider, cfg.Payment.WebhookSecret, cfg.Payment.PublicURL)
+	if err != nil {
+		logger.Error("payment provider init failed", "err", err)
+		os.Exit(1)
+	}
+	depositSvc := service.NewDepositService(db, depositRepo, ledgerSvc, paymentProvider, rgSvc, amlSvc, cfg)
+
+	payoutProvider, err := payout.New(cfg.Payment.PayoutProvider)
+	if err != nil {
+		logger.Error("payout provider init failed", "err", err)
+		os.Exit(1)
+	}
+	docStore, err := storage.New(cfg.Storage.Driver, cfg.Storage.LocalDir)
+	if err != nil {
+		logger.Error("document storage init failed", "err", err)
+		os.Exit(1)
+	}
+	kycSvc := service.NewKYCService(db, kycRepo, walletRepo, bankAccountRepo, docStore, auditRepo, cfg)
+	userAdminSvc := service.NewUserAdminService(db, userRepo, auditRepo)
+	statementSvc := service.NewStatementService(db, walletRepo, statementRepo, docStore, auditRepo, cfg)
+	adjustmentSvc := service.NewAdjustmentService(db, adjustmentRepo, userRepo, ledgerSvc, auditRepo)
+	withdrawalSvc := service.NewWithdrawalService(db, walletRepo, payoutBatchRepo, bankAccountRepo, bonusSvc, kycSvc, amlSvc, ledgerSvc, auditRepo, payoutProvider, cfg)
+	bankAccountSvc := service.NewBankAccountService(db, bankAccountRepo, userRepo, auditRepo, cfg)
+	promotionSvc := service.NewPromotionService(db, promoRepo, userRepo, depositRepo, bonusSvc, auditRepo, cfg)
+	payoutBatchSvc := service.NewPayoutBatchService(db, payoutBatchRepo, withdrawalSvc, walletRepo, auditRepo, cfg)
+
 	// ── Signal context ────────────────────────────────────────────────────────
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	// Pick up price-source edits made by other back-office instances
+	go priceSourceSvc.Watch(ctx)
+	// Follow MM kill-switch changes from any process (LISTEN/NOTIFY)
+	go mmSvc.WatchSwitches(ctx)
+
 	// ── Router ────────────────────────────────────────────────────────────────
 	router := backoffice.SetupBackofficeRouter(backoffice.BackofficeDeps{
-		AuthSvc:    authSvc,
-		MarketSvc:  marketSvc,
-		MMSvc:      mmSvc,
-		UserRepo:   userRepo,
-		MarketRepo: marketRepo,
-		BetRepo:    betRepo,
-		WalletRepo: walletRepo,
-		Hub:        nil, // backoffice does not directly serve WS
-		PriceSvc:   priceSvc,
-		Cfg:        cfg,
+		AuthSvc:        authSvc,
+		MarketSvc:      marketSvc,
+		MMSvc:          mmSvc,
+		MMConfigSvc:    mmConfigSvc,
+		ReconSvc:       reconSvc,
+		DepositSvc:     depositSvc,
+		WithdrawalSvc:  withdrawalSvc,
+		PayoutBatchSvc: payoutBatchSvc,
+		BankAccountSvc: bankAccountSvc,
+		PromotionSvc:   promotionSvc,
+		ReferralSvc:    referralSvc,
+		ResponsibleSvc: rgSvc,
+		KYCSvc:         kycSvc,
+		StatementSvc:   statementSvc,
+		UserAdminSvc:   userAdminSvc,
+		AdjustmentSvc:  adjustmentSvc,
+		AMLSvc:         amlSvc,
+		UserRepo:       userRepo,
+		MarketRepo:     marketRepo,
+		BetRepo:        betRepo,
+		WalletRepo:     walletRepo,
+		AuditRepo:      auditRepo,
+		Hub:            nil, // backoffice does not directly serve WS
+		PriceSvc:       priceSvc,
+		PriceSourceSvc: priceSourceSvc,
+		Cfg:            cfg,
 	})
 
 	srv := &http.Server{
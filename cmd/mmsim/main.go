@@ -0,0 +1,180 @@
+// Package main is mmsim, an offline Market Maker backtester.  It replays
+// historical markets from the database or a synthetic generator through the
+// MM strategies and payout math for every combination of the parameter
+// values given on the command line, and prints one result row per set.
+//
+//	go run ./cmd/mmsim -markets 2000 -strategy threshold,proportional -threshold 0.3,0.5,0.8
+//	go run ./cmd/mmsim -source db -from 2026-03-01 -to 2026-04-01 -json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/mm"
+	"github.com/evetabi/prediction/internal/mmsim"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // postgres driver
+	"github.com/shopspring/decimal"
+)
+
+func main() {
+	// Only MM, wallet and DB settings are used, so skip Validate (no JWT
+	// secrets needed to run a backtest).
+	cfg := config.Get()
+
+	source := flag.String("source", "synthetic", "market source: synthetic | db")
+	seed := flag.Int64("seed", 1, "synthetic: random seed")
+	count := flag.Int("markets", 1000, "synthetic: number of markets")
+	series := flag.String("series", domain.DefaultSeries, "db: market series")
+	from := flag.String("from", time.Now().AddDate(0, 0, -7).Format(time.DateOnly), "db: first close date (YYYY-MM-DD)")
+	to := flag.String("to", time.Now().Format(time.DateOnly), "db: end close date, exclusive")
+
+	strategies := flag.String("strategy", cfg.MM.Strategy, "comma-separated strategies: "+strings.Join(mm.Names(), ", "))
+	thresholds := flag.String("threshold", ftoa(cfg.MM.TriggerThreshold), "comma-separated threshold ratios")
+	seedRatios := flag.String("seed-ratio", ftoa(cfg.MM.SeedRatio), "comma-separated seed ratios")
+	balanceRatios := flag.String("balance-ratio", ftoa(cfg.MM.BalanceRatio), "comma-separated balance ratios")
+	targetOdds := flag.String("target-odds", ftoa(cfg.MM.TargetOdds), "comma-separated odds targets")
+	maxExposure := flag.Float64("max-exposure", cfg.MM.MaxExposurePerMarket, "per-market MM cap (TRY)")
+	minBet := flag.Float64("min-bet", cfg.MM.MinMMBet, "minimum MM injection (TRY)")
+	reserve := flag.Float64("reserve", 1_000_000, "starting platform reserve (TRY)")
+	asJSON := flag.Bool("json", false, "print results as JSON")
+	flag.Parse()
+
+	// ── Markets ───────────────────────────────────────────────────────────────
+	var markets []mmsim.Market
+	switch *source {
+	case "synthetic":
+		markets = mmsim.DefaultSynthetic(*seed, *count).Generate()
+	case "db":
+		var err error
+		markets, err = loadFromDB(cfg, *series, *from, *to)
+		if err != nil {
+			fatal(err)
+		}
+	default:
+		fatal(fmt.Errorf("unknown -source %q", *source))
+	}
+	if len(markets) == 0 {
+		fatal(fmt.Errorf("no markets to replay"))
+	}
+
+	// ── Parameter grid ────────────────────────────────────────────────────────
+	base := mmsim.Config{
+		Params: domain.MMParams{
+			MaxExposure: decimal.NewFromFloat(*maxExposure),
+			MinBet:      decimal.NewFromFloat(*minBet),
+		},
+		Budget: domain.MMRiskBudget{
+			DailyLimit:    decimal.NewFromFloat(cfg.MM.MaxDailyLoss),
+			WeeklyLimit:   decimal.NewFromFloat(cfg.MM.MaxWeeklyLoss),
+			ThrottleStart: decimal.NewFromFloat(cfg.MM.ThrottleStart),
+		},
+		Commission: decimal.NewFromFloat(cfg.Wallet.CommissionRate),
+		Reserve:    decimal.NewFromFloat(*reserve),
+		MinReserve: decimal.NewFromFloat(cfg.MM.MinReserve),
+		Location:   cfg.Location(),
+	}
+
+	var results []*mmsim.Result
+	for _, name := range split(*strategies) {
+		strategy, err := mm.Lookup(name)
+		if err != nil {
+			fatal(err)
+		}
+		for _, th := range decimals(*thresholds) {
+			for _, sr := range decimals(*seedRatios) {
+				for _, br := range decimals(*balanceRatios) {
+					for _, odds := range decimals(*targetOdds) {
+						c := base
+						c.Strategy = strategy
+						c.Params.Strategy = name
+						c.Params.ThresholdRatio = th
+						c.Params.SeedRatio = sr
+						c.Params.BalanceRatio = br
+						c.Params.TargetOdds = odds
+						c.Label = fmt.Sprintf("%s th=%s seed=%s bal=%s odds=%s", name, th, sr, br, odds)
+						results = append(results, mmsim.Run(markets, c))
+					}
+				}
+			}
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			fatal(err)
+		}
+		return
+	}
+	printTable(results)
+}
+
+func loadFromDB(cfg *config.Config, series, fromStr, toStr string) ([]mmsim.Market, error) {
+	from, err := time.ParseInLocation(time.DateOnly, fromStr, cfg.Location())
+	if err != nil {
+		return nil, fmt.Errorf("-from: %w", err)
+	}
+	to, err := time.ParseInLocation(time.DateOnly, toStr, cfg.Location())
+	if err != nil {
+		return nil, fmt.Errorf("-to: %w", err)
+	}
+	db, err := sqlx.Connect("postgres", cfg.DB.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+	defer db.Close()
+	return mmsim.LoadMarkets(context.Background(), db, series, from, to)
+}
+
+func printTable(results []*mmsim.Result) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "params\tmarkets\tinterv.\tthrottled\trejected\tinjected\tmm_pnl\tcommission\thouse_pnl\tmax_exp\tworst_day\tmax_dd\todds_mm\todds_no_mm\t")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+			r.Label, r.Markets, r.Interventions, r.Throttled, r.Rejected,
+			r.Injected.StringFixed(2), r.MMPnL.StringFixed(2), r.Commission.StringFixed(2),
+			r.HousePnL.StringFixed(2), r.MaxMarketExposure.StringFixed(2), r.WorstDay.StringFixed(2),
+			r.MaxDrawdown.StringFixed(2), r.UserOddsWithMM.StringFixed(4), r.UserOddsWithoutMM.StringFixed(4))
+	}
+	_ = w.Flush()
+}
+
+func split(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func decimals(s string) []decimal.Decimal {
+	var out []decimal.Decimal
+	for _, p := range split(s) {
+		d, err := decimal.NewFromString(p)
+		if err != nil {
+			fatal(fmt.Errorf("invalid number %q", p))
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+func ftoa(f float64) string { return decimal.NewFromFloat(f).String() }
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "mmsim:", err)
+	os.Exit(1)
+}
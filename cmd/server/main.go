@@ -18,9 +18,13 @@ import (
 
 	"github.com/evetabi/prediction/internal/api"
 	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/ledger"
+	"github.com/evetabi/prediction/internal/payment"
+	"github.com/evetabi/prediction/internal/payout"
 	"github.com/evetabi/prediction/internal/repository"
 	"github.com/evetabi/prediction/internal/scheduler"
 	"github.com/evetabi/prediction/internal/service"
+	"github.com/evetabi/prediction/internal/storage"
 	"github.com/evetabi/prediction/internal/ws"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq" // postgres driver
@@ -66,22 +70,80 @@ func main() {
 
 	// ── 4. Repositories ───────────────────────────────────────────────────────
 	userRepo := repository.NewUserRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	walletRepo := repository.NewWalletRepository(db)
 	marketRepo := repository.NewMarketRepository(db)
 	betRepo := repository.NewBetRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	priceSourceRepo := repository.NewPriceSourceRepository(db)
+	mmConfigRepo := repository.NewMMConfigRepository(db)
+	mmSwitchRepo := repository.NewMMSwitchRepository(db)
+	treasuryRepo := repository.NewTreasuryRepository(db)
+	reconRepo := repository.NewReconciliationRepository(db)
+	depositRepo := repository.NewDepositRepository(db)
+	payoutBatchRepo := repository.NewPayoutBatchRepository(db)
+	bankAccountRepo := repository.NewBankAccountRepository(db)
+	bonusRepo := repository.NewBonusRepository(db)
+	promoRepo := repository.NewPromotionRepository(db)
+	referralRepo := repository.NewReferralRepository(db)
+	rgRepo := repository.NewResponsibleRepository(db)
+	kycRepo := repository.NewKYCRepository(db)
+	amlRepo := repository.NewAMLRepository(db)
+	statementRepo := repository.NewStatementRepository(db)
+	ledgerSvc := ledger.New(db)
 
 	// ── 5. Services (order matters for injection) ─────────────────────────────
 	priceSvc := service.NewPriceService(cfg)
+	priceSourceSvc := service.NewPriceSourceService(db, priceSourceRepo, auditRepo, priceSvc, cfg)
+	if err = priceSourceSvc.Init(context.Background()); err != nil {
+		logger.Error("price source init failed", "err", err)
+		os.Exit(1)
+	}
 
 	marketSvc := service.NewMarketService(marketRepo, priceSvc, cfg)
 
-	betSvc := service.NewBetService(db, betRepo, marketRepo, walletRepo, cfg)
+	bonusSvc := service.NewBonusService(db, bonusRepo, walletRepo, ledgerSvc, cfg)
+	rgSvc := service.NewResponsibleService(db, rgRepo, auditRepo, cfg)
+	amlSvc := service.NewAMLService(db, amlRepo, auditRepo, cfg)
+
+	betSvc := service.NewBetService(db, betRepo, marketRepo, ledgerSvc, treasuryRepo, bonusSvc, promoRepo, rgSvc, amlSvc, cfg)
+
+	referralSvc := service.NewReferralService(db, referralRepo, userRepo, ledgerSvc, auditRepo, cfg)
+	authSvc := service.NewAuthService(db, userRepo, refreshTokenRepo, bonusSvc, referralSvc, rgSvc, cfg)
+
+	resolutionSvc := service.NewResolutionService(db, marketRepo, betRepo, ledgerSvc, treasuryRepo, priceSvc, bonusSvc, promoRepo, cfg)
 
-	authSvc := service.NewAuthService(db, userRepo, walletRepo, cfg)
+	reconSvc := service.NewReconciliationService(db, reconRepo, auditRepo, cfg)
 
-	resolutionSvc := service.NewResolutionService(db, marketRepo, betRepo, walletRepo, priceSvc, cfg)
+	paymentProvider, err := payment.New(cfg.Payment.Provider, cfg.Payment.WebhookSecret, cfg.Payment.PublicURL)
+	if err != nil {
+		logger.Error("payment provider init failed", "err", err)
+		os.Exit(1)
+	}
+	depositSvc := service.NewDepositService(db, depositRepo, ledgerSvc, paymentProvider, rgSvc, amlSvc, cfg)
+
+	payoutProvider, err := payout.New(cfg.Payment.PayoutProvider)
+	if err != nil {
+		logger.Error("payout provider init failed", "err", err)
+		os.Exit(1)
+	}
+	docStore, err := storage.New(cfg.Storage.Driver, cfg.Storage.LocalDir)
+	if err != nil {
+		logger.Error("document storage init failed", "err", err)
+		os.Exit(1)
+	}
+	kycSvc := service.NewKYCService(db, kycRepo, walletRepo, bankAccountRepo, docStore, auditRepo, cfg)
+	statementSvc := service.NewStatementService(db, walletRepo, statementRepo, docStore, auditRepo, cfg)
+	withdrawalSvc := service.NewWithdrawalService(db, walletRepo, payoutBatchRepo, bankAccountRepo, bonusSvc, kycSvc, amlSvc, ledgerSvc, auditRepo, payoutProvider, cfg)
+	bankAccountSvc := service.NewBankAccountService(db, bankAccountRepo, userRepo, auditRepo, cfg)
+	promotionSvc := service.NewPromotionService(db, promoRepo, userRepo, depositRepo, bonusSvc, auditRepo, cfg)
 
-	mmSvc := service.NewMMService(db, betRepo, marketRepo, walletRepo, cfg)
+	mmConfigSvc := service.NewMMConfigService(db, mmConfigRepo, marketRepo, auditRepo, cfg)
+	mmSvc := service.NewMMService(db, betRepo, marketRepo, walletRepo, ledgerSvc, mmSwitchRepo, auditRepo, mmConfigSvc, cfg)
+	if err = mmSvc.LoadSwitches(context.Background()); err != nil {
+		logger.Error("mm kill-switch load failed", "err", err)
+		os.Exit(1)
+	}
 
 	// Wire circular dependencies via interfaces
 	marketSvc.SetRefunder(resolutionSvc)
@@ -108,18 +170,51 @@ func main() {
 	go hub.Run()
 	logger.Info("websocket hub started")
 
+	// Keep exchange weights in sync with back-office edits
+	go priceSourceSvc.Watch(ctx)
+	// Follow MM kill-switch changes from any process (LISTEN/NOTIFY)
+	go mmSvc.WatchSwitches(ctx)
+
+	// Per-market MM rebalance workers fed by BetService
+	mmSvc.StartRebalancer(ctx)
+	go mmSvc.ReportRebalanceStats(ctx)
+
+	// Recompute wallet balances from the ledger on a schedule
+	go reconSvc.RunScheduled(ctx)
+	// Expire deposit intents the provider never confirmed
+	go depositSvc.ExpireStale(ctx)
+	// Close bonuses whose wagering was not met in time
+	go bonusSvc.ExpireStale(ctx)
+	// Expire free-bet tokens that were never used
+	go promotionSvc.ExpireFreeBets(ctx)
+	// Credit referrers their share of the commission on referred users' bets
+	go referralSvc.RunPayouts(ctx)
+	// Generate last month's account statements once the month is over
+	go statementSvc.RunMonthly(ctx)
+	// Delete refresh tokens past their expiry
+	go authSvc.PurgeExpiredTokens(ctx)
+
 	// ── 9. Scheduler ──────────────────────────────────────────────────────────
 	sched := scheduler.NewScheduler(marketSvc, resolutionSvc, priceSvc, hub, cfg, logger)
 	sched.Start(ctx)
 
 	// ── 10. HTTP Router ───────────────────────────────────────────────────────
 	router := api.SetupRouter(api.RouterDeps{
-		AuthSvc:    authSvc,
-		MarketSvc:  marketSvc,
-		BetSvc:     betSvc,
-		WalletRepo: walletRepo,
-		Hub:        hub,
-		Cfg:        cfg,
+		AuthSvc:        authSvc,
+		MarketSvc:      marketSvc,
+		BetSvc:         betSvc,
+		DepositSvc:     depositSvc,
+		WithdrawalSvc:  withdrawalSvc,
+		BankAccountSvc: bankAccountSvc,
+		PromotionSvc:   promotionSvc,
+		ReferralSvc:    referralSvc,
+		ResponsibleSvc: rgSvc,
+		KYCSvc:         kycSvc,
+		StatementSvc:   statementSvc,
+		BonusSvc:       bonusSvc,
+		WalletRepo:     walletRepo,
+		Hub:            hub,
+		Cfg:            cfg,
 	})
 
 	srv := &http.Server{
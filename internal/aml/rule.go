@@ -0,0 +1,144 @@
+// Package aml contains the anti-money-laundering screening rules.  A Rule is
+// a pure function of the event being screened, the user's recent activity
+// and the configured thresholds: it reports at most one hit and never
+// touches the database.  AMLService loads the activity, runs every rule and
+// turns the hits into alerts on the user's case.
+package aml
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Event is the deposit, bet or withdrawal request being screened.
+type Event struct {
+	Subject   domain.AMLSubject
+	SubjectID uuid.UUID
+	UserID    uuid.UUID
+	Amount    decimal.Decimal
+	At        time.Time
+	IBAN      string    // withdrawals only
+	MarketID  uuid.UUID // bets only
+}
+
+// Movement is one amount in or out of the wallet.
+type Movement struct {
+	Amount decimal.Decimal
+	At     time.Time
+}
+
+// Activity is the user's history the rules decide on, covering at least
+// Params.Lookback before Event.At.  It includes the screened event itself.
+type Activity struct {
+	Deposits    []Movement      // completed deposits, oldest first
+	Withdrawals []Movement      // withdrawal requests not cancelled or rejected, oldest first
+	Stakes      []Movement      // real money put on bets, oldest first
+	IBANUsers   int             // other users who saved or withdrew to Event.IBAN
+	MarketUp    decimal.Decimal // user's open stake on each side of Event.MarketID
+	MarketDown  decimal.Decimal
+}
+
+// Params are the rule thresholds, resolved from config.
+type Params struct {
+	QuickWithdrawWindow time.Duration
+	MinTurnoverRatio    decimal.Decimal
+	StructuringWindow   time.Duration
+	StructuringBand     decimal.Decimal
+	StructuringCount    int
+	DepositLimit        decimal.Decimal // largest single deposit; 0 = no structuring check
+	WithdrawLimit       decimal.Decimal // withdrawals above this need KYC; 0 = no structuring check
+	TurnoverWindow      time.Duration
+	TurnoverMinAmount   decimal.Decimal
+	TurnoverRatio       decimal.Decimal
+	HedgeRatio          decimal.Decimal
+	HedgeMinAmount      decimal.Decimal
+}
+
+// Lookback is how much history the rules need.
+func (p Params) Lookback() time.Duration {
+	return max(p.QuickWithdrawWindow, p.StructuringWindow, p.TurnoverWindow)
+}
+
+// Hit is a rule's finding.  Hold asks for the user's pending withdrawals to
+// be held until the case is reviewed.
+type Hit struct {
+	Rule     string             `json:"rule"`
+	Severity domain.AMLSeverity `json:"severity"`
+	Reason   string             `json:"reason"`
+	Hold     bool               `json:"hold"`
+}
+
+// Rule screens one event.  Evaluate returns nil when the rule does not
+// apply or finds nothing.  Implementations must be pure and safe for
+// concurrent use.
+type Rule interface {
+	Name() string
+	Evaluate(ev Event, act Activity, p Params) *Hit
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Registry
+// ──────────────────────────────────────────────────────────────────────────────
+
+var registry = map[string]Rule{}
+
+func register(r Rule) { registry[r.Name()] = r }
+
+func init() {
+	register(QuickWithdrawal{})
+	register(Structuring{})
+	register(RapidTurnover{})
+	register(SharedIBAN{})
+	register(HedgedBets{})
+}
+
+// Lookup returns the rule registered under name.
+func Lookup(name string) (Rule, error) {
+	r, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("aml: unknown rule %q", name)
+	}
+	return r, nil
+}
+
+// Names returns all registered rule names, sorted.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for n := range registry {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Evaluate runs every registered rule on ev, in name order, and returns
+// the hits.
+func Evaluate(ev Event, act Activity, p Params) []Hit {
+	var hits []Hit
+	for _, n := range Names() {
+		if h := registry[n].Evaluate(ev, act, p); h != nil {
+			hits = append(hits, *h)
+		}
+	}
+	return hits
+}
+
+// since returns the movements at or after t.
+func since(ms []Movement, t time.Time) []Movement {
+	i := sort.Search(len(ms), func(i int) bool { return !ms[i].At.Before(t) })
+	return ms[i:]
+}
+
+// sum adds up the movements' amounts.
+func sum(ms []Movement) decimal.Decimal {
+	total := decimal.Zero
+	for _, m := range ms {
+		total = total.Add(m.Amount)
+	}
+	return total
+}
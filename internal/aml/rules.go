@@ -0,0 +1,189 @@
+package aml
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// ──────────────────────────────────────────────────────────────────────────────
+// QuickWithdrawal
+// ──────────────────────────────────────────────────────────────────────────────
+
+// QuickWithdrawal flags a withdrawal requested within QuickWithdrawWindow of
+// the last deposit when less than MinTurnoverRatio of that deposit has been
+// staked since: money passed through the wallet without being played.
+type QuickWithdrawal struct{}
+
+// Name implements Rule.
+func (QuickWithdrawal) Name() string { return "quick_withdrawal" }
+
+// Evaluate implements Rule.
+func (QuickWithdrawal) Evaluate(ev Event, act Activity, p Params) *Hit {
+	if ev.Subject != domain.AMLSubjectWithdrawal || len(act.Deposits) == 0 {
+		return nil
+	}
+	last := act.Deposits[len(act.Deposits)-1]
+	if ev.At.Sub(last.At) > p.QuickWithdrawWindow {
+		return nil
+	}
+	staked := sum(since(act.Stakes, last.At))
+	if staked.GreaterThanOrEqual(last.Amount.Mul(p.MinTurnoverRatio)) {
+		return nil
+	}
+	return &Hit{
+		Rule:     "quick_withdrawal",
+		Severity: domain.AMLMedium,
+		Reason: fmt.Sprintf("withdrawal of %s TRY %s after a %s TRY deposit with %s TRY staked since",
+			ev.Amount.StringFixed(2), ev.At.Sub(last.At).Round(time.Second), last.Amount.StringFixed(2), staked.StringFixed(2)),
+		Hold: true,
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Structuring
+// ──────────────────────────────────────────────────────────────────────────────
+
+// Structuring flags deposits or withdrawals split to stay just under a limit:
+// StructuringCount or more within StructuringWindow, each within
+// StructuringBand below the limit.  Deposits are measured against the
+// largest single deposit, withdrawals against the KYC threshold.  Only an
+// event that is itself in the band is flagged, so one run of them raises
+// one alert per new amount rather than on every later event.
+type Structuring struct{}
+
+// Name implements Rule.
+func (Structuring) Name() string { return "structuring" }
+
+// Evaluate implements Rule.
+func (Structuring) Evaluate(ev Event, act Activity, p Params) *Hit {
+	var (
+		limit decimal.Decimal
+		ms    []Movement
+	)
+	switch ev.Subject {
+	case domain.AMLSubjectDeposit:
+		limit, ms = p.DepositLimit, act.Deposits
+	case domain.AMLSubjectWithdrawal:
+		limit, ms = p.WithdrawLimit, act.Withdrawals
+	default:
+		return nil
+	}
+	if !limit.IsPositive() {
+		return nil
+	}
+	floor := limit.Mul(decimal.NewFromInt(1).Sub(p.StructuringBand))
+	inBand := func(a decimal.Decimal) bool {
+		return a.GreaterThanOrEqual(floor) && a.LessThanOrEqual(limit)
+	}
+	if !inBand(ev.Amount) {
+		return nil
+	}
+	n := 0
+	for _, m := range since(ms, ev.At.Add(-p.StructuringWindow)) {
+		if inBand(m.Amount) {
+			n++
+		}
+	}
+	if n < p.StructuringCount {
+		return nil
+	}
+	return &Hit{
+		Rule:     "structuring",
+		Severity: domain.AMLHigh,
+		Reason: fmt.Sprintf("%d %ss between %s and %s TRY within %s",
+			n, ev.Subject, floor.StringFixed(2), limit.StringFixed(2), p.StructuringWindow),
+		Hold: true,
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// RapidTurnover
+// ──────────────────────────────────────────────────────────────────────────────
+
+// RapidTurnover flags large sums moving through the wallet: at least
+// TurnoverMinAmount deposited within TurnoverWindow and TurnoverRatio of
+// it requested back out in the same window.
+type RapidTurnover struct{}
+
+// Name implements Rule.
+func (RapidTurnover) Name() string { return "rapid_turnover" }
+
+// Evaluate implements Rule.
+func (RapidTurnover) Evaluate(ev Event, act Activity, p Params) *Hit {
+	if ev.Subject != domain.AMLSubjectDeposit && ev.Subject != domain.AMLSubjectWithdrawal {
+		return nil
+	}
+	from := ev.At.Add(-p.TurnoverWindow)
+	in := sum(since(act.Deposits, from))
+	out := sum(since(act.Withdrawals, from))
+	if in.LessThan(p.TurnoverMinAmount) || !in.IsPositive() || out.LessThan(in.Mul(p.TurnoverRatio)) {
+		return nil
+	}
+	return &Hit{
+		Rule:     "rapid_turnover",
+		Severity: domain.AMLMedium,
+		Reason: fmt.Sprintf("%s TRY deposited and %s TRY withdrawn within %s",
+			in.StringFixed(2), out.StringFixed(2), p.TurnoverWindow),
+		Hold: true,
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// SharedIBAN
+// ──────────────────────────────────────────────────────────────────────────────
+
+// SharedIBAN flags a withdrawal to an IBAN that another user has saved or
+// withdrawn to.  Live bank accounts are unique per IBAN, so this catches
+// accounts deleted and re-added elsewhere and older requests.
+type SharedIBAN struct{}
+
+// Name implements Rule.
+func (SharedIBAN) Name() string { return "shared_iban" }
+
+// Evaluate implements Rule.
+func (SharedIBAN) Evaluate(ev Event, act Activity, _ Params) *Hit {
+	if ev.Subject != domain.AMLSubjectWithdrawal || act.IBANUsers == 0 {
+		return nil
+	}
+	return &Hit{
+		Rule:     "shared_iban",
+		Severity: domain.AMLHigh,
+		Reason:   fmt.Sprintf("IBAN also used by %d other user(s)", act.IBANUsers),
+		Hold:     true,
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// HedgedBets
+// ──────────────────────────────────────────────────────────────────────────────
+
+// HedgedBets flags open stakes on both sides of one market, the smaller at
+// least HedgeMinAmount and HedgeRatio of the larger: the wallet turns over
+// with little risk, which suits layering better than betting.
+type HedgedBets struct{}
+
+// Name implements Rule.
+func (HedgedBets) Name() string { return "hedged_bets" }
+
+// Evaluate implements Rule.
+func (HedgedBets) Evaluate(ev Event, act Activity, p Params) *Hit {
+	if ev.Subject != domain.AMLSubjectBet {
+		return nil
+	}
+	small, large := act.MarketUp, act.MarketDown
+	if small.GreaterThan(large) {
+		small, large = large, small
+	}
+	if small.LessThan(p.HedgeMinAmount) || !small.IsPositive() || small.LessThan(large.Mul(p.HedgeRatio)) {
+		return nil
+	}
+	return &Hit{
+		Rule:     "hedged_bets",
+		Severity: domain.AMLLow,
+		Reason: fmt.Sprintf("%s TRY on UP and %s TRY on DOWN in market %s",
+			act.MarketUp.StringFixed(2), act.MarketDown.StringFixed(2), ev.MarketID),
+	}
+}
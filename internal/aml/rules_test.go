@@ -0,0 +1,203 @@
+package aml_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evetabi/prediction/internal/aml"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+var t0 = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func testParams() aml.Params {
+	return aml.Params{
+		QuickWithdrawWindow: 24 * time.Hour,
+		MinTurnoverRatio:    decimal.NewFromInt(1),
+		StructuringWindow:   24 * time.Hour,
+		StructuringBand:     decimal.NewFromFloat(0.1),
+		StructuringCount:    3,
+		DepositLimit:        decimal.NewFromInt(50000),
+		WithdrawLimit:       decimal.NewFromInt(5000),
+		TurnoverWindow:      24 * time.Hour,
+		TurnoverMinAmount:   decimal.NewFromInt(10000),
+		TurnoverRatio:       decimal.NewFromFloat(0.8),
+		HedgeRatio:          decimal.NewFromFloat(0.5),
+		HedgeMinAmount:      decimal.NewFromInt(1000),
+	}
+}
+
+func mv(amount int64, ago time.Duration) aml.Movement {
+	return aml.Movement{Amount: decimal.NewFromInt(amount), At: t0.Add(-ago)}
+}
+
+func event(subject domain.AMLSubject, amount int64) aml.Event {
+	return aml.Event{Subject: subject, SubjectID: uuid.New(), UserID: uuid.New(), Amount: decimal.NewFromInt(amount), At: t0}
+}
+
+// ── QuickWithdrawal ──────────────────────────────────────────────────────────
+
+func TestQuickWithdrawal(t *testing.T) {
+	ev := event(domain.AMLSubjectWithdrawal, 900)
+	cases := []struct {
+		name string
+		act  aml.Activity
+		want bool
+	}{
+		{"no deposit", aml.Activity{}, false},
+		{"unplayed deposit", aml.Activity{Deposits: []aml.Movement{mv(1000, 2*time.Hour)}, Stakes: []aml.Movement{mv(100, time.Hour)}}, true},
+		{"deposit played through", aml.Activity{Deposits: []aml.Movement{mv(1000, 2*time.Hour)}, Stakes: []aml.Movement{mv(600, time.Hour), mv(400, 30*time.Minute)}}, false},
+		{"stakes before the deposit do not count", aml.Activity{Deposits: []aml.Movement{mv(1000, 2*time.Hour)}, Stakes: []aml.Movement{mv(5000, 3*time.Hour)}}, true},
+		{"old deposit", aml.Activity{Deposits: []aml.Movement{mv(1000, 25*time.Hour)}}, false},
+	}
+	for _, tc := range cases {
+		h := aml.QuickWithdrawal{}.Evaluate(ev, tc.act, testParams())
+		if (h != nil) != tc.want {
+			t.Errorf("%s: hit = %+v, want %v", tc.name, h, tc.want)
+		}
+		if h != nil && (!h.Hold || h.Rule != "quick_withdrawal") {
+			t.Errorf("%s: hit = %+v, want a holding quick_withdrawal", tc.name, h)
+		}
+	}
+	if h := (aml.QuickWithdrawal{}).Evaluate(event(domain.AMLSubjectDeposit, 900),
+		aml.Activity{Deposits: []aml.Movement{mv(1000, time.Hour)}}, testParams()); h != nil {
+		t.Errorf("deposit event: expected no hit, got %+v", h)
+	}
+}
+
+// ── Structuring ──────────────────────────────────────────────────────────────
+
+func TestStructuring_Withdrawals(t *testing.T) {
+	// KYC threshold 5000, band 10% → 4500..5000 counts.
+	act := aml.Activity{Withdrawals: []aml.Movement{mv(4800, 20*time.Hour), mv(4600, 5*time.Hour), mv(4900, 0)}}
+	h := aml.Structuring{}.Evaluate(event(domain.AMLSubjectWithdrawal, 4900), act, testParams())
+	if h == nil || h.Severity != domain.AMLHigh || !h.Hold {
+		t.Fatalf("expected high structuring hit, got %+v", h)
+	}
+
+	// Outside the window, below the band or the event itself out of band.
+	act.Withdrawals[0] = mv(4800, 30*time.Hour)
+	if h := (aml.Structuring{}).Evaluate(event(domain.AMLSubjectWithdrawal, 4900), act, testParams()); h != nil {
+		t.Errorf("two in window: expected no hit, got %+v", h)
+	}
+	act.Withdrawals[0] = mv(3000, time.Hour)
+	if h := (aml.Structuring{}).Evaluate(event(domain.AMLSubjectWithdrawal, 4900), act, testParams()); h != nil {
+		t.Errorf("one below band: expected no hit, got %+v", h)
+	}
+	act = aml.Activity{Withdrawals: []aml.Movement{mv(4800, 3*time.Hour), mv(4600, 2*time.Hour), mv(4700, time.Hour), mv(1000, 0)}}
+	if h := (aml.Structuring{}).Evaluate(event(domain.AMLSubjectWithdrawal, 1000), act, testParams()); h != nil {
+		t.Errorf("event out of band: expected no hit, got %+v", h)
+	}
+}
+
+func TestStructuring_DepositsAndDisabledLimit(t *testing.T) {
+	act := aml.Activity{Deposits: []aml.Movement{mv(49000, 3*time.Hour), mv(47000, 2*time.Hour), mv(50000, 0)}}
+	if h := (aml.Structuring{}).Evaluate(event(domain.AMLSubjectDeposit, 50000), act, testParams()); h == nil {
+		t.Fatal("expected structuring hit on deposits")
+	}
+	p := testParams()
+	p.DepositLimit = decimal.Zero
+	if h := (aml.Structuring{}).Evaluate(event(domain.AMLSubjectDeposit, 50000), act, p); h != nil {
+		t.Errorf("limit off: expected no hit, got %+v", h)
+	}
+}
+
+// ── RapidTurnover ────────────────────────────────────────────────────────────
+
+func TestRapidTurnover(t *testing.T) {
+	act := aml.Activity{
+		Deposits:    []aml.Movement{mv(8000, 10*time.Hour), mv(7000, 6*time.Hour)},
+		Withdrawals: []aml.Movement{mv(12000, 0)},
+	}
+	if h := (aml.RapidTurnover{}).Evaluate(event(domain.AMLSubjectWithdrawal, 12000), act, testParams()); h == nil || !h.Hold {
+		t.Fatalf("expected holding rapid_turnover hit, got %+v", h)
+	}
+
+	act.Withdrawals = []aml.Movement{mv(11000, 0)} // < 80% of 15000
+	if h := (aml.RapidTurnover{}).Evaluate(event(domain.AMLSubjectWithdrawal, 11000), act, testParams()); h != nil {
+		t.Errorf("below ratio: expected no hit, got %+v", h)
+	}
+	small := aml.Activity{Deposits: []aml.Movement{mv(5000, time.Hour)}, Withdrawals: []aml.Movement{mv(5000, 0)}}
+	if h := (aml.RapidTurnover{}).Evaluate(event(domain.AMLSubjectWithdrawal, 5000), small, testParams()); h != nil {
+		t.Errorf("below minimum: expected no hit, got %+v", h)
+	}
+}
+
+// ── SharedIBAN ───────────────────────────────────────────────────────────────
+
+func TestSharedIBAN(t *testing.T) {
+	ev := event(domain.AMLSubjectWithdrawal, 100)
+	if h := (aml.SharedIBAN{}).Evaluate(ev, aml.Activity{}, testParams()); h != nil {
+		t.Errorf("unshared: expected no hit, got %+v", h)
+	}
+	if h := (aml.SharedIBAN{}).Evaluate(ev, aml.Activity{IBANUsers: 2}, testParams()); h == nil || h.Severity != domain.AMLHigh {
+		t.Errorf("shared: expected high hit, got %+v", h)
+	}
+}
+
+// ── HedgedBets ───────────────────────────────────────────────────────────────
+
+func TestHedgedBets(t *testing.T) {
+	ev := event(domain.AMLSubjectBet, 1500)
+	cases := []struct {
+		up, down int64
+		want     bool
+	}{
+		{2000, 1500, true},
+		{1500, 2000, true},
+		{4000, 1500, false}, // smaller side under half
+		{900, 900, false},   // under the minimum
+		{2000, 0, false},
+	}
+	for _, tc := range cases {
+		act := aml.Activity{MarketUp: decimal.NewFromInt(tc.up), MarketDown: decimal.NewFromInt(tc.down)}
+		h := aml.HedgedBets{}.Evaluate(ev, act, testParams())
+		if (h != nil) != tc.want {
+			t.Errorf("%d/%d: hit = %+v, want %v", tc.up, tc.down, h, tc.want)
+		}
+		if h != nil && h.Hold {
+			t.Errorf("%d/%d: hedged bets must not hold withdrawals", tc.up, tc.down)
+		}
+	}
+}
+
+// ── Registry ─────────────────────────────────────────────────────────────────
+
+func TestEvaluate_RunsAllRules(t *testing.T) {
+	act := aml.Activity{
+		Deposits:    []aml.Movement{mv(20000, time.Hour)},
+		Withdrawals: []aml.Movement{mv(19000, 0)},
+		IBANUsers:   1,
+	}
+	hits := aml.Evaluate(event(domain.AMLSubjectWithdrawal, 19000), act, testParams())
+	var got []string
+	for _, h := range hits {
+		got = append(got, h.Rule)
+	}
+	want := []string{"quick_withdrawal", "rapid_turnover", "shared_iban"}
+	if len(got) != len(want) {
+		t.Fatalf("rules = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("rules = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, n := range aml.Names() {
+		r, err := aml.Lookup(n)
+		if err != nil || r.Name() != n {
+			t.Errorf("Lookup(%q) = %v, %v", n, r, err)
+		}
+	}
+	if _, err := aml.Lookup("nope"); err == nil {
+		t.Error("expected error for unknown rule")
+	}
+	if got := testParams().Lookback(); got != 24*time.Hour {
+		t.Errorf("Lookback = %s, want 24h", got)
+	}
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/evetabi/prediction/internal/api/middleware"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BankAccountHandler serves the caller's saved withdrawal bank accounts.
+type BankAccountHandler struct {
+	bankSvc *service.BankAccountService
+}
+
+// NewBankAccountHandler creates a BankAccountHandler.
+func NewBankAccountHandler(bankSvc *service.BankAccountService) *BankAccountHandler {
+	return &BankAccountHandler{bankSvc: bankSvc}
+}
+
+// List godoc
+// GET /api/wallet/bank-accounts [JWT]
+func (h *BankAccountHandler) List(c *gin.Context) {
+	accounts, err := h.bankSvc.List(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not fetch bank accounts")
+		return
+	}
+	respondSuccess(c, http.StatusOK, accounts)
+}
+
+// Add godoc
+// POST /api/wallet/bank-accounts [JWT]
+// Body: {"iban":"TR33 0006 1005 1978 6457 8413 26","holder_name":"Ayşe Yılmaz"}
+// The account is verified at once when holder_name matches the registered
+// name, otherwise after a finance review, and receives withdrawals only
+// after the cooling-off period (usable_from).
+func (h *BankAccountHandler) Add(c *gin.Context) {
+	var body struct {
+		IBAN       string `json:"iban"        binding:"required"`
+		HolderName string `json:"holder_name" binding:"required,max=100"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
+
+	acct, err := h.bankSvc.Add(c.Request.Context(), middleware.GetUserID(c), body.IBAN, body.HolderName)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidIBAN):
+			respondError(c, http.StatusBadRequest, "ERR_INVALID_IBAN", err.Error())
+		case errors.Is(err, domain.ErrBankAccountExists):
+			respondError(c, http.StatusConflict, "ERR_BANK_ACCOUNT_EXISTS", err.Error())
+		case errors.Is(err, domain.ErrBankAccountLimit):
+			respondError(c, http.StatusUnprocessableEntity, "ERR_BANK_ACCOUNT_LIMIT", err.Error())
+		default:
+			respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not add bank account")
+		}
+		return
+	}
+	respondSuccess(c, http.StatusCreated, acct)
+}
+
+// SetDefault godoc
+// POST /api/wallet/bank-accounts/:id/default [JWT]
+func (h *BankAccountHandler) SetDefault(c *gin.Context) {
+	id, ok := bankAccountID(c)
+	if !ok {
+		return
+	}
+	acct, err := h.bankSvc.SetDefault(c.Request.Context(), middleware.GetUserID(c), id)
+	if err != nil {
+		respondBankAccountError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusOK, acct)
+}
+
+// Remove godoc
+// DELETE /api/wallet/bank-accounts/:id [JWT]
+func (h *BankAccountHandler) Remove(c *gin.Context) {
+	id, ok := bankAccountID(c)
+	if !ok {
+		return
+	}
+	if err := h.bankSvc.Remove(c.Request.Context(), middleware.GetUserID(c), id); err != nil {
+		respondBankAccountError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusOK, gin.H{"id": id, "removed": true})
+}
+
+// bankAccountID parses the :id param, answering 400 when it is malformed.
+func bankAccountID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid bank account id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func respondBankAccountError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrBankAccountNotFound) {
+		respondError(c, http.StatusNotFound, "ERR_BANK_ACCOUNT_NOT_FOUND", err.Error())
+		return
+	}
+	respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not update bank account")
+}
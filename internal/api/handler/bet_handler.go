@@ -24,13 +24,16 @@ func NewBetHandler(betSvc *service.BetService) *BetHandler {
 // PlaceBet godoc
 // POST /api/bets [JWT]
 // Body: {"market_id":"uuid","direction":"UP","amount":"500.00"}
+// or {"market_id":"uuid","direction":"UP","free_bet_id":"uuid"} to stake a
+// free-bet token; amount is then ignored.
 func (h *BetHandler) PlaceBet(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 
 	var body struct {
-		MarketID  string `json:"market_id"  binding:"required"`
-		Direction string `json:"direction"  binding:"required"`
-		Amount    string `json:"amount"     binding:"required"`
+		MarketID  string     `json:"market_id"  binding:"required"`
+		Direction string     `json:"direction"  binding:"required"`
+		Amount    string     `json:"amount"`
+		FreeBetID *uuid.UUID `json:"free_bet_id"`
 	}
 	if err := c.ShouldBindJSON(&body); err != nil {
 		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
@@ -43,10 +46,13 @@ func (h *BetHandler) PlaceBet(c *gin.Context) {
 		return
 	}
 
-	amount, err := decimal.NewFromString(body.Amount)
-	if err != nil || amount.IsNegative() || amount.IsZero() {
-		respondError(c, http.StatusBadRequest, "ERR_INVALID_AMOUNT", "amount must be a positive decimal string")
-		return
+	var amount decimal.Decimal
+	if body.FreeBetID == nil {
+		amount, err = decimal.NewFromString(body.Amount)
+		if err != nil || amount.IsNegative() || amount.IsZero() {
+			respondError(c, http.StatusBadRequest, "ERR_INVALID_AMOUNT", "amount must be a positive decimal string")
+			return
+		}
 	}
 
 	direction := domain.Outcome(body.Direction)
@@ -55,10 +61,14 @@ func (h *BetHandler) PlaceBet(c *gin.Context) {
 		MarketID:  marketID,
 		Direction: direction,
 		Amount:    amount,
+		FreeBetID: body.FreeBetID,
 	}
 
 	bet, err := h.betSvc.PlaceBet(c.Request.Context(), req)
 	if err != nil {
+		if respondResponsibleError(c, err) {
+			return
+		}
 		switch err {
 		case domain.ErrBetTooSmall:
 			respondError(c, http.StatusBadRequest, "ERR_BET_TOO_SMALL", err.Error())
@@ -66,10 +76,14 @@ func (h *BetHandler) PlaceBet(c *gin.Context) {
 			respondError(c, http.StatusBadRequest, "ERR_INVALID_DIRECTION", err.Error())
 		case domain.ErrInsufficientBalance:
 			respondError(c, http.StatusPaymentRequired, "ERR_INSUFFICIENT_BALANCE", err.Error())
+		case domain.ErrWalletFrozen:
+			respondError(c, http.StatusForbidden, "ERR_WALLET_FROZEN", err.Error())
 		case domain.ErrMarketNotOpen:
 			respondError(c, http.StatusConflict, "ERR_MARKET_NOT_OPEN", err.Error())
 		case domain.ErrMarketNotFound:
 			respondError(c, http.StatusNotFound, "ERR_MARKET_NOT_FOUND", err.Error())
+		case domain.ErrFreeBetNotFound:
+			respondError(c, http.StatusNotFound, "ERR_FREE_BET_NOT_FOUND", err.Error())
 		default:
 			respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not place bet")
 		}
@@ -98,6 +112,8 @@ func (h *BetHandler) ExitBet(c *gin.Context) {
 			respondError(c, http.StatusForbidden, "ERR_FORBIDDEN", "this bet does not belong to you")
 		case domain.ErrMarketNotOpen:
 			respondError(c, http.StatusConflict, "ERR_MARKET_NOT_OPEN", err.Error())
+		case domain.ErrFreeBetNoExit:
+			respondError(c, http.StatusConflict, "ERR_FREE_BET_NO_EXIT", err.Error())
 		default:
 			respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not exit bet")
 		}
@@ -113,12 +129,12 @@ func (h *BetHandler) GetMyBets(c *gin.Context) {
 	page, limit := parsePagination(c)
 	offset := (page - 1) * limit
 
-	bets, err := h.betSvc.GetMyBets(c.Request.Context(), userID, limit, offset)
+	bets, total, err := h.betSvc.GetMyBets(c.Request.Context(), userID, limit, offset)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not fetch bets")
 		return
 	}
-	respondList(c, bets, len(bets), page, limit)
+	respondList(c, bets, total, page, limit)
 }
 
 // GetBetByID godoc
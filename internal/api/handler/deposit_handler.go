@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/evetabi/prediction/internal/api/middleware"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// maxWebhookBody caps the size of a payment webhook request.
+const maxWebhookBody = 64 << 10
+
+// DepositHandler serves deposit intents and payment-provider webhooks.
+type DepositHandler struct {
+	depositSvc *service.DepositService
+}
+
+// NewDepositHandler creates a DepositHandler.
+func NewDepositHandler(depositSvc *service.DepositService) *DepositHandler {
+	return &DepositHandler{depositSvc: depositSvc}
+}
+
+// CreateDeposit godoc
+// POST /api/wallet/deposits [JWT]
+// Body: {"amount":"250.00"}
+// Returns the pending deposit with the provider's checkout_url.
+func (h *DepositHandler) CreateDeposit(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	var body struct {
+		Amount string `json:"amount" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
+	amount, err := decimal.NewFromString(body.Amount)
+	if err != nil || !amount.IsPositive() {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_AMOUNT", "amount must be a positive decimal string")
+		return
+	}
+
+	d, err := h.depositSvc.CreateDeposit(c.Request.Context(), userID, amount)
+	if err != nil {
+		if respondResponsibleError(c, err) {
+			return
+		}
+		switch err {
+		case domain.ErrInvalidDepositAmount:
+			respondError(c, http.StatusBadRequest, "ERR_INVALID_DEPOSIT_AMOUNT", err.Error())
+		default:
+			respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not create deposit")
+		}
+		return
+	}
+	respondSuccess(c, http.StatusCreated, d)
+}
+
+// GetMyDeposits godoc
+// GET /api/wallet/deposits?page=1&limit=20 [JWT]
+func (h *DepositHandler) GetMyDeposits(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	page, limit := parsePagination(c)
+	offset := (page - 1) * limit
+
+	deposits, total, err := h.depositSvc.ListUserDeposits(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not fetch deposits")
+		return
+	}
+	respondList(c, deposits, total, page, limit)
+}
+
+// GetMyDeposit godoc
+// GET /api/wallet/deposits/:id [JWT]
+func (h *DepositHandler) GetMyDeposit(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid deposit id")
+		return
+	}
+	d, err := h.depositSvc.GetUserDeposit(c.Request.Context(), userID, id)
+	if err != nil {
+		if err == domain.ErrDepositNotFound {
+			respondError(c, http.StatusNotFound, "ERR_DEPOSIT_NOT_FOUND", err.Error())
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not fetch deposit")
+		return
+	}
+	respondSuccess(c, http.StatusOK, d)
+}
+
+// Webhook godoc
+// POST /api/payments/webhook/:provider
+// Called by the payment provider; authenticated by the HMAC signature
+// headers, not by JWT.  Any 5xx makes the provider redeliver.
+func (h *DepositHandler) Webhook(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBody))
+	if err != nil {
+		respondError(c, http.StatusRequestEntityTooLarge, "ERR_VALIDATION", "could not read webhook body")
+		return
+	}
+
+	err = h.depositSvc.HandleWebhook(c.Request.Context(), c.Param("provider"), c.Request.Header, body)
+	switch {
+	case err == nil:
+		respondSuccess(c, http.StatusOK, gin.H{"received": true})
+	case errors.Is(err, domain.ErrInvalidWebhookSignature):
+		respondError(c, http.StatusUnauthorized, "ERR_INVALID_SIGNATURE", err.Error())
+	case errors.Is(err, domain.ErrUnknownPaymentProvider):
+		respondError(c, http.StatusNotFound, "ERR_UNKNOWN_PROVIDER", err.Error())
+	case errors.Is(err, domain.ErrDepositNotFound):
+		respondError(c, http.StatusNotFound, "ERR_DEPOSIT_NOT_FOUND", err.Error())
+	default:
+		log.Printf("[deposit] webhook from %s failed: %v", c.Param("provider"), err)
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not process webhook")
+	}
+}
+
+// MockPay godoc
+// POST /api/payments/mock/:ref?result=success|fail  (development only)
+// Stands in for the mock provider's checkout page: sends the signed webhook
+// the provider would send and returns the settled deposit.
+func (h *DepositHandler) MockPay(c *gin.Context) {
+	succeed := c.DefaultQuery("result", "success") != "fail"
+	d, err := h.depositSvc.MockPay(c.Request.Context(), c.Param("ref"), succeed)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnknownPaymentProvider):
+			respondError(c, http.StatusNotFound, "ERR_UNKNOWN_PROVIDER", "mock provider is not configured")
+		case errors.Is(err, domain.ErrDepositNotFound):
+			respondError(c, http.StatusNotFound, "ERR_DEPOSIT_NOT_FOUND", err.Error())
+		default:
+			respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		}
+		return
+	}
+	respondSuccess(c, http.StatusOK, d)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/evetabi/prediction/internal/api/middleware"
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// kycMultipartOverhead is the room left for multipart headers and the kind
+// field on top of the document size limit.
+const kycMultipartOverhead = 64 << 10
+
+// KYCHandler serves the caller's identity verification: document uploads
+// and submission for review.
+type KYCHandler struct {
+	kycSvc *service.KYCService
+	cfg    *config.Config
+}
+
+// NewKYCHandler creates a KYCHandler.
+func NewKYCHandler(kycSvc *service.KYCService, cfg *config.Config) *KYCHandler {
+	return &KYCHandler{kycSvc: kycSvc, cfg: cfg}
+}
+
+// GetStatus godoc
+// GET /api/kyc [JWT]
+// Returns the verification status, the latest submission and the
+// documents uploaded but not yet submitted.
+func (h *KYCHandler) GetStatus(c *gin.Context) {
+	ov, err := h.kycSvc.Overview(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not fetch verification status")
+		return
+	}
+	respondSuccess(c, http.StatusOK, ov)
+}
+
+// UploadDocument godoc
+// POST /api/kyc/documents [JWT]
+// Multipart form: kind (id_card|passport|driving_license|proof_of_address|selfie)
+// and file (JPEG, PNG or PDF).
+func (h *KYCHandler) UploadDocument(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(h.cfg.KYC.MaxDocumentBytes)+kycMultipartOverhead)
+	fh, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(c, http.StatusRequestEntityTooLarge, "ERR_FILE_TOO_LARGE", domain.ErrKYCDocumentTooLarge.Error())
+			return
+		}
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", "multipart field \"file\" is required")
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
+	defer f.Close()
+
+	kind := domain.KYCDocumentKind(c.PostForm("kind"))
+	doc, err := h.kycSvc.Upload(c.Request.Context(), middleware.GetUserID(c), kind, fh.Filename, f)
+	if err != nil {
+		respondKYCError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusCreated, doc)
+}
+
+// Submit godoc
+// POST /api/kyc/submit [JWT]
+// Sends the uploaded documents for review.  At least one photo identity
+// document is required.
+func (h *KYCHandler) Submit(c *gin.Context) {
+	sub, err := h.kycSvc.Submit(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		respondKYCError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusCreated, sub)
+}
+
+// respondKYCError maps KYC errors to HTTP responses.
+func respondKYCError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrKYCState):
+		respondError(c, http.StatusConflict, "ERR_KYC_STATE", err.Error())
+	case errors.Is(err, domain.ErrKYCInvalidDocument):
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_DOCUMENT", err.Error())
+	case errors.Is(err, domain.ErrKYCDocumentTooLarge):
+		respondError(c, http.StatusRequestEntityTooLarge, "ERR_FILE_TOO_LARGE", err.Error())
+	case errors.Is(err, domain.ErrKYCIdentityMissing):
+		respondError(c, http.StatusUnprocessableEntity, "ERR_IDENTITY_DOCUMENT_REQUIRED", err.Error())
+	default:
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not process verification request")
+	}
+}
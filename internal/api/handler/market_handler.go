@@ -62,12 +62,12 @@ func (h *MarketHandler) GetHistory(c *gin.Context) {
 	page, limit := parsePagination(c)
 	offset := (page - 1) * limit
 
-	markets, err := h.marketSvc.GetMarketHistory(c.Request.Context(), limit, offset)
+	markets, total, err := h.marketSvc.GetMarketHistory(c.Request.Context(), limit, offset)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not fetch history")
 		return
 	}
-	respondList(c, markets, len(markets), page, limit)
+	respondList(c, markets, total, page, limit)
 }
 
 // ListMarkets godoc
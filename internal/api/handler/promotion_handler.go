@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/evetabi/prediction/internal/api/middleware"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// PromotionHandler serves promo-code redemption and the caller's free bets.
+type PromotionHandler struct {
+	promoSvc *service.PromotionService
+}
+
+// NewPromotionHandler creates a PromotionHandler.
+func NewPromotionHandler(promoSvc *service.PromotionService) *PromotionHandler {
+	return &PromotionHandler{promoSvc: promoSvc}
+}
+
+// Redeem godoc
+// POST /api/promotions/redeem [JWT]
+// Body: {"code":"WELCOME50"}
+// Returns the redemption; a bonus reward is credited to the bonus balance,
+// a free-bet reward appears under /api/promotions/free-bets.
+func (h *PromotionHandler) Redeem(c *gin.Context) {
+	var body struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
+
+	red, err := h.promoSvc.Redeem(c.Request.Context(), middleware.GetUserID(c), body.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidPromotion):
+			respondError(c, http.StatusBadRequest, "ERR_INVALID_PROMO_CODE", err.Error())
+		case errors.Is(err, domain.ErrPromotionNotFound):
+			respondError(c, http.StatusNotFound, "ERR_PROMOTION_NOT_FOUND", err.Error())
+		case errors.Is(err, domain.ErrPromotionNotActive):
+			respondError(c, http.StatusUnprocessableEntity, "ERR_PROMOTION_NOT_ACTIVE", err.Error())
+		case errors.Is(err, domain.ErrPromotionExhausted):
+			respondError(c, http.StatusConflict, "ERR_PROMOTION_EXHAUSTED", err.Error())
+		case errors.Is(err, domain.ErrPromotionAlreadyRedeemed):
+			respondError(c, http.StatusConflict, "ERR_PROMOTION_ALREADY_REDEEMED", err.Error())
+		case errors.Is(err, domain.ErrPromotionNotEligible):
+			respondError(c, http.StatusForbidden, "ERR_PROMOTION_NOT_ELIGIBLE", err.Error())
+		case errors.Is(err, domain.ErrBonusActive):
+			respondError(c, http.StatusConflict, "ERR_BONUS_ACTIVE", err.Error())
+		default:
+			respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not redeem promo code")
+		}
+		return
+	}
+	respondSuccess(c, http.StatusCreated, red)
+}
+
+// GetFreeBets godoc
+// GET /api/promotions/free-bets?page=1&limit=20 [JWT]
+func (h *PromotionHandler) GetFreeBets(c *gin.Context) {
+	page, limit := parsePagination(c)
+	offset := (page - 1) * limit
+
+	freeBets, total, err := h.promoSvc.FreeBets(c.Request.Context(), middleware.GetUserID(c), limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not fetch free bets")
+		return
+	}
+	respondList(c, freeBets, total, page, limit)
+}
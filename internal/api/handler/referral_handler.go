@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/evetabi/prediction/internal/api/middleware"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ReferralHandler serves the caller's invite code and referral earnings.
+type ReferralHandler struct {
+	referralSvc *service.ReferralService
+}
+
+// NewReferralHandler creates a ReferralHandler.
+func NewReferralHandler(referralSvc *service.ReferralService) *ReferralHandler {
+	return &ReferralHandler{referralSvc: referralSvc}
+}
+
+// GetStats godoc
+// GET /api/referrals [JWT]
+// Returns the invite code, the share rate, how many users signed up with
+// it (pending = held for review) and the commission earned so far.
+func (h *ReferralHandler) GetStats(c *gin.Context) {
+	stats, err := h.referralSvc.Stats(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not fetch referral stats")
+		return
+	}
+	respondSuccess(c, http.StatusOK, stats)
+}
+
+// GetCommissions godoc
+// GET /api/referrals/commissions?page=1&limit=20 [JWT]
+func (h *ReferralHandler) GetCommissions(c *gin.Context) {
+	page, limit := parsePagination(c)
+	offset := (page - 1) * limit
+
+	rows, total, err := h.referralSvc.Commissions(c.Request.Context(), middleware.GetUserID(c), limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not fetch referral commissions")
+		return
+	}
+	respondList(c, rows, total, page, limit)
+}
@@ -28,6 +28,7 @@ func respondError(c *gin.Context, status int, code, msg string) {
 }
 
 // respondList writes {"success": true, "data": items, "meta": {...}}.
+// total is the size of the whole result set, not of this page.
 func respondList(c *gin.Context, items interface{}, total, page, limit int) {
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -39,3 +40,18 @@ func respondList(c *gin.Context, items interface{}, total, page, limit int) {
 		},
 	})
 }
+
+// respondCursorList is respondList with meta.next_cursor, the token for the
+// page after this one ("" on the last page).
+func respondCursorList(c *gin.Context, items interface{}, total, page, limit int, next string) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    items,
+		"meta": gin.H{
+			"total":       total,
+			"page":        page,
+			"limit":       limit,
+			"next_cursor": next,
+		},
+	})
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/evetabi/prediction/internal/api/middleware"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+// ResponsibleHandler serves the caller's responsible-gambling settings:
+// deposit, loss and stake limits and cooling-off and self-exclusion.
+type ResponsibleHandler struct {
+	rgSvc *service.ResponsibleService
+}
+
+// NewResponsibleHandler creates a ResponsibleHandler.
+func NewResponsibleHandler(rgSvc *service.ResponsibleService) *ResponsibleHandler {
+	return &ResponsibleHandler{rgSvc: rgSvc}
+}
+
+// GetOverview godoc
+// GET /api/responsible-gambling [JWT]
+// Returns every limit with its usage in the current period and the
+// exclusion in force, if any.
+func (h *ResponsibleHandler) GetOverview(c *gin.Context) {
+	ov, err := h.rgSvc.Overview(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not fetch responsible gambling settings")
+		return
+	}
+	respondSuccess(c, http.StatusOK, ov)
+}
+
+// SetLimit godoc
+// PUT /api/responsible-gambling/limits [JWT]
+// Body: {"kind": "deposit|loss|stake", "period": "daily|weekly|monthly", "amount": "500.00"}
+// A null amount removes the limit.  Lowering applies at once; raising or
+// removing applies after the waiting period shown in pending_from.  The
+// response is the stored limit, or null when none is left.
+func (h *ResponsibleHandler) SetLimit(c *gin.Context) {
+	var body struct {
+		Kind   domain.LimitKind   `json:"kind"   binding:"required"`
+		Period domain.LimitPeriod `json:"period" binding:"required"`
+		Amount *string            `json:"amount"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
+	var amount *decimal.Decimal
+	if body.Amount != nil {
+		a, err := decimal.NewFromString(*body.Amount)
+		if err != nil || !a.IsPositive() {
+			respondError(c, http.StatusBadRequest, "ERR_INVALID_AMOUNT", "amount must be a positive decimal string")
+			return
+		}
+		amount = &a
+	}
+
+	l, err := h.rgSvc.SetLimit(c.Request.Context(), middleware.GetUserID(c), body.Kind, body.Period, amount)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidLimit) {
+			respondError(c, http.StatusBadRequest, "ERR_INVALID_LIMIT", err.Error())
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not set limit")
+		return
+	}
+	respondSuccess(c, http.StatusOK, l)
+}
+
+// CoolingOff godoc
+// POST /api/responsible-gambling/cooling-off [JWT]
+// Body: {"days": 7}
+// Blocks betting and deposits for the given number of days.  It cannot be
+// ended early.
+func (h *ResponsibleHandler) CoolingOff(c *gin.Context) {
+	var body struct {
+		Days int `json:"days" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
+	h.exclude(c, domain.ExclusionCoolingOff, body.Days, false)
+}
+
+// SelfExclude godoc
+// POST /api/responsible-gambling/self-exclusion [JWT]
+// Body: {"days": 180} or {"indefinite": true}
+// Blocks logging in, betting and deposits.  It cannot be ended early.
+func (h *ResponsibleHandler) SelfExclude(c *gin.Context) {
+	var body struct {
+		Days       int  `json:"days"`
+		Indefinite bool `json:"indefinite"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
+	h.exclude(c, domain.ExclusionSelf, body.Days, body.Indefinite)
+}
+
+func (h *ResponsibleHandler) exclude(c *gin.Context, kind domain.ExclusionKind, days int, indefinite bool) {
+	e, err := h.rgSvc.Exclude(c.Request.Context(), middleware.GetUserID(c), kind, days, indefinite)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidExclusion):
+			respondError(c, http.StatusBadRequest, "ERR_INVALID_EXCLUSION", err.Error())
+		case errors.Is(err, domain.ErrExclusionActive):
+			respondError(c, http.StatusConflict, "ERR_EXCLUSION_ACTIVE", err.Error())
+		default:
+			respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not start exclusion")
+		}
+		return
+	}
+	respondSuccess(c, http.StatusCreated, e)
+}
+
+// respondResponsibleError writes the response for an exclusion or limit
+// error from the bet, deposit or login flows and reports whether err was
+// one.
+func respondResponsibleError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrSelfExcluded):
+		respondError(c, http.StatusForbidden, "ERR_SELF_EXCLUDED", err.Error())
+	case errors.Is(err, domain.ErrCoolingOff):
+		respondError(c, http.StatusForbidden, "ERR_COOLING_OFF", err.Error())
+	case errors.Is(err, domain.ErrLimitExceeded):
+		respondError(c, http.StatusUnprocessableEntity, "ERR_LIMIT_EXCEEDED", err.Error())
+	default:
+		return false
+	}
+	return true
+}
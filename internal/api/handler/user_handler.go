@@ -23,6 +23,7 @@ func NewUserHandler(authSvc *service.AuthService, walletRepo *repository.WalletR
 
 // Register godoc
 // POST /api/auth/register
+// Body: {"username":"...","email":"...","password":"...","full_name":"...","referral_code":"AB3DKQ7Z"}
 func (h *UserHandler) Register(c *gin.Context) {
 	var req service.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -30,6 +31,8 @@ func (h *UserHandler) Register(c *gin.Context) {
 		return
 	}
 
+	req.IP = c.ClientIP()
+
 	resp, err := h.authSvc.Register(c.Request.Context(), req)
 	if err != nil {
 		switch err {
@@ -37,6 +40,8 @@ func (h *UserHandler) Register(c *gin.Context) {
 			respondError(c, http.StatusConflict, "ERR_EMAIL_TAKEN", err.Error())
 		case domain.ErrUsernameTaken:
 			respondError(c, http.StatusConflict, "ERR_USERNAME_TAKEN", err.Error())
+		case domain.ErrReferralCodeNotFound:
+			respondError(c, http.StatusBadRequest, "ERR_INVALID_REFERRAL_CODE", err.Error())
 		default:
 			respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "registration failed")
 		}
@@ -59,6 +64,9 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 	resp, err := h.authSvc.Login(c.Request.Context(), body.Email, body.Password)
 	if err != nil {
+		if respondResponsibleError(c, err) {
+			return
+		}
 		switch err {
 		case domain.ErrInvalidCredentials:
 			respondError(c, http.StatusUnauthorized, "ERR_INVALID_CREDENTIALS", err.Error())
@@ -85,7 +93,19 @@ func (h *UserHandler) Refresh(c *gin.Context) {
 
 	access, refresh, err := h.authSvc.RefreshToken(c.Request.Context(), body.RefreshToken)
 	if err != nil {
-		respondError(c, http.StatusUnauthorized, "ERR_INVALID_TOKEN", err.Error())
+		if respondResponsibleError(c, err) {
+			return
+		}
+		switch err {
+		case domain.ErrTokenReused:
+			respondError(c, http.StatusUnauthorized, "ERR_TOKEN_REUSED", "refresh token was already used; log in again")
+		case domain.ErrTokenInvalid, domain.ErrTokenExpired, domain.ErrUserNotFound:
+			respondError(c, http.StatusUnauthorized, "ERR_INVALID_TOKEN", err.Error())
+		case domain.ErrUserInactive:
+			respondError(c, http.StatusForbidden, "ERR_ACCOUNT_DISABLED", err.Error())
+		default:
+			respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "refresh failed")
+		}
 		return
 	}
 	respondSuccess(c, http.StatusOK, gin.H{
@@ -94,6 +114,43 @@ func (h *UserHandler) Refresh(c *gin.Context) {
 	})
 }
 
+// Logout godoc
+// POST /api/auth/logout
+// Body: {"refresh_token": "..."}
+// Revokes the refresh token and the others of its login.  The access token
+// stays valid until it expires.
+func (h *UserHandler) Logout(c *gin.Context) {
+	var body struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
+
+	if err := h.authSvc.Logout(c.Request.Context(), body.RefreshToken); err != nil {
+		if err == domain.ErrTokenInvalid {
+			respondError(c, http.StatusUnauthorized, "ERR_INVALID_TOKEN", err.Error())
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "logout failed")
+		return
+	}
+	respondSuccess(c, http.StatusOK, gin.H{"logged_out": true})
+}
+
+// LogoutAll godoc
+// POST /api/auth/logout-all [JWT required]
+// Revokes every refresh token of the user, on all devices.
+func (h *UserHandler) LogoutAll(c *gin.Context) {
+	n, err := h.authSvc.LogoutAll(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "logout failed")
+		return
+	}
+	respondSuccess(c, http.StatusOK, gin.H{"revoked": n})
+}
+
 // Me godoc
 // GET /api/me [JWT required]
 func (h *UserHandler) Me(c *gin.Context) {
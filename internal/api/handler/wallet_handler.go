@@ -1,15 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
-	"strings"
-	"time"
-	"unicode/utf8"
 
 	"github.com/evetabi/prediction/internal/api/middleware"
 	"github.com/evetabi/prediction/internal/config"
 	"github.com/evetabi/prediction/internal/domain"
 	"github.com/evetabi/prediction/internal/repository"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/evetabi/prediction/internal/statement"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -17,17 +17,21 @@ import (
 
 // WalletHandler serves balance, transaction history, and withdrawal endpoints.
 type WalletHandler struct {
-	walletRepo *repository.WalletRepository
-	cfg        *config.Config
+	walletRepo    *repository.WalletRepository
+	withdrawalSvc *service.WithdrawalService
+	bonusSvc      *service.BonusService
+	statementSvc  *service.StatementService
+	cfg           *config.Config
 }
 
 // NewWalletHandler creates a WalletHandler.
-func NewWalletHandler(walletRepo *repository.WalletRepository, cfg *config.Config) *WalletHandler {
-	return &WalletHandler{walletRepo: walletRepo, cfg: cfg}
+func NewWalletHandler(walletRepo *repository.WalletRepository, withdrawalSvc *service.WithdrawalService, bonusSvc *service.BonusService, statementSvc *service.StatementService, cfg *config.Config) *WalletHandler {
+	return &WalletHandler{walletRepo: walletRepo, withdrawalSvc: withdrawalSvc, bonusSvc: bonusSvc, statementSvc: statementSvc, cfg: cfg}
 }
 
 // GetBalance godoc
 // GET /api/wallet/balance [JWT]
+// bonus is the stake-only bonus balance and the grant being wagered.
 func (h *WalletHandler) GetBalance(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 	wallet, err := h.walletRepo.GetByUserID(c.Request.Context(), userID)
@@ -35,37 +39,89 @@ func (h *WalletHandler) GetBalance(c *gin.Context) {
 		respondError(c, http.StatusNotFound, "ERR_WALLET_NOT_FOUND", err.Error())
 		return
 	}
+	bonus, err := h.bonusSvc.Summary(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not fetch bonus balance")
+		return
+	}
 	respondSuccess(c, http.StatusOK, gin.H{
 		"balance":   wallet.Balance,
 		"locked":    wallet.Locked,
 		"available": wallet.Available(),
+		"bonus":     bonus,
 	})
 }
 
+// GetBonuses godoc
+// GET /api/wallet/bonuses?page=1&limit=20 [JWT]
+func (h *WalletHandler) GetBonuses(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	page, limit := parsePagination(c)
+	offset := (page - 1) * limit
+
+	grants, total, err := h.bonusSvc.List(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not fetch bonuses")
+		return
+	}
+	respondList(c, grants, total, page, limit)
+}
+
 // GetTransactions godoc
-// GET /api/wallet/transactions?page=1&limit=20 [JWT]
+// GET /api/wallet/transactions?type=payout,refund&from=2024-01-01&to=2024-01-31&ref_id=uuid&cursor=&page=1&limit=20 [JWT]
+// Dates are business days, to inclusive.  meta.next_cursor fetches the next
+// page without shifting when new transactions arrive; page still works.
 func (h *WalletHandler) GetTransactions(c *gin.Context) {
 	userID := middleware.GetUserID(c)
+	f, err := statement.ParseFilter(c.Request.URL.Query(), h.cfg.Location())
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
 	page, limit := parsePagination(c)
 	offset := (page - 1) * limit
 
-	txns, err := h.walletRepo.GetTransactions(c.Request.Context(), userID, limit, offset)
+	res, err := h.statementSvc.History(c.Request.Context(), userID, f, c.Query("cursor"), limit, offset)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not fetch transactions")
+		respondStatementError(c, err)
 		return
 	}
-	respondList(c, txns, len(txns), page, limit)
+	respondCursorList(c, res.Transactions, res.Total, page, limit, res.NextCursor)
+}
+
+// ExportTransactions godoc
+// GET /api/wallet/transactions/export?format=csv|json&from=2024-01-01&to=2024-01-31&type=&ref_id= [JWT]
+// Downloads the statement for a period, with the same filters as
+// GetTransactions.
+func (h *WalletHandler) ExportTransactions(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	f, err := statement.ParseFilter(c.Request.URL.Query(), h.cfg.Location())
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
+	format := c.DefaultQuery("format", statement.FormatCSV)
+	data, err := h.statementSvc.Export(c.Request.Context(), userID, f, format)
+	if err != nil {
+		respondStatementError(c, err)
+		return
+	}
+	sendStatement(c, "transactions", format, data)
 }
 
 // Withdraw godoc
 // POST /api/wallet/withdraw [JWT]
-// Body: {"amount":"1000.00","iban":"TR..."}
+// Body: {"amount":"1000.00","bank_account_id":"uuid","forfeit_bonus":true}
+// Pays out to a saved bank account; the default one when none is given.
+// With an active bonus the request is refused (409 ERR_BONUS_ACTIVE) unless
+// forfeit_bonus confirms giving the bonus up.
 func (h *WalletHandler) Withdraw(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 
 	var body struct {
-		Amount string `json:"amount" binding:"required"`
-		IBAN   string `json:"iban"   binding:"required"`
+		Amount        string     `json:"amount"          binding:"required"`
+		BankAccountID *uuid.UUID `json:"bank_account_id"` // default account when omitted
+		ForfeitBonus  bool       `json:"forfeit_bonus"`
 	}
 	if err := c.ShouldBindJSON(&body); err != nil {
 		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
@@ -79,13 +135,6 @@ func (h *WalletHandler) Withdraw(c *gin.Context) {
 		return
 	}
 
-	// IBAN format: Turkey TR + 24 digits = 26 chars total
-	iban := strings.ToUpper(strings.ReplaceAll(body.IBAN, " ", ""))
-	if !isValidIBAN(iban) {
-		respondError(c, http.StatusBadRequest, "ERR_INVALID_IBAN", "IBAN must be 26 characters starting with TR followed by digits")
-		return
-	}
-
 	// Min withdraw check
 	minWithdraw := decimal.NewFromFloat(h.cfg.Wallet.MinWithdraw)
 	if amount.LessThan(minWithdraw) {
@@ -102,43 +151,66 @@ func (h *WalletHandler) Withdraw(c *gin.Context) {
 		return
 	}
 
-	// Daily limit check
-	ctx := c.Request.Context()
-	dailyTotal, err := h.walletRepo.GetDailyWithdrawTotal(ctx, userID)
+	// Hold the funds and create the request; the daily limit is checked
+	// under the wallet lock.
+	req, err := h.withdrawalSvc.Request(c.Request.Context(), userID, amount, body.BankAccountID, body.ForfeitBonus)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not check daily limit")
-		return
-	}
-	if dailyTotal.Add(amount).GreaterThan(maxDaily) {
-		respondError(c, http.StatusBadRequest, "ERR_DAILY_LIMIT_EXCEEDED",
-			"daily withdrawal limit of "+maxDaily.StringFixed(2)+" TRY would be exceeded")
+		switch err {
+		case domain.ErrBelowMinWithdraw:
+			respondError(c, http.StatusBadRequest, "ERR_BELOW_MIN_WITHDRAW", err.Error())
+		case domain.ErrBonusActive:
+			respondError(c, http.StatusConflict, "ERR_BONUS_ACTIVE",
+				"withdrawing forfeits your active bonus; resend with forfeit_bonus=true to confirm")
+		case domain.ErrBankAccountNotFound:
+			respondError(c, http.StatusNotFound, "ERR_BANK_ACCOUNT_NOT_FOUND", "add a bank account or choose one of your saved accounts")
+		case domain.ErrBankAccountNotVerified:
+			respondError(c, http.StatusForbidden, "ERR_BANK_ACCOUNT_NOT_VERIFIED", err.Error())
+		case domain.ErrBankAccountCoolingOff:
+			respondError(c, http.StatusForbidden, "ERR_BANK_ACCOUNT_COOLING_OFF", err.Error())
+		case domain.ErrWithdrawLimitExceeded:
+			respondError(c, http.StatusBadRequest, "ERR_DAILY_LIMIT_EXCEEDED",
+				"daily withdrawal limit of "+maxDaily.StringFixed(2)+" TRY would be exceeded")
+		case domain.ErrKYCRequired:
+			respondError(c, http.StatusForbidden, "ERR_KYC_REQUIRED", err.Error())
+		case domain.ErrInsufficientBalance:
+			respondError(c, http.StatusPaymentRequired, "ERR_INSUFFICIENT_BALANCE", err.Error())
+		case domain.ErrWalletFrozen:
+			respondError(c, http.StatusForbidden, "ERR_WALLET_FROZEN", err.Error())
+		case domain.ErrWalletNotFound:
+			respondError(c, http.StatusNotFound, "ERR_WALLET_NOT_FOUND", err.Error())
+		default:
+			respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not create withdrawal request")
+		}
 		return
 	}
+	hideAMLHold(req)
+	respondSuccess(c, http.StatusCreated, req)
+}
 
-	// Check wallet balance
-	wallet, err := h.walletRepo.GetByUserID(ctx, userID)
+// CancelWithdraw godoc
+// POST /api/wallet/withdraw/:id/cancel [JWT]
+// Cancels the caller's pending request and releases the held funds.
+func (h *WalletHandler) CancelWithdraw(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		respondError(c, http.StatusNotFound, "ERR_WALLET_NOT_FOUND", err.Error())
-		return
-	}
-	if wallet.Available().LessThan(amount) {
-		respondError(c, http.StatusPaymentRequired, "ERR_INSUFFICIENT_BALANCE", domain.ErrInsufficientBalance.Error())
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid withdrawal id")
 		return
 	}
-
-	req := &domain.WithdrawRequest{
-		ID:          uuid.New(),
-		UserID:      userID,
-		Amount:      amount,
-		Status:      domain.WithdrawPending,
-		IBAN:        iban,
-		RequestedAt: time.Now().UTC(),
-	}
-	if err = h.walletRepo.CreateWithdrawRequest(ctx, req); err != nil {
-		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not create withdrawal request")
+	req, err := h.withdrawalSvc.Cancel(c.Request.Context(), userID, id)
+	if err != nil {
+		switch err {
+		case domain.ErrWithdrawalNotFound:
+			respondError(c, http.StatusNotFound, "ERR_WITHDRAWAL_NOT_FOUND", err.Error())
+		case domain.ErrWithdrawalState:
+			respondError(c, http.StatusConflict, "ERR_WITHDRAWAL_NOT_PENDING", "only pending withdrawals can be cancelled")
+		default:
+			respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not cancel withdrawal")
+		}
 		return
 	}
-	respondSuccess(c, http.StatusCreated, req)
+	hideAMLHold(req)
+	respondSuccess(c, http.StatusOK, req)
 }
 
 // GetWithdrawStatus godoc
@@ -148,56 +220,81 @@ func (h *WalletHandler) GetWithdrawStatus(c *gin.Context) {
 	page, limit := parsePagination(c)
 	offset := (page - 1) * limit
 
-	// Fetch only this user's requests by re-querying with user filter
-	// (the current GetWithdrawRequests is admin-scoped; for user-facing we
-	// query withdraw_requests directly filtered by user_id)
-	ctx := c.Request.Context()
-	var reqs []*domain.WithdrawRequest
-	err := (func() error {
-		all, err := h.walletRepo.GetWithdrawRequests(ctx, "", limit+offset, 0)
-		if err != nil {
-			return err
-		}
-		for _, r := range all {
-			if r.UserID == userID {
-				reqs = append(reqs, r)
-			}
-		}
-		// Simple in-process pagination (adequate for typical withdraw counts)
-		if offset >= len(reqs) {
-			reqs = nil
-		} else {
-			end := offset + limit
-			if end > len(reqs) {
-				end = len(reqs)
-			}
-			reqs = reqs[offset:end]
-		}
-		return nil
-	})()
+	reqs, total, err := h.withdrawalSvc.GetUserRequests(c.Request.Context(), userID, limit, offset)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not fetch withdrawal requests")
 		return
 	}
-	respondList(c, reqs, len(reqs), page, limit)
+	hideAMLHold(reqs...)
+	respondList(c, reqs, total, page, limit)
+}
+
+// GetStatements godoc
+// GET /api/wallet/statements?page=1&limit=20 [JWT]
+// Lists the user's monthly account statements, newest first.
+func (h *WalletHandler) GetStatements(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	page, limit := parsePagination(c)
+	offset := (page - 1) * limit
+
+	items, total, err := h.statementSvc.ListMonthly(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		respondStatementError(c, err)
+		return
+	}
+	respondList(c, items, total, page, limit)
 }
 
-// ── IBAN validation ───────────────────────────────────────────────────────────
+// DownloadStatement godoc
+// GET /api/wallet/statements/:month?format=pdf|csv [JWT]
+// Downloads the statement for month (YYYY-MM).
+func (h *WalletHandler) DownloadStatement(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	month := c.Param("month")
+	format := c.DefaultQuery("format", statement.FormatPDF)
+	data, err := h.statementSvc.DownloadMonthly(c.Request.Context(), userID, month, format)
+	if err != nil {
+		respondStatementError(c, err)
+		return
+	}
+	sendStatement(c, "statement-"+month, format, data)
+}
 
-// isValidIBAN performs a lightweight structural check on a Turkish IBAN.
-// Full ISO 13616 MOD-97 math validation is omitted for brevity but can be
-// added without changing the handler interface.
-func isValidIBAN(iban string) bool {
-	if utf8.RuneCountInString(iban) != 26 {
-		return false
+// sendStatement writes a statement or export file as a download.
+func sendStatement(c *gin.Context, name, format string, data []byte) {
+	contentType := "text/csv; charset=utf-8"
+	switch format {
+	case statement.FormatJSON:
+		contentType = "application/json"
+	case statement.FormatPDF:
+		contentType = "application/pdf"
 	}
-	if !strings.HasPrefix(iban, "TR") {
-		return false
+	c.Header("Content-Disposition", `attachment; filename="`+name+`.`+format+`"`)
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// respondStatementError maps transaction history errors to HTTP responses.
+func respondStatementError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidCursor):
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_CURSOR", err.Error())
+	case errors.Is(err, statement.ErrUnknownFormat):
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", "unsupported format")
+	case errors.Is(err, domain.ErrInvalidStatementMonth):
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+	case errors.Is(err, domain.ErrStatementNotFound):
+		respondError(c, http.StatusNotFound, "ERR_NOT_FOUND", err.Error())
+	case errors.Is(err, domain.ErrExportTooLarge):
+		respondError(c, http.StatusUnprocessableEntity, "ERR_EXPORT_TOO_LARGE", err.Error()+"; narrow the period")
+	default:
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", "could not fetch transactions")
 	}
-	for _, ch := range iban[2:] {
-		if ch < '0' || ch > '9' {
-			return false
-		}
+}
+
+// hideAMLHold clears the AML hold flag on requests shown to their owner: a
+// user must not learn that they are under AML review.
+func hideAMLHold(reqs ...*domain.WithdrawRequest) {
+	for _, r := range reqs {
+		r.AMLHold = false
 	}
-	return true
 }
@@ -15,12 +15,21 @@ import (
 // RouterDeps bundles every dependency needed to build the router.
 // Populated once in main() and passed to SetupRouter.
 type RouterDeps struct {
-	AuthSvc    *service.AuthService
-	MarketSvc  *service.MarketService
-	BetSvc     *service.BetService
-	WalletRepo *repository.WalletRepository
-	Hub        *ws.Hub
-	Cfg        *config.Config
+	AuthSvc        *service.AuthService
+	MarketSvc      *service.MarketService
+	BetSvc         *service.BetService
+	DepositSvc     *service.DepositService
+	WithdrawalSvc  *service.WithdrawalService
+	BankAccountSvc *service.BankAccountService
+	BonusSvc       *service.BonusService
+	PromotionSvc   *service.PromotionService
+	ReferralSvc    *service.ReferralService
+	ResponsibleSvc *service.ResponsibleService
+	KYCSvc         *service.KYCService
+	StatementSvc   *service.StatementService
+	WalletRepo     *repository.WalletRepository
+	Hub            *ws.Hub
+	Cfg            *config.Config
 }
 
 // SetupRouter creates and configures the main Gin engine with all routes,
@@ -46,7 +55,13 @@ func SetupRouter(deps RouterDeps) *gin.Engine {
 	userH := handler.NewUserHandler(deps.AuthSvc, deps.WalletRepo)
 	marketH := handler.NewMarketHandler(deps.MarketSvc)
 	betH := handler.NewBetHandler(deps.BetSvc)
-	walletH := handler.NewWalletHandler(deps.WalletRepo, deps.Cfg)
+	walletH := handler.NewWalletHandler(deps.WalletRepo, deps.WithdrawalSvc, deps.BonusSvc, deps.StatementSvc, deps.Cfg)
+	depositH := handler.NewDepositHandler(deps.DepositSvc)
+	bankH := handler.NewBankAccountHandler(deps.BankAccountSvc)
+	promoH := handler.NewPromotionHandler(deps.PromotionSvc)
+	referralH := handler.NewReferralHandler(deps.ReferralSvc)
+	rgH := handler.NewResponsibleHandler(deps.ResponsibleSvc)
+	kycH := handler.NewKYCHandler(deps.KYCSvc, deps.Cfg)
 
 	// ── JWT middleware (shared) ───────────────────────────────────────────────
 	jwtMW := middleware.JWTMiddleware(deps.AuthSvc)
@@ -64,6 +79,8 @@ func SetupRouter(deps RouterDeps) *gin.Engine {
 			auth.POST("/register", userH.Register)
 			auth.POST("/login", userH.Login)
 			auth.POST("/refresh", userH.Refresh)
+			auth.POST("/logout", userH.Logout)
+			auth.POST("/logout-all", jwtMW, userH.LogoutAll)
 		}
 
 		// ── Markets (public) ─────────────────────────────────────────────────
@@ -75,6 +92,15 @@ func SetupRouter(deps RouterDeps) *gin.Engine {
 			markets.GET("/:id", marketH.GetByID)
 		}
 
+		// ── Payment provider webhooks (HMAC-signed, no JWT) ──────────────────
+		payments := api.Group("/payments")
+		{
+			payments.POST("/webhook/:provider", depositH.Webhook)
+			if !deps.Cfg.IsProd() {
+				payments.POST("/mock/:ref", depositH.MockPay)
+			}
+		}
+
 		// ── Authenticated routes ──────────────────────────────────────────────
 		authed := api.Group("")
 		authed.Use(jwtMW)
@@ -97,8 +123,51 @@ func SetupRouter(deps RouterDeps) *gin.Engine {
 			{
 				wallet.GET("/balance", walletH.GetBalance)
 				wallet.GET("/transactions", walletH.GetTransactions)
+				wallet.GET("/transactions/export", walletH.ExportTransactions)
+				wallet.GET("/statements", walletH.GetStatements)
+				wallet.GET("/statements/:month", walletH.DownloadStatement)
+				wallet.GET("/bonuses", walletH.GetBonuses)
 				wallet.POST("/withdraw", walletH.Withdraw)
 				wallet.GET("/withdraw/status", walletH.GetWithdrawStatus)
+				wallet.POST("/withdraw/:id/cancel", walletH.CancelWithdraw)
+				wallet.POST("/deposits", depositH.CreateDeposit)
+				wallet.GET("/deposits", depositH.GetMyDeposits)
+				wallet.GET("/deposits/:id", depositH.GetMyDeposit)
+				wallet.GET("/bank-accounts", bankH.List)
+				wallet.POST("/bank-accounts", bankH.Add)
+				wallet.POST("/bank-accounts/:id/default", bankH.SetDefault)
+				wallet.DELETE("/bank-accounts/:id", bankH.Remove)
+			}
+
+			// Promotions (redeem shares the strict limit to slow code guessing)
+			promotions := authed.Group("/promotions")
+			{
+				promotions.POST("/redeem", authRL, promoH.Redeem)
+				promotions.GET("/free-bets", promoH.GetFreeBets)
+			}
+
+			// Referrals
+			referrals := authed.Group("/referrals")
+			{
+				referrals.GET("", referralH.GetStats)
+				referrals.GET("/commissions", referralH.GetCommissions)
+			}
+
+			// Responsible gambling
+			rg := authed.Group("/responsible-gambling")
+			{
+				rg.GET("", rgH.GetOverview)
+				rg.PUT("/limits", rgH.SetLimit)
+				rg.POST("/cooling-off", rgH.CoolingOff)
+				rg.POST("/self-exclusion", rgH.SelfExclude)
+			}
+
+			// Identity verification
+			kyc := authed.Group("/kyc")
+			{
+				kyc.GET("", kycH.GetStatus)
+				kyc.POST("/documents", kycH.UploadDocument)
+				kyc.POST("/submit", kycH.Submit)
 			}
 		}
 	}
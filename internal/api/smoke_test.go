@@ -12,12 +12,14 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/evetabi/prediction/internal/api"
 	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/payment"
 	"github.com/evetabi/prediction/internal/service"
 )
 
@@ -50,12 +52,15 @@ func buildTestRouter(t *testing.T) http.Handler {
 	t.Helper()
 	cfg := testCfg()
 	// NewAuthService with nil DB works for ParseAccessToken (secret-only op)
-	authSvc := service.NewAuthService(nil, nil, nil, cfg)
+	authSvc := service.NewAuthService(nil, nil, nil, nil, nil, nil, cfg)
+	// Webhook signatures are checked before the DB is touched
+	depositSvc := service.NewDepositService(nil, nil, nil, payment.NewMock("test-webhook-secret", ""), nil, nil, cfg)
 
 	r := api.SetupRouter(api.RouterDeps{
 		AuthSvc:    authSvc,
 		MarketSvc:  nil,
 		BetSvc:     nil,
+		DepositSvc: depositSvc,
 		WalletRepo: nil,
 		Hub:        nil,
 		Cfg:        cfg,
@@ -187,6 +192,36 @@ func TestWalletWithdraw_NoToken_Returns401(t *testing.T) {
 	}
 }
 
+func TestWalletDeposit_NoToken_Returns401(t *testing.T) {
+	h := buildTestRouter(t)
+	rr := do(t, h, http.MethodPost, "/api/wallet/deposits", `{"amount":"100.00"}`, nil)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("POST /api/wallet/deposits without token = %d, want 401", rr.Code)
+	}
+}
+
+// ── Payment webhooks ──────────────────────────────────────────────────────────
+
+func TestPaymentWebhook_BadSignature_Returns401(t *testing.T) {
+	h := buildTestRouter(t)
+	payload := `{"id":"evt_1","type":"payment.succeeded","payment_ref":"mock_1","amount":"100"}`
+	rr := do(t, h, http.MethodPost, "/api/payments/webhook/mock", payload, map[string]string{
+		payment.HeaderTimestamp: strconv.FormatInt(time.Now().Unix(), 10),
+		payment.HeaderSignature: "00",
+	})
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("webhook with bad signature = %d, want 401", rr.Code)
+	}
+}
+
+func TestPaymentWebhook_UnknownProvider_Returns404(t *testing.T) {
+	h := buildTestRouter(t)
+	rr := do(t, h, http.MethodPost, "/api/payments/webhook/acme", `{}`, nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("webhook for unknown provider = %d, want 404", rr.Code)
+	}
+}
+
 // ── JWT auth middleware (invalid token → 401) ─────────────────────────────────
 
 func TestMe_InvalidToken_Returns401(t *testing.T) {
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// AdjustmentHandler serves manual balance adjustments: requests under
+// /admin/users/:id/balance and the review queue under
+// /admin/finance/adjustments.
+type AdjustmentHandler struct {
+	adjustmentSvc *service.AdjustmentService
+}
+
+// NewAdjustmentHandler creates an AdjustmentHandler.
+func NewAdjustmentHandler(adjustmentSvc *service.AdjustmentService) *AdjustmentHandler {
+	return &AdjustmentHandler{adjustmentSvc: adjustmentSvc}
+}
+
+// Request godoc
+// POST /admin/users/:id/balance [admin, finance, risk, ops]
+// Body: {"amount": "-250", "reason": "correction", "note": "duplicate deposit 4711"}
+// Records a pending adjustment; the balance changes once another finance
+// or admin user approves it.  reason is one of correction, goodwill,
+// promotion, chargeback, fraud or other.
+func (h *AdjustmentHandler) Request(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid user id")
+		return
+	}
+	var body struct {
+		Amount string `json:"amount" binding:"required"`
+		Reason string `json:"reason" binding:"required"`
+		Note   string `json:"note"   binding:"required,max=1000"`
+	}
+	if err = c.ShouldBindJSON(&body); err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
+	amount, err := decimal.NewFromString(body.Amount)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_AMOUNT", "amount must be a decimal string")
+		return
+	}
+
+	a, err := h.adjustmentSvc.Request(c.Request.Context(), adminUserID(c), userID, amount, domain.AdjustmentReason(body.Reason), body.Note)
+	if err != nil {
+		respondAdjustmentError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusCreated, a)
+}
+
+// List godoc
+// GET /admin/finance/adjustments?status=pending&user_id=&page=1&limit=50
+// Defaults to the pending queue, oldest first; status=all lists everything.
+func (h *AdjustmentHandler) List(c *gin.Context) {
+	var statuses []domain.AdjustmentStatus
+	switch status := c.DefaultQuery("status", string(domain.AdjustmentPending)); status {
+	case "all":
+	case string(domain.AdjustmentPending), string(domain.AdjustmentApproved), string(domain.AdjustmentRejected):
+		statuses = []domain.AdjustmentStatus{domain.AdjustmentStatus(status)}
+	default:
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", "status must be pending, approved, rejected or all")
+		return
+	}
+	var userID *uuid.UUID
+	if s := c.Query("user_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid user id")
+			return
+		}
+		userID = &id
+	}
+	page, limit := adminPagination(c)
+	offset := (page - 1) * limit
+
+	items, total, err := h.adjustmentSvc.List(c.Request.Context(), statuses, userID, limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondList(c, items, total, page, limit)
+}
+
+// Detail godoc
+// GET /admin/finance/adjustments/:id
+func (h *AdjustmentHandler) Detail(c *gin.Context) {
+	id, ok := adjustmentID(c)
+	if !ok {
+		return
+	}
+	a, err := h.adjustmentSvc.Get(c.Request.Context(), id)
+	if err != nil {
+		respondAdjustmentError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusOK, a)
+}
+
+// Approve godoc
+// POST /admin/finance/adjustments/:id/approve
+// Body (optional): {"note": "..."}
+// Posts the adjustment to the wallet.  The requester cannot approve it.
+func (h *AdjustmentHandler) Approve(c *gin.Context) {
+	h.review(c, true)
+}
+
+// Reject godoc
+// POST /admin/finance/adjustments/:id/reject
+// Body: {"note": "reason"}
+func (h *AdjustmentHandler) Reject(c *gin.Context) {
+	h.review(c, false)
+}
+
+func (h *AdjustmentHandler) review(c *gin.Context, approve bool) {
+	id, ok := adjustmentID(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		Note string `json:"note" binding:"max=1000"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+			return
+		}
+	}
+	if !approve && body.Note == "" {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", "note is required when rejecting")
+		return
+	}
+
+	review := h.adjustmentSvc.Reject
+	if approve {
+		review = h.adjustmentSvc.Approve
+	}
+	a, err := review(c.Request.Context(), id, adminUserID(c), body.Note)
+	if err != nil {
+		respondAdjustmentError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusOK, a)
+}
+
+// ── helpers ───────────────────────────────────────────────────────────────────
+
+// adjustmentID parses the :id param, answering 400 when it is malformed.
+func adjustmentID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid adjustment id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func respondAdjustmentError(c *gin.Context, err error) {
+	switch {
+	case domain.IsNotFound(err):
+		respondError(c, http.StatusNotFound, "ERR_NOT_FOUND", err.Error())
+	case errors.Is(err, domain.ErrInvalidAdjustment):
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ADJUSTMENT", "amount must not be zero, reason must be known and note is required")
+	case errors.Is(err, domain.ErrSelfApproval):
+		respondError(c, http.StatusForbidden, "ERR_SELF_APPROVAL", err.Error())
+	case errors.Is(err, domain.ErrAdjustmentState):
+		respondError(c, http.StatusConflict, "ERR_ADJUSTMENT_STATE", "adjustment was already reviewed")
+	case errors.Is(err, domain.ErrInsufficientBalance):
+		respondError(c, http.StatusUnprocessableEntity, "ERR_INSUFFICIENT_BALANCE", err.Error())
+	case errors.Is(err, domain.ErrWalletFrozen):
+		respondError(c, http.StatusUnprocessableEntity, "ERR_WALLET_FROZEN", err.Error())
+	default:
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+	}
+}
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/evetabi/prediction/internal/aml"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AMLHandler serves /admin/aml: the AML case queue and case actions.
+type AMLHandler struct {
+	amlSvc *service.AMLService
+}
+
+// NewAMLHandler creates an AMLHandler.
+func NewAMLHandler(amlSvc *service.AMLService) *AMLHandler {
+	return &AMLHandler{amlSvc: amlSvc}
+}
+
+// Rules godoc
+// GET /admin/aml/rules
+// Lists the screening rules in force.
+func (h *AMLHandler) Rules(c *gin.Context) {
+	respondSuccess(c, http.StatusOK, aml.Names())
+}
+
+// List godoc
+// GET /admin/aml/cases?status=open&user_id=uuid&page=1&limit=20
+// Defaults to the live queue (open and escalated), most severe and then
+// oldest first; status=all lists everything.
+func (h *AMLHandler) List(c *gin.Context) {
+	var statuses []domain.AMLCaseStatus
+	switch status := c.Query("status"); status {
+	case "":
+		statuses = []domain.AMLCaseStatus{domain.AMLCaseOpen, domain.AMLCaseEscalated}
+	case "all":
+	default:
+		statuses = []domain.AMLCaseStatus{domain.AMLCaseStatus(status)}
+	}
+	var userID *uuid.UUID
+	if s := c.Query("user_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid user_id")
+			return
+		}
+		userID = &id
+	}
+	page, limit := adminPagination(c)
+	offset := (page - 1) * limit
+
+	items, total, err := h.amlSvc.List(c.Request.Context(), statuses, userID, limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondList(c, items, total, page, limit)
+}
+
+// Detail godoc
+// GET /admin/aml/cases/:id
+// Returns the case with its alerts, history and the user's pending
+// withdrawals.
+func (h *AMLHandler) Detail(c *gin.Context) {
+	id, ok := amlCaseID(c)
+	if !ok {
+		return
+	}
+	d, err := h.amlSvc.Get(c.Request.Context(), id)
+	if err != nil {
+		respondAMLError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusOK, d)
+}
+
+// Assign godoc
+// POST /admin/aml/cases/:id/assign
+// Body (optional): {"assignee_id": "uuid"}; defaults to the caller.
+func (h *AMLHandler) Assign(c *gin.Context) {
+	id, ok := amlCaseID(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		AssigneeID *uuid.UUID `json:"assignee_id"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+			return
+		}
+	}
+	assignee := adminUserID(c)
+	if body.AssigneeID != nil {
+		assignee = *body.AssigneeID
+	}
+	ac, err := h.amlSvc.Assign(c.Request.Context(), id, adminUserID(c), assignee)
+	if err != nil {
+		respondAMLError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusOK, ac)
+}
+
+// Note godoc
+// POST /admin/aml/cases/:id/notes
+// Body: {"note": "called the user, source of funds is salary"}
+func (h *AMLHandler) Note(c *gin.Context) {
+	h.act(c, true, func(id uuid.UUID, note string) (*domain.AMLCase, error) {
+		return h.amlSvc.Note(c.Request.Context(), id, adminUserID(c), note)
+	})
+}
+
+// Escalate godoc
+// POST /admin/aml/cases/:id/escalate
+// Body (optional): {"note": "..."}
+func (h *AMLHandler) Escalate(c *gin.Context) {
+	h.act(c, false, func(id uuid.UUID, note string) (*domain.AMLCase, error) {
+		return h.amlSvc.Escalate(c.Request.Context(), id, adminUserID(c), note)
+	})
+}
+
+// Hold godoc
+// POST /admin/aml/cases/:id/hold
+// Body (optional): {"note": "..."}
+// Holds the user's pending and future withdrawals while the case is live.
+func (h *AMLHandler) Hold(c *gin.Context) {
+	h.act(c, false, func(id uuid.UUID, note string) (*domain.AMLCase, error) {
+		return h.amlSvc.SetHold(c.Request.Context(), id, adminUserID(c), true, note)
+	})
+}
+
+// Release godoc
+// POST /admin/aml/cases/:id/release
+// Body: {"note": "..."}
+// Releases the hold; the case stays open.
+func (h *AMLHandler) Release(c *gin.Context) {
+	h.act(c, true, func(id uuid.UUID, note string) (*domain.AMLCase, error) {
+		return h.amlSvc.SetHold(c.Request.Context(), id, adminUserID(c), false, note)
+	})
+}
+
+// Clear godoc
+// POST /admin/aml/cases/:id/clear
+// Body: {"note": "..."}
+// Closes the case as explained and releases any hold.
+func (h *AMLHandler) Clear(c *gin.Context) {
+	h.act(c, true, func(id uuid.UUID, note string) (*domain.AMLCase, error) {
+		return h.amlSvc.Close(c.Request.Context(), id, adminUserID(c), false, note)
+	})
+}
+
+// Report godoc
+// POST /admin/aml/cases/:id/report
+// Body: {"note": "STR filed, ref ..."}
+// Closes the case as reported to the authority; held withdrawals stay held
+// until finance rejects them.
+func (h *AMLHandler) Report(c *gin.Context) {
+	h.act(c, true, func(id uuid.UUID, note string) (*domain.AMLCase, error) {
+		return h.amlSvc.Close(c.Request.Context(), id, adminUserID(c), true, note)
+	})
+}
+
+// act parses the case id and note body and runs a case action.
+func (h *AMLHandler) act(c *gin.Context, noteRequired bool, run func(id uuid.UUID, note string) (*domain.AMLCase, error)) {
+	id, ok := amlCaseID(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		Note string `json:"note" binding:"max=2000"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+			return
+		}
+	}
+	if noteRequired && body.Note == "" {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", "note is required")
+		return
+	}
+	ac, err := run(id, body.Note)
+	if err != nil {
+		respondAMLError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusOK, ac)
+}
+
+// ── helpers ───────────────────────────────────────────────────────────────────
+
+// amlCaseID parses the :id param, answering 400 when it is malformed.
+func amlCaseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid case id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondAMLError maps AML errors to HTTP responses.
+func respondAMLError(c *gin.Context, err error) {
+	switch {
+	case domain.IsNotFound(err):
+		respondError(c, http.StatusNotFound, "ERR_NOT_FOUND", err.Error())
+	case errors.Is(err, domain.ErrAMLCaseState):
+		respondError(c, http.StatusConflict, "ERR_AML_CASE_STATE", err.Error())
+	default:
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+	}
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler serves /admin/audit endpoints.
+type AuditHandler struct {
+	auditRepo *repository.AuditRepository
+}
+
+// NewAuditHandler creates an AuditHandler.
+func NewAuditHandler(auditRepo *repository.AuditRepository) *AuditHandler {
+	return &AuditHandler{auditRepo: auditRepo}
+}
+
+// List godoc
+// GET /admin/audit?entity_type=price_source&entity_id=binance&page=1&limit=50
+func (h *AuditHandler) List(c *gin.Context) {
+	page, limit := adminPagination(c)
+	offset := (page - 1) * limit
+
+	entries, total, err := h.auditRepo.List(c.Request.Context(),
+		c.Query("entity_type"), c.Query("entity_id"), limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondList(c, entries, total, page, limit)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BankAccountHandler serves /admin/finance/bank-accounts, the review queue
+// for saved IBANs whose holder did not match the user's registered name.
+type BankAccountHandler struct {
+	bankSvc *service.BankAccountService
+}
+
+// NewBankAccountHandler creates a BankAccountHandler.
+func NewBankAccountHandler(bankSvc *service.BankAccountService) *BankAccountHandler {
+	return &BankAccountHandler{bankSvc: bankSvc}
+}
+
+// List godoc
+// GET /admin/finance/bank-accounts?status=pending&user_id=uuid&page=1&limit=20
+func (h *BankAccountHandler) List(c *gin.Context) {
+	status := c.DefaultQuery("status", "")
+	var userID *uuid.UUID
+	if s := c.Query("user_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid user_id")
+			return
+		}
+		userID = &id
+	}
+	page, limit := adminPagination(c)
+	offset := (page - 1) * limit
+
+	accounts, total, err := h.bankSvc.ListAccounts(c.Request.Context(), status, userID, limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondList(c, accounts, total, page, limit)
+}
+
+// Verify godoc
+// POST /admin/finance/bank-accounts/:id/verify
+// Body (optional): {"note": "holder confirmed with bank"}
+func (h *BankAccountHandler) Verify(c *gin.Context) {
+	h.review(c, true)
+}
+
+// Reject godoc
+// POST /admin/finance/bank-accounts/:id/reject
+// Body: {"note": "holder is not the user"}
+func (h *BankAccountHandler) Reject(c *gin.Context) {
+	h.review(c, false)
+}
+
+func (h *BankAccountHandler) review(c *gin.Context, verify bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid bank account id")
+		return
+	}
+	var body struct {
+		Note string `json:"note"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+			return
+		}
+	}
+	if !verify && body.Note == "" {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", "note is required when rejecting")
+		return
+	}
+
+	acct, err := h.bankSvc.Review(c.Request.Context(), id, adminUserID(c), verify, body.Note)
+	if err != nil {
+		if domain.IsNotFound(err) {
+			respondError(c, http.StatusNotFound, "ERR_NOT_FOUND", err.Error())
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondSuccess(c, http.StatusOK, acct)
+}
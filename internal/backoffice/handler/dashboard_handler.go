@@ -89,7 +89,7 @@ func (h *DashboardHandler) Dashboard(c *gin.Context) {
 	}
 
 	// ── Pending withdrawals ───────────────────────────────────────────────────
-	pending, _ := h.walletRepo.GetWithdrawRequests(ctx, "pending", 1000, 0)
+	pending, pendingCount, _ := h.walletRepo.GetWithdrawRequests(ctx, "pending", 1000, 0)
 	var pendingTotal decimal.Decimal
 	for _, p := range pending {
 		pendingTotal = pendingTotal.Add(p.Amount)
@@ -110,7 +110,7 @@ func (h *DashboardHandler) Dashboard(c *gin.Context) {
 		"mm_daily_limit":   h.cfg.MM.MaxDailyLoss,
 		"mm_interventions": mmDataField(mmStats, "interventions"),
 		"pending_withdrawals": gin.H{
-			"count": len(pending),
+			"count": pendingCount,
 			"total": pendingTotal,
 		},
 		"ws_connections": wsConnections,
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DepositHandler serves /admin/finance/deposits.
+type DepositHandler struct {
+	depositSvc *service.DepositService
+}
+
+// NewDepositHandler creates a DepositHandler.
+func NewDepositHandler(depositSvc *service.DepositService) *DepositHandler {
+	return &DepositHandler{depositSvc: depositSvc}
+}
+
+// List godoc
+// GET /admin/finance/deposits?status=pending&user_id=uuid&page=1&limit=20
+func (h *DepositHandler) List(c *gin.Context) {
+	status := c.DefaultQuery("status", "")
+	var userID *uuid.UUID
+	if s := c.Query("user_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid user_id")
+			return
+		}
+		userID = &id
+	}
+	page, limit := adminPagination(c)
+	offset := (page - 1) * limit
+
+	deposits, total, err := h.depositSvc.ListDeposits(c.Request.Context(), status, userID, limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondList(c, deposits, total, page, limit)
+}
+
+// Detail godoc
+// GET /admin/finance/deposits/:id
+func (h *DepositHandler) Detail(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid deposit id")
+		return
+	}
+	d, err := h.depositSvc.GetDeposit(c.Request.Context(), id)
+	if err != nil {
+		if domain.IsNotFound(err) {
+			respondError(c, http.StatusNotFound, "ERR_NOT_FOUND", err.Error())
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondSuccess(c, http.StatusOK, d)
+}
@@ -1,12 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/evetabi/prediction/internal/config"
 	"github.com/evetabi/prediction/internal/domain"
 	"github.com/evetabi/prediction/internal/repository"
+	"github.com/evetabi/prediction/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -14,18 +16,20 @@ import (
 
 // FinanceHandler serves /admin/finance endpoints.
 type FinanceHandler struct {
-	walletRepo *repository.WalletRepository
-	marketRepo *repository.MarketRepository
-	cfg        *config.Config
+	walletRepo    *repository.WalletRepository
+	marketRepo    *repository.MarketRepository
+	withdrawalSvc *service.WithdrawalService
+	cfg           *config.Config
 }
 
 // NewFinanceHandler creates a FinanceHandler.
 func NewFinanceHandler(
 	walletRepo *repository.WalletRepository,
 	marketRepo *repository.MarketRepository,
+	withdrawalSvc *service.WithdrawalService,
 	cfg *config.Config,
 ) *FinanceHandler {
-	return &FinanceHandler{walletRepo: walletRepo, marketRepo: marketRepo, cfg: cfg}
+	return &FinanceHandler{walletRepo: walletRepo, marketRepo: marketRepo, withdrawalSvc: withdrawalSvc, cfg: cfg}
 }
 
 // Withdrawals godoc
@@ -35,83 +39,133 @@ func (h *FinanceHandler) Withdrawals(c *gin.Context) {
 	page, limit := adminPagination(c)
 	offset := (page - 1) * limit
 
-	reqs, err := h.walletRepo.GetWithdrawRequests(c.Request.Context(), status, limit, offset)
+	reqs, total, err := h.walletRepo.GetWithdrawRequests(c.Request.Context(), status, limit, offset)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
 		return
 	}
-	respondList(c, reqs, len(reqs), page, limit)
+	respondList(c, reqs, total, page, limit)
 }
 
 // ApproveWithdrawal godoc
 // POST /admin/finance/withdrawals/:id/approve
+// Body (optional): {"note": "..."}
+// Releases the hold and debits the wallet.
 func (h *FinanceHandler) ApproveWithdrawal(c *gin.Context) {
-	id, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid withdrawal id")
+	id, ok := withdrawalID(c)
+	if !ok {
 		return
 	}
-	adminID := adminUserID(c)
-	if err = h.walletRepo.UpdateWithdrawStatus(
-		c.Request.Context(), id, domain.WithdrawApproved, "Approved by admin", adminID,
-	); err != nil {
-		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+	var body struct {
+		Note string `json:"note"`
+	}
+	_ = c.ShouldBindJSON(&body) // note is optional
+	req, err := h.withdrawalSvc.Approve(c.Request.Context(), id, adminUserID(c), body.Note)
+	if err != nil {
+		respondWithdrawalError(c, err)
 		return
 	}
-	respondSuccess(c, http.StatusOK, gin.H{"id": id, "status": "approved"})
+	respondSuccess(c, http.StatusOK, req)
 }
 
 // RejectWithdrawal godoc
 // POST /admin/finance/withdrawals/:id/reject
 // Body: {"note": "reason"}
 func (h *FinanceHandler) RejectWithdrawal(c *gin.Context) {
-	id, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid withdrawal id")
+	id, ok := withdrawalID(c)
+	if !ok {
 		return
 	}
 	var body struct {
 		Note string `json:"note"`
 	}
 	_ = c.ShouldBindJSON(&body) // note is optional
-	adminID := adminUserID(c)
-	if err = h.walletRepo.UpdateWithdrawStatus(
-		c.Request.Context(), id, domain.WithdrawRejected, body.Note, adminID,
-	); err != nil {
-		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+	req, err := h.withdrawalSvc.Reject(c.Request.Context(), id, adminUserID(c), body.Note)
+	if err != nil {
+		respondWithdrawalError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusOK, req)
+}
+
+// ExecuteWithdrawal godoc
+// POST /admin/finance/withdrawals/:id/execute
+// Sends an approved withdrawal to the bank payout provider.
+func (h *FinanceHandler) ExecuteWithdrawal(c *gin.Context) {
+	id, ok := withdrawalID(c)
+	if !ok {
+		return
+	}
+	req, err := h.withdrawalSvc.Execute(c.Request.Context(), id, adminUserID(c))
+	if err != nil {
+		respondWithdrawalError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusOK, req)
+}
+
+// SettleWithdrawal godoc
+// POST /admin/finance/withdrawals/:id/settle
+// Body: {"success": true, "payout_ref": "...", "reason": "..."}
+// Records the payout outcome of a processing withdrawal by hand, e.g. after
+// checking a transfer whose outcome the provider did not report.
+func (h *FinanceHandler) SettleWithdrawal(c *gin.Context) {
+	id, ok := withdrawalID(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		Success   *bool  `json:"success" binding:"required"`
+		PayoutRef string `json:"payout_ref"`
+		Reason    string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
+	if !*body.Success && body.Reason == "" {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", "reason is required for a failed payout")
+		return
+	}
+	req, err := h.withdrawalSvc.Settle(c.Request.Context(), id, *body.Success, body.PayoutRef, body.Reason, adminUserID(c))
+	if err != nil {
+		respondWithdrawalError(c, err)
 		return
 	}
-	respondSuccess(c, http.StatusOK, gin.H{"id": id, "status": "rejected"})
+	respondSuccess(c, http.StatusOK, req)
 }
 
 // Report godoc
 // GET /admin/finance/report?from=2024-01-01&to=2024-01-31
+// Dates are business days in the configured business timezone.
 func (h *FinanceHandler) Report(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	fromStr := c.Query("from")
 	toStr := c.Query("to")
+	loc := h.cfg.Location()
 
 	var from, to time.Time
 	var err error
 	if fromStr != "" {
-		from, err = time.Parse("2006-01-02", fromStr)
+		from, err = time.ParseInLocation("2006-01-02", fromStr, loc)
 		if err != nil {
 			respondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "from must be YYYY-MM-DD")
 			return
 		}
 	} else {
-		from = time.Now().UTC().AddDate(0, -1, 0).Truncate(24 * time.Hour) // default: last 30 days
+		y, m, d := time.Now().In(loc).Date()
+		from = time.Date(y, m, d, 0, 0, 0, 0, loc).AddDate(0, -1, 0) // default: last month
 	}
 	if toStr != "" {
-		to, err = time.Parse("2006-01-02", toStr)
+		to, err = time.ParseInLocation("2006-01-02", toStr, loc)
 		if err != nil {
 			respondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "to must be YYYY-MM-DD")
 			return
 		}
-		to = to.Add(24 * time.Hour) // inclusive
+		to = to.AddDate(0, 0, 1) // inclusive
 	} else {
-		to = time.Now().UTC()
+		to = time.Now().In(loc)
 	}
 
 	report, err := h.marketRepo.GetFinanceReport(ctx, from, to)
@@ -127,16 +181,44 @@ func (h *FinanceHandler) Report(c *gin.Context) {
 func (h *FinanceHandler) Transactions(c *gin.Context) {
 	page, limit := adminPagination(c)
 	offset := (page - 1) * limit
-	txns, err := h.walletRepo.GetPlatformTransactions(c.Request.Context(), limit, offset)
+	txns, total, err := h.walletRepo.GetPlatformTransactions(c.Request.Context(), limit, offset)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
 		return
 	}
-	respondList(c, txns, len(txns), page, limit)
+	respondList(c, txns, total, page, limit)
 }
 
 // ── helper ────────────────────────────────────────────────────────────────────
 
+// withdrawalID parses the :id param, answering 400 when it is malformed.
+func withdrawalID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid withdrawal id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondWithdrawalError maps WithdrawalService errors to responses.
+func respondWithdrawalError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrWithdrawalNotFound):
+		respondError(c, http.StatusNotFound, "ERR_NOT_FOUND", err.Error())
+	case errors.Is(err, domain.ErrWithdrawalState):
+		respondError(c, http.StatusConflict, "ERR_WITHDRAWAL_STATE", err.Error())
+	case errors.Is(err, domain.ErrWithdrawalHeld):
+		respondError(c, http.StatusConflict, "ERR_WITHDRAWAL_HELD", err.Error())
+	case errors.Is(err, domain.ErrWalletFrozen):
+		respondError(c, http.StatusUnprocessableEntity, "ERR_WALLET_FROZEN", err.Error())
+	case errors.Is(err, domain.ErrInsufficientBalance):
+		respondError(c, http.StatusUnprocessableEntity, "ERR_INSUFFICIENT_BALANCE", err.Error())
+	default:
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+	}
+}
+
 // adminUserID extracts the admin's UUID from the gin context (set by adminJWTMiddleware).
 func adminUserID(c *gin.Context) uuid.UUID {
 	v, _ := c.Get("userID")
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// KYCHandler serves /admin/kyc: the identity verification review queue.
+type KYCHandler struct {
+	kycSvc *service.KYCService
+}
+
+// NewKYCHandler creates a KYCHandler.
+func NewKYCHandler(kycSvc *service.KYCService) *KYCHandler {
+	return &KYCHandler{kycSvc: kycSvc}
+}
+
+// List godoc
+// GET /admin/kyc?status=pending&page=1&limit=20
+// Defaults to the pending queue, oldest first; status=all lists everything.
+func (h *KYCHandler) List(c *gin.Context) {
+	status := c.DefaultQuery("status", string(domain.KYCPending))
+	if status == "all" {
+		status = ""
+	}
+	page, limit := adminPagination(c)
+	offset := (page - 1) * limit
+
+	items, total, err := h.kycSvc.List(c.Request.Context(), status, limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondList(c, items, total, page, limit)
+}
+
+// Detail godoc
+// GET /admin/kyc/:id
+// Returns the submission and its documents; each document's file is at
+// /admin/kyc/documents/:id/file.
+func (h *KYCHandler) Detail(c *gin.Context) {
+	id, ok := kycID(c, "submission")
+	if !ok {
+		return
+	}
+	sub, docs, err := h.kycSvc.Get(c.Request.Context(), id)
+	if err != nil {
+		respondKYCError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusOK, gin.H{"submission": sub, "documents": docs})
+}
+
+// Document godoc
+// GET /admin/kyc/documents/:id/file [admin, risk]
+// Streams the uploaded file.
+func (h *KYCHandler) Document(c *gin.Context) {
+	id, ok := kycID(c, "document")
+	if !ok {
+		return
+	}
+	doc, f, err := h.kycSvc.OpenDocument(c.Request.Context(), id)
+	if err != nil {
+		respondKYCError(c, err)
+		return
+	}
+	defer f.Close()
+
+	c.Header("Content-Disposition", `inline; filename="`+doc.ID.String()+`"`)
+	c.Header("Content-Length", strconv.FormatInt(doc.SizeBytes, 10))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", doc.ContentType)
+	_, _ = io.Copy(c.Writer, f)
+}
+
+// Approve godoc
+// POST /admin/kyc/:id/approve
+// Body (optional): {"full_name": "Ayşe Yılmaz", "note": "..."}
+// full_name replaces the user's legal name with the one on the documents.
+func (h *KYCHandler) Approve(c *gin.Context) {
+	h.review(c, true)
+}
+
+// Reject godoc
+// POST /admin/kyc/:id/reject
+// Body: {"note": "document expired"}
+// The note is shown to the user, who may upload new documents.
+func (h *KYCHandler) Reject(c *gin.Context) {
+	h.review(c, false)
+}
+
+func (h *KYCHandler) review(c *gin.Context, approve bool) {
+	id, ok := kycID(c, "submission")
+	if !ok {
+		return
+	}
+	var body struct {
+		Note     string `json:"note"      binding:"max=1000"`
+		FullName string `json:"full_name" binding:"max=100"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+			return
+		}
+	}
+	if !approve && body.Note == "" {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", "note is required when rejecting")
+		return
+	}
+
+	sub, err := h.kycSvc.Review(c.Request.Context(), id, adminUserID(c), approve, body.Note, body.FullName)
+	if err != nil {
+		respondKYCError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusOK, sub)
+}
+
+// ── helpers ───────────────────────────────────────────────────────────────────
+
+// kycID parses the :id param, answering 400 when it is malformed.
+func kycID(c *gin.Context, what string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid "+what+" id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondKYCError maps KYC errors to HTTP responses.
+func respondKYCError(c *gin.Context, err error) {
+	switch {
+	case domain.IsNotFound(err):
+		respondError(c, http.StatusNotFound, "ERR_NOT_FOUND", err.Error())
+	case errors.Is(err, domain.ErrKYCState):
+		respondError(c, http.StatusConflict, "ERR_KYC_STATE", "submission was already reviewed")
+	default:
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+	}
+}
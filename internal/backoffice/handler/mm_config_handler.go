@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/mm"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// MMConfigHandler serves /admin/risk/mm-config endpoints.
+type MMConfigHandler struct {
+	mmConfigSvc *service.MMConfigService
+}
+
+// NewMMConfigHandler creates an MMConfigHandler.
+func NewMMConfigHandler(mmConfigSvc *service.MMConfigService) *MMConfigHandler {
+	return &MMConfigHandler{mmConfigSvc: mmConfigSvc}
+}
+
+// List godoc
+// GET /admin/risk/mm-config
+// Returns the env defaults plus every active override.
+func (h *MMConfigHandler) List(c *gin.Context) {
+	overrides, err := h.mmConfigSvc.ListActive(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondSuccess(c, http.StatusOK, gin.H{
+		"defaults":   h.mmConfigSvc.Defaults(),
+		"overrides":  overrides,
+		"strategies": mm.Names(),
+	})
+}
+
+// Effective godoc
+// GET /admin/risk/mm-config/effective?market_id=<uuid> | ?series=btc_5m
+func (h *MMConfigHandler) Effective(c *gin.Context) {
+	marketID, series, ok := mmScopeFromQuery(c)
+	if !ok {
+		return
+	}
+
+	var (
+		params domain.MMParams
+		err    error
+	)
+	switch {
+	case marketID != nil:
+		params, err = h.mmConfigSvc.EffectiveForMarket(c.Request.Context(), *marketID)
+	case series != nil:
+		params, err = h.mmConfigSvc.EffectiveForSeries(c.Request.Context(), *series)
+	default:
+		params, err = h.mmConfigSvc.EffectiveForSeries(c.Request.Context(), domain.DefaultSeries)
+	}
+	if err != nil {
+		if domain.IsNotFound(err) {
+			respondError(c, http.StatusNotFound, "ERR_NOT_FOUND", err.Error())
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondSuccess(c, http.StatusOK, params)
+}
+
+// Set godoc
+// PUT /admin/risk/mm-config
+// Body: {"series": "btc_5m", "strategy": "time_decay", "threshold_ratio": "0.7"}
+// Omit market_id and series for the global scope.  Omitted parameters inherit.
+func (h *MMConfigHandler) Set(c *gin.Context) {
+	var body struct {
+		MarketID       *uuid.UUID       `json:"market_id"`
+		Series         *string          `json:"series"`
+		ThresholdRatio *decimal.Decimal `json:"threshold_ratio"`
+		MaxExposure    *decimal.Decimal `json:"max_exposure_try"`
+		MinBet         *decimal.Decimal `json:"min_bet_try"`
+		SeedRatio      *decimal.Decimal `json:"seed_ratio"`
+		BalanceRatio   *decimal.Decimal `json:"balance_ratio"`
+		TargetOdds     *decimal.Decimal `json:"target_odds"`
+		Strategy       *string          `json:"strategy"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
+
+	cfg, err := h.mmConfigSvc.Set(c.Request.Context(), &domain.MMConfig{
+		MarketID:       body.MarketID,
+		Series:         body.Series,
+		ThresholdRatio: body.ThresholdRatio,
+		MaxExposure:    body.MaxExposure,
+		MinBet:         body.MinBet,
+		SeedRatio:      body.SeedRatio,
+		BalanceRatio:   body.BalanceRatio,
+		TargetOdds:     body.TargetOdds,
+		Strategy:       body.Strategy,
+	}, adminUserID(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidMMConfig) {
+			respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondSuccess(c, http.StatusOK, cfg)
+}
+
+// Clear godoc
+// DELETE /admin/risk/mm-config?market_id=<uuid> | ?series=btc_5m | (none = global)
+func (h *MMConfigHandler) Clear(c *gin.Context) {
+	marketID, series, ok := mmScopeFromQuery(c)
+	if !ok {
+		return
+	}
+	if err := h.mmConfigSvc.Clear(c.Request.Context(), marketID, series, adminUserID(c)); err != nil {
+		if errors.Is(err, domain.ErrMMConfigNotFound) {
+			respondError(c, http.StatusNotFound, "ERR_NOT_FOUND", err.Error())
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondSuccess(c, http.StatusOK, gin.H{"cleared": true})
+}
+
+// History godoc
+// GET /admin/risk/mm-config/history?market_id=<uuid> | ?series=btc_5m | (none = global)
+func (h *MMConfigHandler) History(c *gin.Context) {
+	marketID, series, ok := mmScopeFromQuery(c)
+	if !ok {
+		return
+	}
+	page, limit := adminPagination(c)
+	rows, total, err := h.mmConfigSvc.History(c.Request.Context(), marketID, series, limit, (page-1)*limit)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondList(c, rows, total, page, limit)
+}
+
+// mmScopeFromQuery reads market_id / series query params.  Writes a 400 and
+// returns ok=false on malformed input.
+func mmScopeFromQuery(c *gin.Context) (marketID *uuid.UUID, series *string, ok bool) {
+	if v := c.Query("market_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid market_id")
+			return nil, nil, false
+		}
+		marketID = &id
+	}
+	if v := c.Query("series"); v != "" {
+		series = &v
+	}
+	if marketID != nil && series != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", "set either market_id or series, not both")
+		return nil, nil, false
+	}
+	return marketID, series, true
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/payout"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxResultFileBytes caps an uploaded bank result file.
+const maxResultFileBytes = 5 << 20
+
+// PayoutBatchHandler serves /admin/finance/withdrawals/batches.
+type PayoutBatchHandler struct {
+	batchSvc *service.PayoutBatchService
+}
+
+// NewPayoutBatchHandler creates a PayoutBatchHandler.
+func NewPayoutBatchHandler(batchSvc *service.PayoutBatchService) *PayoutBatchHandler {
+	return &PayoutBatchHandler{batchSvc: batchSvc}
+}
+
+// List godoc
+// GET /admin/finance/withdrawals/batches?status=exported&page=1&limit=20
+func (h *PayoutBatchHandler) List(c *gin.Context) {
+	page, limit := adminPagination(c)
+	offset := (page - 1) * limit
+	batches, total, err := h.batchSvc.List(c.Request.Context(), c.DefaultQuery("status", ""), limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondList(c, batches, total, page, limit)
+}
+
+// Create godoc
+// POST /admin/finance/withdrawals/batches
+// Body (optional): {"withdrawal_ids": ["uuid", ...]}
+// Batches the given approved withdrawals, or all approved withdrawals.
+func (h *PayoutBatchHandler) Create(c *gin.Context) {
+	var body struct {
+		WithdrawalIDs []uuid.UUID `json:"withdrawal_ids"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+			return
+		}
+	}
+	if len(body.WithdrawalIDs) == 0 {
+		body.WithdrawalIDs = nil
+	}
+
+	batch, err := h.batchSvc.Create(c.Request.Context(), adminUserID(c), body.WithdrawalIDs)
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusCreated, batch)
+}
+
+// Detail godoc
+// GET /admin/finance/withdrawals/batches/:id
+func (h *PayoutBatchHandler) Detail(c *gin.Context) {
+	id, ok := batchID(c)
+	if !ok {
+		return
+	}
+	batch, items, err := h.batchSvc.Get(c.Request.Context(), id)
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusOK, gin.H{"batch": batch, "items": items})
+}
+
+// Export godoc
+// GET /admin/finance/withdrawals/batches/:id/export?format=csv|pain001
+// Downloads the withdrawals still awaiting payment as a bank file.
+func (h *PayoutBatchHandler) Export(c *gin.Context) {
+	id, ok := batchID(c)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", payout.FormatCSV)
+	data, err := h.batchSvc.Export(c.Request.Context(), id, adminUserID(c), format)
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+
+	contentType, ext := "text/csv; charset=utf-8", "csv"
+	if format == payout.FormatPain001 {
+		contentType, ext = "application/xml", "xml"
+	}
+	c.Header("Content-Disposition", `attachment; filename="payout-`+id.String()+`.`+ext+`"`)
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// Import godoc
+// POST /admin/finance/withdrawals/batches/:id/import
+// Body: the bank result file (CSV or pain.002), raw or as multipart field "file".
+func (h *PayoutBatchHandler) Import(c *gin.Context) {
+	id, ok := batchID(c)
+	if !ok {
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxResultFileBytes)
+
+	var file io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "ERR_VALIDATION", "multipart field \"file\" is required")
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+			return
+		}
+		defer f.Close()
+		file = f
+	}
+
+	res, err := h.batchSvc.Import(c.Request.Context(), id, adminUserID(c), file)
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusOK, res)
+}
+
+// ── helper ────────────────────────────────────────────────────────────────────
+
+// batchID parses the :id param, answering 400 when it is malformed.
+func batchID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid batch id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondBatchError maps payout batch errors to HTTP responses.
+func respondBatchError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.Is(err, domain.ErrPayoutBatchNotFound):
+		respondError(c, http.StatusNotFound, "ERR_NOT_FOUND", err.Error())
+	case errors.Is(err, domain.ErrPayoutBatchEmpty):
+		respondError(c, http.StatusUnprocessableEntity, "ERR_BATCH_EMPTY", err.Error())
+	case errors.Is(err, domain.ErrPayoutHolderMissing):
+		respondError(c, http.StatusUnprocessableEntity, "ERR_HOLDER_NAME_MISSING", err.Error())
+	case errors.Is(err, payout.ErrUnknownFormat):
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", "format must be csv or pain001")
+	case errors.Is(err, payout.ErrInvalidResultFile):
+		respondError(c, http.StatusUnprocessableEntity, "ERR_INVALID_RESULT_FILE", err.Error())
+	case errors.As(err, &tooLarge):
+		respondError(c, http.StatusRequestEntityTooLarge, "ERR_FILE_TOO_LARGE", "result file too large")
+	default:
+		respondWithdrawalError(c, err)
+	}
+}
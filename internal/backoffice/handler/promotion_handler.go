@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PromotionHandler serves /admin/promotions: campaign management for the
+// marketing team and redemption reporting.
+type PromotionHandler struct {
+	promoSvc *service.PromotionService
+}
+
+// NewPromotionHandler creates a PromotionHandler.
+func NewPromotionHandler(promoSvc *service.PromotionService) *PromotionHandler {
+	return &PromotionHandler{promoSvc: promoSvc}
+}
+
+// List godoc
+// GET /admin/promotions?status=active|inactive&page=1&limit=20
+func (h *PromotionHandler) List(c *gin.Context) {
+	status := c.DefaultQuery("status", "")
+	if status != "" && status != "active" && status != "inactive" {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", "status must be active or inactive")
+		return
+	}
+	page, limit := adminPagination(c)
+	offset := (page - 1) * limit
+
+	promos, total, err := h.promoSvc.List(c.Request.Context(), status, limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondList(c, promos, total, page, limit)
+}
+
+// Create godoc
+// POST /admin/promotions
+// Body: {"name":"Welcome","code":"WELCOME50","reward_type":"free_bet",
+// "reward_amount":"50","free_bet_days":7,"starts_at":"...","ends_at":"...",
+// "max_redemptions":1000,"per_user_limit":1,"new_users_days":30,
+// "requires_deposit":true}
+func (h *PromotionHandler) Create(c *gin.Context) {
+	var body struct {
+		Name            string     `json:"name"           binding:"required,max=100"`
+		Code            string     `json:"code"           binding:"required"`
+		RewardType      string     `json:"reward_type"    binding:"required"`
+		RewardAmount    string     `json:"reward_amount"  binding:"required"`
+		FreeBetDays     int        `json:"free_bet_days"` // default 7 for free bets
+		StartsAt        time.Time  `json:"starts_at"`     // default now
+		EndsAt          *time.Time `json:"ends_at"`
+		MaxRedemptions  int        `json:"max_redemptions"`
+		PerUserLimit    *int       `json:"per_user_limit"` // default 1
+		NewUsersDays    int        `json:"new_users_days"`
+		RequiresDeposit bool       `json:"requires_deposit"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
+	amount, err := decimal.NewFromString(body.RewardAmount)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_AMOUNT", "reward_amount must be a decimal string")
+		return
+	}
+
+	p := &domain.Promotion{
+		Name:            body.Name,
+		Code:            body.Code,
+		RewardType:      domain.RewardType(body.RewardType),
+		RewardAmount:    amount,
+		FreeBetDays:     body.FreeBetDays,
+		StartsAt:        body.StartsAt,
+		EndsAt:          body.EndsAt,
+		MaxRedemptions:  body.MaxRedemptions,
+		PerUserLimit:    1,
+		NewUsersDays:    body.NewUsersDays,
+		RequiresDeposit: body.RequiresDeposit,
+		IsActive:        true,
+	}
+	if body.PerUserLimit != nil {
+		p.PerUserLimit = *body.PerUserLimit
+	}
+	if p.RewardType == domain.RewardFreeBet && p.FreeBetDays == 0 {
+		p.FreeBetDays = 7
+	}
+
+	p, err = h.promoSvc.Create(c.Request.Context(), adminUserID(c), p)
+	if err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusCreated, p)
+}
+
+// Detail godoc
+// GET /admin/promotions/:id
+// Returns the campaign with its redemption report.
+func (h *PromotionHandler) Detail(c *gin.Context) {
+	id, ok := promotionID(c)
+	if !ok {
+		return
+	}
+	p, err := h.promoSvc.Get(c.Request.Context(), id)
+	if err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+	report, err := h.promoSvc.Report(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondSuccess(c, http.StatusOK, gin.H{"promotion": p, "report": report})
+}
+
+// Update godoc
+// PATCH /admin/promotions/:id
+// Body: any of name, starts_at, ends_at, max_redemptions, per_user_limit,
+// new_users_days, requires_deposit, is_active.  The code and the reward
+// cannot be changed.
+func (h *PromotionHandler) Update(c *gin.Context) {
+	id, ok := promotionID(c)
+	if !ok {
+		return
+	}
+	var body service.PromotionUpdate
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
+
+	p, err := h.promoSvc.Update(c.Request.Context(), id, adminUserID(c), body)
+	if err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+	respondSuccess(c, http.StatusOK, p)
+}
+
+// Redemptions godoc
+// GET /admin/promotions/:id/redemptions?page=1&limit=20
+func (h *PromotionHandler) Redemptions(c *gin.Context) {
+	id, ok := promotionID(c)
+	if !ok {
+		return
+	}
+	page, limit := adminPagination(c)
+	offset := (page - 1) * limit
+
+	items, total, err := h.promoSvc.Redemptions(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondList(c, items, total, page, limit)
+}
+
+func promotionID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid promotion id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func respondPromotionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrPromotionNotFound):
+		respondError(c, http.StatusNotFound, "ERR_NOT_FOUND", err.Error())
+	case errors.Is(err, domain.ErrInvalidPromotion):
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_PROMOTION", err.Error())
+	case errors.Is(err, domain.ErrPromoCodeTaken):
+		respondError(c, http.StatusConflict, "ERR_PROMO_CODE_TAKEN", err.Error())
+	default:
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+	}
+}
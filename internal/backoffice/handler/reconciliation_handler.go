@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReconciliationHandler serves /admin/finance/reconciliation and wallet
+// freeze endpoints.
+type ReconciliationHandler struct {
+	reconSvc *service.ReconciliationService
+}
+
+// NewReconciliationHandler creates a ReconciliationHandler.
+func NewReconciliationHandler(reconSvc *service.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{reconSvc: reconSvc}
+}
+
+// Runs godoc
+// GET /admin/finance/reconciliation?page=1&limit=20
+func (h *ReconciliationHandler) Runs(c *gin.Context) {
+	page, limit := adminPagination(c)
+	offset := (page - 1) * limit
+
+	runs, total, err := h.reconSvc.ListRuns(c.Request.Context(), limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondList(c, runs, total, page, limit)
+}
+
+// Run godoc
+// GET /admin/finance/reconciliation/:id
+func (h *ReconciliationHandler) Run(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid run id")
+		return
+	}
+	run, err := h.reconSvc.GetRun(c.Request.Context(), id)
+	if err != nil {
+		if domain.IsNotFound(err) {
+			respondError(c, http.StatusNotFound, "ERR_NOT_FOUND", err.Error())
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondSuccess(c, http.StatusOK, run)
+}
+
+// Reconcile godoc
+// POST /admin/finance/reconciliation
+// Body (optional): {"freeze": true}
+// Runs a pass now and returns it; with freeze, drifting wallets are frozen.
+func (h *ReconciliationHandler) Reconcile(c *gin.Context) {
+	var body struct {
+		Freeze bool `json:"freeze"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+			return
+		}
+	}
+
+	run, err := h.reconSvc.Run(c.Request.Context(), domain.ReconManual, adminUserID(c), body.Freeze)
+	if err != nil {
+		if errors.Is(err, domain.ErrReconciliationRunning) {
+			respondError(c, http.StatusConflict, "ERR_RECON_RUNNING", err.Error())
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondSuccess(c, http.StatusOK, run)
+}
+
+// FreezeWallet godoc
+// POST /admin/finance/wallets/:id/freeze
+// Body: {"reason": "..."}
+func (h *ReconciliationHandler) FreezeWallet(c *gin.Context) {
+	h.setFrozen(c, true)
+}
+
+// UnfreezeWallet godoc
+// POST /admin/finance/wallets/:id/unfreeze
+// Body: {"reason": "..."}
+func (h *ReconciliationHandler) UnfreezeWallet(c *gin.Context) {
+	h.setFrozen(c, false)
+}
+
+func (h *ReconciliationHandler) setFrozen(c *gin.Context, frozen bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid wallet id")
+		return
+	}
+	var body struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err = c.ShouldBindJSON(&body); err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
+
+	wallet, err := h.reconSvc.SetWalletFrozen(c.Request.Context(), id, frozen, body.Reason, adminUserID(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrWalletNotFound) {
+			respondError(c, http.StatusNotFound, "ERR_WALLET_NOT_FOUND", err.Error())
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondSuccess(c, http.StatusOK, wallet)
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReferralHandler serves /admin/referrals: the referral report and the
+// review queue for referrals flagged as possible fraud.
+type ReferralHandler struct {
+	referralSvc *service.ReferralService
+}
+
+// NewReferralHandler creates a ReferralHandler.
+func NewReferralHandler(referralSvc *service.ReferralService) *ReferralHandler {
+	return &ReferralHandler{referralSvc: referralSvc}
+}
+
+// List godoc
+// GET /admin/referrals?status=flagged&referrer_id=uuid&page=1&limit=20
+func (h *ReferralHandler) List(c *gin.Context) {
+	status := c.DefaultQuery("status", "")
+	var referrerID *uuid.UUID
+	if s := c.Query("referrer_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid referrer_id")
+			return
+		}
+		referrerID = &id
+	}
+	page, limit := adminPagination(c)
+	offset := (page - 1) * limit
+
+	items, total, err := h.referralSvc.List(c.Request.Context(), status, referrerID, limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondList(c, items, total, page, limit)
+}
+
+// Report godoc
+// GET /admin/referrals/report?page=1&limit=20
+// Per referrer: referrals by status and commission paid, highest first.
+func (h *ReferralHandler) Report(c *gin.Context) {
+	page, limit := adminPagination(c)
+	offset := (page - 1) * limit
+
+	rows, total, err := h.referralSvc.Report(c.Request.Context(), limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondList(c, rows, total, page, limit)
+}
+
+// Approve godoc
+// POST /admin/referrals/:id/approve
+// Body (optional): {"note": "siblings sharing a home connection"}
+func (h *ReferralHandler) Approve(c *gin.Context) {
+	h.review(c, true)
+}
+
+// Block godoc
+// POST /admin/referrals/:id/block
+// Body: {"note": "same person, second account"}
+func (h *ReferralHandler) Block(c *gin.Context) {
+	h.review(c, false)
+}
+
+func (h *ReferralHandler) review(c *gin.Context, approve bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid referral id")
+		return
+	}
+	var body struct {
+		Note string `json:"note"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+			return
+		}
+	}
+	if !approve && body.Note == "" {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", "note is required when blocking")
+		return
+	}
+
+	ref, err := h.referralSvc.Review(c.Request.Context(), id, adminUserID(c), approve, body.Note)
+	if err != nil {
+		if domain.IsNotFound(err) {
+			respondError(c, http.StatusNotFound, "ERR_NOT_FOUND", err.Error())
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondSuccess(c, http.StatusOK, ref)
+}
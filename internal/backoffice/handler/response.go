@@ -35,6 +35,21 @@ func respondList(c *gin.Context, items interface{}, total, page, limit int) {
 	})
 }
 
+// respondCursorList is respondList with meta.next_cursor, the token for the
+// page after this one ("" on the last page).
+func respondCursorList(c *gin.Context, items interface{}, total, page, limit int, next string) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    items,
+		"meta": gin.H{
+			"total":       total,
+			"page":        page,
+			"limit":       limit,
+			"next_cursor": next,
+		},
+	})
+}
+
 // adminPagination reads page/limit query params with sane defaults for admin views.
 func adminPagination(c *gin.Context) (page, limit int) {
 	page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
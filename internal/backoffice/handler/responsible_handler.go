@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ResponsibleHandler gives support and risk read access to users'
+// responsible-gambling limits and exclusions.  Only the users themselves
+// can change them.
+type ResponsibleHandler struct {
+	rgSvc *service.ResponsibleService
+}
+
+// NewResponsibleHandler creates a ResponsibleHandler.
+func NewResponsibleHandler(rgSvc *service.ResponsibleService) *ResponsibleHandler {
+	return &ResponsibleHandler{rgSvc: rgSvc}
+}
+
+// UserDetail godoc
+// GET /admin/users/:id/responsible-gambling?page=1&limit=20
+// Returns the user's limits with current usage, the exclusion in force
+// and their exclusion history (paginated).
+func (h *ResponsibleHandler) UserDetail(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid user id")
+		return
+	}
+	page, limit := adminPagination(c)
+	offset := (page - 1) * limit
+
+	ctx := c.Request.Context()
+	ov, err := h.rgSvc.Overview(ctx, id)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	history, err := h.rgSvc.Exclusions(ctx, id, limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondSuccess(c, http.StatusOK, gin.H{
+		"limits":     ov.Limits,
+		"exclusion":  ov.Exclusion,
+		"exclusions": history,
+	})
+}
+
+// Exclusions godoc
+// GET /admin/responsible-gambling/exclusions?kind=self_exclusion&page=1&limit=20
+// Exclusions currently in force, ending soonest first.
+func (h *ResponsibleHandler) Exclusions(c *gin.Context) {
+	kind := c.DefaultQuery("kind", "")
+	page, limit := adminPagination(c)
+	offset := (page - 1) * limit
+
+	rows, total, err := h.rgSvc.ActiveExclusions(c.Request.Context(), kind, limit, offset)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondList(c, rows, total, page, limit)
+}
@@ -1,34 +1,42 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
 	"github.com/evetabi/prediction/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
 
-// mmOverrideEnabled is an in-process MM kill-switch toggle.
-// In production this should live in Redis / the DB so it survives restarts.
-var mmOverrideEnabled = true
-
 // RiskHandler serves /admin/risk endpoints.
 type RiskHandler struct {
-	mmSvc     *service.MMService
-	priceSvc  *service.PriceService
-	marketSvc *service.MarketService
-	cfg       *config.Config
+	mmSvc          *service.MMService
+	priceSvc       *service.PriceService
+	priceSourceSvc *service.PriceSourceService
+	marketSvc      *service.MarketService
+	cfg            *config.Config
 }
 
 // NewRiskHandler creates a RiskHandler.
 func NewRiskHandler(
 	mmSvc *service.MMService,
 	priceSvc *service.PriceService,
+	priceSourceSvc *service.PriceSourceService,
 	marketSvc *service.MarketService,
 	cfg *config.Config,
 ) *RiskHandler {
-	return &RiskHandler{mmSvc: mmSvc, priceSvc: priceSvc, marketSvc: marketSvc, cfg: cfg}
+	return &RiskHandler{
+		mmSvc:          mmSvc,
+		priceSvc:       priceSvc,
+		priceSourceSvc: priceSourceSvc,
+		marketSvc:      marketSvc,
+		cfg:            cfg,
+	}
 }
 
 // Live godoc
@@ -38,7 +46,7 @@ func (h *RiskHandler) Live(c *gin.Context) {
 
 	market, err := h.marketSvc.GetActiveMarket(ctx)
 	if err != nil {
-		respondSuccess(c, http.StatusOK, gin.H{"market": nil, "mm_enabled": mmOverrideEnabled})
+		respondSuccess(c, http.StatusOK, gin.H{"market": nil, "mm_enabled": h.mmSvc.GlobalMMEnabled()})
 		return
 	}
 
@@ -54,7 +62,7 @@ func (h *RiskHandler) Live(c *gin.Context) {
 		"up_pct":         upPct,
 		"down_pct":       downPct,
 		"risk_indicator": riskIndicator(upPct, downPct),
-		"mm_enabled":     mmOverrideEnabled,
+		"mm_enabled":     h.mmSvc.MMEnabled(market.ID),
 		"mm_stats":       mmStats,
 	})
 }
@@ -70,19 +78,57 @@ func (h *RiskHandler) MMStats(c *gin.Context) {
 	respondSuccess(c, http.StatusOK, stats)
 }
 
+// MMQueue godoc
+// GET /admin/risk/mm-queue
+// Rebalance queue depth and decision counters reported by each API server
+// process within the last few minutes.
+func (h *RiskHandler) MMQueue(c *gin.Context) {
+	reports, err := h.mmSvc.RebalanceReports(c.Request.Context(), 5*time.Minute)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondSuccess(c, http.StatusOK, reports)
+}
+
+// MMSwitches godoc
+// GET /admin/risk/mm-override
+func (h *RiskHandler) MMSwitches(c *gin.Context) {
+	switches, err := h.mmSvc.Switches(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondSuccess(c, http.StatusOK, gin.H{
+		"global_enabled": h.mmSvc.GlobalMMEnabled(),
+		"switches":       switches,
+	})
+}
+
 // MMOverride godoc
 // POST /admin/risk/mm-override
-// Body: {"enabled": false}
+// Body: {"enabled": false, "market_id": "<uuid, optional>", "reason": "..."}
+// Omitting market_id toggles the global switch.
 func (h *RiskHandler) MMOverride(c *gin.Context) {
 	var body struct {
-		Enabled bool `json:"enabled"`
+		Enabled  *bool      `json:"enabled"   binding:"required"`
+		MarketID *uuid.UUID `json:"market_id"`
+		Reason   string     `json:"reason"`
 	}
 	if err := c.ShouldBindJSON(&body); err != nil {
 		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
 		return
 	}
-	mmOverrideEnabled = body.Enabled
-	respondSuccess(c, http.StatusOK, gin.H{"mm_enabled": mmOverrideEnabled})
+
+	sw, err := h.mmSvc.SetEnabled(c.Request.Context(), body.MarketID, *body.Enabled, body.Reason, adminUserID(c))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondSuccess(c, http.StatusOK, gin.H{
+		"switch":         sw,
+		"global_enabled": h.mmSvc.GlobalMMEnabled(),
+	})
 }
 
 // Alerts godoc
@@ -100,13 +146,16 @@ func (h *RiskHandler) Alerts(c *gin.Context) {
 	}
 	var alerts []Alert
 
-	maxDailyLoss := decimal.NewFromFloat(h.cfg.MM.MaxDailyLoss)
+	throttleStart := decimal.NewFromFloat(h.cfg.MM.ThrottleStart)
 	minReserve := decimal.NewFromFloat(h.cfg.MM.MinReserve)
 	pct90 := decimal.NewFromFloat(0.90)
 	pct110 := decimal.NewFromFloat(1.10)
 
-	if stats.DailySpend.GreaterThanOrEqual(maxDailyLoss.Mul(pct90)) {
-		alerts = append(alerts, Alert{"RED", "MM daily loss limit at 90%+"})
+	switch {
+	case stats.BudgetUsage.GreaterThanOrEqual(pct90):
+		alerts = append(alerts, Alert{"RED", "MM loss budget at 90%+"})
+	case stats.BudgetUsage.GreaterThan(throttleStart):
+		alerts = append(alerts, Alert{"YELLOW", "MM loss budget throttling injections"})
 	}
 	if stats.PlatformReserve.LessThan(minReserve.Mul(pct110)) {
 		alerts = append(alerts, Alert{"YELLOW", "Platform reserve approaching minimum threshold"})
@@ -131,3 +180,42 @@ func (h *RiskHandler) ExchangeStatus(c *gin.Context) {
 		"error":          errMsg,
 	})
 }
+
+// PriceSources godoc
+// GET /admin/risk/price-sources
+func (h *RiskHandler) PriceSources(c *gin.Context) {
+	sources, err := h.priceSourceSvc.List(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
+	}
+	respondSuccess(c, http.StatusOK, gin.H{
+		"sources": sources,
+		"status":  h.priceSvc.ExchangeStatus(),
+	})
+}
+
+// UpdatePriceSource godoc
+// PATCH /admin/risk/price-sources/:name
+// Body: {"enabled": false} | {"weight": 0} | {"symbol": "BTCUSDT"}
+func (h *RiskHandler) UpdatePriceSource(c *gin.Context) {
+	var body service.PriceSourceUpdate
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return
+	}
+
+	src, err := h.priceSourceSvc.Update(c.Request.Context(), c.Param("name"), body, adminUserID(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPriceSourceNotFound):
+			respondError(c, http.StatusNotFound, "ERR_NOT_FOUND", err.Error())
+		case errors.Is(err, domain.ErrInvalidPriceSource), errors.Is(err, domain.ErrNoActivePriceSource):
+			respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		default:
+			respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		}
+		return
+	}
+	respondSuccess(c, http.StatusOK, src)
+}
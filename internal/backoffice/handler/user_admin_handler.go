@@ -1,30 +1,36 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/evetabi/prediction/internal/config"
 	"github.com/evetabi/prediction/internal/domain"
 	"github.com/evetabi/prediction/internal/repository"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/evetabi/prediction/internal/statement"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/shopspring/decimal"
 )
 
 // UserAdminHandler serves /admin/users endpoints.
 type UserAdminHandler struct {
-	userRepo   *repository.UserRepository
-	walletRepo *repository.WalletRepository
-	cfg        *config.Config
+	userRepo     *repository.UserRepository
+	walletRepo   *repository.WalletRepository
+	userAdminSvc *service.UserAdminService
+	statementSvc *service.StatementService
+	cfg          *config.Config
 }
 
 // NewUserAdminHandler creates a UserAdminHandler.
 func NewUserAdminHandler(
 	userRepo *repository.UserRepository,
 	walletRepo *repository.WalletRepository,
+	userAdminSvc *service.UserAdminService,
+	statementSvc *service.StatementService,
 	cfg *config.Config,
 ) *UserAdminHandler {
-	return &UserAdminHandler{userRepo: userRepo, walletRepo: walletRepo, cfg: cfg}
+	return &UserAdminHandler{userRepo: userRepo, walletRepo: walletRepo, userAdminSvc: userAdminSvc, statementSvc: statementSvc, cfg: cfg}
 }
 
 // List godoc
@@ -62,7 +68,7 @@ func (h *UserAdminHandler) Detail(c *gin.Context) {
 	}
 
 	wallet, _ := h.walletRepo.GetByUserID(ctx, id)
-	txns, _ := h.walletRepo.GetTransactions(ctx, id, 50, 0)
+	txns, _ := h.walletRepo.ListTransactions(ctx, id, domain.TxFilter{}, nil, 50, 0)
 
 	respondSuccess(c, http.StatusOK, gin.H{
 		"user":         user,
@@ -71,104 +77,184 @@ func (h *UserAdminHandler) Detail(c *gin.Context) {
 	})
 }
 
-// Suspend godoc
-// POST /admin/users/:id/suspend
-func (h *UserAdminHandler) Suspend(c *gin.Context) {
-	id, err := uuid.Parse(c.Param("id"))
+// Transactions godoc
+// GET /admin/users/:id/transactions?type=&from=2024-01-01&to=2024-01-31&ref_id=&cursor=&page=1&limit=50
+// The user's transaction history with the filters of the user-facing
+// endpoint; dates are business days, to inclusive.
+func (h *UserAdminHandler) Transactions(c *gin.Context) {
+	id, f, ok := h.historyQuery(c)
+	if !ok {
+		return
+	}
+	page, limit := adminPagination(c)
+	offset := (page - 1) * limit
+
+	res, err := h.statementSvc.History(c.Request.Context(), id, f, c.Query("cursor"), limit, offset)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid user id")
+		respondStatementError(c, err)
 		return
 	}
-	if err = h.userRepo.SetActive(c.Request.Context(), id, false); err != nil {
-		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+	respondCursorList(c, res.Transactions, res.Total, page, limit, res.NextCursor)
+}
+
+// ExportTransactions godoc
+// GET /admin/users/:id/transactions/export?format=csv|json&from=&to=&type=&ref_id=
+func (h *UserAdminHandler) ExportTransactions(c *gin.Context) {
+	id, f, ok := h.historyQuery(c)
+	if !ok {
 		return
 	}
-	respondSuccess(c, http.StatusOK, gin.H{"user_id": id, "is_active": false})
+	format := c.DefaultQuery("format", statement.FormatCSV)
+	data, err := h.statementSvc.Export(c.Request.Context(), id, f, format)
+	if err != nil {
+		respondStatementError(c, err)
+		return
+	}
+	sendStatement(c, "transactions-"+id.String(), format, data)
 }
 
-// Activate godoc
-// POST /admin/users/:id/activate
-func (h *UserAdminHandler) Activate(c *gin.Context) {
+// Statements godoc
+// GET /admin/users/:id/statements?page=1&limit=50
+// Lists the user's monthly account statements, newest first.
+func (h *UserAdminHandler) Statements(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid user id")
 		return
 	}
-	if err = h.userRepo.SetActive(c.Request.Context(), id, true); err != nil {
-		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+	page, limit := adminPagination(c)
+	offset := (page - 1) * limit
+
+	items, total, err := h.statementSvc.ListMonthly(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		respondStatementError(c, err)
 		return
 	}
-	respondSuccess(c, http.StatusOK, gin.H{"user_id": id, "is_active": true})
+	respondList(c, items, total, page, limit)
 }
 
-// AdjustBalance godoc
-// POST /admin/users/:id/balance
-// Body: {"amount": "500", "note": "bonus"}
-func (h *UserAdminHandler) AdjustBalance(c *gin.Context) {
+// DownloadStatement godoc
+// GET /admin/users/:id/statements/:month?format=pdf|csv
+func (h *UserAdminHandler) DownloadStatement(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid user id")
 		return
 	}
+	month := c.Param("month")
+	format := c.DefaultQuery("format", statement.FormatPDF)
+	data, err := h.statementSvc.DownloadMonthly(c.Request.Context(), id, month, format)
+	if err != nil {
+		respondStatementError(c, err)
+		return
+	}
+	sendStatement(c, "statement-"+id.String()+"-"+month, format, data)
+}
+
+// RegenerateStatements godoc
+// POST /admin/finance/statements/regenerate
+// Body: {"month": "2024-01", "user_ids": ["uuid", ...]}
+// Rebuilds a completed month's statements in the background, for user_ids
+// or, when it is omitted, for every user due one.  Answers 202 with the
+// number queued.
+func (h *UserAdminHandler) RegenerateStatements(c *gin.Context) {
 	var body struct {
-		Amount string `json:"amount" binding:"required"`
-		Note   string `json:"note"`
+		Month   string      `json:"month"    binding:"required"`
+		UserIDs []uuid.UUID `json:"user_ids"`
 	}
-	if err = c.ShouldBindJSON(&body); err != nil {
+	if err := c.ShouldBindJSON(&body); err != nil {
 		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
 		return
 	}
-	amount, err := decimal.NewFromString(body.Amount)
+	n, err := h.statementSvc.Regenerate(c.Request.Context(), adminUserID(c), body.Month, body.UserIDs)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "ERR_INVALID_AMOUNT", "amount must be a decimal string")
+		respondStatementError(c, err)
 		return
 	}
+	respondSuccess(c, http.StatusAccepted, gin.H{"month": body.Month, "queued": n})
+}
 
-	ctx := c.Request.Context()
-	wallet, err := h.walletRepo.GetByUserID(ctx, id)
+// sendStatement writes a statement or export file as a download.
+func sendStatement(c *gin.Context, name, format string, data []byte) {
+	contentType := "text/csv; charset=utf-8"
+	switch format {
+	case statement.FormatJSON:
+		contentType = "application/json"
+	case statement.FormatPDF:
+		contentType = "application/pdf"
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+name+`.`+format+`"`)
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// historyQuery parses the :id param and the history filter, answering 400
+// when either is malformed.
+func (h *UserAdminHandler) historyQuery(c *gin.Context) (uuid.UUID, domain.TxFilter, bool) {
+	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		respondError(c, http.StatusNotFound, "ERR_WALLET_NOT_FOUND", err.Error())
-		return
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid user id")
+		return uuid.Nil, domain.TxFilter{}, false
+	}
+	f, err := statement.ParseFilter(c.Request.URL.Query(), h.cfg.Location())
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+		return uuid.Nil, domain.TxFilter{}, false
 	}
+	return id, f, true
+}
 
-	// Use a direct DB exec (no separate tx needed — admin adjustment is single op)
-	// We reuse AddBalance (positive) or DeductBalance logic via a signed amount.
-	// For simplicity: exec raw update and write audit log.
-	txType := domain.TxBonus
-	if amount.IsNegative() {
-		txType = domain.TxWithdraw
+// respondStatementError maps transaction history errors to HTTP responses.
+func respondStatementError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidCursor):
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_CURSOR", err.Error())
+	case errors.Is(err, statement.ErrUnknownFormat):
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", "unsupported format")
+	case errors.Is(err, domain.ErrInvalidStatementMonth):
+		respondError(c, http.StatusBadRequest, "ERR_VALIDATION", err.Error())
+	case errors.Is(err, domain.ErrStatementNotFound):
+		respondError(c, http.StatusNotFound, "ERR_NOT_FOUND", err.Error())
+	case errors.Is(err, domain.ErrExportTooLarge):
+		respondError(c, http.StatusUnprocessableEntity, "ERR_EXPORT_TOO_LARGE", err.Error()+"; narrow the period")
+	default:
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
 	}
+}
 
-	if err = h.walletRepo.AdminAdjustBalance(ctx, id, amount); err != nil {
+// Suspend godoc
+// POST /admin/users/:id/suspend
+func (h *UserAdminHandler) Suspend(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid user id")
+		return
+	}
+	if err = h.userRepo.SetActive(c.Request.Context(), id, false); err != nil {
 		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
 		return
 	}
+	respondSuccess(c, http.StatusOK, gin.H{"user_id": id, "is_active": false})
+}
 
-	note := body.Note
-	if note == "" {
-		note = "Admin balance adjustment"
+// Activate godoc
+// POST /admin/users/:id/activate
+func (h *UserAdminHandler) Activate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "invalid user id")
+		return
 	}
-	txn := &domain.Transaction{
-		ID:            uuid.New(),
-		WalletID:      wallet.ID,
-		Type:          txType,
-		Amount:        amount.Abs(),
-		BalanceBefore: wallet.Balance,
-		BalanceAfter:  wallet.Balance.Add(amount),
-		Description:   note,
+	if err = h.userRepo.SetActive(c.Request.Context(), id, true); err != nil {
+		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		return
 	}
-	_ = h.walletRepo.LogTransactionDirect(ctx, txn)
-
-	respondSuccess(c, http.StatusOK, gin.H{
-		"user_id":     id,
-		"amount":      amount,
-		"new_balance": wallet.Balance.Add(amount),
-	})
+	respondSuccess(c, http.StatusOK, gin.H{"user_id": id, "is_active": true})
 }
 
 // SetRole godoc
-// POST /admin/users/:id/role
+// POST /admin/users/:id/role [admin]
 // Body: {"role": "finance"}
+// Admins cannot change their own role.
 func (h *UserAdminHandler) SetRole(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -183,20 +269,19 @@ func (h *UserAdminHandler) SetRole(c *gin.Context) {
 		return
 	}
 	role := domain.UserRole(body.Role)
-	validRoles := map[domain.UserRole]bool{
-		domain.RoleUser:     true,
-		domain.RoleAdmin:    true,
-		domain.RoleRisk:     true,
-		domain.RoleFinance:  true,
-		domain.RoleOps:      true,
-		domain.RoleReadOnly: true,
-	}
-	if !validRoles[role] {
+	if !role.IsValid() {
 		respondError(c, http.StatusBadRequest, "ERR_INVALID_ROLE", "unknown role")
 		return
 	}
-	if err = h.userRepo.UpdateRole(c.Request.Context(), id, role); err != nil {
-		respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+	if err = h.userAdminSvc.SetRole(c.Request.Context(), adminUserID(c), id, role); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrSelfRoleChange):
+			respondError(c, http.StatusForbidden, "ERR_SELF_ROLE_CHANGE", err.Error())
+		case errors.Is(err, domain.ErrUserNotFound):
+			respondError(c, http.StatusNotFound, "ERR_NOT_FOUND", err.Error())
+		default:
+			respondError(c, http.StatusInternalServerError, "ERR_INTERNAL", err.Error())
+		}
 		return
 	}
 	respondSuccess(c, http.StatusOK, gin.H{"user_id": id, "role": role})
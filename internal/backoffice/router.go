@@ -6,6 +6,7 @@ import (
 
 	"github.com/evetabi/prediction/internal/backoffice/handler"
 	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
 	"github.com/evetabi/prediction/internal/repository"
 	"github.com/evetabi/prediction/internal/service"
 	"github.com/evetabi/prediction/internal/ws"
@@ -14,16 +15,32 @@ import (
 
 // BackofficeDeps bundles every dependency needed for the admin router.
 type BackofficeDeps struct {
-	AuthSvc    *service.AuthService
-	MarketSvc  *service.MarketService
-	MMSvc      *service.MMService
-	UserRepo   *repository.UserRepository
-	MarketRepo *repository.MarketRepository
-	BetRepo    *repository.BetRepository
-	WalletRepo *repository.WalletRepository
-	Hub        *ws.Hub
-	PriceSvc   *service.PriceService
-	Cfg        *config.Config
+	AuthSvc        *service.AuthService
+	MarketSvc      *service.MarketService
+	MMSvc          *service.MMService
+	MMConfigSvc    *service.MMConfigService
+	ReconSvc       *service.ReconciliationService
+	DepositSvc     *service.DepositService
+	WithdrawalSvc  *service.WithdrawalService
+	PayoutBatchSvc *service.PayoutBatchService
+	BankAccountSvc *service.BankAccountService
+	PromotionSvc   *service.PromotionService
+	ReferralSvc    *service.ReferralService
+	ResponsibleSvc *service.ResponsibleService
+	KYCSvc         *service.KYCService
+	AMLSvc         *service.AMLService
+	StatementSvc   *service.StatementService
+	UserAdminSvc   *service.UserAdminService
+	AdjustmentSvc  *service.AdjustmentService
+	UserRepo       *repository.UserRepository
+	MarketRepo     *repository.MarketRepository
+	BetRepo        *repository.BetRepository
+	WalletRepo     *repository.WalletRepository
+	AuditRepo      *repository.AuditRepository
+	Hub            *ws.Hub
+	PriceSvc       *service.PriceService
+	PriceSourceSvc *service.PriceSourceService
+	Cfg            *config.Config
 }
 
 // SetupBackofficeRouter creates the admin Gin engine on port 8081.
@@ -39,16 +56,50 @@ func SetupBackofficeRouter(deps BackofficeDeps) *gin.Engine {
 
 	dashH := handler.NewDashboardHandler(deps.MarketSvc, deps.MMSvc, deps.WalletRepo, deps.BetRepo, deps.Hub, deps.Cfg)
 	marketH := handler.NewMarketAdminHandler(deps.MarketSvc, deps.BetRepo, deps.Cfg)
-	userH := handler.NewUserAdminHandler(deps.UserRepo, deps.WalletRepo, deps.Cfg)
-	riskH := handler.NewRiskHandler(deps.MMSvc, deps.PriceSvc, deps.MarketSvc, deps.Cfg)
-	financeH := handler.NewFinanceHandler(deps.WalletRepo, deps.MarketRepo, deps.Cfg)
+	userH := handler.NewUserAdminHandler(deps.UserRepo, deps.WalletRepo, deps.UserAdminSvc, deps.StatementSvc, deps.Cfg)
+	riskH := handler.NewRiskHandler(deps.MMSvc, deps.PriceSvc, deps.PriceSourceSvc, deps.MarketSvc, deps.Cfg)
+	financeH := handler.NewFinanceHandler(deps.WalletRepo, deps.MarketRepo, deps.WithdrawalSvc, deps.Cfg)
+	auditH := handler.NewAuditHandler(deps.AuditRepo)
+	mmConfigH := handler.NewMMConfigHandler(deps.MMConfigSvc)
+	reconH := handler.NewReconciliationHandler(deps.ReconSvc)
+	depositH := handler.NewDepositHandler(deps.DepositSvc)
+	batchH := handler.NewPayoutBatchHandler(deps.PayoutBatchSvc)
+	bankH := handler.NewBankAccountHandler(deps.BankAccountSvc)
+	promoH := handler.NewPromotionHandler(deps.PromotionSvc)
+	referralH := handler.NewReferralHandler(deps.ReferralSvc)
+	rgH := handler.NewResponsibleHandler(deps.ResponsibleSvc)
+	kycH := handler.NewKYCHandler(deps.KYCSvc)
+	amlH := handler.NewAMLHandler(deps.AMLSvc)
+	adjustmentH := handler.NewAdjustmentHandler(deps.AdjustmentSvc)
 
 	jwtMW := adminJWTMiddleware(deps.AuthSvc)
+	riskWrite := requireRoles(domain.RoleAdmin, domain.RoleRisk)
+	financeWrite := requireRoles(domain.RoleAdmin, domain.RoleFinance)
+	promoWrite := requireRoles(domain.RoleAdmin, domain.RoleMarketing)
+	adminOnly := requireRoles(domain.RoleAdmin)
+	staff := requireRoles(domain.RoleAdmin, domain.RoleRisk, domain.RoleFinance, domain.RoleOps, domain.RoleReadOnly)
+	// Balance adjustments are raised by staff handling the account; finance
+	// or admin approves them (see AdjustmentService).
+	adjustmentRequest := requireRoles(domain.RoleAdmin, domain.RoleFinance, domain.RoleRisk, domain.RoleOps)
 
-	admin := r.Group("/admin")
-	admin.Use(jwtMW)
+	root := r.Group("/admin")
+	root.Use(jwtMW)
+
+	// Promotions: the only area open to marketing
+	promo := root.Group("/promotions")
+	{
+		promo.GET("", promoH.List)
+		promo.POST("", promoWrite, promoH.Create)
+		promo.GET("/:id", promoH.Detail)
+		promo.PATCH("/:id", promoWrite, promoH.Update)
+		promo.GET("/:id/redemptions", promoH.Redemptions)
+	}
+
+	// Everything else is for the operational roles
+	admin := root.Group("", staff)
 	{
 		admin.GET("/dashboard", dashH.Dashboard)
+		admin.GET("/audit", auditH.List)
 
 		// Markets
 		m := admin.Group("/markets")
@@ -66,10 +117,15 @@ func SetupBackofficeRouter(deps BackofficeDeps) *gin.Engine {
 		{
 			u.GET("", userH.List)
 			u.GET("/:id", userH.Detail)
+			u.GET("/:id/transactions", userH.Transactions)
+			u.GET("/:id/transactions/export", userH.ExportTransactions)
+			u.GET("/:id/statements", userH.Statements)
+			u.GET("/:id/statements/:month", userH.DownloadStatement)
 			u.POST("/:id/suspend", userH.Suspend)
 			u.POST("/:id/activate", userH.Activate)
-			u.POST("/:id/balance", userH.AdjustBalance)
-			u.POST("/:id/role", userH.SetRole)
+			u.POST("/:id/balance", adjustmentRequest, adjustmentH.Request)
+			u.POST("/:id/role", adminOnly, userH.SetRole)
+			u.GET("/:id/responsible-gambling", rgH.UserDetail)
 		}
 
 		// Risk
@@ -77,19 +133,87 @@ func SetupBackofficeRouter(deps BackofficeDeps) *gin.Engine {
 		{
 			risk.GET("/live", riskH.Live)
 			risk.GET("/mm-stats", riskH.MMStats)
-			risk.POST("/mm-override", riskH.MMOverride)
+			risk.GET("/mm-queue", riskH.MMQueue)
+			risk.GET("/mm-override", riskH.MMSwitches)
+			risk.POST("/mm-override", riskWrite, riskH.MMOverride)
 			risk.GET("/alerts", riskH.Alerts)
 			risk.GET("/exchange-status", riskH.ExchangeStatus)
+			risk.GET("/price-sources", riskH.PriceSources)
+			risk.PATCH("/price-sources/:name", riskWrite, riskH.UpdatePriceSource)
+			risk.GET("/mm-config", mmConfigH.List)
+			risk.GET("/mm-config/effective", mmConfigH.Effective)
+			risk.GET("/mm-config/history", mmConfigH.History)
+			risk.PUT("/mm-config", riskWrite, mmConfigH.Set)
+			risk.DELETE("/mm-config", riskWrite, mmConfigH.Clear)
 		}
 
 		// Finance
 		fin := admin.Group("/finance")
 		{
 			fin.GET("/withdrawals", financeH.Withdrawals)
-			fin.POST("/withdrawals/:id/approve", financeH.ApproveWithdrawal)
-			fin.POST("/withdrawals/:id/reject", financeH.RejectWithdrawal)
+			fin.GET("/withdrawals/batches", batchH.List)
+			fin.POST("/withdrawals/batches", financeWrite, batchH.Create)
+			fin.GET("/withdrawals/batches/:id", batchH.Detail)
+			fin.GET("/withdrawals/batches/:id/export", financeWrite, batchH.Export)
+			fin.POST("/withdrawals/batches/:id/import", financeWrite, batchH.Import)
+			fin.POST("/withdrawals/:id/approve", financeWrite, financeH.ApproveWithdrawal)
+			fin.POST("/withdrawals/:id/reject", financeWrite, financeH.RejectWithdrawal)
+			fin.POST("/withdrawals/:id/execute", financeWrite, financeH.ExecuteWithdrawal)
+			fin.POST("/withdrawals/:id/settle", financeWrite, financeH.SettleWithdrawal)
 			fin.GET("/report", financeH.Report)
 			fin.GET("/transactions", financeH.Transactions)
+			fin.GET("/bank-accounts", bankH.List)
+			fin.POST("/bank-accounts/:id/verify", financeWrite, bankH.Verify)
+			fin.POST("/bank-accounts/:id/reject", financeWrite, bankH.Reject)
+			fin.GET("/deposits", depositH.List)
+			fin.GET("/deposits/:id", depositH.Detail)
+			fin.GET("/reconciliation", reconH.Runs)
+			fin.GET("/reconciliation/:id", reconH.Run)
+			fin.POST("/reconciliation", financeWrite, reconH.Reconcile)
+			fin.POST("/wallets/:id/freeze", financeWrite, reconH.FreezeWallet)
+			fin.POST("/wallets/:id/unfreeze", financeWrite, reconH.UnfreezeWallet)
+			fin.POST("/statements/regenerate", financeWrite, userH.RegenerateStatements)
+			fin.GET("/adjustments", adjustmentH.List)
+			fin.GET("/adjustments/:id", adjustmentH.Detail)
+			fin.POST("/adjustments/:id/approve", financeWrite, adjustmentH.Approve)
+			fin.POST("/adjustments/:id/reject", financeWrite, adjustmentH.Reject)
+		}
+
+		// Referrals
+		ref := admin.Group("/referrals")
+		{
+			ref.GET("", referralH.List)
+			ref.GET("/report", referralH.Report)
+			ref.POST("/:id/approve", riskWrite, referralH.Approve)
+			ref.POST("/:id/block", riskWrite, referralH.Block)
+		}
+
+		// Responsible gambling (read-only; users manage their own settings)
+		admin.GET("/responsible-gambling/exclusions", rgH.Exclusions)
+
+		// Identity verification
+		kyc := admin.Group("/kyc")
+		{
+			kyc.GET("", kycH.List)
+			kyc.GET("/:id", kycH.Detail)
+			kyc.GET("/documents/:id/file", riskWrite, kycH.Document)
+			kyc.POST("/:id/approve", riskWrite, kycH.Approve)
+			kyc.POST("/:id/reject", riskWrite, kycH.Reject)
+		}
+
+		// AML case queue: finance works the cases
+		aml := admin.Group("/aml")
+		{
+			aml.GET("/rules", amlH.Rules)
+			aml.GET("/cases", amlH.List)
+			aml.GET("/cases/:id", amlH.Detail)
+			aml.POST("/cases/:id/assign", financeWrite, amlH.Assign)
+			aml.POST("/cases/:id/notes", financeWrite, amlH.Note)
+			aml.POST("/cases/:id/escalate", financeWrite, amlH.Escalate)
+			aml.POST("/cases/:id/hold", financeWrite, amlH.Hold)
+			aml.POST("/cases/:id/release", financeWrite, amlH.Release)
+			aml.POST("/cases/:id/clear", financeWrite, amlH.Clear)
+			aml.POST("/cases/:id/report", financeWrite, amlH.Report)
 		}
 	}
 
@@ -128,7 +252,7 @@ func ipWhitelistMiddleware(allowedIPs string) gin.HandlerFunc {
 // ── Admin JWT middleware ──────────────────────────────────────────────────────
 
 // adminJWTMiddleware validates a JWT and requires the caller to have a
-// backoffice-capable role (admin, risk, finance, ops).
+// backoffice-capable role (admin, risk, finance, ops, marketing, readonly).
 func adminJWTMiddleware(authSvc *service.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		header := c.GetHeader("Authorization")
@@ -145,11 +269,12 @@ func adminJWTMiddleware(authSvc *service.AuthService) gin.HandlerFunc {
 
 		// Require at least one backoffice role
 		backofficeRoles := map[string]bool{
-			"admin":    true,
-			"risk":     true,
-			"finance":  true,
-			"ops":      true,
-			"readonly": true,
+			"admin":     true,
+			"risk":      true,
+			"finance":   true,
+			"ops":       true,
+			"marketing": true,
+			"readonly":  true,
 		}
 		if !backofficeRoles[claims.Role] {
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
@@ -161,3 +286,19 @@ func adminJWTMiddleware(authSvc *service.AuthService) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// requireRoles restricts a route to the listed roles.  Must run after
+// adminJWTMiddleware, which sets "role" on the context.
+func requireRoles(roles ...domain.UserRole) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(roles))
+	for _, r := range roles {
+		allowed[string(r)] = true
+	}
+	return func(c *gin.Context) {
+		if !allowed[c.GetString("role")] {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,85 @@
+package backoffice_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evetabi/prediction/internal/backoffice"
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const testAccessSecret = "test-access-secret-abcdefghijklmnop"
+
+// buildTestRouter creates the admin router with a real AuthService (token
+// parsing needs no DB) and nil for everything that requires a DB.
+func buildTestRouter(t *testing.T) http.Handler {
+	t.Helper()
+	cfg := &config.Config{
+		Server: config.ServerConfig{Env: "development"},
+		JWT: config.JWTConfig{
+			AccessSecret: testAccessSecret,
+			AccessTTL:    15 * time.Minute,
+		},
+	}
+	return backoffice.SetupBackofficeRouter(backoffice.BackofficeDeps{
+		AuthSvc: service.NewAuthService(nil, nil, nil, nil, nil, nil, cfg),
+		Cfg:     cfg,
+	})
+}
+
+// accessToken signs an access token for a fresh user with the given role.
+func accessToken(t *testing.T, role string) string {
+	t.Helper()
+	now := time.Now().UTC()
+	tok, err := jwt.NewWithClaims(jwt.SigningMethodHS256, service.AppClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+		Role:      role,
+		TokenType: "access",
+	}).SignedString([]byte(testAccessSecret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return tok
+}
+
+func TestKYCDocument_ReviewRolesOnly(t *testing.T) {
+	h := buildTestRouter(t)
+	path := "/admin/kyc/documents/" + uuid.NewString() + "/file"
+
+	for _, role := range []string{"readonly", "marketing", "finance", "ops"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+accessToken(t, role))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("GET %s as %s = %d, want 403", path, role, rr.Code)
+		}
+	}
+}
+
+func TestMarketing_PromotionsOnly(t *testing.T) {
+	h := buildTestRouter(t)
+	id := uuid.NewString()
+
+	for _, path := range []string{
+		"/admin/markets/" + id + "/resolve",
+		"/admin/users/" + id + "/suspend",
+	} {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Authorization", "Bearer "+accessToken(t, "marketing"))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("POST %s as marketing = %d, want 403", path, rr.Code)
+		}
+	}
+}
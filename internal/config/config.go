@@ -6,9 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
+	_ "time/tzdata" // BUSINESS_TIMEZONE must load on hosts without a zoneinfo database
 )
 
 // ──────────────────────────────────────────────────────────────────────────────
@@ -17,12 +20,13 @@ import (
 
 // ServerConfig holds HTTP server settings.
 type ServerConfig struct {
-	Port                 string        // e.g. "8080"
-	BackofficePort       string        // e.g. "8081"
-	Env                  string        // "development" | "production"
-	ReadTimeout          time.Duration // default 10s
-	WriteTimeout         time.Duration // default 10s
-	BackofficeAllowedIPs string        // comma-separated IPs; "" = allow all
+	Port                 string         // e.g. "8080"
+	BackofficePort       string         // e.g. "8081"
+	Env                  string         // "development" | "production"
+	ReadTimeout          time.Duration  // default 10s
+	WriteTimeout         time.Duration  // default 10s
+	BackofficeAllowedIPs string         // comma-separated IPs; "" = allow all
+	BusinessTZ           *time.Location // days, weeks and months of limits, budgets and reports; default Europe/Istanbul
 }
 
 // DBConfig holds PostgreSQL connection settings.
@@ -36,7 +40,7 @@ type DBConfig struct {
 // JWTConfig holds JWT signing settings.
 type JWTConfig struct {
 	AccessSecret  string        // must be set
-	RefreshSecret string        // must be set
+	RefreshSecret string        // must be set; keys the stored refresh-token hashes
 	AccessTTL     time.Duration // default 15m
 	RefreshTTL    time.Duration // default 720h (30 days)
 }
@@ -48,19 +52,29 @@ type PriceConfig struct {
 	OKXURL       string        // default "https://www.okx.com"
 	FetchTimeout time.Duration // default 2s
 	CacheTTL     time.Duration // default 1s
-	// Weight percentages (must sum to 100)
+	// Weight percentages (must sum to 100); seed values for the price_sources
+	// table, which is authoritative once populated.
 	BinanceWeight int // default 50
 	BybitWeight   int // default 30
 	OKXWeight     int // default 20
+	// How often each process re-reads price_sources to pick up back-office edits
+	SourceRefresh time.Duration // default 5s
 }
 
 // MMConfig holds Market Maker settings.
 type MMConfig struct {
 	MaxExposurePerMarket float64 // max TRY per market the house risks
-	MaxDailyLoss         float64 // house stops injecting liquidity after this daily loss (TRY)
+	MaxDailyLoss         float64 // realized + worst-case open MM loss allowed per day (TRY)
+	MaxWeeklyLoss        float64 // same, per Monday-started week (TRY)
+	ThrottleStart        float64 // fraction of a loss budget after which injections shrink, default 0.7
 	MinReserve           float64 // house must keep at least this in reserve (TRY)
 	TriggerThreshold     float64 // imbalance ratio triggering MM, e.g. 0.8 = 80/20
 	MinMMBet             float64 // minimum TRY the MM injects per action
+	SeedRatio            float64 // empty side is seeded at this fraction of the other, default 0.30
+	BalanceRatio         float64 // thin side is topped up to this fraction of the thick, default 0.20
+	TargetOdds           float64 // odds_target strategy: desired thin-side multiplier, default 3.0
+	Strategy             string  // default strategy name, see internal/mm; default "threshold"
+	RebalanceWorkers     int     // markets rebalanced in parallel, default 4
 }
 
 // WalletConfig holds wallet and fee settings.
@@ -69,6 +83,87 @@ type WalletConfig struct {
 	MaxDailyWithdraw float64 // max cumulative withdrawal per day per user (TRY)
 	CommissionRate   float64 // pari-mutuel commission, e.g. 0.03 = 3%
 	CashoutFeeRate   float64 // early cashout fee, e.g. 0.05 = 5%
+
+	ReconInterval   time.Duration // scheduled wallet reconciliation; 0 = off
+	ReconAutoFreeze bool          // freeze user wallets that drift on scheduled runs
+
+	BankAccountCoolingOff time.Duration // new IBANs receive withdrawals only after this, default 24h
+	MaxBankAccounts       int           // saved IBANs per user, default 5
+}
+
+// PaymentConfig holds deposit and payment-provider settings.
+type PaymentConfig struct {
+	Provider      string        // see internal/payment; default "mock"
+	WebhookSecret string        // HMAC key for provider webhooks
+	PublicURL     string        // externally reachable API base URL, default "http://localhost:8080"
+	MinDeposit    float64       // minimum deposit (TRY)
+	MaxDeposit    float64       // maximum single deposit (TRY)
+	DepositTTL    time.Duration // pending deposits expire after this, default 30m
+
+	PayoutProvider string // bank payouts for withdrawals, see internal/payout; default "mock"
+
+	// House account debited in exported bank payout files.
+	PayoutDebtorName string // default "Evetabi"
+	PayoutDebtorIBAN string
+	PayoutDebtorBIC  string
+}
+
+// BonusConfig holds bonus balance and wagering settings.
+type BonusConfig struct {
+	SignupAmount       float64       // bonus granted at registration (TRY); 0 = none, default 1000
+	WageringMultiplier float64       // turnover required = grant × this, default 10
+	MinOdds            float64       // settled bets below these odds do not count, default 1.5
+	Validity           time.Duration // unconverted grants expire after this, default 720h
+	ForfeitOnWithdraw  bool          // withdrawing forfeits the active bonus, default true
+}
+
+// ResponsibleConfig holds player-protection settings.
+type ResponsibleConfig struct {
+	LimitIncreaseDelay   time.Duration // raising or removing a limit waits this long, default 24h
+	CoolingOffMaxDays    int           // longest cooling-off break, default 42
+	SelfExclusionMinDays int           // shortest fixed self-exclusion, default 180
+}
+
+// ReferralConfig holds referral program settings.
+type ReferralConfig struct {
+	ShareRate      float64       // referrer's share of the commission on referred users' bets, default 0.20
+	PayoutInterval time.Duration // how often earned shares are credited; 0 = off, default 1h
+}
+
+// KYCConfig holds identity verification settings.
+type KYCConfig struct {
+	WithdrawThreshold      float64 // unverified users cannot withdraw more than this at once (TRY); 0 = off, default 5000
+	TotalWithdrawThreshold float64 // nor more than this in total (TRY); 0 = off, default 20000
+	MaxDocumentBytes       int     // largest accepted document upload, default 10 MiB
+}
+
+// StorageConfig holds file storage settings for uploaded documents and
+// generated statements.
+type StorageConfig struct {
+	Driver   string // see internal/storage; default "local"
+	LocalDir string // root of the local driver, default "./data/uploads"; absolute and shared by server and backoffice in production
+}
+
+// AMLConfig holds transaction monitoring settings; see internal/aml for
+// the rules.
+type AMLConfig struct {
+	Enabled             bool          // screen deposits, bets and withdrawal requests, default true
+	AutoHold            bool          // rules that call for it hold the user's pending withdrawals, default true
+	QuickWithdrawWindow time.Duration // quick_withdrawal: withdrawing this soon after a deposit, default 24h
+	MinTurnoverRatio    float64       // quick_withdrawal: … having staked less than this × the deposit, default 1.0
+	StructuringWindow   time.Duration // structuring: amounts counted over this window, default 24h
+	StructuringBand     float64       // structuring: amounts within this fraction below a limit, default 0.10
+	StructuringCount    int           // structuring: this many of them raise an alert, default 3
+	TurnoverWindow      time.Duration // rapid_turnover: deposits and withdrawals counted over this window, default 24h
+	TurnoverMinAmount   float64       // rapid_turnover: deposits below this are ignored (TRY), default 10000
+	TurnoverRatio       float64       // rapid_turnover: withdrawing this share of them raises an alert, default 0.8
+	HedgeRatio          float64       // hedged_bets: smaller side at least this × the larger one, default 0.5
+	HedgeMinAmount      float64       // hedged_bets: smaller side at least this (TRY), default 1000
+}
+
+// StatementConfig holds monthly account statement settings.
+type StatementConfig struct {
+	Interval time.Duration // how often due statements are looked for; 0 = off, default 1h
 }
 
 // ──────────────────────────────────────────────────────────────────────────────
@@ -77,12 +172,30 @@ type WalletConfig struct {
 
 // Config is the root configuration object for the entire application.
 type Config struct {
-	Server ServerConfig
-	DB     DBConfig
-	JWT    JWTConfig
-	Price  PriceConfig
-	MM     MMConfig
-	Wallet WalletConfig
+	Server      ServerConfig
+	DB          DBConfig
+	JWT         JWTConfig
+	Price       PriceConfig
+	MM          MMConfig
+	Wallet      WalletConfig
+	Payment     PaymentConfig
+	Bonus       BonusConfig
+	Referral    ReferralConfig
+	Responsible ResponsibleConfig
+	KYC         KYCConfig
+	Storage     StorageConfig
+	AML         AMLConfig
+	Statement   StatementConfig
+}
+
+// Location returns the business timezone.  Daily, weekly and monthly
+// limits, MM loss budgets, dashboards and reports all start their periods
+// at midnight there.  It is UTC when unset, e.g. in tests.
+func (c *Config) Location() *time.Location {
+	if c.Server.BusinessTZ == nil {
+		return time.UTC
+	}
+	return c.Server.BusinessTZ
 }
 
 // IsProd returns true when running in the production environment.
@@ -131,12 +244,110 @@ func (c *Config) Validate() error {
 		))
 	}
 
+	if c.Wallet.MaxBankAccounts < 1 {
+		errs = append(errs, fmt.Errorf("WALLET_MAX_BANK_ACCOUNTS must be at least 1, got %d", c.Wallet.MaxBankAccounts))
+	}
+
+	// Deposits
+	if c.Payment.WebhookSecret == "" {
+		errs = append(errs, errors.New("PAYMENT_WEBHOOK_SECRET must be set"))
+	}
+	if c.IsProd() && c.Payment.Provider == "mock" {
+		errs = append(errs, errors.New("PAYMENT_PROVIDER=mock is not allowed in production"))
+	}
+	if c.IsProd() && c.Payment.PayoutProvider == "mock" {
+		errs = append(errs, errors.New("PAYOUT_PROVIDER=mock is not allowed in production"))
+	}
+	if c.IsProd() && c.Payment.PayoutDebtorIBAN == "" {
+		errs = append(errs, errors.New("PAYOUT_DEBTOR_IBAN must be set in production"))
+	}
+	if c.Payment.MinDeposit <= 0 || c.Payment.MaxDeposit < c.Payment.MinDeposit {
+		errs = append(errs, fmt.Errorf(
+			"deposit limits must satisfy 0 < PAYMENT_MIN_DEPOSIT <= PAYMENT_MAX_DEPOSIT, got %.2f / %.2f",
+			c.Payment.MinDeposit, c.Payment.MaxDeposit,
+		))
+	}
+
+	// Bonuses
+	if c.Bonus.SignupAmount < 0 || c.Bonus.WageringMultiplier < 0 {
+		errs = append(errs, errors.New("BONUS_SIGNUP_AMOUNT and BONUS_WAGERING_MULTIPLIER must not be negative"))
+	}
+	if c.Bonus.MinOdds < 1 {
+		errs = append(errs, fmt.Errorf("BONUS_MIN_ODDS must be at least 1, got %.2f", c.Bonus.MinOdds))
+	}
+	if c.Bonus.Validity <= 0 {
+		errs = append(errs, errors.New("BONUS_VALIDITY must be positive"))
+	}
+
+	// Referrals
+	if c.Referral.ShareRate < 0 || c.Referral.ShareRate > 1 {
+		errs = append(errs, fmt.Errorf("REFERRAL_SHARE_RATE must be between 0 and 1, got %.4f", c.Referral.ShareRate))
+	}
+	if c.Referral.PayoutInterval < 0 {
+		errs = append(errs, errors.New("REFERRAL_PAYOUT_INTERVAL must not be negative"))
+	}
+
+	// Responsible gambling
+	if c.Responsible.LimitIncreaseDelay <= 0 {
+		errs = append(errs, errors.New("RG_LIMIT_INCREASE_DELAY must be positive"))
+	}
+	if c.Responsible.CoolingOffMaxDays < 1 || c.Responsible.SelfExclusionMinDays < 1 {
+		errs = append(errs, errors.New("RG_COOLING_OFF_MAX_DAYS and RG_SELF_EXCLUSION_MIN_DAYS must be at least 1"))
+	}
+
+	// KYC
+	if c.KYC.WithdrawThreshold < 0 || c.KYC.TotalWithdrawThreshold < 0 {
+		errs = append(errs, errors.New("KYC_WITHDRAW_THRESHOLD and KYC_TOTAL_WITHDRAW_THRESHOLD must not be negative"))
+	}
+	if c.KYC.MaxDocumentBytes <= 0 {
+		errs = append(errs, errors.New("KYC_MAX_DOCUMENT_BYTES must be positive"))
+	}
+	if c.Storage.Driver == "" {
+		errs = append(errs, errors.New("STORAGE_DRIVER must be set"))
+	}
+	// The server and the backoffice read each other's files, so in
+	// production the local driver must sit on a volume both mount.
+	if c.IsProd() && c.Storage.Driver == "local" && !isDurableDir(c.Storage.LocalDir) {
+		errs = append(errs, fmt.Errorf("STORAGE_LOCAL_DIR must be an absolute path on a shared, persistent volume in production, got %q", c.Storage.LocalDir))
+	}
+
+	// AML
+	if c.AML.MinTurnoverRatio < 0 || c.AML.TurnoverRatio <= 0 || c.AML.HedgeRatio <= 0 {
+		errs = append(errs, errors.New("AML_MIN_TURNOVER_RATIO must not be negative; AML_TURNOVER_RATIO and AML_HEDGE_RATIO must be positive"))
+	}
+	if c.AML.StructuringBand <= 0 || c.AML.StructuringBand >= 1 {
+		errs = append(errs, fmt.Errorf("AML_STRUCTURING_BAND must be between 0 and 1 (got %g)", c.AML.StructuringBand))
+	}
+	if c.AML.StructuringCount < 2 {
+		errs = append(errs, fmt.Errorf("AML_STRUCTURING_COUNT must be at least 2 (got %d)", c.AML.StructuringCount))
+	}
+
+	// Statements
+	if c.Statement.Interval < 0 {
+		errs = append(errs, errors.New("STATEMENT_INTERVAL must not be negative"))
+	}
+
 	if len(errs) > 0 {
 		return errors.Join(errs...)
 	}
 	return nil
 }
 
+// isDurableDir reports whether dir is absolute and outside the usual
+// temporary directories, which do not survive a container restart.
+func isDurableDir(dir string) bool {
+	if !filepath.IsAbs(dir) {
+		return false
+	}
+	dir = filepath.Clean(dir)
+	for _, tmp := range []string{"/tmp", "/var/tmp", "/dev/shm", "/run"} {
+		if dir == tmp || strings.HasPrefix(dir, tmp+"/") {
+			return false
+		}
+	}
+	return true
+}
+
 // ──────────────────────────────────────────────────────────────────────────────
 // Singleton
 // ──────────────────────────────────────────────────────────────────────────────
@@ -178,6 +389,11 @@ func load() (*Config, error) {
 	cfg := &Config{}
 
 	// ── Server ────────────────────────────────────────────────────────────────
+	businessTZ, err := time.LoadLocation(getEnv("BUSINESS_TIMEZONE", "Europe/Istanbul"))
+	if err != nil {
+		return nil, fmt.Errorf("BUSINESS_TIMEZONE: %w", err)
+	}
+
 	cfg.Server = ServerConfig{
 		Port:                 getEnv("SERVER_PORT", "8080"),
 		BackofficePort:       getEnv("BACKOFFICE_PORT", "8081"),
@@ -185,6 +401,7 @@ func load() (*Config, error) {
 		ReadTimeout:          getDuration("SERVER_READ_TIMEOUT", 10*time.Second),
 		WriteTimeout:         getDuration("SERVER_WRITE_TIMEOUT", 10*time.Second),
 		BackofficeAllowedIPs: getEnv("BACKOFFICE_ALLOWED_IPS", ""),
+		BusinessTZ:           businessTZ,
 	}
 
 	// ── Database ──────────────────────────────────────────────────────────────
@@ -249,6 +466,7 @@ func load() (*Config, error) {
 		BinanceWeight: binW,
 		BybitWeight:   byW,
 		OKXWeight:     okxW,
+		SourceRefresh: getDuration("PRICE_SOURCE_REFRESH", 5*time.Second),
 	}
 
 	// ── Market Maker ──────────────────────────────────────────────────────────
@@ -260,6 +478,14 @@ func load() (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("MM_MAX_DAILY_LOSS: %w", err)
 	}
+	mmWeeklyLoss, err := getFloat("MM_MAX_WEEKLY_LOSS", 150000)
+	if err != nil {
+		return nil, fmt.Errorf("MM_MAX_WEEKLY_LOSS: %w", err)
+	}
+	mmThrottle, err := getFloat("MM_THROTTLE_START", 0.7)
+	if err != nil {
+		return nil, fmt.Errorf("MM_THROTTLE_START: %w", err)
+	}
 	mmReserve, err := getFloat("MM_MIN_RESERVE", 100000)
 	if err != nil {
 		return nil, fmt.Errorf("MM_MIN_RESERVE: %w", err)
@@ -273,12 +499,38 @@ func load() (*Config, error) {
 		return nil, fmt.Errorf("MM_MIN_BET: %w", err)
 	}
 
+	mmSeed, err := getFloat("MM_SEED_RATIO", 0.30)
+	if err != nil {
+		return nil, fmt.Errorf("MM_SEED_RATIO: %w", err)
+	}
+	mmBalance, err := getFloat("MM_BALANCE_RATIO", 0.20)
+	if err != nil {
+		return nil, fmt.Errorf("MM_BALANCE_RATIO: %w", err)
+	}
+
+	mmTargetOdds, err := getFloat("MM_TARGET_ODDS", 3.0)
+	if err != nil {
+		return nil, fmt.Errorf("MM_TARGET_ODDS: %w", err)
+	}
+
+	mmWorkers, err := getInt("MM_REBALANCE_WORKERS", 4)
+	if err != nil {
+		return nil, fmt.Errorf("MM_REBALANCE_WORKERS: %w", err)
+	}
+
 	cfg.MM = MMConfig{
 		MaxExposurePerMarket: mmExposure,
 		MaxDailyLoss:         mmDailyLoss,
+		MaxWeeklyLoss:        mmWeeklyLoss,
+		ThrottleStart:        mmThrottle,
 		MinReserve:           mmReserve,
 		TriggerThreshold:     mmThreshold,
 		MinMMBet:             mmMinBet,
+		SeedRatio:            mmSeed,
+		BalanceRatio:         mmBalance,
+		TargetOdds:           mmTargetOdds,
+		Strategy:             getEnv("MM_STRATEGY", "threshold"),
+		RebalanceWorkers:     mmWorkers,
 	}
 
 	// ── Wallet ────────────────────────────────────────────────────────────────
@@ -299,11 +551,186 @@ func load() (*Config, error) {
 		return nil, fmt.Errorf("WALLET_CASHOUT_FEE_RATE: %w", err)
 	}
 
+	autoFreeze, err := getBool("WALLET_RECON_AUTO_FREEZE", false)
+	if err != nil {
+		return nil, fmt.Errorf("WALLET_RECON_AUTO_FREEZE: %w", err)
+	}
+
+	maxBankAccounts, err := getInt("WALLET_MAX_BANK_ACCOUNTS", 5)
+	if err != nil {
+		return nil, fmt.Errorf("WALLET_MAX_BANK_ACCOUNTS: %w", err)
+	}
+
 	cfg.Wallet = WalletConfig{
 		MinWithdraw:      minW,
 		MaxDailyWithdraw: maxDW,
 		CommissionRate:   commission,
 		CashoutFeeRate:   cashoutFee,
+		ReconInterval:    getDuration("WALLET_RECON_INTERVAL", time.Hour),
+		ReconAutoFreeze:  autoFreeze,
+
+		BankAccountCoolingOff: getDuration("WALLET_BANK_ACCOUNT_COOLING_OFF", 24*time.Hour),
+		MaxBankAccounts:       maxBankAccounts,
+	}
+
+	// ── Payment ───────────────────────────────────────────────────────────────
+	minDep, err := getFloat("PAYMENT_MIN_DEPOSIT", 10)
+	if err != nil {
+		return nil, fmt.Errorf("PAYMENT_MIN_DEPOSIT: %w", err)
+	}
+	maxDep, err := getFloat("PAYMENT_MAX_DEPOSIT", 50000)
+	if err != nil {
+		return nil, fmt.Errorf("PAYMENT_MAX_DEPOSIT: %w", err)
+	}
+
+	cfg.Payment = PaymentConfig{
+		Provider:      getEnv("PAYMENT_PROVIDER", "mock"),
+		WebhookSecret: getEnv("PAYMENT_WEBHOOK_SECRET", ""),
+		PublicURL:     getEnv("PAYMENT_PUBLIC_URL", "http://localhost:8080"),
+		MinDeposit:    minDep,
+		MaxDeposit:    maxDep,
+		DepositTTL:    getDuration("PAYMENT_DEPOSIT_TTL", 30*time.Minute),
+
+		PayoutProvider:   getEnv("PAYOUT_PROVIDER", "mock"),
+		PayoutDebtorName: getEnv("PAYOUT_DEBTOR_NAME", "Evetabi"),
+		PayoutDebtorIBAN: getEnv("PAYOUT_DEBTOR_IBAN", ""),
+		PayoutDebtorBIC:  getEnv("PAYOUT_DEBTOR_BIC", ""),
+	}
+
+	// ── Bonus ─────────────────────────────────────────────────────────────────
+	signupBonus, err := getFloat("BONUS_SIGNUP_AMOUNT", 1000)
+	if err != nil {
+		return nil, fmt.Errorf("BONUS_SIGNUP_AMOUNT: %w", err)
+	}
+	wagering, err := getFloat("BONUS_WAGERING_MULTIPLIER", 10)
+	if err != nil {
+		return nil, fmt.Errorf("BONUS_WAGERING_MULTIPLIER: %w", err)
+	}
+	bonusMinOdds, err := getFloat("BONUS_MIN_ODDS", 1.5)
+	if err != nil {
+		return nil, fmt.Errorf("BONUS_MIN_ODDS: %w", err)
+	}
+	forfeitOnWithdraw, err := getBool("BONUS_FORFEIT_ON_WITHDRAW", true)
+	if err != nil {
+		return nil, fmt.Errorf("BONUS_FORFEIT_ON_WITHDRAW: %w", err)
+	}
+
+	cfg.Bonus = BonusConfig{
+		SignupAmount:       signupBonus,
+		WageringMultiplier: wagering,
+		MinOdds:            bonusMinOdds,
+		Validity:           getDuration("BONUS_VALIDITY", 720*time.Hour),
+		ForfeitOnWithdraw:  forfeitOnWithdraw,
+	}
+
+	// ── Referral ──────────────────────────────────────────────────────────────
+	referralShare, err := getFloat("REFERRAL_SHARE_RATE", 0.20)
+	if err != nil {
+		return nil, fmt.Errorf("REFERRAL_SHARE_RATE: %w", err)
+	}
+
+	cfg.Referral = ReferralConfig{
+		ShareRate:      referralShare,
+		PayoutInterval: getDuration("REFERRAL_PAYOUT_INTERVAL", time.Hour),
+	}
+
+	// ── Responsible gambling ──────────────────────────────────────────────────
+	coolingOffMax, err := getInt("RG_COOLING_OFF_MAX_DAYS", 42)
+	if err != nil {
+		return nil, fmt.Errorf("RG_COOLING_OFF_MAX_DAYS: %w", err)
+	}
+	selfExclusionMin, err := getInt("RG_SELF_EXCLUSION_MIN_DAYS", 180)
+	if err != nil {
+		return nil, fmt.Errorf("RG_SELF_EXCLUSION_MIN_DAYS: %w", err)
+	}
+
+	cfg.Responsible = ResponsibleConfig{
+		LimitIncreaseDelay:   getDuration("RG_LIMIT_INCREASE_DELAY", 24*time.Hour),
+		CoolingOffMaxDays:    coolingOffMax,
+		SelfExclusionMinDays: selfExclusionMin,
+	}
+
+	// ── KYC ───────────────────────────────────────────────────────────────────
+	kycThreshold, err := getFloat("KYC_WITHDRAW_THRESHOLD", 5000)
+	if err != nil {
+		return nil, fmt.Errorf("KYC_WITHDRAW_THRESHOLD: %w", err)
+	}
+	kycTotalThreshold, err := getFloat("KYC_TOTAL_WITHDRAW_THRESHOLD", 20000)
+	if err != nil {
+		return nil, fmt.Errorf("KYC_TOTAL_WITHDRAW_THRESHOLD: %w", err)
+	}
+	kycMaxDoc, err := getInt("KYC_MAX_DOCUMENT_BYTES", 10<<20)
+	if err != nil {
+		return nil, fmt.Errorf("KYC_MAX_DOCUMENT_BYTES: %w", err)
+	}
+
+	cfg.KYC = KYCConfig{
+		WithdrawThreshold:      kycThreshold,
+		TotalWithdrawThreshold: kycTotalThreshold,
+		MaxDocumentBytes:       kycMaxDoc,
+	}
+
+	cfg.Storage = StorageConfig{
+		Driver:   getEnv("STORAGE_DRIVER", "local"),
+		LocalDir: getEnv("STORAGE_LOCAL_DIR", "./data/uploads"),
+	}
+
+	// ── AML ───────────────────────────────────────────────────────────────────
+	amlEnabled, err := getBool("AML_ENABLED", true)
+	if err != nil {
+		return nil, fmt.Errorf("AML_ENABLED: %w", err)
+	}
+	amlAutoHold, err := getBool("AML_AUTO_HOLD", true)
+	if err != nil {
+		return nil, fmt.Errorf("AML_AUTO_HOLD: %w", err)
+	}
+	amlMinTurnover, err := getFloat("AML_MIN_TURNOVER_RATIO", 1.0)
+	if err != nil {
+		return nil, fmt.Errorf("AML_MIN_TURNOVER_RATIO: %w", err)
+	}
+	amlBand, err := getFloat("AML_STRUCTURING_BAND", 0.10)
+	if err != nil {
+		return nil, fmt.Errorf("AML_STRUCTURING_BAND: %w", err)
+	}
+	amlCount, err := getInt("AML_STRUCTURING_COUNT", 3)
+	if err != nil {
+		return nil, fmt.Errorf("AML_STRUCTURING_COUNT: %w", err)
+	}
+	amlTurnoverMin, err := getFloat("AML_TURNOVER_MIN_AMOUNT", 10000)
+	if err != nil {
+		return nil, fmt.Errorf("AML_TURNOVER_MIN_AMOUNT: %w", err)
+	}
+	amlTurnoverRatio, err := getFloat("AML_TURNOVER_RATIO", 0.8)
+	if err != nil {
+		return nil, fmt.Errorf("AML_TURNOVER_RATIO: %w", err)
+	}
+	amlHedgeRatio, err := getFloat("AML_HEDGE_RATIO", 0.5)
+	if err != nil {
+		return nil, fmt.Errorf("AML_HEDGE_RATIO: %w", err)
+	}
+	amlHedgeMin, err := getFloat("AML_HEDGE_MIN_AMOUNT", 1000)
+	if err != nil {
+		return nil, fmt.Errorf("AML_HEDGE_MIN_AMOUNT: %w", err)
+	}
+
+	cfg.AML = AMLConfig{
+		Enabled:             amlEnabled,
+		AutoHold:            amlAutoHold,
+		QuickWithdrawWindow: getDuration("AML_QUICK_WITHDRAW_WINDOW", 24*time.Hour),
+		MinTurnoverRatio:    amlMinTurnover,
+		StructuringWindow:   getDuration("AML_STRUCTURING_WINDOW", 24*time.Hour),
+		StructuringBand:     amlBand,
+		StructuringCount:    amlCount,
+		TurnoverWindow:      getDuration("AML_TURNOVER_WINDOW", 24*time.Hour),
+		TurnoverMinAmount:   amlTurnoverMin,
+		TurnoverRatio:       amlTurnoverRatio,
+		HedgeRatio:          amlHedgeRatio,
+		HedgeMinAmount:      amlHedgeMin,
+	}
+
+	// ── Statements ────────────────────────────────────────────────────────────
+	cfg.Statement = StatementConfig{
+		Interval: getDuration("STATEMENT_INTERVAL", time.Hour),
 	}
 
 	return cfg, nil
@@ -344,6 +771,18 @@ func getFloat(key string, defaultVal float64) (float64, error) {
 	return f, nil
 }
 
+func getBool(key string, defaultVal bool) (bool, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return defaultVal, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean %q", v)
+	}
+	return b, nil
+}
+
 // getDuration parses an env var as a Go duration string (e.g. "15m", "2s").
 // Falls back to defaultVal if the variable is unset or empty.
 func getDuration(key string, defaultVal time.Duration) time.Duration {
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// AdjustmentStatus is the review state of a balance adjustment.
+type AdjustmentStatus string
+
+const (
+	AdjustmentPending  AdjustmentStatus = "pending"  // waiting for a second admin
+	AdjustmentApproved AdjustmentStatus = "approved" // posted to the wallet
+	AdjustmentRejected AdjustmentStatus = "rejected" // refused; nothing was posted
+)
+
+// AdjustmentReason categorises why a balance is adjusted.
+type AdjustmentReason string
+
+const (
+	AdjustmentCorrection AdjustmentReason = "correction" // fixes a processing error
+	AdjustmentGoodwill   AdjustmentReason = "goodwill"   // compensation decided by support
+	AdjustmentPromotion  AdjustmentReason = "promotion"  // manual promotional credit
+	AdjustmentChargeback AdjustmentReason = "chargeback" // deposit reversed by the payment provider
+	AdjustmentFraud      AdjustmentReason = "fraud"      // winnings voided after a fraud finding
+	AdjustmentOther      AdjustmentReason = "other"
+)
+
+// IsValid reports whether r is a known adjustment reason.
+func (r AdjustmentReason) IsValid() bool {
+	switch r {
+	case AdjustmentCorrection, AdjustmentGoodwill, AdjustmentPromotion,
+		AdjustmentChargeback, AdjustmentFraud, AdjustmentOther:
+		return true
+	}
+	return false
+}
+
+// BalanceAdjustment is a manual change to a user's balance.  One admin
+// requests it and another approves it; only then is it posted to the
+// wallet, as a ledger entry of type adjustment against house:adjustments.
+// Amount is signed: negative debits the wallet.
+type BalanceAdjustment struct {
+	ID          uuid.UUID        `json:"id"           db:"id"`
+	UserID      uuid.UUID        `json:"user_id"      db:"user_id"`
+	Amount      decimal.Decimal  `json:"amount"       db:"amount"`
+	Reason      AdjustmentReason `json:"reason"       db:"reason"`
+	Note        string           `json:"note"         db:"note"`
+	Status      AdjustmentStatus `json:"status"       db:"status"`
+	RequestedBy uuid.UUID        `json:"requested_by" db:"requested_by"`
+	RequestedAt time.Time        `json:"requested_at" db:"requested_at"`
+	ReviewedBy  *uuid.UUID       `json:"reviewed_by"  db:"reviewed_by"`
+	ReviewedAt  *time.Time       `json:"reviewed_at"  db:"reviewed_at"`
+	ReviewNote  *string          `json:"review_note"  db:"review_note"`
+	EntryID     *uuid.UUID       `json:"entry_id"     db:"entry_id"` // ledger entry, once approved
+}
+
+// NewBalanceAdjustment validates and returns a pending adjustment.  Returns
+// ErrInvalidAdjustment for a zero amount, an unknown reason or an empty
+// note, and ErrSelfApproval when an admin requests it for themselves.
+func NewBalanceAdjustment(userID, requestedBy uuid.UUID, amount decimal.Decimal, reason AdjustmentReason, note string) (*BalanceAdjustment, error) {
+	note = strings.TrimSpace(note)
+	if amount.IsZero() || !reason.IsValid() || note == "" {
+		return nil, ErrInvalidAdjustment
+	}
+	if userID == requestedBy {
+		return nil, ErrSelfApproval
+	}
+	return &BalanceAdjustment{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Amount:      amount,
+		Reason:      reason,
+		Note:        note,
+		Status:      AdjustmentPending,
+		RequestedBy: requestedBy,
+		RequestedAt: time.Now().UTC(),
+	}, nil
+}
+
+// CheckApprover returns ErrSelfApproval unless adminID may approve a: the
+// approver must be neither its requester nor the user whose balance it
+// changes, and neither may the requester.
+func (a *BalanceAdjustment) CheckApprover(adminID uuid.UUID) error {
+	if adminID == a.RequestedBy || adminID == a.UserID || a.RequestedBy == a.UserID {
+		return ErrSelfApproval
+	}
+	return nil
+}
+
+// BalanceAdjustmentItem is an adjustment as listed for review, with the
+// usernames involved.
+type BalanceAdjustmentItem struct {
+	BalanceAdjustment
+	Username        string `json:"username"          db:"username"`
+	RequestedByName string `json:"requested_by_name" db:"requested_by_name"`
+}
@@ -0,0 +1,75 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestNewBalanceAdjustment(t *testing.T) {
+	user, admin := uuid.New(), uuid.New()
+
+	a, err := domain.NewBalanceAdjustment(user, admin, decimal.NewFromInt(-250), domain.AdjustmentCorrection, "  duplicate deposit ")
+	if err != nil {
+		t.Fatalf("NewBalanceAdjustment: %v", err)
+	}
+	if a.Status != domain.AdjustmentPending || a.Note != "duplicate deposit" || a.RequestedBy != admin || a.ReviewedBy != nil {
+		t.Errorf("got %+v", a)
+	}
+
+	cases := []struct {
+		name   string
+		amount decimal.Decimal
+		reason domain.AdjustmentReason
+		note   string
+	}{
+		{"zero amount", decimal.Zero, domain.AdjustmentGoodwill, "apology"},
+		{"unknown reason", decimal.NewFromInt(10), "bonus", "apology"},
+		{"blank note", decimal.NewFromInt(10), domain.AdjustmentGoodwill, "   "},
+	}
+	for _, c := range cases {
+		if _, err := domain.NewBalanceAdjustment(user, admin, c.amount, c.reason, c.note); !errors.Is(err, domain.ErrInvalidAdjustment) {
+			t.Errorf("%s: err = %v, want ErrInvalidAdjustment", c.name, err)
+		}
+	}
+}
+
+func TestAccountStatement_AddAdjustment(t *testing.T) {
+	s := domain.NewAccountStatement(uuid.New(), "2026-03", time.Time{}, time.Time{}, decimal.NewFromInt(100))
+	s.Add(&domain.Transaction{Type: domain.TxAdjustment, BalanceBefore: decimal.NewFromInt(100), BalanceAfter: decimal.NewFromInt(75)})
+	if !s.Other.Equal(decimal.NewFromInt(-25)) || !s.ClosingBalance.Equal(decimal.NewFromInt(75)) {
+		t.Errorf("other = %s, closing = %s", s.Other, s.ClosingBalance)
+	}
+}
+
+func TestBalanceAdjustment_SelfApproval(t *testing.T) {
+	user, requester, approver := uuid.New(), uuid.New(), uuid.New()
+
+	if _, err := domain.NewBalanceAdjustment(requester, requester, decimal.NewFromInt(100), domain.AdjustmentGoodwill, "own wallet"); !errors.Is(err, domain.ErrSelfApproval) {
+		t.Errorf("request for own balance: err = %v, want ErrSelfApproval", err)
+	}
+
+	a, err := domain.NewBalanceAdjustment(user, requester, decimal.NewFromInt(100), domain.AdjustmentGoodwill, "apology")
+	if err != nil {
+		t.Fatalf("NewBalanceAdjustment: %v", err)
+	}
+	if err := a.CheckApprover(approver); err != nil {
+		t.Errorf("independent approver: err = %v", err)
+	}
+	if err := a.CheckApprover(requester); !errors.Is(err, domain.ErrSelfApproval) {
+		t.Errorf("requester approving: err = %v, want ErrSelfApproval", err)
+	}
+
+	// A colleague's credit to the approving admin's own wallet.
+	own, err := domain.NewBalanceAdjustment(approver, requester, decimal.NewFromInt(100), domain.AdjustmentGoodwill, "apology")
+	if err != nil {
+		t.Fatalf("NewBalanceAdjustment: %v", err)
+	}
+	if err := own.CheckApprover(approver); !errors.Is(err, domain.ErrSelfApproval) {
+		t.Errorf("approving own credit: err = %v, want ErrSelfApproval", err)
+	}
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// AMLSeverity ranks an alert and, as the highest of its alerts, a case.
+type AMLSeverity string
+
+const (
+	AMLLow    AMLSeverity = "low"
+	AMLMedium AMLSeverity = "medium"
+	AMLHigh   AMLSeverity = "high"
+)
+
+// Rank orders severities for comparison; unknown values rank lowest.
+func (s AMLSeverity) Rank() int {
+	switch s {
+	case AMLLow:
+		return 1
+	case AMLMedium:
+		return 2
+	case AMLHigh:
+		return 3
+	}
+	return 0
+}
+
+// AMLCaseStatus is the state of an AML case.  A user has at most one case
+// that is not closed; new alerts are added to it.
+type AMLCaseStatus string
+
+const (
+	AMLCaseOpen      AMLCaseStatus = "open"      // raised by the rules, waiting for review
+	AMLCaseEscalated AMLCaseStatus = "escalated" // passed to the compliance officer
+	AMLCaseCleared   AMLCaseStatus = "cleared"   // closed: activity explained, holds released
+	AMLCaseReported  AMLCaseStatus = "reported"  // closed: reported to the authority, holds kept
+)
+
+// IsClosed reports whether no further alerts or actions apply to the case.
+func (s AMLCaseStatus) IsClosed() bool {
+	return s == AMLCaseCleared || s == AMLCaseReported
+}
+
+// AMLSubject is the kind of activity an alert was raised on.
+type AMLSubject string
+
+const (
+	AMLSubjectDeposit    AMLSubject = "deposit"
+	AMLSubjectBet        AMLSubject = "bet"
+	AMLSubjectWithdrawal AMLSubject = "withdrawal"
+)
+
+// AMLCase groups a user's alerts for review.  While Hold is set, the user's
+// pending withdrawals cannot be approved, nor approved ones paid out.
+type AMLCase struct {
+	ID         uuid.UUID     `json:"id"          db:"id"`
+	UserID     uuid.UUID     `json:"user_id"     db:"user_id"`
+	Status     AMLCaseStatus `json:"status"      db:"status"`
+	Severity   AMLSeverity   `json:"severity"    db:"severity"`
+	Hold       bool          `json:"hold"        db:"hold"`
+	AssignedTo *uuid.UUID    `json:"assigned_to" db:"assigned_to"`
+	Resolution string        `json:"resolution"  db:"resolution"`
+	OpenedAt   time.Time     `json:"opened_at"   db:"opened_at"`
+	UpdatedAt  time.Time     `json:"updated_at"  db:"updated_at"`
+	ClosedBy   *uuid.UUID    `json:"closed_by"   db:"closed_by"`
+	ClosedAt   *time.Time    `json:"closed_at"   db:"closed_at"`
+}
+
+// HoldsWithdrawals reports whether c is a live case holding the user's
+// withdrawals.  A nil case holds nothing.
+func (c *AMLCase) HoldsWithdrawals() bool {
+	return c != nil && c.Hold && !c.Status.IsClosed()
+}
+
+// AMLAlert is one rule hit on one deposit, bet or withdrawal request.
+type AMLAlert struct {
+	ID        uuid.UUID       `json:"id"         db:"id"`
+	CaseID    uuid.UUID       `json:"case_id"    db:"case_id"`
+	UserID    uuid.UUID       `json:"user_id"    db:"user_id"`
+	Rule      string          `json:"rule"       db:"rule"`
+	Severity  AMLSeverity     `json:"severity"   db:"severity"`
+	Subject   AMLSubject      `json:"subject"    db:"subject"`
+	SubjectID uuid.UUID       `json:"subject_id" db:"subject_id"`
+	Amount    decimal.Decimal `json:"amount"     db:"amount"`
+	Reason    string          `json:"reason"     db:"reason"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AMLCaseEvent is one entry in a case's append-only history.  ActorID is
+// nil for entries made by the rules engine.
+type AMLCaseEvent struct {
+	ID        uuid.UUID  `json:"id"         db:"id"`
+	CaseID    uuid.UUID  `json:"case_id"    db:"case_id"`
+	ActorID   *uuid.UUID `json:"actor_id"   db:"actor_id"`
+	Action    string     `json:"action"     db:"action"` // opened|alert|hold|release|assign|note|escalate|clear|report
+	Note      string     `json:"note"       db:"note"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// AMLCaseItem is a case in the back-office queue.
+type AMLCaseItem struct {
+	AMLCase
+	Username   string `json:"username"    db:"username"`
+	AlertCount int    `json:"alert_count" db:"alert_count"`
+}
+
+// AMLCaseDetail is a case with its alerts, history and the user's
+// withdrawals it holds.
+type AMLCaseDetail struct {
+	Case        *AMLCaseItem       `json:"case"`
+	Alerts      []*AMLAlert        `json:"alerts"`
+	Events      []*AMLCaseEvent    `json:"events"`
+	Withdrawals []*WithdrawRequest `json:"withdrawals"`
+}
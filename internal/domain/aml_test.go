@@ -0,0 +1,26 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/evetabi/prediction/internal/domain"
+)
+
+func TestAMLCase_HoldsWithdrawals(t *testing.T) {
+	cases := []struct {
+		name string
+		c    *domain.AMLCase
+		want bool
+	}{
+		{"no case", nil, false},
+		{"open, not held", &domain.AMLCase{Status: domain.AMLCaseOpen}, false},
+		{"open, held", &domain.AMLCase{Status: domain.AMLCaseOpen, Hold: true}, true},
+		{"escalated, held", &domain.AMLCase{Status: domain.AMLCaseEscalated, Hold: true}, true},
+		{"cleared", &domain.AMLCase{Status: domain.AMLCaseCleared, Hold: true}, false},
+	}
+	for _, c := range cases {
+		if got := c.c.HoldsWithdrawals(); got != c.want {
+			t.Errorf("%s: HoldsWithdrawals() = %t, want %t", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// AuditEntry is a single back-office write action recorded in admin_audit_log.
+// Details carries a JSON snapshot (typically {"before": ..., "after": ...}).
+type AuditEntry struct {
+	ID         uuid.UUID      `json:"id"          db:"id"`
+	ActorID    *uuid.UUID     `json:"actor_id"    db:"actor_id"` // nil = system
+	Action     string         `json:"action"      db:"action"`
+	EntityType string         `json:"entity_type" db:"entity_type"`
+	EntityID   string         `json:"entity_id"   db:"entity_id"`
+	Details    types.JSONText `json:"details"     db:"details"`
+	CreatedAt  time.Time      `json:"created_at"  db:"created_at"`
+}
@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// BankAccountStatus represents the verification state of a saved IBAN.
+type BankAccountStatus string
+
+const (
+	BankAccountPending  BankAccountStatus = "pending"  // waiting for finance to check the holder
+	BankAccountVerified BankAccountStatus = "verified" // may receive withdrawals after the cooling-off
+	BankAccountRejected BankAccountStatus = "rejected" // holder could not be confirmed
+)
+
+// NameMatch is the result of comparing the account holder with the user's
+// registered name.
+type NameMatch string
+
+const (
+	NameMatchExact    NameMatch = "match"
+	NameMatchMismatch NameMatch = "mismatch"
+	NameMatchUnknown  NameMatch = "unknown" // user has no registered name
+)
+
+// BankAccount is an IBAN a user has saved to receive withdrawals.
+type BankAccount struct {
+	ID         uuid.UUID         `json:"id"          db:"id"`
+	UserID     uuid.UUID         `json:"user_id"     db:"user_id"`
+	IBAN       string            `json:"iban"        db:"iban"`
+	HolderName string            `json:"holder_name" db:"holder_name"`
+	Status     BankAccountStatus `json:"status"      db:"status"`
+	NameMatch  NameMatch         `json:"name_match"  db:"name_match"`
+	IsDefault  bool              `json:"is_default"  db:"is_default"`
+	UsableFrom time.Time         `json:"usable_from" db:"usable_from"` // end of the cooling-off period
+	ReviewedBy *uuid.UUID        `json:"reviewed_by" db:"reviewed_by"`
+	ReviewNote string            `json:"review_note" db:"review_note"`
+	ReviewedAt *time.Time        `json:"reviewed_at" db:"reviewed_at"`
+	CreatedAt  time.Time         `json:"created_at"  db:"created_at"`
+	DeletedAt  *time.Time        `json:"-"           db:"deleted_at"` // removed by the user
+}
+
+// CheckUsable returns nil when the account may receive a withdrawal at now,
+// otherwise ErrBankAccountNotVerified or ErrBankAccountCoolingOff.
+func (a *BankAccount) CheckUsable(now time.Time) error {
+	if a.Status != BankAccountVerified {
+		return ErrBankAccountNotVerified
+	}
+	if now.Before(a.UsableFrom) {
+		return ErrBankAccountCoolingOff
+	}
+	return nil
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// IBAN validation (ISO 13616)
+// ──────────────────────────────────────────────────────────────────────────────
+
+// ibanLengths is the IBAN length per country from the SWIFT IBAN registry.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22,
+	"BH": 22, "BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22,
+	"DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24, "FI": 18, "FO": 18, "FR": 27,
+	"GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28, "HR": 21, "HU": 28,
+	"IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30, "KZ": 20,
+	"LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27, "MD": 24,
+	"ME": 22, "MK": 19, "MR": 27, "MT": 31, "MU": 30, "NL": 18, "NO": 15, "PK": 24,
+	"PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "SA": 24, "SC": 31,
+	"SE": 24, "SI": 19, "SK": 24, "SM": 27, "ST": 25, "SV": 28, "TL": 23, "TN": 24,
+	"TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20,
+}
+
+var big97 = big.NewInt(97)
+
+// NormalizeIBAN strips spaces and upper-cases an IBAN as typed by a user.
+func NormalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(iban), ""))
+}
+
+// ValidateIBAN normalises iban and checks its country, length and mod-97
+// check digits.  Errors wrap ErrInvalidIBAN with the reason.
+func ValidateIBAN(iban string) (string, error) {
+	iban = NormalizeIBAN(iban)
+	if len(iban) < 5 {
+		return "", fmt.Errorf("%w: too short", ErrInvalidIBAN)
+	}
+	for _, r := range iban {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return "", fmt.Errorf("%w: only letters and digits are allowed", ErrInvalidIBAN)
+		}
+	}
+	want, ok := ibanLengths[iban[:2]]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown country code %s", ErrInvalidIBAN, iban[:2])
+	}
+	if len(iban) != want {
+		return "", fmt.Errorf("%w: %s IBANs have %d characters, got %d", ErrInvalidIBAN, iban[:2], want, len(iban))
+	}
+	if iban[2] < '0' || iban[2] > '9' || iban[3] < '0' || iban[3] > '9' {
+		return "", fmt.Errorf("%w: check digits must be numeric", ErrInvalidIBAN)
+	}
+
+	// Move the first four characters to the end, turn letters into 10..35
+	// and the result must be 1 mod 97.
+	var digits strings.Builder
+	for _, r := range iban[4:] + iban[:4] {
+		if r >= 'A' {
+			fmt.Fprintf(&digits, "%d", r-'A'+10)
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+	n, _ := new(big.Int).SetString(digits.String(), 10)
+	if new(big.Int).Mod(n, big97).Int64() != 1 {
+		return "", fmt.Errorf("%w: checksum does not match", ErrInvalidIBAN)
+	}
+	return iban, nil
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Holder name matching
+// ──────────────────────────────────────────────────────────────────────────────
+
+// InitialBankAccountStatus returns the status of an account whose holder
+// compares to the user's name as match.  The name is self-declared at
+// sign-up, so a match verifies the account only once KYC has confirmed it.
+func InitialBankAccountStatus(match NameMatch, kyc KYCStatus) BankAccountStatus {
+	if match == NameMatchExact && kyc == KYCVerified {
+		return BankAccountVerified
+	}
+	return BankAccountPending
+}
+
+// Recheck compares the holder with the user's current fullName and returns
+// the new name match and status for a user in KYC state kyc.  Accounts
+// finance has reviewed keep their status.
+func (a *BankAccount) Recheck(fullName string, kyc KYCStatus) (NameMatch, BankAccountStatus) {
+	match := MatchHolderName(a.HolderName, fullName)
+	if a.ReviewedBy != nil {
+		return match, a.Status
+	}
+	return match, InitialBankAccountStatus(match, kyc)
+}
+
+// NextDefault picks the account to become the default after the default
+// one is removed: the most recently added verified account, or nil.
+func NextDefault(accounts []*BankAccount) *BankAccount {
+	var next *BankAccount
+	for _, a := range accounts {
+		if a.Status != BankAccountVerified || a.DeletedAt != nil {
+			continue
+		}
+		if next == nil || a.CreatedAt.After(next.CreatedAt) {
+			next = a
+		}
+	}
+	return next
+}
+
+// foldTurkish maps Turkish letters to their ASCII base so that "Şükrü Işık"
+// and "SUKRU ISIK" compare equal.
+var foldTurkish = strings.NewReplacer(
+	"ç", "c", "ğ", "g", "ı", "i", "ö", "o", "ş", "s", "ü", "u", "â", "a", "î", "i", "û", "u",
+)
+
+// nameTokens lower-cases, folds and splits a personal name into words.
+func nameTokens(name string) []string {
+	name = foldTurkish.Replace(strings.ToLowerSpecial(unicode.TurkishCase, name))
+	return strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) })
+}
+
+// MatchHolderName compares a bank account holder with the user's registered
+// full name, ignoring case, Turkish diacritics and punctuation.  Names match
+// when all words agree or, to allow an omitted middle name, when the first
+// and last words agree.
+func MatchHolderName(holder, fullName string) NameMatch {
+	want := nameTokens(fullName)
+	if len(want) == 0 {
+		return NameMatchUnknown
+	}
+	got := nameTokens(holder)
+	if len(got) == 0 {
+		return NameMatchMismatch
+	}
+	if strings.Join(got, " ") == strings.Join(want, " ") {
+		return NameMatchExact
+	}
+	if len(got) >= 2 && len(want) >= 2 &&
+		got[0] == want[0] && got[len(got)-1] == want[len(want)-1] {
+		return NameMatchExact
+	}
+	return NameMatchMismatch
+}
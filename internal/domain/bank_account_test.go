@@ -0,0 +1,123 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestValidateIBAN(t *testing.T) {
+	valid := map[string]string{
+		"TR330006100519786457841326":      "TR330006100519786457841326",
+		"tr33 0006 1005 1978 6457 841326": "TR330006100519786457841326",
+		"GB82WEST12345698765432":          "GB82WEST12345698765432",
+		"DE89 3704 0044 0532 0130 00":     "DE89370400440532013000",
+	}
+	for in, want := range valid {
+		got, err := domain.ValidateIBAN(in)
+		if err != nil || got != want {
+			t.Errorf("ValidateIBAN(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"TR34",
+		"TR340006100519786457841326",  // wrong check digits
+		"TR33000610051978645784132",   // too short for TR
+		"TR3300061005197864578413266", // too long for TR
+		"XX330006100519786457841326",  // unknown country
+		"TR33-0006-1005-1978-6457-8413",
+		"TRAB0006100519786457841326",
+	}
+	for _, in := range invalid {
+		if _, err := domain.ValidateIBAN(in); !errors.Is(err, domain.ErrInvalidIBAN) {
+			t.Errorf("ValidateIBAN(%q) err = %v, want ErrInvalidIBAN", in, err)
+		}
+	}
+}
+
+func TestMatchHolderName(t *testing.T) {
+	cases := []struct {
+		holder, full string
+		want         domain.NameMatch
+	}{
+		{"Şükrü Işık", "SUKRU ISIK", domain.NameMatchExact},
+		{"AYŞE  NUR YILMAZ", "ayşe yılmaz", domain.NameMatchExact},
+		{"Mehmet Ali Kaya", "Mehmet Ali Kaya", domain.NameMatchExact},
+		{"İbrahim Çelik", "IBRAHIM CELIK", domain.NameMatchExact},
+		{"Ayşe Demir", "Ayşe Yılmaz", domain.NameMatchMismatch},
+		{"Yılmaz", "Ayşe Yılmaz", domain.NameMatchMismatch},
+		{"Ayşe Yılmaz", "", domain.NameMatchUnknown},
+	}
+	for _, c := range cases {
+		if got := domain.MatchHolderName(c.holder, c.full); got != c.want {
+			t.Errorf("MatchHolderName(%q, %q) = %s, want %s", c.holder, c.full, got, c.want)
+		}
+	}
+}
+
+func TestBankAccount_CheckUsable(t *testing.T) {
+	now := time.Now()
+	acct := &domain.BankAccount{Status: domain.BankAccountPending, UsableFrom: now.Add(-time.Hour)}
+	if err := acct.CheckUsable(now); !errors.Is(err, domain.ErrBankAccountNotVerified) {
+		t.Errorf("pending: err = %v", err)
+	}
+	acct.Status = domain.BankAccountVerified
+	acct.UsableFrom = now.Add(time.Hour)
+	if err := acct.CheckUsable(now); !errors.Is(err, domain.ErrBankAccountCoolingOff) {
+		t.Errorf("cooling off: err = %v", err)
+	}
+	if err := acct.CheckUsable(now.Add(2 * time.Hour)); err != nil {
+		t.Errorf("after cooling off: err = %v", err)
+	}
+}
+
+func TestInitialBankAccountStatus(t *testing.T) {
+	// A self-declared name is not enough to verify an account.
+	if got := domain.InitialBankAccountStatus(domain.NameMatchExact, domain.KYCUnverified); got != domain.BankAccountPending {
+		t.Errorf("match, KYC unverified = %s, want pending", got)
+	}
+	if got := domain.InitialBankAccountStatus(domain.NameMatchExact, domain.KYCVerified); got != domain.BankAccountVerified {
+		t.Errorf("match, KYC verified = %s, want verified", got)
+	}
+	if got := domain.InitialBankAccountStatus(domain.NameMatchMismatch, domain.KYCVerified); got != domain.BankAccountPending {
+		t.Errorf("mismatch, KYC verified = %s, want pending", got)
+	}
+}
+
+func TestBankAccount_Recheck(t *testing.T) {
+	auto := &domain.BankAccount{HolderName: "Ayse Yilmaz", Status: domain.BankAccountVerified, NameMatch: domain.NameMatchExact}
+	match, status := auto.Recheck("Fatma Demir", domain.KYCVerified)
+	if match != domain.NameMatchMismatch || status != domain.BankAccountPending {
+		t.Errorf("auto-verified after name change = %s/%s, want mismatch/pending", match, status)
+	}
+
+	pending := &domain.BankAccount{HolderName: "Ayse Yilmaz", Status: domain.BankAccountPending, NameMatch: domain.NameMatchExact}
+	if _, status = pending.Recheck("Ayşe Yılmaz", domain.KYCVerified); status != domain.BankAccountVerified {
+		t.Errorf("pending matching account after KYC = %s, want verified", status)
+	}
+
+	admin := uuid.New()
+	reviewed := &domain.BankAccount{HolderName: "Ayse Yilmaz", Status: domain.BankAccountVerified, ReviewedBy: &admin}
+	if _, status = reviewed.Recheck("Fatma Demir", domain.KYCVerified); status != domain.BankAccountVerified {
+		t.Errorf("finance-reviewed account = %s, want verified", status)
+	}
+}
+
+func TestNextDefault(t *testing.T) {
+	now := time.Now()
+	older := &domain.BankAccount{ID: uuid.New(), Status: domain.BankAccountVerified, CreatedAt: now.Add(-2 * time.Hour)}
+	newer := &domain.BankAccount{ID: uuid.New(), Status: domain.BankAccountVerified, CreatedAt: now.Add(-time.Hour)}
+	pending := &domain.BankAccount{ID: uuid.New(), Status: domain.BankAccountPending, CreatedAt: now}
+
+	if got := domain.NextDefault([]*domain.BankAccount{older, newer, pending}); got != newer {
+		t.Errorf("NextDefault = %v, want the most recent verified account", got)
+	}
+	if got := domain.NextDefault([]*domain.BankAccount{pending}); got != nil {
+		t.Errorf("NextDefault with no verified account = %v, want nil", got)
+	}
+}
@@ -25,6 +25,9 @@ const (
 // CashoutFeeRate is the fee taken on early exit (5 %).
 var CashoutFeeRate = decimal.NewFromFloat(0.05)
 
+// MinBetAmount is the smallest stake accepted (TRY).
+var MinBetAmount = decimal.NewFromInt(10)
+
 // ──────────────────────────────────────────────────────────────────────────────
 // Bet
 // ──────────────────────────────────────────────────────────────────────────────
@@ -36,6 +39,8 @@ type Bet struct {
 	MarketID      uuid.UUID        `json:"market_id"      db:"market_id"`
 	Direction     Outcome          `json:"direction"      db:"direction"`
 	Amount        decimal.Decimal  `json:"amount"         db:"amount"`
+	BonusAmount   decimal.Decimal  `json:"bonus_amount"   db:"bonus_amount"` // part of Amount staked from the bonus balance
+	FreeBetID     *uuid.UUID       `json:"free_bet_id"    db:"free_bet_id"`  // stake paid by a free-bet token
 	OddsAtEntry   decimal.Decimal  `json:"odds_at_entry"  db:"odds_at_entry"`
 	Status        BetStatus        `json:"status"         db:"status"`
 	Payout        *decimal.Decimal `json:"payout"        db:"payout"`
@@ -50,6 +55,25 @@ func (b *Bet) IsActive() bool {
 	return b.Status == BetStatusActive
 }
 
+// IsFreeBet reports whether the stake was paid by a free-bet token.  Such a
+// bet returns only its winnings to the user; the stake goes back to the house.
+func (b *Bet) IsFreeBet() bool {
+	return b.FreeBetID != nil
+}
+
+// BonusShare returns the part of amount (a payout, refund or exit value of
+// this bet) that belongs to the bonus balance, in proportion to the bonus
+// part of the stake.  The real-money part is amount minus the result.
+func (b *Bet) BonusShare(amount decimal.Decimal) decimal.Decimal {
+	if !b.BonusAmount.IsPositive() || !b.Amount.IsPositive() {
+		return decimal.Zero
+	}
+	if b.BonusAmount.GreaterThanOrEqual(b.Amount) {
+		return amount
+	}
+	return amount.Mul(b.BonusAmount).Div(b.Amount).RoundDown(4)
+}
+
 // CalculateExitAmount computes how much TRY a user receives when cashing out early.
 //
 // Formula:
@@ -89,6 +113,7 @@ type PlaceBetRequest struct {
 	MarketID  uuid.UUID
 	Direction Outcome
 	Amount    decimal.Decimal
+	FreeBetID *uuid.UUID // stake with this free-bet token; Amount is ignored
 }
 
 // BetResponse is the API-safe view of a bet (no internal IDs leaked beyond IDs).
@@ -97,6 +122,8 @@ type BetResponse struct {
 	MarketID      uuid.UUID        `json:"market_id"`
 	Direction     Outcome          `json:"direction"`
 	Amount        decimal.Decimal  `json:"amount"`
+	BonusAmount   decimal.Decimal  `json:"bonus_amount"`
+	FreeBetID     *uuid.UUID       `json:"free_bet_id,omitempty"`
 	OddsAtEntry   decimal.Decimal  `json:"odds_at_entry"`
 	Status        BetStatus        `json:"status"`
 	Payout        *decimal.Decimal `json:"payout,omitempty"`
@@ -112,6 +139,8 @@ func (b *Bet) ToResponse() BetResponse {
 		MarketID:      b.MarketID,
 		Direction:     b.Direction,
 		Amount:        b.Amount,
+		BonusAmount:   b.BonusAmount,
+		FreeBetID:     b.FreeBetID,
 		OddsAtEntry:   b.OddsAtEntry,
 		Status:        b.Status,
 		Payout:        b.Payout,
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// BonusStatus represents the lifecycle state of a bonus grant.
+type BonusStatus string
+
+const (
+	BonusActive    BonusStatus = "active"    // being wagered
+	BonusConverted BonusStatus = "converted" // wagering met; balance moved to the wallet
+	BonusExpired   BonusStatus = "expired"   // wagering not met in time; balance returned to the house
+	BonusForfeited BonusStatus = "forfeited" // given up, e.g. to withdraw; balance returned to the house
+)
+
+// Bonus grant reasons.
+const (
+	BonusReasonSignup = "signup"
+)
+
+// BonusGrant is one bonus credited to a user's bonus balance together with
+// the turnover that must be staked before it becomes withdrawable.
+type BonusGrant struct {
+	ID               uuid.UUID       `json:"id"                db:"id"`
+	UserID           uuid.UUID       `json:"user_id"           db:"user_id"`
+	Amount           decimal.Decimal `json:"amount"            db:"amount"`
+	WageringRequired decimal.Decimal `json:"wagering_required" db:"wagering_required"`
+	Wagered          decimal.Decimal `json:"wagered"           db:"wagered"`
+	Status           BonusStatus     `json:"status"            db:"status"`
+	Reason           string          `json:"reason"            db:"reason"`
+	CreatedBy        *uuid.UUID      `json:"created_by"        db:"created_by"`
+	CreatedAt        time.Time       `json:"created_at"        db:"created_at"`
+	ExpiresAt        time.Time       `json:"expires_at"        db:"expires_at"`
+	ClosedAt         *time.Time      `json:"closed_at"         db:"closed_at"`
+}
+
+// WageringMet reports whether enough has been staked to convert the bonus.
+func (g *BonusGrant) WageringMet() bool {
+	return g.Wagered.GreaterThanOrEqual(g.WageringRequired)
+}
+
+// WageringRemaining returns the turnover still to be staked, never negative.
+func (g *BonusGrant) WageringRemaining() decimal.Decimal {
+	if g.WageringMet() {
+		return decimal.Zero
+	}
+	return g.WageringRequired.Sub(g.Wagered)
+}
+
+// WageringStake returns what a user's stakes on one settled market count
+// toward a bonus's wagering requirement: the net stake on one side.  Stakes
+// on both outcomes cancel out, so hedging a market, which risks little more
+// than the commission, does not clear the bonus.
+func WageringStake(up, down decimal.Decimal) decimal.Decimal {
+	return up.Sub(down).Abs()
+}
+
+// SplitStake divides a stake between the real-money and bonus balances.
+// Real money is used first so that bonus funds are only at risk once the
+// withdrawable balance is exhausted; any shortfall is left for the ledger to
+// reject.
+func SplitStake(amount, available, bonus decimal.Decimal) (real, fromBonus decimal.Decimal) {
+	if available.IsNegative() {
+		available = decimal.Zero
+	}
+	if amount.LessThanOrEqual(available) || !bonus.IsPositive() {
+		return amount, decimal.Zero
+	}
+	fromBonus = decimal.Min(amount.Sub(available), bonus)
+	return amount.Sub(fromBonus), fromBonus
+}
+
+// BonusSummary is the bonus part of a user's balance view.
+type BonusSummary struct {
+	Balance decimal.Decimal `json:"balance"`
+	Active  *BonusGrant     `json:"active"` // nil when no bonus is being wagered
+}
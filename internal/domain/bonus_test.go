@@ -0,0 +1,78 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func TestWageringStake(t *testing.T) {
+	d := decimal.RequireFromString
+	cases := []struct {
+		up, down, want string
+	}{
+		{"100", "0", "100"},
+		{"0", "250", "250"},
+		{"100", "100", "0"}, // fully hedged: nothing counts
+		{"300", "100", "200"},
+		{"100", "400", "300"},
+	}
+	for _, c := range cases {
+		if got := domain.WageringStake(d(c.up), d(c.down)); !got.Equal(d(c.want)) {
+			t.Errorf("WageringStake(%s, %s) = %s; want %s", c.up, c.down, got, c.want)
+		}
+	}
+}
+
+func TestSplitStake(t *testing.T) {
+	d := decimal.RequireFromString
+	cases := []struct {
+		amount, available, bonus string
+		real, fromBonus          string
+	}{
+		{"100", "500", "1000", "100", "0"},
+		{"100", "40", "1000", "40", "60"},
+		{"100", "0", "1000", "0", "100"},
+		{"100", "40", "30", "70", "30"}, // shortfall stays on the real side
+		{"100", "-5", "1000", "0", "100"},
+		{"100", "40", "0", "100", "0"},
+	}
+	for _, c := range cases {
+		real, fromBonus := domain.SplitStake(d(c.amount), d(c.available), d(c.bonus))
+		if !real.Equal(d(c.real)) || !fromBonus.Equal(d(c.fromBonus)) {
+			t.Errorf("SplitStake(%s, %s, %s) = %s, %s; want %s, %s",
+				c.amount, c.available, c.bonus, real, fromBonus, c.real, c.fromBonus)
+		}
+	}
+}
+
+func TestBet_BonusShare(t *testing.T) {
+	d := decimal.RequireFromString
+	cases := []struct {
+		amount, bonus, value, want string
+	}{
+		{"100", "0", "250", "0"},
+		{"100", "100", "250", "250"},
+		{"100", "40", "250", "100"},
+		{"30", "10", "100", "33.3333"},
+	}
+	for _, c := range cases {
+		b := &domain.Bet{Amount: d(c.amount), BonusAmount: d(c.bonus)}
+		if got := b.BonusShare(d(c.value)); !got.Equal(d(c.want)) {
+			t.Errorf("BonusShare(%s) with %s/%s = %s, want %s", c.value, c.bonus, c.amount, got, c.want)
+		}
+	}
+}
+
+func TestBonusGrant_Wagering(t *testing.T) {
+	d := decimal.RequireFromString
+	g := &domain.BonusGrant{WageringRequired: d("10000"), Wagered: d("2500")}
+	if g.WageringMet() || !g.WageringRemaining().Equal(d("7500")) {
+		t.Errorf("partial: met=%v remaining=%s", g.WageringMet(), g.WageringRemaining())
+	}
+	g.Wagered = d("10400")
+	if !g.WageringMet() || !g.WageringRemaining().IsZero() {
+		t.Errorf("over: met=%v remaining=%s", g.WageringMet(), g.WageringRemaining())
+	}
+}
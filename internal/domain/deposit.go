@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// DepositStatus represents the lifecycle of a deposit.
+type DepositStatus string
+
+const (
+	DepositPending   DepositStatus = "pending"   // intent created, waiting for the provider
+	DepositCompleted DepositStatus = "completed" // provider confirmed, wallet credited
+	DepositFailed    DepositStatus = "failed"    // provider declined or amount mismatch
+	DepositExpired   DepositStatus = "expired"   // no confirmation within the TTL
+)
+
+// Deposit is a user's payment into their wallet through a payment provider.
+type Deposit struct {
+	ID            uuid.UUID       `json:"id"             db:"id"`
+	UserID        uuid.UUID       `json:"user_id"        db:"user_id"`
+	Amount        decimal.Decimal `json:"amount"         db:"amount"`
+	Status        DepositStatus   `json:"status"         db:"status"`
+	Provider      string          `json:"provider"       db:"provider"`
+	ProviderRef   string          `json:"provider_ref"   db:"provider_ref"`
+	CheckoutURL   string          `json:"checkout_url"   db:"checkout_url"`
+	FailureReason string          `json:"failure_reason" db:"failure_reason"`
+	EntryID       *uuid.UUID      `json:"entry_id"       db:"entry_id"` // ledger entry of the credit
+	CreatedAt     time.Time       `json:"created_at"     db:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"     db:"updated_at"`
+	CompletedAt   *time.Time      `json:"completed_at"   db:"completed_at"`
+}
+
+// IsFinal returns true once the deposit can no longer change.
+func (d *Deposit) IsFinal() bool {
+	return d.Status != DepositPending
+}
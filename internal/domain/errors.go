@@ -58,10 +58,18 @@ var (
 	// ErrUserInactive is returned when a suspended/banned user attempts an action.
 	ErrUserInactive = errors.New("user account is inactive")
 
+	// ErrSelfRoleChange is returned when a back-office user tries to change
+	// their own role.
+	ErrSelfRoleChange = errors.New("you cannot change your own role")
+
 	// ErrInsufficientBalance is returned when a user's available balance is too
 	// low to place a bet or make a withdrawal.
 	ErrInsufficientBalance = errors.New("insufficient wallet balance")
 
+	// ErrInsufficientLocked is returned when releasing a hold larger than the
+	// amount locked on the wallet, which means the holds are out of step.
+	ErrInsufficientLocked = errors.New("locked balance is less than the hold released")
+
 	// ErrWithdrawLimitExceeded is returned when a withdrawal would breach the
 	// user's daily or per-transaction limit.
 	ErrWithdrawLimitExceeded = errors.New("withdrawal limit exceeded")
@@ -72,6 +80,256 @@ var (
 
 	// ErrWalletNotFound is returned when no wallet exists for the requested user.
 	ErrWalletNotFound = errors.New("wallet not found")
+
+	// ErrWalletFrozen is returned when a debit hits a wallet frozen after a
+	// failed reconciliation.
+	ErrWalletFrozen = errors.New("wallet is frozen")
+
+	// ErrInvalidCursor is returned when a transaction history cursor cannot
+	// be decoded.
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrExportTooLarge is returned when a statement export would hold more
+	// transactions than allowed; the caller should narrow the period.
+	ErrExportTooLarge = errors.New("too many transactions to export")
+
+	// ErrStatementNotFound is returned when the user has no statement for
+	// the month.
+	ErrStatementNotFound = errors.New("statement not found")
+
+	// ErrInvalidStatementMonth is returned for a month that is malformed or
+	// not yet over.
+	ErrInvalidStatementMonth = errors.New("statement month must be a completed month, YYYY-MM")
+
+	// ErrReconciliationRunNotFound is returned when no reconciliation run
+	// matches the ID.
+	ErrReconciliationRunNotFound = errors.New("reconciliation run not found")
+
+	// ErrReconciliationRunning is returned when a reconciliation pass is
+	// already in progress in some process.
+	ErrReconciliationRunning = errors.New("reconciliation already running")
+)
+
+// Withdrawal errors
+var (
+	// ErrWithdrawalNotFound is returned when no withdrawal request matches
+	// the ID (or it belongs to another user).
+	ErrWithdrawalNotFound = errors.New("withdrawal request not found")
+
+	// ErrWithdrawalState is returned when a withdrawal action does not apply
+	// to the request's current status, e.g. approving a cancelled request.
+	ErrWithdrawalState = errors.New("withdrawal request is not in a state that allows this action")
+
+	// ErrPayoutBatchNotFound is returned when no payout batch matches the ID.
+	ErrPayoutBatchNotFound = errors.New("payout batch not found")
+
+	// ErrPayoutBatchEmpty is returned when a batch is requested but there are
+	// no approved withdrawals to put in it.
+	ErrPayoutBatchEmpty = errors.New("no approved withdrawals to batch")
+
+	// ErrPayoutHolderMissing is returned when a batch is exported with a
+	// withdrawal whose account holder name is unknown.
+	ErrPayoutHolderMissing = errors.New("withdrawal has no account holder name")
+)
+
+// Bank account errors
+var (
+	// ErrInvalidIBAN is returned when an IBAN fails the ISO 13616 checks;
+	// the wrapping error says which.
+	ErrInvalidIBAN = errors.New("invalid IBAN")
+
+	// ErrBankAccountNotFound is returned when no saved bank account matches
+	// the ID (or it belongs to another user).
+	ErrBankAccountNotFound = errors.New("bank account not found")
+
+	// ErrBankAccountExists is returned when the IBAN is already saved.
+	ErrBankAccountExists = errors.New("bank account already registered")
+
+	// ErrBankAccountLimit is returned when a user already has the maximum
+	// number of saved bank accounts.
+	ErrBankAccountLimit = errors.New("too many bank accounts")
+
+	// ErrBankAccountNotVerified is returned when a withdrawal targets an
+	// account finance has not verified.
+	ErrBankAccountNotVerified = errors.New("bank account is not verified")
+
+	// ErrBankAccountCoolingOff is returned when a withdrawal targets an
+	// account still inside its cooling-off period.
+	ErrBankAccountCoolingOff = errors.New("bank account was added recently and cannot receive withdrawals yet")
+)
+
+// Bonus errors
+var (
+	// ErrBonusNotFound is returned when the user has no active bonus.
+	ErrBonusNotFound = errors.New("no active bonus")
+
+	// ErrBonusActive is returned when a bonus is granted to a user who is
+	// still wagering another, or when a withdrawal is requested without
+	// forfeiting the active bonus.
+	ErrBonusActive = errors.New("an active bonus exists")
+)
+
+// Promotion errors
+var (
+	// ErrPromotionNotFound is returned when no promotion matches the ID or
+	// promo code.
+	ErrPromotionNotFound = errors.New("promotion not found")
+
+	// ErrInvalidPromotion is returned when a campaign definition is invalid;
+	// the wrapping error says why.
+	ErrInvalidPromotion = errors.New("invalid promotion")
+
+	// ErrPromoCodeTaken is returned when another campaign uses the code.
+	ErrPromoCodeTaken = errors.New("promo code already in use")
+
+	// ErrPromotionNotActive is returned when a code is redeemed outside its
+	// campaign window or after the campaign was switched off.
+	ErrPromotionNotActive = errors.New("promotion is not active")
+
+	// ErrPromotionExhausted is returned when the campaign's global
+	// redemption cap has been reached.
+	ErrPromotionExhausted = errors.New("promotion has no redemptions left")
+
+	// ErrPromotionAlreadyRedeemed is returned when the user has used up the
+	// campaign's per-user limit.
+	ErrPromotionAlreadyRedeemed = errors.New("promotion already redeemed")
+
+	// ErrPromotionNotEligible is returned when the user does not meet the
+	// campaign's eligibility rules.
+	ErrPromotionNotEligible = errors.New("not eligible for this promotion")
+
+	// ErrFreeBetNotFound is returned when a free bet does not exist, belongs
+	// to another user, or is no longer available.
+	ErrFreeBetNotFound = errors.New("free bet not found or no longer available")
+
+	// ErrFreeBetNoExit is returned when an early exit is attempted on a bet
+	// placed with a free bet.
+	ErrFreeBetNoExit = errors.New("bets placed with a free bet cannot be cashed out early")
+)
+
+// Balance adjustment errors
+var (
+	// ErrAdjustmentNotFound is returned when no balance adjustment matches
+	// the ID.
+	ErrAdjustmentNotFound = errors.New("balance adjustment not found")
+
+	// ErrInvalidAdjustment is returned for an adjustment with a zero amount,
+	// an unknown reason or no note.
+	ErrInvalidAdjustment = errors.New("invalid balance adjustment")
+
+	// ErrAdjustmentState is returned when an adjustment has already been
+	// approved or rejected.
+	ErrAdjustmentState = errors.New("balance adjustment is not pending")
+
+	// ErrSelfApproval is returned when the admin who requested an adjustment
+	// tries to approve it, or when the requester or approver is the user
+	// whose balance it changes.
+	ErrSelfApproval = errors.New("an adjustment must be requested and approved by two admins other than its user")
+)
+
+// Referral errors
+var (
+	// ErrReferralCodeNotFound is returned when a signup names an invite code
+	// that belongs to no active user.
+	ErrReferralCodeNotFound = errors.New("referral code not found")
+
+	// ErrReferralNotFound is returned when no referral matches the ID.
+	ErrReferralNotFound = errors.New("referral not found")
+)
+
+// Responsible gambling errors
+var (
+	// ErrInvalidLimit is returned for an unknown limit kind or period or a
+	// non-positive amount.
+	ErrInvalidLimit = errors.New("invalid gambling limit")
+
+	// ErrLimitExceeded is returned when a bet or deposit would exceed one of
+	// the user's limits; the wrapping error names the limit.
+	ErrLimitExceeded = errors.New("gambling limit exceeded")
+
+	// ErrCoolingOff is returned when a user in a cooling-off period tries to
+	// bet or deposit.
+	ErrCoolingOff = errors.New("account is in a cooling-off period")
+
+	// ErrSelfExcluded is returned when a self-excluded user tries to log in,
+	// bet or deposit.
+	ErrSelfExcluded = errors.New("account is self-excluded")
+
+	// ErrInvalidExclusion is returned for an unknown exclusion kind or a
+	// duration outside the allowed range.
+	ErrInvalidExclusion = errors.New("invalid exclusion period")
+
+	// ErrExclusionActive is returned when an exclusion at least as strict as
+	// the requested one is already in force.
+	ErrExclusionActive = errors.New("a stricter exclusion is already in force")
+)
+
+// KYC errors
+var (
+	// ErrKYCRequired is returned when an unverified user asks to withdraw
+	// more than the thresholds allow.
+	ErrKYCRequired = errors.New("identity verification is required for this withdrawal")
+
+	// ErrKYCState is returned when documents are uploaded or submitted while
+	// a submission is pending or the user is verified, or a submission that
+	// is no longer pending is reviewed.
+	ErrKYCState = errors.New("verification is not in a state that allows this action")
+
+	// ErrKYCInvalidDocument is returned for an unknown document kind or a
+	// file that is empty or not a JPEG, PNG or PDF.
+	ErrKYCInvalidDocument = errors.New("invalid KYC document")
+
+	// ErrKYCDocumentTooLarge is returned for a file over the size limit.
+	ErrKYCDocumentTooLarge = errors.New("KYC document is too large")
+
+	// ErrKYCIdentityMissing is returned when a submission has no photo
+	// identity document.
+	ErrKYCIdentityMissing = errors.New("an identity document (id card, passport or driving licence) is required")
+
+	// ErrKYCSubmissionNotFound is returned when no submission matches the ID.
+	ErrKYCSubmissionNotFound = errors.New("KYC submission not found")
+
+	// ErrKYCDocumentNotFound is returned when no document matches the ID or
+	// its file is missing from storage.
+	ErrKYCDocumentNotFound = errors.New("KYC document not found")
+)
+
+// AML errors
+var (
+	// ErrAMLCaseNotFound is returned when no AML case matches the ID.
+	ErrAMLCaseNotFound = errors.New("AML case not found")
+
+	// ErrAMLCaseState is returned when a case action does not apply to the
+	// case's current status, e.g. escalating a closed case.
+	ErrAMLCaseState = errors.New("AML case is not in a state that allows this action")
+
+	// ErrWithdrawalHeld is returned when approving or paying out a withdrawal
+	// held by an AML case; the case must be cleared first, or the request
+	// rejected.
+	ErrWithdrawalHeld = errors.New("withdrawal is on AML hold")
+)
+
+// Deposit errors
+var (
+	// ErrDepositNotFound is returned when no deposit matches the ID or the
+	// provider reference.
+	ErrDepositNotFound = errors.New("deposit not found")
+
+	// ErrInvalidDepositAmount is returned when a deposit is outside the
+	// configured minimum and maximum.
+	ErrInvalidDepositAmount = errors.New("deposit amount is outside the allowed range")
+
+	// ErrDepositState is returned when a deposit is already final and cannot
+	// be closed again.
+	ErrDepositState = errors.New("deposit is already final")
+
+	// ErrInvalidWebhookSignature is returned when a payment webhook's
+	// signature does not verify or its timestamp is stale.
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+	// ErrUnknownPaymentProvider is returned when a webhook names a provider
+	// that is not configured.
+	ErrUnknownPaymentProvider = errors.New("unknown payment provider")
 )
 
 // Market Maker errors
@@ -80,9 +338,37 @@ var (
 	// configured minimum and MM injection is blocked.
 	ErrMMReserveInsufficient = errors.New("market maker reserve is below minimum threshold")
 
-	// ErrMMDailyLossExceeded is returned when the Daily Loss Limit is reached and
-	// the MM will no longer inject liquidity for the day.
+	// ErrMMDailyLossExceeded is returned when realized plus worst-case open
+	// losses would exceed the daily MM loss budget.
 	ErrMMDailyLossExceeded = errors.New("market maker daily loss limit exceeded")
+
+	// ErrMMWeeklyLossExceeded is the weekly counterpart of ErrMMDailyLossExceeded.
+	ErrMMWeeklyLossExceeded = errors.New("market maker weekly loss limit exceeded")
+
+	// ErrMMDisabled is returned when the MM kill switch is off globally or for
+	// the market being rebalanced.
+	ErrMMDisabled = errors.New("market maker is disabled")
+
+	// ErrInvalidMMConfig is returned when an MM config override has an invalid
+	// scope or an out-of-range parameter.
+	ErrInvalidMMConfig = errors.New("invalid market maker configuration")
+
+	// ErrMMConfigNotFound is returned when no active override exists for a scope.
+	ErrMMConfigNotFound = errors.New("market maker config override not found")
+)
+
+// Price source errors
+var (
+	// ErrPriceSourceNotFound is returned when no price source matches the name.
+	ErrPriceSourceNotFound = errors.New("price source not found")
+
+	// ErrInvalidPriceSource is returned when a price source update carries an
+	// out-of-range weight or an empty symbol.
+	ErrInvalidPriceSource = errors.New("invalid price source configuration")
+
+	// ErrNoActivePriceSource is returned when an update would leave no enabled
+	// source with a non-zero weight.
+	ErrNoActivePriceSource = errors.New("at least one price source must be enabled with a non-zero weight")
 )
 
 // Auth errors
@@ -99,6 +385,10 @@ var (
 	// ErrTokenInvalid is returned when a token cannot be parsed or its signature
 	// does not match.
 	ErrTokenInvalid = errors.New("token is invalid")
+
+	// ErrTokenReused is returned when a refresh token that was already
+	// exchanged is presented again; every token of its login is revoked.
+	ErrTokenReused = errors.New("refresh token was already used")
 )
 
 // ──────────────────────────────────────────────────────────────────────────────
@@ -112,6 +402,23 @@ var notFoundErrors = []error{
 	ErrUserNotFound,
 	ErrWalletNotFound,
 	ErrNoOpenMarket,
+	ErrPriceSourceNotFound,
+	ErrMMConfigNotFound,
+	ErrReconciliationRunNotFound,
+	ErrDepositNotFound,
+	ErrWithdrawalNotFound,
+	ErrPayoutBatchNotFound,
+	ErrBankAccountNotFound,
+	ErrBonusNotFound,
+	ErrPromotionNotFound,
+	ErrFreeBetNotFound,
+	ErrReferralCodeNotFound,
+	ErrReferralNotFound,
+	ErrKYCSubmissionNotFound,
+	ErrKYCDocumentNotFound,
+	ErrAMLCaseNotFound,
+	ErrStatementNotFound,
+	ErrAdjustmentNotFound,
 }
 
 // IsNotFound returns true when err (or any error in its chain) is one of the
@@ -135,6 +442,17 @@ func IsConflict(err error) bool {
 		ErrMarketAlreadyResolved,
 		ErrBetAlreadyResolved,
 		ErrMarketNotOpen,
+		ErrReconciliationRunning,
+		ErrWithdrawalState,
+		ErrBankAccountExists,
+		ErrBonusActive,
+		ErrPromoCodeTaken,
+		ErrPromotionAlreadyRedeemed,
+		ErrExclusionActive,
+		ErrKYCState,
+		ErrAMLCaseState,
+		ErrWithdrawalHeld,
+		ErrAdjustmentState,
 	}
 	for _, target := range conflictErrors {
 		if errors.Is(err, target) {
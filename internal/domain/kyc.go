@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// KYCStatus is a user's identity verification state (users.kyc_status).
+// A submission is pending, verified or rejected.
+type KYCStatus string
+
+const (
+	KYCUnverified KYCStatus = "unverified" // nothing submitted yet
+	KYCPending    KYCStatus = "pending"    // documents waiting for review
+	KYCVerified   KYCStatus = "verified"   // identity confirmed by risk
+	KYCRejected   KYCStatus = "rejected"   // documents refused; the user may submit again
+)
+
+// CanSubmit reports whether a user in status s may upload and submit
+// documents.
+func (s KYCStatus) CanSubmit() bool {
+	return s == KYCUnverified || s == KYCRejected
+}
+
+// KYCDocumentKind is what an uploaded document shows.
+type KYCDocumentKind string
+
+const (
+	KYCIDCard         KYCDocumentKind = "id_card"
+	KYCPassport       KYCDocumentKind = "passport"
+	KYCDrivingLicense KYCDocumentKind = "driving_license"
+	KYCProofOfAddress KYCDocumentKind = "proof_of_address"
+	KYCSelfie         KYCDocumentKind = "selfie"
+)
+
+// IsValid reports whether k is a known document kind.
+func (k KYCDocumentKind) IsValid() bool {
+	switch k {
+	case KYCIDCard, KYCPassport, KYCDrivingLicense, KYCProofOfAddress, KYCSelfie:
+		return true
+	}
+	return false
+}
+
+// IsIdentity reports whether k is a photo identity document; every
+// submission needs at least one.
+func (k KYCDocumentKind) IsIdentity() bool {
+	return k == KYCIDCard || k == KYCPassport || k == KYCDrivingLicense
+}
+
+// KYCContentTypes are the file types accepted for documents, as sniffed
+// from the content rather than taken from the upload.
+var KYCContentTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"application/pdf": ".pdf",
+}
+
+// KYCSubmission is one set of documents sent for review.
+type KYCSubmission struct {
+	ID          uuid.UUID  `json:"id"           db:"id"`
+	UserID      uuid.UUID  `json:"user_id"      db:"user_id"`
+	Status      KYCStatus  `json:"status"       db:"status"` // pending|verified|rejected
+	SubmittedAt time.Time  `json:"submitted_at" db:"submitted_at"`
+	ReviewedBy  *uuid.UUID `json:"reviewed_by"  db:"reviewed_by"`
+	ReviewedAt  *time.Time `json:"reviewed_at"  db:"reviewed_at"`
+	ReviewNote  *string    `json:"review_note"  db:"review_note"`
+}
+
+// KYCDocument is an uploaded file.  The bytes live in file storage under
+// StorageKey; SubmissionID is nil until the user submits it.
+type KYCDocument struct {
+	ID           uuid.UUID       `json:"id"            db:"id"`
+	UserID       uuid.UUID       `json:"user_id"       db:"user_id"`
+	SubmissionID *uuid.UUID      `json:"submission_id" db:"submission_id"`
+	Kind         KYCDocumentKind `json:"kind"          db:"kind"`
+	StorageKey   string          `json:"-"             db:"storage_key"`
+	FileName     string          `json:"file_name"     db:"file_name"`
+	ContentType  string          `json:"content_type"  db:"content_type"`
+	SizeBytes    int64           `json:"size_bytes"    db:"size_bytes"`
+	SHA256       string          `json:"sha256"        db:"sha256"`
+	CreatedAt    time.Time       `json:"created_at"    db:"created_at"`
+}
+
+// KYCSubmissionItem is a submission with the user's details for the
+// review queue.
+type KYCSubmissionItem struct {
+	KYCSubmission
+	Username string `json:"username"  db:"username"`
+	Email    string `json:"email"     db:"email"`
+	FullName string `json:"full_name" db:"full_name"`
+}
+
+// KYCOverview is what a user sees of their verification: the status, the
+// latest submission and the documents uploaded but not yet submitted.
+type KYCOverview struct {
+	Status     KYCStatus      `json:"status"`
+	Submission *KYCSubmission `json:"submission"`
+	Documents  []*KYCDocument `json:"documents"`
+}
+
+// KYCRequired reports whether a withdrawal of amount by an unverified user
+// who has already withdrawn withdrawn needs verification: it is above the
+// single threshold, or takes the user's total above the cumulative one.  A
+// zero threshold is not enforced.
+func KYCRequired(amount, withdrawn, single, cumulative decimal.Decimal) bool {
+	if single.IsPositive() && amount.GreaterThan(single) {
+		return true
+	}
+	return cumulative.IsPositive() && withdrawn.Add(amount).GreaterThan(cumulative)
+}
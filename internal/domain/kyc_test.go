@@ -0,0 +1,46 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/evetabi/prediction/internal/domain"
+)
+
+func TestKYCRequired(t *testing.T) {
+	cases := []struct {
+		name                                  string
+		amount, withdrawn, single, cumulative string
+		want                                  bool
+	}{
+		{"small first withdrawal", "500", "0", "5000", "20000", false},
+		{"at the single threshold", "5000", "0", "5000", "20000", false},
+		{"above the single threshold", "5000.01", "0", "5000", "20000", true},
+		{"total reaches cumulative", "1000", "19000", "5000", "20000", false},
+		{"total passes cumulative", "1000", "19500", "5000", "20000", true},
+		{"thresholds disabled", "1000000", "1000000", "0", "0", false},
+		{"only cumulative set", "9000", "0", "0", "5000", true},
+	}
+	for _, tc := range cases {
+		got := domain.KYCRequired(dec(tc.amount), dec(tc.withdrawn), dec(tc.single), dec(tc.cumulative))
+		if got != tc.want {
+			t.Errorf("%s: KYCRequired = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestKYCStatusAndKinds(t *testing.T) {
+	for s, want := range map[domain.KYCStatus]bool{
+		domain.KYCUnverified: true, domain.KYCRejected: true,
+		domain.KYCPending: false, domain.KYCVerified: false,
+	} {
+		if s.CanSubmit() != want {
+			t.Errorf("%s.CanSubmit() = %v", s, !want)
+		}
+	}
+	if !domain.KYCPassport.IsIdentity() || domain.KYCSelfie.IsIdentity() || domain.KYCProofOfAddress.IsIdentity() {
+		t.Error("IsIdentity wrong")
+	}
+	if domain.KYCDocumentKind("utility_bill").IsValid() {
+		t.Error("unknown kind accepted")
+	}
+}
@@ -38,6 +38,9 @@ func (o Outcome) IsValid() bool {
 	return o == OutcomeUp || o == OutcomeDown
 }
 
+// DefaultSeries is the series assigned to the scheduler's 5-minute BTC rounds.
+const DefaultSeries = "btc_5m"
+
 // CommissionRate is the pari-mutuel pool commission (3 %).
 var CommissionRate = decimal.NewFromFloat(0.03)
 
@@ -60,6 +63,7 @@ type PriceSource struct {
 // Market represents a single 5-minute BTC UP/DOWN prediction round.
 type Market struct {
 	ID              uuid.UUID        `json:"id"               db:"id"`
+	Series          string           `json:"series"           db:"series"`
 	Status          MarketStatus     `json:"status"           db:"status"`
 	OpenPrice       *decimal.Decimal `json:"open_price"     db:"open_price"`
 	ClosePrice      *decimal.Decimal `json:"close_price"    db:"close_price"`
@@ -171,6 +175,52 @@ func (m *Market) WeightedPrice(sources []PriceSource) decimal.Decimal {
 	return sumWeighted.Div(sumWeights)
 }
 
+// ──────────────────────────────────────────────────────────────────────────────
+// Settlement
+// ──────────────────────────────────────────────────────────────────────────────
+
+// Settlement splits a resolved market's pool between the winning bets, the
+// winning MM positions and the house.
+type Settlement struct {
+	Payouts    map[uuid.UUID]decimal.Decimal // winning bet ID → payout
+	MMPayouts  map[uuid.UUID]decimal.Decimal // winning MM position ID → payout
+	Commission decimal.Decimal               // what the pool keeps after every payout
+}
+
+// Settle computes the settlement for winner from the market's pools and its
+// bets and MM positions, which must all be read under the same lock.  Only
+// open bets and positions on the winning side are paid.  Commission is the
+// total pool minus every payout, so payout rounding and one-sided markets
+// are accounted for exactly and the escrow is left empty.
+func (m *Market) Settle(winner Outcome, bets []*Bet, positions []*MMLog, commission decimal.Decimal) Settlement {
+	winnerPool, loserPool := m.PoolUp, m.PoolDown
+	if winner == OutcomeDown {
+		winnerPool, loserPool = m.PoolDown, m.PoolUp
+	}
+
+	s := Settlement{
+		Payouts:   make(map[uuid.UUID]decimal.Decimal),
+		MMPayouts: make(map[uuid.UUID]decimal.Decimal),
+	}
+	paid := decimal.Zero
+	for _, b := range bets {
+		if b.IsActive() && b.Direction == winner {
+			payout := PariMutuelPayout(b.Amount, winnerPool, loserPool, commission)
+			s.Payouts[b.ID] = payout
+			paid = paid.Add(payout)
+		}
+	}
+	for _, p := range positions {
+		if p.Status == "open" && p.Direction == winner {
+			payout := PariMutuelPayout(p.Amount, winnerPool, loserPool, commission)
+			s.MMPayouts[p.ID] = payout
+			paid = paid.Add(payout)
+		}
+	}
+	s.Commission = m.TotalPool().Sub(paid)
+	return s
+}
+
 // ──────────────────────────────────────────────────────────────────────────────
 // MarketSummary — lightweight read model for WS broadcasts and list endpoints
 // ──────────────────────────────────────────────────────────────────────────────
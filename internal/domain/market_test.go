@@ -153,3 +153,48 @@ func TestWallet_Available(t *testing.T) {
 		t.Errorf("Available() = %s, want %s", w.Available(), want)
 	}
 }
+
+// ── Settlement ────────────────────────────────────────────────────────────────
+
+// TestMarket_Settle_UsesStateAtLock settles from the state read under the
+// market lock: a bet placed and a bet exited after the expiry scan.
+func TestMarket_Settle_UsesStateAtLock(t *testing.T) {
+	exited := &domain.Bet{ID: uuid.New(), Direction: domain.OutcomeUp, Amount: decimal.NewFromInt(100), Status: domain.BetStatusExited}
+	loser := &domain.Bet{ID: uuid.New(), Direction: domain.OutcomeDown, Amount: decimal.NewFromInt(100), Status: domain.BetStatusActive}
+	late := &domain.Bet{ID: uuid.New(), Direction: domain.OutcomeUp, Amount: decimal.NewFromInt(50), Status: domain.BetStatusActive}
+	mm := &domain.MMLog{ID: uuid.New(), Direction: domain.OutcomeUp, Amount: decimal.NewFromInt(50), Status: "open"}
+
+	// At the scan the pools were UP 150 (exited + mm), DOWN 100.  Since then
+	// the exit took 100 out of UP and the late bet added 50.
+	m := &domain.Market{PoolUp: decimal.NewFromInt(100), PoolDown: decimal.NewFromInt(100)}
+	s := m.Settle(domain.OutcomeUp, []*domain.Bet{exited, loser, late}, []*domain.MMLog{mm}, domain.CommissionRate)
+
+	if _, ok := s.Payouts[exited.ID]; ok {
+		t.Error("exited bet must not be paid again")
+	}
+	if _, ok := s.Payouts[loser.ID]; ok {
+		t.Error("losing bet must not be paid")
+	}
+	want := decimal.RequireFromString("98.5") // 50 + 50/100 × 100 × 0.97
+	if got := s.Payouts[late.ID]; !got.Equal(want) {
+		t.Errorf("late bet payout = %s, want %s", got, want)
+	}
+	if got := s.MMPayouts[mm.ID]; !got.Equal(want) {
+		t.Errorf("MM payout = %s, want %s", got, want)
+	}
+
+	// Payouts and commission must empty the escrow, which holds the pools.
+	total := s.Commission
+	for _, p := range s.Payouts {
+		total = total.Add(p)
+	}
+	for _, p := range s.MMPayouts {
+		total = total.Add(p)
+	}
+	if !total.Equal(m.TotalPool()) {
+		t.Errorf("payouts + commission = %s, want pool %s", total, m.TotalPool())
+	}
+	if !s.Commission.Equal(decimal.NewFromInt(3)) {
+		t.Errorf("commission = %s, want 3", s.Commission)
+	}
+}
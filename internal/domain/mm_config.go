@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// MM config scopes, from least to most specific.
+const (
+	MMScopeGlobal = "global"
+	MMScopeSeries = "series"
+	MMScopeMarket = "market"
+	MMScopeEnv    = "env" // value came from the process configuration
+)
+
+// MMConfig is one row of mm_config: an override of MM parameters for a scope.
+// Exactly one of MarketID / Series is set for market / series scope; both nil
+// means global.  Nil parameter fields inherit from the next broader scope.
+type MMConfig struct {
+	ID             uuid.UUID        `json:"id"               db:"id"`
+	MarketID       *uuid.UUID       `json:"market_id"        db:"market_id"`
+	Series         *string          `json:"series"           db:"series"`
+	ThresholdRatio *decimal.Decimal `json:"threshold_ratio"  db:"threshold_ratio"`
+	MaxExposure    *decimal.Decimal `json:"max_exposure_try" db:"max_exposure_try"`
+	MinBet         *decimal.Decimal `json:"min_bet_try"      db:"min_bet_try"`
+	SeedRatio      *decimal.Decimal `json:"seed_ratio"       db:"seed_ratio"`
+	BalanceRatio   *decimal.Decimal `json:"balance_ratio"    db:"balance_ratio"`
+	TargetOdds     *decimal.Decimal `json:"target_odds"      db:"target_odds"`
+	Strategy       *string          `json:"strategy"         db:"strategy"`
+	IsActive       bool             `json:"is_active"        db:"is_active"`
+	CreatedBy      *uuid.UUID       `json:"created_by"       db:"created_by"`
+	CreatedAt      time.Time        `json:"created_at"       db:"created_at"`
+	DeactivatedAt  *time.Time       `json:"deactivated_at"   db:"deactivated_at"`
+}
+
+// Scope returns MMScopeGlobal, MMScopeSeries or MMScopeMarket.
+func (c *MMConfig) Scope() string {
+	switch {
+	case c.MarketID != nil:
+		return MMScopeMarket
+	case c.Series != nil:
+		return MMScopeSeries
+	default:
+		return MMScopeGlobal
+	}
+}
+
+// ScopeKey returns a human-readable identifier such as "series:btc_5m".
+func (c *MMConfig) ScopeKey() string {
+	switch c.Scope() {
+	case MMScopeMarket:
+		return MMScopeMarket + ":" + c.MarketID.String()
+	case MMScopeSeries:
+		return MMScopeSeries + ":" + *c.Series
+	default:
+		return MMScopeGlobal
+	}
+}
+
+// Validate checks scope and parameter ranges.
+func (c *MMConfig) Validate() error {
+	if c.MarketID != nil && c.Series != nil {
+		return fmt.Errorf("%w: set either market_id or series, not both", ErrInvalidMMConfig)
+	}
+	if c.Series != nil && *c.Series == "" {
+		return fmt.Errorf("%w: series must not be empty", ErrInvalidMMConfig)
+	}
+	one := decimal.NewFromInt(1)
+	ratios := []struct {
+		name string
+		v    *decimal.Decimal
+	}{
+		{"threshold_ratio", c.ThresholdRatio},
+		{"seed_ratio", c.SeedRatio},
+		{"balance_ratio", c.BalanceRatio},
+	}
+	for _, r := range ratios {
+		if r.v != nil && (!r.v.IsPositive() || r.v.GreaterThan(one)) {
+			return fmt.Errorf("%w: %s must be in (0, 1], got %s", ErrInvalidMMConfig, r.name, r.v)
+		}
+	}
+	if c.MaxExposure != nil && c.MaxExposure.IsNegative() {
+		return fmt.Errorf("%w: max_exposure_try must not be negative", ErrInvalidMMConfig)
+	}
+	if c.MinBet != nil && !c.MinBet.IsPositive() {
+		return fmt.Errorf("%w: min_bet_try must be positive", ErrInvalidMMConfig)
+	}
+	if c.TargetOdds != nil && !c.TargetOdds.GreaterThan(one) {
+		return fmt.Errorf("%w: target_odds must be greater than 1", ErrInvalidMMConfig)
+	}
+	if c.Strategy != nil && *c.Strategy == "" {
+		return fmt.Errorf("%w: strategy must not be empty", ErrInvalidMMConfig)
+	}
+	return nil
+}
+
+// MMParams is the fully-resolved parameter set the Market Maker acts on.
+// Sources records which scope supplied each field (see ResolveMMParams).
+type MMParams struct {
+	ThresholdRatio decimal.Decimal   `json:"threshold_ratio"`
+	MaxExposure    decimal.Decimal   `json:"max_exposure_try"`
+	MinBet         decimal.Decimal   `json:"min_bet_try"`
+	SeedRatio      decimal.Decimal   `json:"seed_ratio"`
+	BalanceRatio   decimal.Decimal   `json:"balance_ratio"`
+	TargetOdds     decimal.Decimal   `json:"target_odds"`
+	Strategy       string            `json:"strategy"`
+	Sources        map[string]string `json:"sources"`
+}
+
+// ResolveMMParams layers overrides on top of defaults field by field.  Layers
+// are applied in the order given, so callers pass them broadest first
+// (global, series, market); nil layers are skipped.
+func ResolveMMParams(defaults MMParams, layers ...*MMConfig) MMParams {
+	p := defaults
+	p.Sources = map[string]string{
+		"threshold_ratio":  MMScopeEnv,
+		"max_exposure_try": MMScopeEnv,
+		"min_bet_try":      MMScopeEnv,
+		"seed_ratio":       MMScopeEnv,
+		"balance_ratio":    MMScopeEnv,
+		"target_odds":      MMScopeEnv,
+		"strategy":         MMScopeEnv,
+	}
+	for _, l := range layers {
+		if l == nil {
+			continue
+		}
+		scope := l.ScopeKey()
+		if l.ThresholdRatio != nil {
+			p.ThresholdRatio = *l.ThresholdRatio
+			p.Sources["threshold_ratio"] = scope
+		}
+		if l.MaxExposure != nil {
+			p.MaxExposure = *l.MaxExposure
+			p.Sources["max_exposure_try"] = scope
+		}
+		if l.MinBet != nil {
+			p.MinBet = *l.MinBet
+			p.Sources["min_bet_try"] = scope
+		}
+		if l.SeedRatio != nil {
+			p.SeedRatio = *l.SeedRatio
+			p.Sources["seed_ratio"] = scope
+		}
+		if l.BalanceRatio != nil {
+			p.BalanceRatio = *l.BalanceRatio
+			p.Sources["balance_ratio"] = scope
+		}
+		if l.TargetOdds != nil {
+			p.TargetOdds = *l.TargetOdds
+			p.Sources["target_odds"] = scope
+		}
+		if l.Strategy != nil {
+			p.Strategy = *l.Strategy
+			p.Sources["strategy"] = scope
+		}
+	}
+	return p
+}
@@ -0,0 +1,82 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func dptr(f float64) *decimal.Decimal {
+	d := decimal.NewFromFloat(f)
+	return &d
+}
+
+// ── MM config resolution ──────────────────────────────────────────────────────
+
+func TestResolveMMParams_MostSpecificWinsPerField(t *testing.T) {
+	defaults := domain.MMParams{
+		ThresholdRatio: decimal.NewFromFloat(0.8),
+		MaxExposure:    decimal.NewFromInt(10000),
+		MinBet:         decimal.NewFromInt(10),
+		SeedRatio:      decimal.NewFromFloat(0.3),
+		BalanceRatio:   decimal.NewFromFloat(0.2),
+	}
+	series := "btc_5m"
+	marketID := uuid.New()
+
+	global := &domain.MMConfig{ThresholdRatio: dptr(0.7), MaxExposure: dptr(20000)}
+	seriesCfg := &domain.MMConfig{Series: &series, MaxExposure: dptr(15000)}
+	market := &domain.MMConfig{MarketID: &marketID, MinBet: dptr(25)}
+
+	p := domain.ResolveMMParams(defaults, global, seriesCfg, market)
+
+	if !p.ThresholdRatio.Equal(decimal.NewFromFloat(0.7)) || p.Sources["threshold_ratio"] != "global" {
+		t.Errorf("threshold = %s from %s, want 0.7 from global", p.ThresholdRatio, p.Sources["threshold_ratio"])
+	}
+	if !p.MaxExposure.Equal(decimal.NewFromInt(15000)) || p.Sources["max_exposure_try"] != "series:btc_5m" {
+		t.Errorf("max exposure = %s from %s, want 15000 from series", p.MaxExposure, p.Sources["max_exposure_try"])
+	}
+	if !p.MinBet.Equal(decimal.NewFromInt(25)) || p.Sources["min_bet_try"] != "market:"+marketID.String() {
+		t.Errorf("min bet = %s from %s, want 25 from market", p.MinBet, p.Sources["min_bet_try"])
+	}
+	if !p.SeedRatio.Equal(decimal.NewFromFloat(0.3)) || p.Sources["seed_ratio"] != "env" {
+		t.Errorf("seed ratio = %s from %s, want env default", p.SeedRatio, p.Sources["seed_ratio"])
+	}
+}
+
+func TestResolveMMParams_NilLayersSkipped(t *testing.T) {
+	defaults := domain.MMParams{ThresholdRatio: decimal.NewFromFloat(0.8)}
+	p := domain.ResolveMMParams(defaults, nil, nil, nil)
+	if !p.ThresholdRatio.Equal(defaults.ThresholdRatio) {
+		t.Errorf("threshold = %s, want default", p.ThresholdRatio)
+	}
+}
+
+func TestMMConfig_Validate(t *testing.T) {
+	series := "btc_5m"
+	marketID := uuid.New()
+	cases := []struct {
+		name string
+		cfg  domain.MMConfig
+		ok   bool
+	}{
+		{"global ok", domain.MMConfig{ThresholdRatio: dptr(0.5)}, true},
+		{"both scopes", domain.MMConfig{MarketID: &marketID, Series: &series}, false},
+		{"threshold zero", domain.MMConfig{ThresholdRatio: dptr(0)}, false},
+		{"ratio above one", domain.MMConfig{SeedRatio: dptr(1.5)}, false},
+		{"negative exposure", domain.MMConfig{MaxExposure: dptr(-1)}, false},
+		{"zero min bet", domain.MMConfig{MinBet: dptr(0)}, false},
+	}
+	for _, tc := range cases {
+		err := tc.cfg.Validate()
+		if tc.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+		}
+		if !tc.ok && !errors.Is(err, domain.ErrInvalidMMConfig) {
+			t.Errorf("%s: expected ErrInvalidMMConfig, got %v", tc.name, err)
+		}
+	}
+}
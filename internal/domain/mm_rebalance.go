@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// MMRebalanceReport is the latest rebalance-queue snapshot published by one
+// API server process.  It maps to the mm_rebalance_stats table.
+type MMRebalanceReport struct {
+	Instance  string         `json:"instance"   db:"instance"`
+	Stats     types.JSONText `json:"stats"      db:"stats"`
+	StartedAt time.Time      `json:"started_at" db:"started_at"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+}
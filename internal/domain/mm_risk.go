@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PariMutuelPayout returns what a winning stake receives at settlement:
+//
+//	distributable = loserPool × (1 - commission)
+//	payout        = stake + stake / winnerPool × distributable
+//
+// rounded down to 4 decimal places.  Returns stake unchanged when winnerPool
+// is zero.  ResolutionService, the MM risk budget and the simulator all use
+// this so their numbers agree.
+func PariMutuelPayout(stake, winnerPool, loserPool, commission decimal.Decimal) decimal.Decimal {
+	if winnerPool.IsZero() {
+		return stake
+	}
+	distributable := loserPool.Mul(decimal.NewFromInt(1).Sub(commission))
+	return stake.Add(stake.Div(winnerPool).Mul(distributable)).RoundDown(4)
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// MMExposure — open MM position in one market
+// ──────────────────────────────────────────────────────────────────────────────
+
+// MMExposure is the Market Maker's open stake in one market together with the
+// market's current pools (which already include the MM stake).
+type MMExposure struct {
+	MarketID  uuid.UUID       `json:"market_id"  db:"market_id"`
+	StakeUp   decimal.Decimal `json:"stake_up"   db:"stake_up"`
+	StakeDown decimal.Decimal `json:"stake_down" db:"stake_down"`
+	PoolUp    decimal.Decimal `json:"pool_up"    db:"pool_up"`
+	PoolDown  decimal.Decimal `json:"pool_down"  db:"pool_down"`
+}
+
+// With returns a copy of e with amount added to the MM stake and the pool on
+// side o, i.e. the exposure after a proposed injection.
+func (e MMExposure) With(o Outcome, amount decimal.Decimal) MMExposure {
+	if o == OutcomeUp {
+		e.StakeUp = e.StakeUp.Add(amount)
+		e.PoolUp = e.PoolUp.Add(amount)
+	} else {
+		e.StakeDown = e.StakeDown.Add(amount)
+		e.PoolDown = e.PoolDown.Add(amount)
+	}
+	return e
+}
+
+// PnLIf returns the MM's profit (negative = loss) if the market settles with
+// winner at the current pools.
+func (e MMExposure) PnLIf(winner Outcome, commission decimal.Decimal) decimal.Decimal {
+	winStake, loseStake := e.StakeUp, e.StakeDown
+	winPool, losePool := e.PoolUp, e.PoolDown
+	if winner == OutcomeDown {
+		winStake, loseStake = e.StakeDown, e.StakeUp
+		winPool, losePool = e.PoolDown, e.PoolUp
+	}
+	payout := decimal.Zero
+	if winStake.IsPositive() {
+		payout = PariMutuelPayout(winStake, winPool, losePool, commission)
+	}
+	return payout.Sub(winStake).Sub(loseStake)
+}
+
+// WorstCase returns the lower of the two settlement outcomes.
+func (e MMExposure) WorstCase(commission decimal.Decimal) decimal.Decimal {
+	return decimal.Min(e.PnLIf(OutcomeUp, commission), e.PnLIf(OutcomeDown, commission))
+}
+
+// MarkToMarket values the position at the pool-implied probability of each
+// outcome (share of the total pool).  With an empty pool both outcomes are
+// weighted equally.
+func (e MMExposure) MarkToMarket(commission decimal.Decimal) decimal.Decimal {
+	total := e.PoolUp.Add(e.PoolDown)
+	pUp := decimal.NewFromFloat(0.5)
+	if total.IsPositive() {
+		pUp = e.PoolUp.Div(total)
+	}
+	pDown := decimal.NewFromInt(1).Sub(pUp)
+	return e.PnLIf(OutcomeUp, commission).Mul(pUp).
+		Add(e.PnLIf(OutcomeDown, commission).Mul(pDown)).
+		RoundDown(4)
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// MMRiskBudget — daily / weekly loss budgets with throttling
+// ──────────────────────────────────────────────────────────────────────────────
+
+// MMPnL summarises MM profit and loss for the risk budget.  Negative values
+// are losses.
+type MMPnL struct {
+	RealizedDay   decimal.Decimal `json:"realized_day"`
+	RealizedWeek  decimal.Decimal `json:"realized_week"`
+	OpenMTM       decimal.Decimal `json:"open_mark_to_market"`
+	OpenWorstCase decimal.Decimal `json:"open_worst_case"`
+}
+
+// MMRiskBudget limits MM losses per business day and week.
+//
+// Usage is the share of a budget consumed by realized plus mark-to-market
+// losses.  Above ThrottleStart injections shrink linearly, reaching zero when
+// the budget is exhausted.  Independently, a new injection is refused when the
+// realized loss plus the worst-case loss of all open positions would exceed
+// either budget.
+type MMRiskBudget struct {
+	DailyLimit    decimal.Decimal // TRY
+	WeeklyLimit   decimal.Decimal // TRY
+	ThrottleStart decimal.Decimal // 0–1 fraction of the tighter budget
+}
+
+// Usage returns the consumed fraction of the tighter budget, at least 0.
+func (b MMRiskBudget) Usage(p MMPnL) decimal.Decimal {
+	return decimal.Max(
+		lossShare(p.RealizedDay.Add(p.OpenMTM), b.DailyLimit),
+		lossShare(p.RealizedWeek.Add(p.OpenMTM), b.WeeklyLimit),
+	)
+}
+
+// Scale returns the factor (0–1) by which a proposed injection is shrunk.
+func (b MMRiskBudget) Scale(p MMPnL) decimal.Decimal {
+	one := decimal.NewFromInt(1)
+	usage := b.Usage(p)
+	if usage.LessThanOrEqual(b.ThrottleStart) {
+		return one
+	}
+	if usage.GreaterThanOrEqual(one) || b.ThrottleStart.GreaterThanOrEqual(one) {
+		return decimal.Zero
+	}
+	return one.Sub(usage).Div(one.Sub(b.ThrottleStart))
+}
+
+// Check returns ErrMMDailyLossExceeded or ErrMMWeeklyLossExceeded when the
+// realized loss plus the worst case of open positions breaches a budget.
+func (b MMRiskBudget) Check(p MMPnL) error {
+	if p.RealizedDay.Add(p.OpenWorstCase).LessThan(b.DailyLimit.Neg()) {
+		return ErrMMDailyLossExceeded
+	}
+	if p.RealizedWeek.Add(p.OpenWorstCase).LessThan(b.WeeklyLimit.Neg()) {
+		return ErrMMWeeklyLossExceeded
+	}
+	return nil
+}
+
+// lossShare returns max(0, -pnl) / limit; a non-positive limit counts as
+// fully consumed.
+func lossShare(pnl, limit decimal.Decimal) decimal.Decimal {
+	if !limit.IsPositive() {
+		return decimal.NewFromInt(1)
+	}
+	if !pnl.IsNegative() {
+		return decimal.Zero
+	}
+	return pnl.Neg().Div(limit)
+}
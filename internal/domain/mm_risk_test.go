@@ -0,0 +1,89 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func dec(s string) decimal.Decimal { return decimal.RequireFromString(s) }
+
+var commission3 = dec("0.03")
+
+// TestPariMutuelPayout reuses the scenario from TestParimutuelPayoutMath.
+func TestPariMutuelPayout(t *testing.T) {
+	got := domain.PariMutuelPayout(dec("1000"), dec("1200"), dec("500"), commission3)
+	if !got.Equal(dec("1404.1666")) {
+		t.Errorf("payout = %s, want 1404.1666", got)
+	}
+	if got := domain.PariMutuelPayout(dec("10"), decimal.Zero, dec("500"), commission3); !got.Equal(dec("10")) {
+		t.Errorf("payout with empty winner pool = %s, want stake back", got)
+	}
+}
+
+// TestMMExposure_PnL: MM holds 100 on DOWN in a 900 UP / 100 DOWN market.
+//
+//	DOWN wins: payout = 100 + 100/100 × 900 × 0.97 = 973 → pnl +873
+//	UP wins:   stake lost                          → pnl -100
+func TestMMExposure_PnL(t *testing.T) {
+	e := domain.MMExposure{
+		StakeDown: dec("100"),
+		PoolUp:    dec("900"),
+		PoolDown:  dec("100"),
+	}
+
+	if got := e.PnLIf(domain.OutcomeDown, commission3); !got.Equal(dec("873")) {
+		t.Errorf("PnLIf(DOWN) = %s, want 873", got)
+	}
+	if got := e.PnLIf(domain.OutcomeUp, commission3); !got.Equal(dec("-100")) {
+		t.Errorf("PnLIf(UP) = %s, want -100", got)
+	}
+	if got := e.WorstCase(commission3); !got.Equal(dec("-100")) {
+		t.Errorf("WorstCase = %s, want -100", got)
+	}
+	// 0.9 × -100 + 0.1 × 873 = -2.7
+	if got := e.MarkToMarket(commission3); !got.Equal(dec("-2.7")) {
+		t.Errorf("MarkToMarket = %s, want -2.7", got)
+	}
+
+	more := e.With(domain.OutcomeDown, dec("50"))
+	if !more.StakeDown.Equal(dec("150")) || !more.PoolDown.Equal(dec("150")) {
+		t.Errorf("With: stake=%s pool=%s, want 150/150", more.StakeDown, more.PoolDown)
+	}
+	if !e.StakeDown.Equal(dec("100")) {
+		t.Error("With must not modify the receiver")
+	}
+}
+
+func TestMMRiskBudget(t *testing.T) {
+	b := domain.MMRiskBudget{
+		DailyLimit:    dec("1000"),
+		WeeklyLimit:   dec("5000"),
+		ThrottleStart: dec("0.5"),
+	}
+
+	cases := []struct {
+		name      string
+		pnl       domain.MMPnL
+		wantScale string
+		wantErr   error
+	}{
+		{"in profit", domain.MMPnL{RealizedDay: dec("2000"), RealizedWeek: dec("2000"), OpenWorstCase: dec("-2500")}, "1", nil},
+		{"below throttle", domain.MMPnL{RealizedDay: dec("-400"), RealizedWeek: dec("-400")}, "1", nil},
+		{"throttling", domain.MMPnL{RealizedDay: dec("-600"), RealizedWeek: dec("-600"), OpenMTM: dec("-150")}, "0.5", nil},
+		{"daily worst case", domain.MMPnL{RealizedDay: dec("-600"), RealizedWeek: dec("-600"), OpenWorstCase: dec("-500")}, "0.8", domain.ErrMMDailyLossExceeded},
+		{"weekly", domain.MMPnL{RealizedDay: dec("0"), RealizedWeek: dec("-5200")}, "0", domain.ErrMMWeeklyLossExceeded},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := b.Scale(tc.pnl); !got.Equal(dec(tc.wantScale)) {
+				t.Errorf("Scale = %s, want %s", got, tc.wantScale)
+			}
+			if err := b.Check(tc.pnl); !errors.Is(err, tc.wantErr) {
+				t.Errorf("Check = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MMSwitch is the persisted Market Maker kill switch for one scope.
+// MarketID nil is the global switch; it overrides every per-market switch.
+type MMSwitch struct {
+	ID        uuid.UUID  `json:"id"         db:"id"`
+	MarketID  *uuid.UUID `json:"market_id"  db:"market_id"`
+	Enabled   bool       `json:"enabled"    db:"enabled"`
+	Reason    string     `json:"reason"     db:"reason"`
+	ChangedBy *uuid.UUID `json:"changed_by" db:"changed_by"`
+	ChangedAt time.Time  `json:"changed_at" db:"changed_at"`
+}
+
+// ScopeKey returns "global" or "market:<id>".
+func (s *MMSwitch) ScopeKey() string {
+	if s.MarketID == nil {
+		return MMScopeGlobal
+	}
+	return MMScopeMarket + ":" + s.MarketID.String()
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PayoutBatchStatus represents the lifecycle of a bank payout batch.
+type PayoutBatchStatus string
+
+const (
+	BatchOpen     PayoutBatchStatus = "open"     // created, not yet exported
+	BatchExported PayoutBatchStatus = "exported" // bank file downloaded at least once
+	BatchSettled  PayoutBatchStatus = "settled"  // every withdrawal completed or failed
+)
+
+// PayoutBatchProvider is the payout_provider recorded on withdrawals paid
+// through a batch.
+const PayoutBatchProvider = "bank_file"
+
+// PayoutBatch groups approved withdrawals into one bank file.
+type PayoutBatch struct {
+	ID          uuid.UUID         `json:"id"           db:"id"`
+	Status      PayoutBatchStatus `json:"status"       db:"status"`
+	ItemCount   int               `json:"item_count"   db:"item_count"`
+	TotalAmount decimal.Decimal   `json:"total_amount" db:"total_amount"`
+	Completed   int               `json:"completed"    db:"completed"`
+	Failed      int               `json:"failed"       db:"failed"`
+	CreatedBy   *uuid.UUID        `json:"created_by"   db:"created_by"`
+	CreatedAt   time.Time         `json:"created_at"   db:"created_at"`
+	ExportedAt  *time.Time        `json:"exported_at"  db:"exported_at"`
+	SettledAt   *time.Time        `json:"settled_at"   db:"settled_at"`
+}
+
+// PayoutBatchItem is a withdrawal in a batch with its owner's username, for
+// display, and the payee the bank file needs: the saved bank account's
+// holder and IBAN, or for withdrawals made before saved accounts the user's
+// legal name and the IBAN typed in.
+type PayoutBatchItem struct {
+	WithdrawRequest
+	Username   string `json:"username"     db:"username"`
+	HolderName string `json:"holder_name"  db:"holder_name"` // the bank checks it against the IBAN
+	PayeeIBAN  string `json:"payee_iban"   db:"payee_iban"`
+}
+
+// PayoutImportResult summarises a bank result file import.
+type PayoutImportResult struct {
+	Completed int                `json:"completed"`
+	Failed    int                `json:"failed"`
+	Pending   int                `json:"pending"` // still in process at the bank
+	Skipped   []PayoutImportSkip `json:"skipped"`
+	Batch     *PayoutBatch       `json:"batch"`
+}
+
+// PayoutImportSkip is a result line that was not applied.
+type PayoutImportSkip struct {
+	Line   int    `json:"line"`
+	Ref    string `json:"ref"`
+	Reason string `json:"reason"`
+}
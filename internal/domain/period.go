@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// StartOfDay returns midnight of the day containing t in t's location.
+// Business periods use the business timezone, so callers pass
+// t.In(cfg.Location()).
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns midnight of the Monday of t's week in t's location.
+func StartOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // Monday = 0
+	return StartOfDay(t).AddDate(0, 0, -offset)
+}
+
+// StartOfMonth returns midnight of the first day of t's month in t's
+// location.
+func StartOfMonth(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+}
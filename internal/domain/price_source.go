@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceSourceConfig is the runtime configuration of a single exchange feed.
+// It maps to the price_sources table and is editable from the back-office
+// without a redeploy.
+type PriceSourceConfig struct {
+	Name      string     `json:"name"       db:"name"`
+	Enabled   bool       `json:"enabled"    db:"enabled"`
+	Weight    int        `json:"weight"     db:"weight"` // 0–100, renormalised over live sources
+	Symbol    string     `json:"symbol"     db:"symbol"` // exchange-specific, e.g. BTCUSDT / BTC-USDT
+	UpdatedBy *uuid.UUID `json:"updated_by" db:"updated_by"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Validate checks a single source's fields.
+func (p *PriceSourceConfig) Validate() error {
+	if p.Weight < 0 || p.Weight > 100 {
+		return fmt.Errorf("%w: weight must be between 0 and 100, got %d", ErrInvalidPriceSource, p.Weight)
+	}
+	if strings.TrimSpace(p.Symbol) == "" {
+		return fmt.Errorf("%w: symbol must not be empty", ErrInvalidPriceSource)
+	}
+	return nil
+}
+
+// ValidatePriceSourceSet checks that the full source set can still produce a
+// price: at least one source must be enabled with a non-zero weight.
+func ValidatePriceSourceSet(sources []*PriceSourceConfig) error {
+	for _, s := range sources {
+		if err := s.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, s := range sources {
+		if s.Enabled && s.Weight > 0 {
+			return nil
+		}
+	}
+	return ErrNoActivePriceSource
+}
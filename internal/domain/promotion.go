@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// RewardType is what redeeming a promotion gives the user.
+type RewardType string
+
+const (
+	RewardBonus   RewardType = "bonus"    // a bonus grant, subject to wagering
+	RewardFreeBet RewardType = "free_bet" // a free-bet token of reward_amount
+)
+
+// FreeBetStatus represents the state of a free-bet token.
+type FreeBetStatus string
+
+const (
+	FreeBetAvailable FreeBetStatus = "available"
+	FreeBetUsed      FreeBetStatus = "used"
+	FreeBetExpired   FreeBetStatus = "expired"
+)
+
+// Promotion is a marketing campaign redeemable with its promo code.
+type Promotion struct {
+	ID              uuid.UUID       `json:"id"               db:"id"`
+	Name            string          `json:"name"             db:"name"`
+	Code            string          `json:"code"             db:"code"`
+	RewardType      RewardType      `json:"reward_type"      db:"reward_type"`
+	RewardAmount    decimal.Decimal `json:"reward_amount"    db:"reward_amount"`
+	FreeBetDays     int             `json:"free_bet_days"    db:"free_bet_days"` // free-bet tokens expire after this
+	StartsAt        time.Time       `json:"starts_at"        db:"starts_at"`
+	EndsAt          *time.Time      `json:"ends_at"          db:"ends_at"`         // nil = open-ended
+	MaxRedemptions  int             `json:"max_redemptions"  db:"max_redemptions"` // 0 = unlimited
+	PerUserLimit    int             `json:"per_user_limit"   db:"per_user_limit"`
+	RedeemedCount   int             `json:"redeemed_count"   db:"redeemed_count"`
+	NewUsersDays    int             `json:"new_users_days"   db:"new_users_days"` // only accounts younger than this; 0 = everyone
+	RequiresDeposit bool            `json:"requires_deposit" db:"requires_deposit"`
+	IsActive        bool            `json:"is_active"        db:"is_active"`
+	CreatedBy       *uuid.UUID      `json:"created_by"       db:"created_by"`
+	CreatedAt       time.Time       `json:"created_at"       db:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"       db:"updated_at"`
+}
+
+// NormalizePromoCode upper-cases a promo code as typed by a user and checks
+// that it is 3–32 letters, digits, '-' or '_'.
+func NormalizePromoCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) < 3 || len(code) > 32 {
+		return "", fmt.Errorf("%w: code must be 3-32 characters", ErrInvalidPromotion)
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return "", fmt.Errorf("%w: code may contain only letters, digits, '-' and '_'", ErrInvalidPromotion)
+		}
+	}
+	return code, nil
+}
+
+// Validate checks a campaign definition.  Errors wrap ErrInvalidPromotion.
+func (p *Promotion) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPromotion)
+	}
+	switch p.RewardType {
+	case RewardBonus:
+	case RewardFreeBet:
+		if p.RewardAmount.LessThan(MinBetAmount) {
+			return fmt.Errorf("%w: a free bet must be at least the minimum stake of %s TRY", ErrInvalidPromotion, MinBetAmount)
+		}
+		if p.FreeBetDays < 1 {
+			return fmt.Errorf("%w: free_bet_days must be at least 1", ErrInvalidPromotion)
+		}
+	default:
+		return fmt.Errorf("%w: unknown reward type %q", ErrInvalidPromotion, p.RewardType)
+	}
+	if !p.RewardAmount.IsPositive() {
+		return fmt.Errorf("%w: reward amount must be positive", ErrInvalidPromotion)
+	}
+	if p.EndsAt != nil && !p.EndsAt.After(p.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidPromotion)
+	}
+	if p.MaxRedemptions < 0 || p.PerUserLimit < 1 || p.NewUsersDays < 0 {
+		return fmt.Errorf("%w: max_redemptions and new_users_days must not be negative and per_user_limit must be at least 1", ErrInvalidPromotion)
+	}
+	return nil
+}
+
+// CheckRedeemable returns nil when the campaign accepts redemptions at now,
+// otherwise ErrPromotionNotActive or ErrPromotionExhausted.
+func (p *Promotion) CheckRedeemable(now time.Time) error {
+	if !p.IsActive || now.Before(p.StartsAt) || (p.EndsAt != nil && !now.Before(*p.EndsAt)) {
+		return ErrPromotionNotActive
+	}
+	if p.MaxRedemptions > 0 && p.RedeemedCount >= p.MaxRedemptions {
+		return ErrPromotionExhausted
+	}
+	return nil
+}
+
+// CheckEligible applies the campaign's eligibility rules to a user.
+// hasDeposit reports whether the user has completed a deposit.
+func (p *Promotion) CheckEligible(u *User, hasDeposit bool, now time.Time) error {
+	if p.NewUsersDays > 0 && now.Sub(u.CreatedAt) > time.Duration(p.NewUsersDays)*24*time.Hour {
+		return fmt.Errorf("%w: only for accounts younger than %d days", ErrPromotionNotEligible, p.NewUsersDays)
+	}
+	if p.RequiresDeposit && !hasDeposit {
+		return fmt.Errorf("%w: a completed deposit is required", ErrPromotionNotEligible)
+	}
+	return nil
+}
+
+// PromotionRedemption records one use of a promo code.
+type PromotionRedemption struct {
+	ID           uuid.UUID       `json:"id"             db:"id"`
+	PromotionID  uuid.UUID       `json:"promotion_id"   db:"promotion_id"`
+	UserID       uuid.UUID       `json:"user_id"        db:"user_id"`
+	RewardType   RewardType      `json:"reward_type"    db:"reward_type"`
+	Amount       decimal.Decimal `json:"amount"         db:"amount"`
+	BonusGrantID *uuid.UUID      `json:"bonus_grant_id" db:"bonus_grant_id"`
+	FreeBetID    *uuid.UUID      `json:"free_bet_id"    db:"free_bet_id"`
+	CreatedAt    time.Time       `json:"created_at"     db:"created_at"`
+}
+
+// PromotionRedemptionItem is a redemption with the user's name for the
+// back-office report.
+type PromotionRedemptionItem struct {
+	PromotionRedemption
+	Username string `json:"username" db:"username"`
+}
+
+// PromotionReport summarises the redemptions of a campaign.
+type PromotionReport struct {
+	Redemptions      int             `json:"redemptions"       db:"redemptions"`
+	UniqueUsers      int             `json:"unique_users"      db:"unique_users"`
+	AmountAwarded    decimal.Decimal `json:"amount_awarded"    db:"amount_awarded"`
+	FreeBetsUsed     int             `json:"free_bets_used"    db:"free_bets_used"`
+	FreeBetsExpired  int             `json:"free_bets_expired" db:"free_bets_expired"`
+	FreeBetWinnings  decimal.Decimal `json:"free_bet_winnings" db:"free_bet_winnings"` // paid to users by winning free bets
+	BonusesConverted int             `json:"bonuses_converted" db:"bonuses_converted"`
+}
+
+// FreeBet is a token that pays the stake of one bet.
+type FreeBet struct {
+	ID          uuid.UUID       `json:"id"           db:"id"`
+	UserID      uuid.UUID       `json:"user_id"      db:"user_id"`
+	PromotionID uuid.UUID       `json:"promotion_id" db:"promotion_id"`
+	Amount      decimal.Decimal `json:"amount"       db:"amount"`
+	Status      FreeBetStatus   `json:"status"       db:"status"`
+	BetID       *uuid.UUID      `json:"bet_id"       db:"bet_id"`
+	CreatedAt   time.Time       `json:"created_at"   db:"created_at"`
+	ExpiresAt   time.Time       `json:"expires_at"   db:"expires_at"`
+	UsedAt      *time.Time      `json:"used_at"      db:"used_at"`
+}
@@ -0,0 +1,93 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func TestNormalizePromoCode(t *testing.T) {
+	if got, err := domain.NormalizePromoCode("  hosgeldin_2026 "); err != nil || got != "HOSGELDIN_2026" {
+		t.Errorf("NormalizePromoCode = %q, %v", got, err)
+	}
+	for _, in := range []string{"", "AB", "HOŞGELDİN", "WITH SPACE", "THIS-CODE-IS-WAY-TOO-LONG-TO-BE-VALID"} {
+		if _, err := domain.NormalizePromoCode(in); !errors.Is(err, domain.ErrInvalidPromotion) {
+			t.Errorf("NormalizePromoCode(%q) err = %v, want ErrInvalidPromotion", in, err)
+		}
+	}
+}
+
+func TestPromotion_Validate(t *testing.T) {
+	now := time.Now()
+	valid := func() *domain.Promotion {
+		return &domain.Promotion{
+			Name:         "Welcome",
+			RewardType:   domain.RewardFreeBet,
+			RewardAmount: decimal.NewFromInt(50),
+			FreeBetDays:  7,
+			StartsAt:     now,
+			PerUserLimit: 1,
+		}
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("valid promotion: %v", err)
+	}
+
+	before := now.Add(-time.Hour)
+	broken := map[string]func(p *domain.Promotion){
+		"no name":          func(p *domain.Promotion) { p.Name = " " },
+		"unknown reward":   func(p *domain.Promotion) { p.RewardType = "cash" },
+		"tiny free bet":    func(p *domain.Promotion) { p.RewardAmount = decimal.NewFromInt(5) },
+		"zero bonus":       func(p *domain.Promotion) { p.RewardType, p.RewardAmount = domain.RewardBonus, decimal.Zero },
+		"ends before":      func(p *domain.Promotion) { p.EndsAt = &before },
+		"no per-user":      func(p *domain.Promotion) { p.PerUserLimit = 0 },
+		"no free-bet days": func(p *domain.Promotion) { p.FreeBetDays = 0 },
+	}
+	for name, breakIt := range broken {
+		p := valid()
+		breakIt(p)
+		if err := p.Validate(); !errors.Is(err, domain.ErrInvalidPromotion) {
+			t.Errorf("%s: err = %v, want ErrInvalidPromotion", name, err)
+		}
+	}
+}
+
+func TestPromotion_CheckRedeemable(t *testing.T) {
+	now := time.Now()
+	end := now.Add(time.Hour)
+	p := &domain.Promotion{IsActive: true, StartsAt: now.Add(-time.Hour), EndsAt: &end, MaxRedemptions: 2, RedeemedCount: 1}
+	if err := p.CheckRedeemable(now); err != nil {
+		t.Errorf("open campaign: %v", err)
+	}
+	if err := p.CheckRedeemable(end); !errors.Is(err, domain.ErrPromotionNotActive) {
+		t.Errorf("at ends_at: %v", err)
+	}
+	p.RedeemedCount = 2
+	if err := p.CheckRedeemable(now); !errors.Is(err, domain.ErrPromotionExhausted) {
+		t.Errorf("cap reached: %v", err)
+	}
+	p.RedeemedCount, p.IsActive = 0, false
+	if err := p.CheckRedeemable(now); !errors.Is(err, domain.ErrPromotionNotActive) {
+		t.Errorf("switched off: %v", err)
+	}
+}
+
+func TestPromotion_CheckEligible(t *testing.T) {
+	now := time.Now()
+	p := &domain.Promotion{NewUsersDays: 7, RequiresDeposit: true}
+	fresh := &domain.User{CreatedAt: now.Add(-48 * time.Hour)}
+	old := &domain.User{CreatedAt: now.Add(-30 * 24 * time.Hour)}
+
+	if err := p.CheckEligible(fresh, true, now); err != nil {
+		t.Errorf("eligible user: %v", err)
+	}
+	if err := p.CheckEligible(old, true, now); !errors.Is(err, domain.ErrPromotionNotEligible) {
+		t.Errorf("old account: %v", err)
+	}
+	if err := p.CheckEligible(fresh, false, now); !errors.Is(err, domain.ErrPromotionNotEligible) {
+		t.Errorf("no deposit: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/shopspring/decimal"
+)
+
+// Reconciliation run sources.
+const (
+	ReconScheduled = "scheduled"
+	ReconManual    = "manual"
+)
+
+// WalletDrift compares a wallet's stored balance with the balance recomputed
+// from its ledger history and with its own wallet_transactions history.
+// Drift = Balance - Expected; non-zero means some change bypassed the ledger
+// or the history is incomplete.  HistoryDrift = Balance - History, where
+// History is the wallet's opening balance plus its ledger-posted
+// transactions signed by direction; BrokenTxID is the first of those, in
+// posting order, whose balance_before is not the previous balance_after
+// (the opening balance for the first) or whose amount is not its balance
+// change.
+type WalletDrift struct {
+	WalletID     uuid.UUID       `json:"wallet_id"               db:"wallet_id"`
+	UserID       *uuid.UUID      `json:"user_id"                 db:"user_id"` // nil for the platform wallet
+	Account      string          `json:"account"                 db:"account"`
+	Balance      decimal.Decimal `json:"balance"                 db:"balance"`
+	Expected     decimal.Decimal `json:"expected"                db:"expected"`
+	Drift        decimal.Decimal `json:"drift"                   db:"drift"`
+	History      decimal.Decimal `json:"history"                 db:"history"`
+	HistoryDrift decimal.Decimal `json:"history_drift"           db:"history_drift"`
+	BrokenTxID   *uuid.UUID      `json:"broken_tx_id,omitempty"  db:"broken_tx_id"`
+	BrokenSeq    *int64          `json:"broken_seq,omitempty"    db:"broken_seq"`
+	Frozen       bool            `json:"frozen"                  db:"frozen"`
+}
+
+// Summary describes every check the wallet fails, for freeze reasons.
+func (d *WalletDrift) Summary() string {
+	var parts []string
+	if !d.Drift.IsZero() {
+		parts = append(parts, fmt.Sprintf("ledger drift %s TRY", d.Drift.StringFixed(4)))
+	}
+	if !d.HistoryDrift.IsZero() {
+		parts = append(parts, fmt.Sprintf("history drift %s TRY", d.HistoryDrift.StringFixed(4)))
+	}
+	if d.BrokenTxID != nil {
+		parts = append(parts, fmt.Sprintf("history broken at transaction %s", d.BrokenTxID))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ReconciliationRun is the stored result of one reconciliation pass.
+type ReconciliationRun struct {
+	ID             uuid.UUID       `json:"id"              db:"id"`
+	Source         string          `json:"source"          db:"source"`
+	TriggeredBy    *uuid.UUID      `json:"triggered_by"    db:"triggered_by"`
+	StartedAt      time.Time       `json:"started_at"      db:"started_at"`
+	FinishedAt     time.Time       `json:"finished_at"     db:"finished_at"`
+	WalletsChecked int             `json:"wallets_checked" db:"wallets_checked"`
+	Mismatched     int             `json:"mismatched"      db:"mismatched"`
+	TotalDrift     decimal.Decimal `json:"total_drift"     db:"total_drift"`
+	AbsDrift       decimal.Decimal `json:"abs_drift"       db:"abs_drift"`
+	Frozen         int             `json:"frozen"          db:"frozen"`
+	Drifts         types.JSONText  `json:"drifts"          db:"drifts"` // []WalletDrift
+}
+
+// IsPlatform reports whether the drift is on the platform MM wallet.
+func (d *WalletDrift) IsPlatform() bool { return d.UserID == nil }
+
+// Reconciled reports whether every wallet matched its history.
+func (r *ReconciliationRun) Reconciled() bool { return r.Mismatched == 0 }
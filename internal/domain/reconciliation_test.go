@@ -0,0 +1,40 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestWalletDrift_Summary(t *testing.T) {
+	broken := uuid.New()
+	d := domain.WalletDrift{
+		Drift:      decimal.Zero,
+		BrokenTxID: &broken,
+	}
+	if got := d.Summary(); got != "history broken at transaction "+broken.String() {
+		t.Errorf("chain break only: Summary() = %q", got)
+	}
+
+	d.Drift = decimal.NewFromInt(5)
+	d.HistoryDrift = decimal.NewFromInt(-5)
+	got := d.Summary()
+	for _, want := range []string{"ledger drift 5.0000 TRY", "history drift -5.0000 TRY", broken.String()} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Summary() = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestWalletDrift_IsPlatform(t *testing.T) {
+	user := uuid.New()
+	if (&domain.WalletDrift{UserID: &user}).IsPlatform() {
+		t.Error("user wallet reported as platform")
+	}
+	if !(&domain.WalletDrift{}).IsPlatform() {
+		t.Error("wallet without user must be the platform wallet")
+	}
+}
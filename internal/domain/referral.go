@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"crypto/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ReferralStatus represents the review state of a referral.
+type ReferralStatus string
+
+const (
+	ReferralActive  ReferralStatus = "active"  // earns commission for the referrer
+	ReferralFlagged ReferralStatus = "flagged" // held for risk review, earns nothing
+	ReferralBlocked ReferralStatus = "blocked" // rejected as fraudulent
+)
+
+// Reasons a referral is flagged at signup.
+const (
+	ReferralFlagSelf   = "self_referral" // referrer and new user look like the same person
+	ReferralFlagSameIP = "same_ip"       // signed up from an IP the referrer already used
+)
+
+// referralAlphabet leaves out 0/O and 1/I so that codes survive being read
+// out or typed by hand.
+const referralAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// referralCodeLen gives 32^8 ≈ 10^12 codes.
+const referralCodeLen = 8
+
+// NewReferralCode returns a random invite code.
+func NewReferralCode() string {
+	b := make([]byte, referralCodeLen)
+	if _, err := rand.Read(b); err != nil {
+		panic("domain: crypto/rand failed: " + err.Error())
+	}
+	for i := range b {
+		b[i] = referralAlphabet[int(b[i])%len(referralAlphabet)]
+	}
+	return string(b)
+}
+
+// NormalizeReferralCode upper-cases an invite code as typed by a user.
+func NormalizeReferralCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Referral attributes a user to the user whose invite code they signed up
+// with.
+type Referral struct {
+	ID         uuid.UUID      `json:"id"          db:"id"`
+	ReferrerID uuid.UUID      `json:"referrer_id" db:"referrer_id"`
+	ReferredID uuid.UUID      `json:"referred_id" db:"referred_id"`
+	Status     ReferralStatus `json:"status"      db:"status"`
+	FlagReason *string        `json:"flag_reason" db:"flag_reason"`
+	SignupIP   *string        `json:"signup_ip"   db:"signup_ip"`
+	ReviewedBy *uuid.UUID     `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedAt *time.Time     `json:"reviewed_at" db:"reviewed_at"`
+	ReviewNote *string        `json:"review_note" db:"review_note"`
+	CreatedAt  time.Time      `json:"created_at"  db:"created_at"`
+}
+
+// ReferralFlag returns the reason a new referral should be held for review,
+// or "" when it may earn at once.  sameIP reports whether the new user's
+// signup IP was already used by the referrer or by another user they
+// referred.
+func ReferralFlag(referrer, referred *User, sameIP bool) string {
+	if SamePerson(referrer, referred) {
+		return ReferralFlagSelf
+	}
+	if sameIP {
+		return ReferralFlagSameIP
+	}
+	return ""
+}
+
+// SamePerson reports whether two accounts look like they belong to one
+// person: the same mailbox once "+tag" suffixes are removed, or the same
+// legal name.
+func SamePerson(a, b *User) bool {
+	if canonicalEmail(a.Email) == canonicalEmail(b.Email) {
+		return true
+	}
+	na, nb := canonicalName(a.FullName), canonicalName(b.FullName)
+	return na != "" && na == nb
+}
+
+func canonicalEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndexByte(email, '@')
+	if at < 0 {
+		return email
+	}
+	local, host := email[:at], email[at:]
+	if plus := strings.IndexByte(local, '+'); plus >= 0 {
+		local = local[:plus]
+	}
+	return local + host
+}
+
+func canonicalName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// ReferralCommission is the commission share paid to a referrer for one
+// referred user and day.
+type ReferralCommission struct {
+	ID             uuid.UUID       `json:"id"              db:"id"`
+	ReferralID     uuid.UUID       `json:"referral_id"     db:"referral_id"`
+	ReferrerID     uuid.UUID       `json:"referrer_id"     db:"referrer_id"`
+	PeriodStart    time.Time       `json:"period_start"    db:"period_start"`
+	CommissionBase decimal.Decimal `json:"commission_base" db:"commission_base"`
+	ShareRate      decimal.Decimal `json:"share_rate"      db:"share_rate"`
+	Amount         decimal.Decimal `json:"amount"          db:"amount"`
+	CreatedAt      time.Time       `json:"created_at"      db:"created_at"`
+}
+
+// ReferralShare is the referrer's cut of base at rate, rounded down to the
+// ledger's precision.
+func ReferralShare(base, rate decimal.Decimal) decimal.Decimal {
+	return base.Mul(rate).RoundDown(4)
+}
+
+// ReferralStats is what a user sees about their own referrals.
+type ReferralStats struct {
+	Code      string          `json:"code"`
+	ShareRate decimal.Decimal `json:"share_rate"`
+	Referred  int             `json:"referred"  db:"referred"`
+	Active    int             `json:"active"    db:"active"`
+	Pending   int             `json:"pending"   db:"pending"` // flagged, awaiting review
+	Earned    decimal.Decimal `json:"earned"    db:"earned"`
+}
+
+// ReferralItem is a referral with both usernames for the back office.
+type ReferralItem struct {
+	Referral
+	ReferrerUsername string `json:"referrer_username" db:"referrer_username"`
+	ReferredUsername string `json:"referred_username" db:"referred_username"`
+}
+
+// ReferrerReport is one row of the back-office referral report.
+type ReferrerReport struct {
+	ReferrerID     uuid.UUID       `json:"referrer_id"     db:"referrer_id"`
+	Username       string          `json:"username"        db:"username"`
+	Referred       int             `json:"referred"        db:"referred"`
+	Active         int             `json:"active"          db:"active"`
+	Flagged        int             `json:"flagged"         db:"flagged"`
+	Blocked        int             `json:"blocked"         db:"blocked"`
+	CommissionPaid decimal.Decimal `json:"commission_paid" db:"commission_paid"`
+}
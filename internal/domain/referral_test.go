@@ -0,0 +1,58 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func TestNewReferralCode(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		code := domain.NewReferralCode()
+		if len(code) != 8 {
+			t.Fatalf("code %q has length %d, want 8", code, len(code))
+		}
+		if strings.ContainsAny(code, "01IO") || code != strings.ToUpper(code) {
+			t.Fatalf("code %q uses an ambiguous or lower-case character", code)
+		}
+		if seen[code] {
+			t.Fatalf("duplicate code %q", code)
+		}
+		seen[code] = true
+	}
+	if got := domain.NormalizeReferralCode(" ab3dkq7z "); got != "AB3DKQ7Z" {
+		t.Errorf("NormalizeReferralCode = %q", got)
+	}
+}
+
+func TestReferralFlag(t *testing.T) {
+	referrer := &domain.User{Email: "ayse.yilmaz@example.com", FullName: "Ayşe Yılmaz"}
+	tests := []struct {
+		name     string
+		referred domain.User
+		sameIP   bool
+		want     string
+	}{
+		{"unrelated", domain.User{Email: "mehmet@example.com", FullName: "Mehmet Kaya"}, false, ""},
+		{"no names", domain.User{Email: "mehmet@example.com"}, false, ""},
+		{"plus alias", domain.User{Email: "Ayse.Yilmaz+2@Example.com"}, false, domain.ReferralFlagSelf},
+		{"same legal name", domain.User{Email: "other@example.com", FullName: "  ayşe   yılmaz "}, false, domain.ReferralFlagSelf},
+		{"same ip", domain.User{Email: "mehmet@example.com"}, true, domain.ReferralFlagSameIP},
+		{"self wins over ip", domain.User{Email: "ayse.yilmaz+x@example.com"}, true, domain.ReferralFlagSelf},
+	}
+	for _, tc := range tests {
+		if got := domain.ReferralFlag(referrer, &tc.referred, tc.sameIP); got != tc.want {
+			t.Errorf("%s: ReferralFlag = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestReferralShare(t *testing.T) {
+	got := domain.ReferralShare(decimal.RequireFromString("12.34567"), decimal.RequireFromString("0.2"))
+	if want := decimal.RequireFromString("2.4691"); !got.Equal(want) {
+		t.Errorf("ReferralShare = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is an issued refresh token.  Only a keyed hash of the token
+// is stored.  Tokens descending from one login share a FamilyID: each
+// refresh revokes the presented token and points ReplacedBy at its
+// successor.
+type RefreshToken struct {
+	ID         uuid.UUID  `db:"id"`
+	UserID     uuid.UUID  `db:"user_id"`
+	FamilyID   uuid.UUID  `db:"family_id"`
+	TokenHash  string     `db:"token_hash"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	Revoked    bool       `db:"revoked"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	ReplacedBy *uuid.UUID `db:"replaced_by"` // set when rotated rather than logged out
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+// Rotated reports whether t was revoked by being exchanged for a new
+// token.  Presenting a rotated token again means it was copied.
+func (t *RefreshToken) Rotated() bool {
+	return t.Revoked && t.ReplacedBy != nil
+}
@@ -0,0 +1,26 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestRefreshToken_Rotated(t *testing.T) {
+	next := uuid.New()
+	cases := []struct {
+		name string
+		tok  domain.RefreshToken
+		want bool
+	}{
+		{"live", domain.RefreshToken{}, false},
+		{"logged out", domain.RefreshToken{Revoked: true}, false},
+		{"rotated", domain.RefreshToken{Revoked: true, ReplacedBy: &next}, true},
+	}
+	for _, c := range cases {
+		if got := c.tok.Rotated(); got != c.want {
+			t.Errorf("%s: Rotated() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// LimitKind is what a responsible-gambling limit caps.
+type LimitKind string
+
+const (
+	LimitDeposit LimitKind = "deposit" // deposits requested in the period
+	LimitLoss    LimitKind = "loss"    // real money staked minus real money returned
+	LimitStake   LimitKind = "stake"   // real money staked
+)
+
+// IsValid reports whether k is a known limit kind.
+func (k LimitKind) IsValid() bool {
+	return k == LimitDeposit || k == LimitLoss || k == LimitStake
+}
+
+// LimitPeriod is the window a limit applies to.
+type LimitPeriod string
+
+const (
+	PeriodDaily   LimitPeriod = "daily"
+	PeriodWeekly  LimitPeriod = "weekly"
+	PeriodMonthly LimitPeriod = "monthly"
+)
+
+// IsValid reports whether p is a known limit period.
+func (p LimitPeriod) IsValid() bool {
+	return p == PeriodDaily || p == PeriodWeekly || p == PeriodMonthly
+}
+
+// GamblingLimit is a user's cap on one kind of activity per period.
+// Lowering a limit applies at once; raising or removing one is queued in
+// the pending fields and applies from PendingFrom, so that a user cannot
+// loosen their limits on impulse.  A due change with a nil PendingAmount
+// removes the limit.
+type GamblingLimit struct {
+	UserID        uuid.UUID        `json:"user_id"        db:"user_id"`
+	Kind          LimitKind        `json:"kind"           db:"kind"`
+	Period        LimitPeriod      `json:"period"         db:"period"`
+	Amount        decimal.Decimal  `json:"amount"         db:"amount"`
+	PendingAmount *decimal.Decimal `json:"pending_amount" db:"pending_amount"`
+	PendingFrom   *time.Time       `json:"pending_from"   db:"pending_from"`
+	CreatedAt     time.Time        `json:"created_at"     db:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"     db:"updated_at"`
+}
+
+// Effective returns the limit in force at now; ok is false when a queued
+// removal has taken effect or, for a zero Amount, no limit was ever set.
+func (l *GamblingLimit) Effective(now time.Time) (amount decimal.Decimal, ok bool) {
+	if l.PendingFrom != nil && !now.Before(*l.PendingFrom) {
+		if l.PendingAmount == nil {
+			return decimal.Zero, false
+		}
+		return *l.PendingAmount, true
+	}
+	return l.Amount, l.Amount.IsPositive()
+}
+
+// Change applies a user's request to set the limit to amount (nil to
+// remove it) at now.  A stricter limit replaces the current one and
+// cancels any queued change; a looser one or a removal is queued to apply
+// after delay.  It reports whether the limit row is still needed: false
+// when there is no limit in force and nothing queued.
+func (l *GamblingLimit) Change(amount *decimal.Decimal, now time.Time, delay time.Duration) bool {
+	cur, set := l.Effective(now)
+	switch {
+	case amount == nil && !set:
+		return false
+	case amount != nil && (!set || amount.LessThanOrEqual(cur)):
+		l.Amount = *amount
+		l.PendingAmount, l.PendingFrom = nil, nil
+	default:
+		// Raising or removing: keep the current limit until the delay ends.
+		from := now.Add(delay)
+		l.Amount = cur
+		l.PendingAmount, l.PendingFrom = amount, &from
+	}
+	return true
+}
+
+// LimitUsage is a limit with how much of it the current period has used.
+type LimitUsage struct {
+	GamblingLimit
+	InForce     *decimal.Decimal `json:"in_force"` // nil once a removal has applied
+	Used        decimal.Decimal  `json:"used"`
+	PeriodStart time.Time        `json:"period_start"`
+}
+
+// ExclusionKind distinguishes a short break from a self-exclusion.
+type ExclusionKind string
+
+const (
+	// ExclusionCoolingOff blocks betting and deposits; the user can still log
+	// in and withdraw.
+	ExclusionCoolingOff ExclusionKind = "cooling_off"
+	// ExclusionSelf blocks logging in altogether.
+	ExclusionSelf ExclusionKind = "self_exclusion"
+)
+
+// IsValid reports whether k is a known exclusion kind.
+func (k ExclusionKind) IsValid() bool {
+	return k == ExclusionCoolingOff || k == ExclusionSelf
+}
+
+// SelfExclusion is a period during which the user asked to be kept from
+// playing.  It cannot be ended early.
+type SelfExclusion struct {
+	ID        uuid.UUID     `json:"id"         db:"id"`
+	UserID    uuid.UUID     `json:"user_id"    db:"user_id"`
+	Kind      ExclusionKind `json:"kind"       db:"kind"`
+	StartsAt  time.Time     `json:"starts_at"  db:"starts_at"`
+	EndsAt    *time.Time    `json:"ends_at"    db:"ends_at"` // nil = indefinite
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+}
+
+// ActiveAt reports whether the exclusion is in force at now.
+func (e *SelfExclusion) ActiveAt(now time.Time) bool {
+	return !now.Before(e.StartsAt) && (e.EndsAt == nil || now.Before(*e.EndsAt))
+}
+
+// Err returns the error reported to a user blocked by e.
+func (e *SelfExclusion) Err() error {
+	until := "indefinitely"
+	if e.EndsAt != nil {
+		until = "until " + e.EndsAt.UTC().Format(time.RFC3339)
+	}
+	if e.Kind == ExclusionSelf {
+		return fmt.Errorf("%w %s", ErrSelfExcluded, until)
+	}
+	return fmt.Errorf("%w %s", ErrCoolingOff, until)
+}
+
+// Covers reports whether e is at least as strict as an exclusion of kind
+// ending at endsAt (nil = indefinite), so that the new one would change
+// nothing.
+func (e *SelfExclusion) Covers(kind ExclusionKind, endsAt *time.Time) bool {
+	if e.Kind == ExclusionCoolingOff && kind == ExclusionSelf {
+		return false
+	}
+	if e.EndsAt == nil {
+		return true
+	}
+	return endsAt != nil && !endsAt.After(*e.EndsAt)
+}
+
+// SelfExclusionItem is an exclusion with the user's name for the back
+// office.
+type SelfExclusionItem struct {
+	SelfExclusion
+	Username string `json:"username" db:"username"`
+}
+
+// ResponsibleOverview is what a user sees of their own protections: every
+// limit with its usage and the exclusion in force, if any.
+type ResponsibleOverview struct {
+	Limits    []*LimitUsage  `json:"limits"`
+	Exclusion *SelfExclusion `json:"exclusion"`
+}
@@ -0,0 +1,91 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func decPtr(s string) *decimal.Decimal {
+	d := dec(s)
+	return &d
+}
+
+func TestGamblingLimit_Change(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	delay := 24 * time.Hour
+
+	// A new limit applies at once.
+	l := &domain.GamblingLimit{}
+	if !l.Change(decPtr("100"), now, delay) {
+		t.Fatal("new limit: row not kept")
+	}
+	if got, ok := l.Effective(now); !ok || !got.Equal(decimal.NewFromInt(100)) || l.PendingFrom != nil {
+		t.Fatalf("new limit: effective %s/%v, pending %v", got, ok, l.PendingFrom)
+	}
+
+	// Raising is queued until the delay has passed.
+	l.Change(decPtr("500"), now, delay)
+	if got, _ := l.Effective(now.Add(delay - time.Second)); !got.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("raise before delay: effective %s, want 100", got)
+	}
+	if got, _ := l.Effective(now.Add(delay)); !got.Equal(decimal.NewFromInt(500)) {
+		t.Errorf("raise after delay: effective %s, want 500", got)
+	}
+
+	// Lowering applies at once and cancels the queued raise.
+	l.Change(decPtr("50"), now.Add(time.Hour), delay)
+	if got, _ := l.Effective(now.Add(48 * time.Hour)); !got.Equal(decimal.NewFromInt(50)) || l.PendingFrom != nil {
+		t.Errorf("lower: effective %s, pending %v", got, l.PendingFrom)
+	}
+
+	// Removing is queued too.
+	l.Change(nil, now, delay)
+	if _, ok := l.Effective(now); !ok {
+		t.Error("remove before delay: limit gone")
+	}
+	if _, ok := l.Effective(now.Add(delay)); ok {
+		t.Error("remove after delay: limit still in force")
+	}
+
+	// Once removed, a new limit applies at once and removing again drops the row.
+	later := now.Add(2 * delay)
+	if l.Change(nil, later, delay) {
+		t.Error("remove with no limit in force: row kept")
+	}
+	l.Change(decPtr("1000"), later, delay)
+	if got, ok := l.Effective(later); !ok || !got.Equal(decimal.NewFromInt(1000)) {
+		t.Errorf("set after removal: effective %s/%v", got, ok)
+	}
+}
+
+func TestSelfExclusion(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	week := now.Add(7 * 24 * time.Hour)
+	month := now.Add(30 * 24 * time.Hour)
+
+	cooling := &domain.SelfExclusion{Kind: domain.ExclusionCoolingOff, StartsAt: now, EndsAt: &week}
+	if !cooling.ActiveAt(now) || cooling.ActiveAt(week) {
+		t.Error("cooling-off active window wrong")
+	}
+	if !errors.Is(cooling.Err(), domain.ErrCoolingOff) {
+		t.Errorf("cooling-off Err = %v", cooling.Err())
+	}
+	if !cooling.Covers(domain.ExclusionCoolingOff, &now) || cooling.Covers(domain.ExclusionCoolingOff, &month) {
+		t.Error("cooling-off must cover shorter breaks only")
+	}
+	if cooling.Covers(domain.ExclusionSelf, &now) {
+		t.Error("cooling-off must not cover a self-exclusion")
+	}
+
+	forever := &domain.SelfExclusion{Kind: domain.ExclusionSelf, StartsAt: now}
+	if !forever.ActiveAt(month) || !errors.Is(forever.Err(), domain.ErrSelfExcluded) {
+		t.Error("indefinite self-exclusion must stay active")
+	}
+	if !forever.Covers(domain.ExclusionSelf, nil) || !forever.Covers(domain.ExclusionCoolingOff, &month) {
+		t.Error("indefinite self-exclusion must cover everything")
+	}
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// AccountStatement is a user's monthly account statement: the wallet's
+// balance at both ends of a business month and the movements between them
+// by category.  Movements are signed, so OpeningBalance plus every category
+// equals ClosingBalance.  The rendered files live in file storage.
+type AccountStatement struct {
+	ID             uuid.UUID       `json:"id"              db:"id"`
+	UserID         uuid.UUID       `json:"user_id"         db:"user_id"`
+	Month          string          `json:"month"           db:"month"` // YYYY-MM, business timezone
+	PeriodStart    time.Time       `json:"period_start"    db:"period_start"`
+	PeriodEnd      time.Time       `json:"period_end"      db:"period_end"` // exclusive
+	OpeningBalance decimal.Decimal `json:"opening_balance" db:"opening_balance"`
+	ClosingBalance decimal.Decimal `json:"closing_balance" db:"closing_balance"`
+	Deposits       decimal.Decimal `json:"deposits"        db:"deposits"`
+	Withdrawals    decimal.Decimal `json:"withdrawals"     db:"withdrawals"`
+	Stakes         decimal.Decimal `json:"stakes"          db:"stakes"`
+	Payouts        decimal.Decimal `json:"payouts"         db:"payouts"` // payouts, cash-outs and refunds
+	Bonuses        decimal.Decimal `json:"bonuses"         db:"bonuses"` // bonus conversions and referral shares
+	Other          decimal.Decimal `json:"other"           db:"other"`   // back-office adjustments and anything else
+	Fees           decimal.Decimal `json:"fees"            db:"fees"`    // cash-out fees, already netted from Payouts
+	TxCount        int             `json:"tx_count"        db:"tx_count"`
+	PDFKey         string          `json:"-"               db:"pdf_key"`
+	CSVKey         string          `json:"-"               db:"csv_key"`
+	GeneratedAt    time.Time       `json:"generated_at"    db:"generated_at"`
+}
+
+// NewAccountStatement starts a statement for the period with the wallet's
+// balance at its start; Add the period's transactions.
+func NewAccountStatement(userID uuid.UUID, month string, start, end time.Time, opening decimal.Decimal) *AccountStatement {
+	return &AccountStatement{
+		ID:             uuid.New(),
+		UserID:         userID,
+		Month:          month,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		OpeningBalance: opening,
+		ClosingBalance: opening,
+	}
+}
+
+// Add books t, a transaction of the period, under its category.  The
+// closing balance moves by t's delta rather than taking its balance_after,
+// so it does not depend on the order of transactions posted together.
+func (s *AccountStatement) Add(t *Transaction) {
+	delta := t.BalanceAfter.Sub(t.BalanceBefore)
+	switch t.Type {
+	case TxDeposit:
+		s.Deposits = s.Deposits.Add(delta)
+	case TxWithdraw:
+		s.Withdrawals = s.Withdrawals.Add(delta)
+	case TxBetLock, TxBetUnlock:
+		s.Stakes = s.Stakes.Add(delta)
+	case TxPayout, TxCashout, TxRefund:
+		s.Payouts = s.Payouts.Add(delta)
+	case TxBonus, TxReferral:
+		s.Bonuses = s.Bonuses.Add(delta)
+	default:
+		s.Other = s.Other.Add(delta)
+	}
+	s.ClosingBalance = s.ClosingBalance.Add(delta)
+	s.TxCount++
+}
@@ -0,0 +1,82 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestAccountStatement_Add(t *testing.T) {
+	start := time.Date(2026, 2, 28, 21, 0, 0, 0, time.UTC)
+	s := domain.NewAccountStatement(uuid.New(), "2026-03", start, start.AddDate(0, 1, 0), decimal.NewFromInt(50))
+
+	bal := decimal.NewFromInt(50)
+	move := func(typ domain.TxType, delta int64) {
+		after := bal.Add(decimal.NewFromInt(delta))
+		s.Add(&domain.Transaction{Type: typ, Amount: decimal.NewFromInt(delta).Abs(), BalanceBefore: bal, BalanceAfter: after})
+		bal = after
+	}
+	move(domain.TxDeposit, 1000)
+	move(domain.TxBetLock, -300)
+	move(domain.TxPayout, 540)
+	move(domain.TxBetLock, -200)
+	move(domain.TxCashout, 150)
+	move(domain.TxRefund, 100)
+	move(domain.TxReferral, 12)
+	move(domain.TxWithdraw, -800)
+
+	checks := []struct {
+		name      string
+		got, want decimal.Decimal
+	}{
+		{"deposits", s.Deposits, decimal.NewFromInt(1000)},
+		{"withdrawals", s.Withdrawals, decimal.NewFromInt(-800)},
+		{"stakes", s.Stakes, decimal.NewFromInt(-500)},
+		{"payouts", s.Payouts, decimal.NewFromInt(790)},
+		{"bonuses", s.Bonuses, decimal.NewFromInt(12)},
+		{"other", s.Other, decimal.Zero},
+		{"closing", s.ClosingBalance, bal},
+	}
+	for _, c := range checks {
+		if !c.got.Equal(c.want) {
+			t.Errorf("%s = %s, want %s", c.name, c.got, c.want)
+		}
+	}
+	sum := s.OpeningBalance.Add(s.Deposits).Add(s.Withdrawals).Add(s.Stakes).Add(s.Payouts).Add(s.Bonuses).Add(s.Other)
+	if !sum.Equal(s.ClosingBalance) {
+		t.Errorf("opening + movements = %s, closing = %s", sum, s.ClosingBalance)
+	}
+	if s.TxCount != 8 {
+		t.Errorf("tx count = %d, want 8", s.TxCount)
+	}
+}
+
+func TestAccountStatement_NoActivity(t *testing.T) {
+	s := domain.NewAccountStatement(uuid.New(), "2026-03", time.Now(), time.Now(), decimal.NewFromInt(75))
+	if !s.ClosingBalance.Equal(decimal.NewFromInt(75)) || s.TxCount != 0 {
+		t.Errorf("closing = %s, count = %d; want the opening balance and no transactions", s.ClosingBalance, s.TxCount)
+	}
+}
+
+func TestAccountStatement_PostingsInOneTransaction(t *testing.T) {
+	// Two winning bets paid to one wallet at settlement: both rows carry the
+	// DB transaction's start time, so their order is not known
+	at := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	first := &domain.Transaction{Type: domain.TxPayout, CreatedAt: at, Seq: 1,
+		BalanceBefore: decimal.NewFromInt(50), BalanceAfter: decimal.NewFromInt(150)}
+	second := &domain.Transaction{Type: domain.TxPayout, CreatedAt: at, Seq: 2,
+		BalanceBefore: decimal.NewFromInt(150), BalanceAfter: decimal.NewFromInt(190)}
+
+	for _, order := range [][]*domain.Transaction{{first, second}, {second, first}} {
+		s := domain.NewAccountStatement(uuid.New(), "2026-03", at.AddDate(0, 0, -9), at.AddDate(0, 0, 21), decimal.NewFromInt(50))
+		for _, tx := range order {
+			s.Add(tx)
+		}
+		if !s.ClosingBalance.Equal(decimal.NewFromInt(190)) || !s.Payouts.Equal(decimal.NewFromInt(140)) {
+			t.Errorf("seq %d first: closing = %s, payouts = %s; want 190 and 140", order[0].Seq, s.ClosingBalance, s.Payouts)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestTxCursor_RoundTrip(t *testing.T) {
+	c := domain.CursorAfter(&domain.Transaction{ID: uuid.New(), CreatedAt: time.Now(), Seq: 4821})
+
+	got, err := domain.ParseTxCursor(c.String())
+	if err != nil {
+		t.Fatalf("ParseTxCursor: %v", err)
+	}
+	if got != c {
+		t.Errorf("round trip = %+v, want %+v", got, c)
+	}
+}
+
+func TestParseTxCursor_Invalid(t *testing.T) {
+	for _, s := range []string{"", "!!!", "bm90LWEtY3Vyc29y", "MTIzX25vdC1hLXV1aWQ", "MA", "LTU"} {
+		if _, err := domain.ParseTxCursor(s); !errors.Is(err, domain.ErrInvalidCursor) {
+			t.Errorf("ParseTxCursor(%q) = %v, want ErrInvalidCursor", s, err)
+		}
+	}
+}
+
+func TestTxType_IsValid(t *testing.T) {
+	if !domain.TxMMPayout.IsValid() || !domain.TxDeposit.IsValid() {
+		t.Error("known types must be valid")
+	}
+	if domain.TxType("adjust").IsValid() {
+		t.Error("unknown type must not be valid")
+	}
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// TreasuryEntry is the house's take from one market (house_treasury row).
+// Each field accumulates as events happen: cashout fees and PnL while the
+// market is open, commission and MM PnL at settlement.
+type TreasuryEntry struct {
+	ID                uuid.UUID       `json:"id"                  db:"id"`
+	MarketID          uuid.UUID       `json:"market_id"           db:"market_id"`
+	CommissionEarned  decimal.Decimal `json:"commission_earned"   db:"commission_earned"`
+	MMPnL             decimal.Decimal `json:"mm_pnl"              db:"mm_pnl"`
+	CashoutFeesEarned decimal.Decimal `json:"cashout_fees_earned" db:"cashout_fees_earned"`
+	CashoutPnL        decimal.Decimal `json:"cashout_pnl"         db:"cashout_pnl"` // stake minus gross exit value; may be negative
+	CreatedAt         time.Time       `json:"created_at"          db:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"          db:"updated_at"`
+}
+
+// Net returns commission + MM PnL + cashout fees + cashout PnL.
+func (t *TreasuryEntry) Net() decimal.Decimal {
+	return t.CommissionEarned.Add(t.MMPnL).Add(t.CashoutFeesEarned).Add(t.CashoutPnL)
+}
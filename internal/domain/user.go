@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,14 +18,24 @@ import (
 type UserRole string
 
 const (
-	RoleUser     UserRole = "user"     // standard bettor
-	RoleAdmin    UserRole = "admin"    // full back-office access
-	RoleRisk     UserRole = "risk"     // risk management view
-	RoleFinance  UserRole = "finance"  // financial reports, withdrawals
-	RoleOps      UserRole = "ops"      // operations: market management
-	RoleReadOnly UserRole = "readonly" // read-only back-office access
+	RoleUser      UserRole = "user"      // standard bettor
+	RoleAdmin     UserRole = "admin"     // full back-office access
+	RoleRisk      UserRole = "risk"      // risk management view
+	RoleFinance   UserRole = "finance"   // financial reports, withdrawals
+	RoleOps       UserRole = "ops"       // operations: market management
+	RoleMarketing UserRole = "marketing" // promotion campaigns
+	RoleReadOnly  UserRole = "readonly"  // read-only back-office access
 )
 
+// IsValid reports whether r is a known role.
+func (r UserRole) IsValid() bool {
+	switch r {
+	case RoleUser, RoleAdmin, RoleRisk, RoleFinance, RoleOps, RoleMarketing, RoleReadOnly:
+		return true
+	}
+	return false
+}
+
 // CanAccessBackoffice returns true for all non-standard roles.
 func (r UserRole) CanAccessBackoffice() bool {
 	return r != RoleUser
@@ -39,14 +52,18 @@ func (r UserRole) IsAdmin() bool {
 
 // User is the domain entity for registered accounts.
 type User struct {
-	ID           uuid.UUID `json:"id"         db:"id"`
-	Email        string    `json:"email"      db:"email"`
-	Username     string    `json:"username"   db:"username"`
-	PasswordHash string    `json:"-"          db:"password_hash"` // never serialised
-	Role         UserRole  `json:"role"       db:"role"`
-	IsActive     bool      `json:"is_active"  db:"is_active"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID           uuid.UUID `json:"id"            db:"id"`
+	Email        string    `json:"email"         db:"email"`
+	Username     string    `json:"username"      db:"username"`
+	FullName     string    `json:"full_name"     db:"full_name"`     // legal name; bank account holders must match it
+	PasswordHash string    `json:"-"             db:"password_hash"` // never serialised
+	Role         UserRole  `json:"role"          db:"role"`
+	IsActive     bool      `json:"is_active"     db:"is_active"`
+	ReferralCode *string   `json:"referral_code" db:"referral_code"` // invite code; nil until first needed
+	KYCStatus    KYCStatus `json:"kyc_status"    db:"kyc_status"`
+	SignupIP     *string   `json:"-"             db:"signup_ip"`
+	CreatedAt    time.Time `json:"created_at"    db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"    db:"updated_at"`
 }
 
 // PublicProfile returns a user view safe to expose via API (no password hash).
@@ -54,8 +71,10 @@ type PublicProfile struct {
 	ID        uuid.UUID `json:"id"`
 	Email     string    `json:"email"`
 	Username  string    `json:"username"`
+	FullName  string    `json:"full_name"`
 	Role      UserRole  `json:"role"`
 	IsActive  bool      `json:"is_active"`
+	KYCStatus KYCStatus `json:"kyc_status"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -65,8 +84,10 @@ func (u *User) ToPublicProfile() PublicProfile {
 		ID:        u.ID,
 		Email:     u.Email,
 		Username:  u.Username,
+		FullName:  u.FullName,
 		Role:      u.Role,
 		IsActive:  u.IsActive,
+		KYCStatus: u.KYCStatus,
 		CreatedAt: u.CreatedAt,
 	}
 }
@@ -77,16 +98,19 @@ func (u *User) ToPublicProfile() PublicProfile {
 
 // Wallet holds a user's TRY balance.
 type Wallet struct {
-	ID         uuid.UUID       `json:"id"          db:"id"`
-	UserID     uuid.UUID       `json:"user_id"     db:"user_id"`
-	WalletType *string         `json:"wallet_type" db:"wallet_type"` // NULL=user, 'platform_mm'=house
-	Balance    decimal.Decimal `json:"balance"     db:"balance"`
-	Locked     decimal.Decimal `json:"locked"      db:"locked"` // reserved for open bets
-	CreatedAt  time.Time       `json:"created_at"  db:"created_at"`
-	UpdatedAt  time.Time       `json:"updated_at"  db:"updated_at"`
+	ID           uuid.UUID       `json:"id"            db:"id"`
+	UserID       uuid.UUID       `json:"user_id"       db:"user_id"`
+	WalletType   *string         `json:"wallet_type"   db:"wallet_type"` // NULL=user, 'platform_mm'=house
+	Balance      decimal.Decimal `json:"balance"       db:"balance"`
+	Locked       decimal.Decimal `json:"locked"        db:"locked"` // held for pending withdrawals
+	Frozen       bool            `json:"frozen"        db:"frozen"` // debits refused until finance unfreezes
+	FrozenReason string          `json:"frozen_reason" db:"frozen_reason"`
+	FrozenAt     *time.Time      `json:"frozen_at"     db:"frozen_at"`
+	CreatedAt    time.Time       `json:"created_at"    db:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"    db:"updated_at"`
 }
 
-// Available returns the balance that is free to use (not locked for bets).
+// Available returns the balance that is free to use (not held for withdrawals).
 func (w *Wallet) Available() decimal.Decimal {
 	return w.Balance.Sub(w.Locked)
 }
@@ -107,10 +131,25 @@ const (
 	TxCashout    TxType = "cashout"
 	TxCommission TxType = "commission"
 	TxRefund     TxType = "refund"
-	TxBonus      TxType = "bonus" // registration / promotional bonus
+	TxBonus      TxType = "bonus"      // registration / promotional bonus
+	TxReferral   TxType = "referral"   // commission share paid to a referrer
+	TxMMStake    TxType = "mm_stake"   // platform wallet → market pool, ref_id = market
+	TxMMPayout   TxType = "mm_payout"  // market settlement → platform wallet, ref_id = market
+	TxAdjustment TxType = "adjustment" // approved back-office balance adjustment, ref_id = adjustment
 )
 
-// Transaction is an immutable audit record for every wallet balance change.
+// IsValid reports whether t is a known transaction type.
+func (t TxType) IsValid() bool {
+	switch t {
+	case TxDeposit, TxWithdraw, TxBetLock, TxBetUnlock, TxPayout, TxCashout, TxCommission,
+		TxRefund, TxBonus, TxReferral, TxMMStake, TxMMPayout, TxAdjustment:
+		return true
+	}
+	return false
+}
+
+// Transaction is an immutable audit record for every wallet balance change,
+// written by the ledger when an entry touches the wallet.
 type Transaction struct {
 	ID            uuid.UUID       `json:"id"             db:"id"`
 	WalletID      uuid.UUID       `json:"wallet_id"      db:"wallet_id"`
@@ -120,7 +159,50 @@ type Transaction struct {
 	BalanceAfter  decimal.Decimal `json:"balance_after"  db:"balance_after"`
 	RefID         *uuid.UUID      `json:"ref_id"         db:"ref_id"` // bet or market ID
 	Description   string          `json:"description"    db:"description"`
+	EntryID       *uuid.UUID      `json:"entry_id"       db:"entry_id"` // ledger entry that made the change
 	CreatedAt     time.Time       `json:"created_at"     db:"created_at"`
+	Seq           int64           `json:"-"              db:"seq"` // posting order; created_at ties within a DB transaction
+}
+
+// TxFilter narrows a wallet's transaction history.  Zero fields match
+// everything; From is inclusive and To exclusive.
+type TxFilter struct {
+	Types []TxType
+	From  *time.Time
+	To    *time.Time
+	RefID *uuid.UUID // bet or market
+}
+
+// TxCursor marks a position in transaction history, which is listed newest
+// first in posting order (seq): the next page starts after this transaction.
+type TxCursor struct {
+	Seq int64
+}
+
+// CursorAfter returns the cursor pointing past t.
+func CursorAfter(t *Transaction) TxCursor {
+	return TxCursor{Seq: t.Seq}
+}
+
+// String encodes the cursor as an opaque, URL-safe token.
+func (c TxCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.Seq, 10)))
+}
+
+// ParseTxCursor decodes a token made by TxCursor.String.
+func ParseTxCursor(s string) (TxCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return TxCursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	seq, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return TxCursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if seq <= 0 {
+		return TxCursor{}, ErrInvalidCursor
+	}
+	return TxCursor{Seq: seq}, nil
 }
 
 // ──────────────────────────────────────────────────────────────────────────────
@@ -131,22 +213,33 @@ type Transaction struct {
 type WithdrawStatus string
 
 const (
-	WithdrawPending   WithdrawStatus = "pending"
-	WithdrawApproved  WithdrawStatus = "approved"
-	WithdrawRejected  WithdrawStatus = "rejected"
-	WithdrawCompleted WithdrawStatus = "completed"
+	WithdrawPending    WithdrawStatus = "pending"    // funds held in wallets.locked
+	WithdrawApproved   WithdrawStatus = "approved"   // wallet debited, waiting for payout
+	WithdrawProcessing WithdrawStatus = "processing" // sent to the payout provider
+	WithdrawCompleted  WithdrawStatus = "completed"  // paid out
+	WithdrawFailed     WithdrawStatus = "failed"     // payout failed, debit refunded
+	WithdrawRejected   WithdrawStatus = "rejected"   // hold released by finance
+	WithdrawCancelled  WithdrawStatus = "cancelled"  // hold released by the user
 )
 
 // WithdrawRequest is submitted by a user who wants to withdraw TRY.
 type WithdrawRequest struct {
-	ID          uuid.UUID       `json:"id"           db:"id"`
-	UserID      uuid.UUID       `json:"user_id"      db:"user_id"`
-	Amount      decimal.Decimal `json:"amount"       db:"amount"`
-	Status      WithdrawStatus  `json:"status"       db:"status"`
-	IBAN        string          `json:"iban"         db:"iban"`
-	Note        string          `json:"note"         db:"note"`
-	ReviewedBy  *uuid.UUID      `json:"reviewed_by"  db:"reviewed_by"`
-	ReviewNote  string          `json:"review_note"  db:"review_note"`
-	RequestedAt time.Time       `json:"requested_at" db:"requested_at"`
-	ReviewedAt  *time.Time      `json:"reviewed_at"  db:"reviewed_at"`
+	ID             uuid.UUID       `json:"id"              db:"id"`
+	UserID         uuid.UUID       `json:"user_id"         db:"user_id"`
+	Amount         decimal.Decimal `json:"amount"          db:"amount"`
+	Status         WithdrawStatus  `json:"status"          db:"status"`
+	IBAN           string          `json:"iban"            db:"iban"`
+	Note           string          `json:"note"            db:"note"`
+	ReviewedBy     *uuid.UUID      `json:"reviewed_by"     db:"reviewed_by"`
+	ReviewNote     string          `json:"review_note"     db:"review_note"`
+	PayoutProvider *string         `json:"payout_provider" db:"payout_provider"`
+	PayoutRef      *string         `json:"payout_ref"      db:"payout_ref"`
+	BankAccountID  *uuid.UUID      `json:"bank_account_id" db:"bank_account_id"`
+	BatchID        *uuid.UUID      `json:"batch_id"        db:"batch_id"` // bank payout batch, if any
+	FailureReason  string          `json:"failure_reason"  db:"failure_reason"`
+	AMLHold        bool            `json:"aml_hold,omitempty" db:"aml_hold"` // approval blocked by an AML case; never shown to the user
+	RequestedAt    time.Time       `json:"requested_at"    db:"requested_at"`
+	ReviewedAt     *time.Time      `json:"reviewed_at"     db:"reviewed_at"`
+	ProcessedAt    *time.Time      `json:"processed_at"    db:"processed_at"` // handed to the payout provider
+	CompletedAt    *time.Time      `json:"completed_at"    db:"completed_at"` // completed or failed
 }
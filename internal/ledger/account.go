@@ -0,0 +1,65 @@
+package ledger
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Account identifies a ledger account by its code, e.g. "user:<id>" or
+// "house:commission".
+type Account string
+
+// Fixed accounts.
+const (
+	HouseMM            Account = "house:mm"                      // platform MM wallet
+	HouseCommission    Account = "house:commission"              // pari-mutuel commission kept at settlement
+	HouseCashoutFees   Account = "house:cashout_fees"            // fee charged on early exits
+	HouseCashoutPnL    Account = "house:cashout_pnl"             // stake minus gross exit value on early exits
+	HouseBonus         Account = "house:bonus"                   // bonuses paid to users
+	HouseFreeBets      Account = "house:free_bets"               // stakes of free bets; winners keep only the winnings
+	HouseAdjustments   Account = "house:adjustments"             // manual back-office corrections
+	PendingWithdrawals Account = "liability:pending_withdrawals" // reserved for approved withdrawals
+	ExternalPayments   Account = "external:payments"             // money entering or leaving the platform
+	OpeningEquity      Account = "equity:opening"                // balances carried in by migration 010
+)
+
+const (
+	userPrefix   = "user:"
+	escrowPrefix = "escrow:market:"
+	bonusPrefix  = "bonus:user:"
+)
+
+// User returns the wallet account of a user.
+func User(userID uuid.UUID) Account { return Account(userPrefix + userID.String()) }
+
+// MarketEscrow returns the account holding a market's pools.
+func MarketEscrow(marketID uuid.UUID) Account { return Account(escrowPrefix + marketID.String()) }
+
+// UserBonus returns the bonus balance account of a user.  Bonus money can
+// only be staked; it moves to the wallet once its grant's wagering is met.
+func UserBonus(userID uuid.UUID) Account { return Account(bonusPrefix + userID.String()) }
+
+// UserID returns the user of a user wallet account.
+func (a Account) UserID() (uuid.UUID, bool) {
+	s, ok := strings.CutPrefix(string(a), userPrefix)
+	if !ok {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(s)
+	return id, err == nil
+}
+
+// IsWallet reports whether the account's balance lives in the wallets table.
+func (a Account) IsWallet() bool {
+	_, ok := a.UserID()
+	return ok || a == HouseMM
+}
+
+// NoOverdraft reports whether the account may never go below zero.  User
+// wallets are further limited to their available (unlocked) balance.
+func (a Account) NoOverdraft() bool {
+	return a.IsWallet() ||
+		strings.HasPrefix(string(a), escrowPrefix) ||
+		strings.HasPrefix(string(a), bonusPrefix)
+}
@@ -0,0 +1,102 @@
+package ledger
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrEmptyEntry is returned when an entry moves no money.
+	ErrEmptyEntry = errors.New("ledger: entry has no lines")
+	// ErrUnbalanced is returned when an entry's lines do not sum to zero.
+	ErrUnbalanced = errors.New("ledger: entry does not balance")
+)
+
+// Line is one leg of an entry.  Amount is signed: positive increases the
+// account's balance, negative decreases it.
+type Line struct {
+	Account      Account         `json:"account"       db:"account"`
+	Amount       decimal.Decimal `json:"amount"        db:"amount"`
+	BalanceAfter decimal.Decimal `json:"balance_after" db:"balance_after"` // set by Post
+}
+
+// Entry is a journal entry: a set of lines that sum to zero, posted
+// atomically.  Kind doubles as the wallet_transactions type written for
+// every wallet account the entry touches.
+type Entry struct {
+	ID          uuid.UUID
+	Kind        domain.TxType
+	RefID       *uuid.UUID // bet, market or request; nil when there is none
+	Description string
+	CreatedBy   *uuid.UUID // admin for manual entries
+	Lines       []Line
+}
+
+// NewEntry starts an entry.  Pass uuid.Nil when there is no reference.
+func NewEntry(kind domain.TxType, refID uuid.UUID, description string) *Entry {
+	e := &Entry{ID: uuid.New(), Kind: kind, Description: description}
+	if refID != uuid.Nil {
+		e.RefID = &refID
+	}
+	return e
+}
+
+// Transfer moves amount from one account to another.  A negative amount
+// moves it the other way; zero is ignored.
+func (e *Entry) Transfer(from, to Account, amount decimal.Decimal) *Entry {
+	if amount.IsZero() {
+		return e
+	}
+	e.Lines = append(e.Lines,
+		Line{Account: from, Amount: amount.Neg()},
+		Line{Account: to, Amount: amount},
+	)
+	return e
+}
+
+// Validate checks that the entry moves money and balances.
+func (e *Entry) Validate() error {
+	sum := decimal.Zero
+	for _, l := range e.Lines {
+		sum = sum.Add(l.Amount)
+	}
+	if len(e.Net()) == 0 {
+		return ErrEmptyEntry
+	}
+	if !sum.IsZero() {
+		return ErrUnbalanced
+	}
+	return nil
+}
+
+// Net returns one line per account with the amounts summed, sorted by
+// account code so concurrent postings lock rows in the same order.  Accounts
+// that net to zero are dropped.
+func (e *Entry) Net() []Line {
+	totals := map[Account]decimal.Decimal{}
+	for _, l := range e.Lines {
+		totals[l.Account] = totals[l.Account].Add(l.Amount)
+	}
+	out := make([]Line, 0, len(totals))
+	for a, amt := range totals {
+		if !amt.IsZero() {
+			out = append(out, Line{Account: a, Amount: amt})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Account < out[j].Account })
+	return out
+}
+
+// BalanceAfter returns the balance of a after the entry was posted.
+func (e *Entry) BalanceAfter(a Account) (decimal.Decimal, bool) {
+	for _, l := range e.Lines {
+		if l.Account == a {
+			return l.BalanceAfter, true
+		}
+	}
+	return decimal.Zero, false
+}
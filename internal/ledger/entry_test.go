@@ -0,0 +1,111 @@
+package ledger_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/ledger"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func dec(s string) decimal.Decimal { return decimal.RequireFromString(s) }
+
+// TestEntry_CashoutNets builds the cashout entry from BetService.ExitBet for a
+// 100 TRY stake exited at 95 with a 5 TRY fee (gross exit 100): the escrow
+// lines net to one debit and the zero cashout PnL leg disappears.
+func TestEntry_CashoutNets(t *testing.T) {
+	user, market := uuid.New(), uuid.New()
+	escrow := ledger.MarketEscrow(market)
+
+	e := ledger.NewEntry(domain.TxCashout, uuid.New(), "cashout").
+		Transfer(escrow, ledger.User(user), dec("95")).
+		Transfer(escrow, ledger.HouseCashoutFees, dec("5")).
+		Transfer(escrow, ledger.HouseCashoutPnL, dec("0"))
+	if err := e.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	want := map[ledger.Account]string{
+		escrow:                  "-100",
+		ledger.User(user):       "95",
+		ledger.HouseCashoutFees: "5",
+	}
+	net := e.Net()
+	if len(net) != len(want) {
+		t.Fatalf("Net has %d lines, want %d: %v", len(net), len(want), net)
+	}
+	for i, l := range net {
+		if i > 0 && net[i-1].Account >= l.Account {
+			t.Errorf("Net not sorted by account: %s before %s", net[i-1].Account, l.Account)
+		}
+		if !l.Amount.Equal(dec(want[l.Account])) {
+			t.Errorf("%s = %s, want %s", l.Account, l.Amount, want[l.Account])
+		}
+	}
+}
+
+func TestEntry_NegativeTransferReverses(t *testing.T) {
+	user := ledger.User(uuid.New())
+	e := ledger.NewEntry(domain.TxWithdraw, uuid.Nil, "debit").
+		Transfer(ledger.HouseAdjustments, user, dec("-40"))
+	if e.RefID != nil {
+		t.Error("uuid.Nil ref should be stored as nil")
+	}
+	for _, l := range e.Net() {
+		if l.Account == user && !l.Amount.Equal(dec("-40")) {
+			t.Errorf("user line = %s, want -40", l.Amount)
+		}
+	}
+}
+
+func TestEntry_Validate(t *testing.T) {
+	empty := ledger.NewEntry(domain.TxBonus, uuid.Nil, "")
+	if err := empty.Validate(); !errors.Is(err, ledger.ErrEmptyEntry) {
+		t.Errorf("empty entry: %v, want ErrEmptyEntry", err)
+	}
+
+	selfTransfer := ledger.NewEntry(domain.TxBonus, uuid.Nil, "").
+		Transfer(ledger.HouseBonus, ledger.HouseBonus, dec("10"))
+	if err := selfTransfer.Validate(); !errors.Is(err, ledger.ErrEmptyEntry) {
+		t.Errorf("self transfer: %v, want ErrEmptyEntry", err)
+	}
+
+	unbalanced := &ledger.Entry{Lines: []ledger.Line{
+		{Account: ledger.HouseBonus, Amount: dec("-10")},
+		{Account: ledger.User(uuid.New()), Amount: dec("9")},
+	}}
+	if err := unbalanced.Validate(); !errors.Is(err, ledger.ErrUnbalanced) {
+		t.Errorf("unbalanced entry: %v, want ErrUnbalanced", err)
+	}
+}
+
+func TestAccount(t *testing.T) {
+	id := uuid.New()
+	if got, ok := ledger.User(id).UserID(); !ok || got != id {
+		t.Errorf("User(id).UserID() = %s, %v", got, ok)
+	}
+	cases := []struct {
+		account     ledger.Account
+		wallet      bool
+		noOverdraft bool
+	}{
+		{ledger.User(id), true, true},
+		{ledger.HouseMM, true, true},
+		{ledger.MarketEscrow(id), false, true},
+		{ledger.UserBonus(id), false, true},
+		{ledger.HouseCommission, false, false},
+		{ledger.HouseFreeBets, false, false},
+		{ledger.ExternalPayments, false, false},
+		{ledger.Account("user:not-a-uuid"), false, false},
+	}
+	for _, tc := range cases {
+		if got := tc.account.IsWallet(); got != tc.wallet {
+			t.Errorf("%s.IsWallet() = %v, want %v", tc.account, got, tc.wallet)
+		}
+		if got := tc.account.NoOverdraft(); got != tc.noOverdraft {
+			t.Errorf("%s.NoOverdraft() = %v, want %v", tc.account, got, tc.noOverdraft)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Package ledger is the double-entry ledger through which every money
+// movement is posted.  An entry's lines must sum to zero; posting one updates
+// the balance of every account it touches, appends the journal rows and
+// writes a wallet_transactions record for each wallet account, all inside the
+// caller's transaction.  The journal tables are append-only and the balance
+// rule is also enforced by the database (migration 010).
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// Ledger posts journal entries.
+type Ledger struct {
+	db *sqlx.DB
+}
+
+// New creates a Ledger.
+func New(db *sqlx.DB) *Ledger {
+	return &Ledger{db: db}
+}
+
+// Post writes e inside tx.  Lines are netted per account and applied in
+// account order; e.Lines is replaced by the netted lines with their
+// resulting balances.  Returns domain.ErrInsufficientBalance (wrapped with
+// the account) when an account that may not be overdrawn would go negative,
+// domain.ErrWalletFrozen when debiting a frozen wallet, and
+// domain.ErrWalletNotFound when a wallet account has no wallet.
+func (l *Ledger) Post(ctx context.Context, tx *sqlx.Tx, e *Entry) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	lines := e.Net()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO ledger_entries (id, kind, ref_id, description, created_by)
+		VALUES ($1, $2, $3, $4, $5)`,
+		e.ID, string(e.Kind), e.RefID, e.Description, e.CreatedBy); err != nil {
+		return fmt.Errorf("ledger.Post entry: %w", err)
+	}
+
+	for i := range lines {
+		line := &lines[i]
+		var err error
+		if line.Account.IsWallet() {
+			line.BalanceAfter, err = l.applyWallet(ctx, tx, e, line.Account, line.Amount)
+		} else {
+			line.BalanceAfter, err = l.applyAccount(ctx, tx, line.Account, line.Amount)
+		}
+		if err != nil {
+			return err
+		}
+		if _, err = tx.ExecContext(ctx, `
+			INSERT INTO ledger_lines (entry_id, account, amount, balance_after)
+			VALUES ($1, $2, $3, $4)`,
+			e.ID, string(line.Account), line.Amount, line.BalanceAfter); err != nil {
+			return fmt.Errorf("ledger.Post line %s: %w", line.Account, err)
+		}
+	}
+	e.Lines = lines
+	return nil
+}
+
+// PostAtomic posts e in a transaction of its own, for movements that touch
+// nothing but balances (admin adjustments, bonuses).
+func (l *Ledger) PostAtomic(ctx context.Context, e *Entry) error {
+	tx, err := l.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ledger.PostAtomic: begin tx: %w", err)
+	}
+	if err = l.Post(ctx, tx, e); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("ledger.PostAtomic: commit: %w", err)
+	}
+	return nil
+}
+
+// Balance returns the current balance of a.  Accounts that were never
+// posted to have a zero balance.
+func (l *Ledger) Balance(ctx context.Context, a Account) (decimal.Decimal, error) {
+	var (
+		bal decimal.Decimal
+		err error
+	)
+	switch userID, isUser := a.UserID(); {
+	case isUser:
+		err = l.db.GetContext(ctx, &bal, `SELECT balance FROM wallets WHERE user_id = $1`, userID)
+	case a == HouseMM:
+		err = l.db.GetContext(ctx, &bal, `SELECT balance FROM wallets WHERE wallet_type = 'platform_mm'`)
+	default:
+		err = l.db.GetContext(ctx, &bal, `SELECT balance FROM ledger_accounts WHERE code = $1`, string(a))
+		if errors.Is(err, sql.ErrNoRows) {
+			return decimal.Zero, nil
+		}
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return decimal.Zero, domain.ErrWalletNotFound
+	}
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("ledger.Balance %s: %w", a, err)
+	}
+	return bal, nil
+}
+
+// LockBalance locks the balance row of the non-wallet account a until tx
+// ends and returns the balance, so that the caller can move all of it.
+func (l *Ledger) LockBalance(ctx context.Context, tx *sqlx.Tx, a Account) (decimal.Decimal, error) {
+	if a.IsWallet() {
+		return decimal.Zero, fmt.Errorf("ledger.LockBalance: %s is a wallet account", a)
+	}
+	var bal decimal.Decimal
+	err := tx.GetContext(ctx, &bal, `
+		SELECT balance FROM ledger_accounts WHERE code = $1 FOR UPDATE`, string(a))
+	if errors.Is(err, sql.ErrNoRows) {
+		return decimal.Zero, nil
+	}
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("ledger.LockBalance %s: %w", a, err)
+	}
+	return bal, nil
+}
+
+// applyWallet moves a wallet balance and records the wallet transaction.
+// The conditional UPDATE both locks the row and enforces the available
+// balance and the freeze, so before/after are exact.
+func (l *Ledger) applyWallet(ctx context.Context, tx *sqlx.Tx, e *Entry, a Account, delta decimal.Decimal) (decimal.Decimal, error) {
+	var row struct {
+		ID      uuid.UUID       `db:"id"`
+		Balance decimal.Decimal `db:"balance"`
+	}
+	var err error
+	userID, isUser := a.UserID()
+	if isUser {
+		err = tx.GetContext(ctx, &row, `
+			UPDATE wallets SET balance = balance + $1, updated_at = now()
+			WHERE user_id = $2 AND ($1 >= 0 OR (NOT frozen AND balance - locked + $1 >= 0))
+			RETURNING id, balance`,
+			delta, userID)
+	} else {
+		err = tx.GetContext(ctx, &row, `
+			UPDATE wallets SET balance = balance + $1, updated_at = now()
+			WHERE wallet_type = 'platform_mm' AND ($1 >= 0 OR (NOT frozen AND balance - locked + $1 >= 0))
+			RETURNING id, balance`,
+			delta)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return decimal.Zero, l.walletMissingOrShort(ctx, tx, a, userID, isUser)
+	}
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("ledger.Post wallet %s: %w", a, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO wallet_transactions
+			(id, wallet_id, type, amount, balance_before, balance_after, ref_id, description, entry_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, now())`,
+		uuid.New(), row.ID, string(e.Kind), delta.Abs(), row.Balance.Sub(delta), row.Balance,
+		e.RefID, e.Description, e.ID); err != nil {
+		return decimal.Zero, fmt.Errorf("ledger.Post wallet tx %s: %w", a, err)
+	}
+	return row.Balance, nil
+}
+
+// walletMissingOrShort tells apart the reasons applyWallet's UPDATE can
+// match no row.
+func (l *Ledger) walletMissingOrShort(ctx context.Context, tx *sqlx.Tx, a Account, userID uuid.UUID, isUser bool) error {
+	var frozen bool
+	var err error
+	if isUser {
+		err = tx.GetContext(ctx, &frozen, `SELECT frozen FROM wallets WHERE user_id = $1`, userID)
+	} else {
+		err = tx.GetContext(ctx, &frozen, `SELECT frozen FROM wallets WHERE wallet_type = 'platform_mm'`)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrWalletNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("ledger.Post wallet %s: %w", a, err)
+	}
+	if frozen {
+		return fmt.Errorf("ledger: %s: %w", a, domain.ErrWalletFrozen)
+	}
+	return fmt.Errorf("ledger: %s: %w", a, domain.ErrInsufficientBalance)
+}
+
+// applyAccount moves the balance of a non-wallet account, creating it on
+// first use.
+func (l *Ledger) applyAccount(ctx context.Context, tx *sqlx.Tx, a Account, delta decimal.Decimal) (decimal.Decimal, error) {
+	var bal decimal.Decimal
+	err := tx.GetContext(ctx, &bal, `
+		INSERT INTO ledger_accounts (code, balance) VALUES ($1, $2)
+		ON CONFLICT (code) DO UPDATE
+		SET balance = ledger_accounts.balance + EXCLUDED.balance, updated_at = now()
+		RETURNING balance`,
+		string(a), delta)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("ledger.Post account %s: %w", a, err)
+	}
+	if bal.IsNegative() && a.NoOverdraft() {
+		return decimal.Zero, fmt.Errorf("ledger: %s: %w", a, domain.ErrInsufficientBalance)
+	}
+	return bal, nil
+}
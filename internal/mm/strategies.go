@@ -0,0 +1,127 @@
+package mm
+
+import (
+	"strings"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Threshold — the original policy
+// ──────────────────────────────────────────────────────────────────────────────
+
+// Threshold seeds an empty side at SeedRatio of the other side and, once the
+// thin/thick ratio drops below ThresholdRatio, tops the thin side up to
+// BalanceRatio of the thick side.
+type Threshold struct{}
+
+// Name implements Strategy.
+func (Threshold) Name() string { return "threshold" }
+
+// Propose implements Strategy.
+func (Threshold) Propose(snap Snapshot, params domain.MMParams) *Proposal {
+	if p := seed(snap, params); p != nil {
+		return p
+	}
+	side, thin, thick, ok := snap.thin()
+	if !ok || thin.Div(thick).GreaterThanOrEqual(params.ThresholdRatio) {
+		return nil
+	}
+	needed := thick.Mul(params.BalanceRatio).RoundDown(4).Sub(thin)
+	if !needed.GreaterThan(params.MinBet) {
+		return nil
+	}
+	return &Proposal{side, needed, rebalanceReason(side)}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Proportional
+// ──────────────────────────────────────────────────────────────────────────────
+
+// Proportional closes BalanceRatio of the gap between the thin side and the
+// ThresholdRatio target on every call, so repeated rebalances converge
+// geometrically instead of jumping straight to the target.
+type Proportional struct{}
+
+// Name implements Strategy.
+func (Proportional) Name() string { return "proportional" }
+
+// Propose implements Strategy.
+func (Proportional) Propose(snap Snapshot, params domain.MMParams) *Proposal {
+	if p := seed(snap, params); p != nil {
+		return p
+	}
+	side, thin, thick, ok := snap.thin()
+	if !ok {
+		return nil
+	}
+	gap := thick.Mul(params.ThresholdRatio).Sub(thin)
+	if !gap.IsPositive() {
+		return nil
+	}
+	amount := gap.Mul(params.BalanceRatio).RoundDown(4)
+	if amount.LessThan(params.MinBet) {
+		return nil
+	}
+	return &Proposal{side, amount, rebalanceReason(side) + "_proportional"}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// TimeDecay
+// ──────────────────────────────────────────────────────────────────────────────
+
+// TimeDecay scales the Base strategy's injection by 1 + elapsed fraction of
+// the betting window: 1× at open rising to 2× at close, when an imbalance is
+// least likely to be corrected by organic flow.  Seeds are not scaled.
+type TimeDecay struct {
+	Base Strategy
+}
+
+// Name implements Strategy.
+func (TimeDecay) Name() string { return "time_decay" }
+
+// Propose implements Strategy.
+func (t TimeDecay) Propose(snap Snapshot, params domain.MMParams) *Proposal {
+	p := t.Base.Propose(snap, params)
+	if p == nil || seed(snap, params) != nil {
+		return p
+	}
+	factor := decimal.NewFromInt(1).Add(snap.ElapsedFraction())
+	p.Amount = p.Amount.Mul(factor).RoundDown(4)
+	p.Reason += "_decay"
+	return p
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// OddsTarget
+// ──────────────────────────────────────────────────────────────────────────────
+
+// OddsTarget injects on the thin side just enough to bring its payout odds
+// down to TargetOdds.  With commission c, thick pool K and target T the thin
+// pool must reach K·(1−c) / (T − (1−c)).
+type OddsTarget struct{}
+
+// Name implements Strategy.
+func (OddsTarget) Name() string { return "odds_target" }
+
+// Propose implements Strategy.
+func (OddsTarget) Propose(snap Snapshot, params domain.MMParams) *Proposal {
+	if p := seed(snap, params); p != nil {
+		return p
+	}
+	side, thin, thick, ok := snap.thin()
+	if !ok {
+		return nil
+	}
+	keep := decimal.NewFromInt(1).Sub(domain.CommissionRate)
+	denom := params.TargetOdds.Sub(keep)
+	if !denom.IsPositive() {
+		return nil // target below the break-even multiplier is unreachable
+	}
+	needed := thick.Mul(keep).Div(denom).Sub(thin).RoundDown(4)
+	if needed.LessThan(params.MinBet) {
+		return nil
+	}
+	return &Proposal{side, needed, "odds_target_" + strings.ToLower(string(side))}
+}
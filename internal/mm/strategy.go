@@ -0,0 +1,144 @@
+// Package mm contains the Market Maker decision strategies.  A Strategy is a
+// pure function of a market snapshot and the resolved MM parameters: it
+// proposes at most one liquidity injection and never touches the database.
+// MMService (and cmd/mmsim) feed the proposal through the risk limits, which
+// approve, shrink or reject it.
+package mm
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultStrategy is used when no scope selects a strategy.
+const DefaultStrategy = "threshold"
+
+// Snapshot is the market state a strategy decides on.
+type Snapshot struct {
+	MarketID    uuid.UUID
+	Series      string
+	PoolUp      decimal.Decimal // includes MM stakes
+	PoolDown    decimal.Decimal
+	MMStakeUp   decimal.Decimal // open MM stake already on each side
+	MMStakeDown decimal.Decimal
+	OpensAt     time.Time
+	ClosesAt    time.Time
+	Now         time.Time
+}
+
+// SnapshotOf builds a Snapshot from a market row; MM stakes are left zero.
+func SnapshotOf(m *domain.Market, now time.Time) Snapshot {
+	return Snapshot{
+		MarketID: m.ID,
+		Series:   m.Series,
+		PoolUp:   m.PoolUp,
+		PoolDown: m.PoolDown,
+		OpensAt:  m.OpensAt,
+		ClosesAt: m.ClosesAt,
+		Now:      now,
+	}
+}
+
+// ElapsedFraction returns how far through its betting window the market is,
+// clamped to [0, 1].
+func (s Snapshot) ElapsedFraction() decimal.Decimal {
+	total := s.ClosesAt.Sub(s.OpensAt)
+	if total <= 0 {
+		return decimal.NewFromInt(1)
+	}
+	elapsed := s.Now.Sub(s.OpensAt)
+	switch {
+	case elapsed <= 0:
+		return decimal.Zero
+	case elapsed >= total:
+		return decimal.NewFromInt(1)
+	}
+	return decimal.NewFromInt(int64(elapsed)).Div(decimal.NewFromInt(int64(total)))
+}
+
+// thin returns the under-represented side and both pool sizes.  ok is false
+// when both pools are empty.
+func (s Snapshot) thin() (side domain.Outcome, thin, thick decimal.Decimal, ok bool) {
+	if s.PoolUp.IsZero() && s.PoolDown.IsZero() {
+		return "", decimal.Zero, decimal.Zero, false
+	}
+	if s.PoolDown.LessThan(s.PoolUp) {
+		return domain.OutcomeDown, s.PoolDown, s.PoolUp, true
+	}
+	return domain.OutcomeUp, s.PoolUp, s.PoolDown, true
+}
+
+// Proposal is a suggested MM injection.  Risk limits may still reject it.
+type Proposal struct {
+	Outcome domain.Outcome  `json:"outcome"`
+	Amount  decimal.Decimal `json:"amount"`
+	Reason  string          `json:"reason"`
+}
+
+// Strategy decides whether and how much liquidity to inject.  Propose returns
+// nil when no action is needed.  Implementations must be pure and safe for
+// concurrent use.
+type Strategy interface {
+	Name() string
+	Propose(snap Snapshot, params domain.MMParams) *Proposal
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Registry
+// ──────────────────────────────────────────────────────────────────────────────
+
+var registry = map[string]Strategy{}
+
+func register(s Strategy) { registry[s.Name()] = s }
+
+func init() {
+	register(Threshold{})
+	register(Proportional{})
+	register(TimeDecay{Base: Proportional{}})
+	register(OddsTarget{})
+}
+
+// Lookup returns the strategy registered under name.
+func Lookup(name string) (Strategy, error) {
+	s, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("mm: unknown strategy %q", name)
+	}
+	return s, nil
+}
+
+// Names returns all registered strategy names, sorted.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for n := range registry {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// seed proposes the opening stake for an empty side, or nil if neither side
+// is empty.  Shared by every strategy so a one-sided market always gets a
+// counterparty.
+func seed(snap Snapshot, params domain.MMParams) *Proposal {
+	switch {
+	case snap.PoolDown.IsZero() && !snap.PoolUp.IsZero():
+		return &Proposal{domain.OutcomeDown, snap.PoolUp.Mul(params.SeedRatio).RoundDown(4), "seed_down"}
+	case snap.PoolUp.IsZero() && !snap.PoolDown.IsZero():
+		return &Proposal{domain.OutcomeUp, snap.PoolDown.Mul(params.SeedRatio).RoundDown(4), "seed_up"}
+	}
+	return nil
+}
+
+// rebalanceReason returns "rebalance_up" / "rebalance_down".
+func rebalanceReason(side domain.Outcome) string {
+	if side == domain.OutcomeUp {
+		return "rebalance_up"
+	}
+	return "rebalance_down"
+}
@@ -0,0 +1,128 @@
+package mm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/mm"
+	"github.com/shopspring/decimal"
+)
+
+func testParams() domain.MMParams {
+	return domain.MMParams{
+		ThresholdRatio: decimal.NewFromFloat(0.8),
+		MaxExposure:    decimal.NewFromInt(10000),
+		MinBet:         decimal.NewFromInt(10),
+		SeedRatio:      decimal.NewFromFloat(0.3),
+		BalanceRatio:   decimal.NewFromFloat(0.2),
+		TargetOdds:     decimal.NewFromFloat(3),
+	}
+}
+
+func snap(up, down int64) mm.Snapshot {
+	opens := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	return mm.Snapshot{
+		PoolUp:   decimal.NewFromInt(up),
+		PoolDown: decimal.NewFromInt(down),
+		OpensAt:  opens,
+		ClosesAt: opens.Add(5 * time.Minute),
+		Now:      opens,
+	}
+}
+
+// ── Threshold (legacy policy) ─────────────────────────────────────────────────
+
+func TestThreshold_SeedsEmptySide(t *testing.T) {
+	p := mm.Threshold{}.Propose(snap(1000, 0), testParams())
+	if p == nil || p.Outcome != domain.OutcomeDown || !p.Amount.Equal(decimal.NewFromInt(300)) {
+		t.Fatalf("expected seed_down 300, got %+v", p)
+	}
+	if p.Reason != "seed_down" {
+		t.Errorf("reason = %q, want seed_down", p.Reason)
+	}
+}
+
+func TestThreshold_TopsUpThinSide(t *testing.T) {
+	// 100/1000 < 0.8 → target 20% of 1000 = 200, need 100
+	p := mm.Threshold{}.Propose(snap(1000, 100), testParams())
+	if p == nil || p.Outcome != domain.OutcomeDown || !p.Amount.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("expected rebalance_down 100, got %+v", p)
+	}
+}
+
+func TestThreshold_NoActionWhenBalancedOrEmpty(t *testing.T) {
+	for _, s := range []mm.Snapshot{snap(1000, 900), snap(0, 0), snap(1000, 300)} {
+		if p := (mm.Threshold{}).Propose(s, testParams()); p != nil {
+			t.Errorf("pools %s/%s: expected no proposal, got %+v", s.PoolUp, s.PoolDown, p)
+		}
+	}
+}
+
+// ── Proportional ──────────────────────────────────────────────────────────────
+
+func TestProportional_ClosesFractionOfGap(t *testing.T) {
+	// gap = 0.8*1000 - 100 = 700; 20% of gap = 140 on UP (thin side)
+	p := mm.Proportional{}.Propose(snap(100, 1000), testParams())
+	if p == nil || p.Outcome != domain.OutcomeUp || !p.Amount.Equal(decimal.NewFromInt(140)) {
+		t.Fatalf("expected 140 on UP, got %+v", p)
+	}
+}
+
+// ── TimeDecay ─────────────────────────────────────────────────────────────────
+
+func TestTimeDecay_ScalesTowardsClose(t *testing.T) {
+	s := snap(100, 1000)
+	s.Now = s.OpensAt.Add(150 * time.Second) // halfway → 1.5×
+
+	p := mm.TimeDecay{Base: mm.Proportional{}}.Propose(s, testParams())
+	if p == nil || !p.Amount.Equal(decimal.NewFromInt(210)) {
+		t.Fatalf("expected 140 × 1.5 = 210, got %+v", p)
+	}
+}
+
+func TestTimeDecay_DoesNotScaleSeeds(t *testing.T) {
+	s := snap(1000, 0)
+	s.Now = s.ClosesAt
+	p := mm.TimeDecay{Base: mm.Proportional{}}.Propose(s, testParams())
+	if p == nil || !p.Amount.Equal(decimal.NewFromInt(300)) {
+		t.Fatalf("expected unscaled seed 300, got %+v", p)
+	}
+}
+
+// ── OddsTarget ────────────────────────────────────────────────────────────────
+
+func TestOddsTarget_ReachesTargetOdds(t *testing.T) {
+	s := snap(1000, 100)
+	params := testParams()
+	p := mm.OddsTarget{}.Propose(s, params)
+	if p == nil || p.Outcome != domain.OutcomeDown {
+		t.Fatalf("expected DOWN proposal, got %+v", p)
+	}
+
+	m := &domain.Market{PoolUp: s.PoolUp, PoolDown: s.PoolDown.Add(p.Amount)}
+	if m.DownOdds().Sub(params.TargetOdds).Abs().GreaterThan(decimal.NewFromFloat(0.01)) {
+		t.Errorf("DOWN odds after injection = %s, want ~%s", m.DownOdds(), params.TargetOdds)
+	}
+}
+
+func TestOddsTarget_NoActionBelowTarget(t *testing.T) {
+	// 1000/800: DOWN odds ≈ 2.18 < 3 → nothing to do
+	if p := (mm.OddsTarget{}).Propose(snap(1000, 800), testParams()); p != nil {
+		t.Errorf("expected no proposal, got %+v", p)
+	}
+}
+
+// ── Registry ──────────────────────────────────────────────────────────────────
+
+func TestLookup(t *testing.T) {
+	for _, name := range []string{"threshold", "proportional", "time_decay", "odds_target"} {
+		s, err := mm.Lookup(name)
+		if err != nil || s.Name() != name {
+			t.Errorf("Lookup(%q) = %v, %v", name, s, err)
+		}
+	}
+	if _, err := mm.Lookup("nope"); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
@@ -0,0 +1,76 @@
+package mmsim
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// LoadMarkets reads resolved markets of series closing in [from, to) with
+// their user bets.  Refunded bets are skipped; cashed-out bets are replayed at
+// their original stake because the pool history of the exit is not stored.
+// MM positions are ignored — the simulator places its own.
+func LoadMarkets(ctx context.Context, db *sqlx.DB, series string, from, to time.Time) ([]Market, error) {
+	var rows []struct {
+		ID       uuid.UUID      `db:"id"`
+		Series   string         `db:"series"`
+		OpensAt  time.Time      `db:"opens_at"`
+		ClosesAt time.Time      `db:"closes_at"`
+		Result   domain.Outcome `db:"result"`
+	}
+	err := db.SelectContext(ctx, &rows, `
+		SELECT id, series, opens_at, closes_at, result
+		FROM markets
+		WHERE status = 'resolved'
+		  AND result IS NOT NULL
+		  AND series = $1
+		  AND closes_at >= $2 AND closes_at < $3
+		ORDER BY closes_at`,
+		series, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("mmsim.LoadMarkets: markets: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, r := range rows {
+		ids[i] = r.ID
+	}
+	query, args, err := sqlx.In(`
+		SELECT market_id, direction, amount, created_at
+		FROM bets
+		WHERE market_id IN (?)
+		  AND status <> 'cancelled'
+		ORDER BY created_at`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("mmsim.LoadMarkets: %w", err)
+	}
+	var bets []struct {
+		MarketID  uuid.UUID       `db:"market_id"`
+		Direction domain.Outcome  `db:"direction"`
+		Amount    decimal.Decimal `db:"amount"`
+		CreatedAt time.Time       `db:"created_at"`
+	}
+	if err = db.SelectContext(ctx, &bets, db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("mmsim.LoadMarkets: bets: %w", err)
+	}
+
+	byID := make(map[uuid.UUID]*Market, len(rows))
+	markets := make([]Market, len(rows))
+	for i, r := range rows {
+		markets[i] = Market{ID: r.ID, Series: r.Series, OpensAt: r.OpensAt, ClosesAt: r.ClosesAt, Winner: r.Result}
+		byID[r.ID] = &markets[i]
+	}
+	for _, b := range bets {
+		m := byID[b.MarketID]
+		m.Bets = append(m.Bets, Bet{At: b.CreatedAt, Outcome: b.Direction, Amount: b.Amount})
+	}
+	return markets, nil
+}
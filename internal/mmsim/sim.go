@@ -0,0 +1,232 @@
+// Package mmsim replays markets through the Market Maker strategies and the
+// pari-mutuel payout math to estimate house PnL, MM exposure and the effect on
+// user odds for a given parameter set.  It is pure: markets come from a
+// Source (synthetic or database) and nothing is written back.
+package mmsim
+
+import (
+	"sort"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/mm"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Bet is one user bet replayed into a simulated market.
+type Bet struct {
+	At      time.Time
+	Outcome domain.Outcome
+	Amount  decimal.Decimal
+}
+
+// Market is a resolved market with its user bets in placement order.
+type Market struct {
+	ID       uuid.UUID
+	Series   string
+	OpensAt  time.Time
+	ClosesAt time.Time
+	Winner   domain.Outcome
+	Bets     []Bet
+}
+
+// Config is one parameter set to simulate.
+type Config struct {
+	Label      string
+	Strategy   mm.Strategy
+	Params     domain.MMParams
+	Budget     domain.MMRiskBudget
+	Commission decimal.Decimal
+	Reserve    decimal.Decimal // platform wallet balance at the start
+	MinReserve decimal.Decimal
+	Location   *time.Location // where loss-budget days start, as in MMService; UTC when nil
+}
+
+// Result summarises one simulation run.  PnL figures are from the house's
+// point of view; negative values are losses.
+type Result struct {
+	Label             string          `json:"label"`
+	Strategy          string          `json:"strategy"`
+	Params            domain.MMParams `json:"params"`
+	Markets           int             `json:"markets"`
+	Interventions     int             `json:"interventions"`
+	Throttled         int             `json:"throttled"`            // proposals shrunk by the loss budget
+	Rejected          int             `json:"rejected"`             // proposals blocked by loss budget or reserve
+	Injected          decimal.Decimal `json:"injected"`             // total MM stake
+	MMPnL             decimal.Decimal `json:"mm_pnl"`               // settled MM positions
+	Commission        decimal.Decimal `json:"commission"`           // pool left after all payouts
+	HousePnL          decimal.Decimal `json:"house_pnl"`            // MMPnL + Commission
+	MaxMarketExposure decimal.Decimal `json:"max_market_exposure"`  // largest MM stake in one market
+	WorstMarket       decimal.Decimal `json:"worst_market"`         // lowest MM PnL in one market
+	WorstDay          decimal.Decimal `json:"worst_day"`            // lowest MM PnL in one day
+	MaxDrawdown       decimal.Decimal `json:"max_drawdown"`         // of cumulative house PnL
+	UserOddsWithMM    decimal.Decimal `json:"user_odds_with_mm"`    // stake-weighted winning multiplier
+	UserOddsWithoutMM decimal.Decimal `json:"user_odds_without_mm"` // same, replayed with no MM
+	OneSidedWithoutMM int             `json:"one_sided_without_mm"` // markets where winners had no counterparty
+}
+
+// Run replays markets in chronological order under cfg.  After every user
+// bet the strategy is asked for a proposal, exactly as MMService does when a
+// bet schedules a rebalance; approved proposals are placed immediately.
+func Run(markets []Market, cfg Config) *Result {
+	ordered := append([]Market(nil), markets...)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].ClosesAt.Before(ordered[j].ClosesAt) })
+
+	res := &Result{
+		Label:    cfg.Label,
+		Strategy: cfg.Strategy.Name(),
+		Params:   cfg.Params,
+		Markets:  len(ordered),
+	}
+	loc := cfg.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	reserve := cfg.Reserve
+	realized := decimal.Zero // house PnL, for drawdown
+	peak := decimal.Zero
+	dayPnL := map[time.Time]decimal.Decimal{}
+	var userStakeWon, userPaidWith, userPaidWithout decimal.Decimal
+
+	for i, m := range ordered {
+		day, week := domain.StartOfDay(m.ClosesAt.In(loc)), domain.StartOfWeek(m.ClosesAt.In(loc))
+		pnl := domain.MMPnL{
+			RealizedDay:  dayPnL[day],
+			RealizedWeek: weekPnL(dayPnL, week, day),
+		}
+
+		e := domain.MMExposure{MarketID: m.ID}
+		var bare domain.MMExposure // pools without MM
+
+		for _, b := range m.Bets {
+			e = addUserBet(e, b)
+			bare = addUserBet(bare, b)
+
+			snap := mm.Snapshot{
+				MarketID:    m.ID,
+				Series:      m.Series,
+				PoolUp:      e.PoolUp,
+				PoolDown:    e.PoolDown,
+				MMStakeUp:   e.StakeUp,
+				MMStakeDown: e.StakeDown,
+				OpensAt:     m.OpensAt,
+				ClosesAt:    m.ClosesAt,
+				Now:         b.At,
+			}
+			p := cfg.Strategy.Propose(snap, cfg.Params)
+			if p == nil {
+				continue
+			}
+
+			amount := p.Amount
+			open := pnl
+			open.OpenMTM = e.MarkToMarket(cfg.Commission)
+			open.OpenWorstCase = e.WorstCase(cfg.Commission)
+			if scale := cfg.Budget.Scale(open); scale.LessThan(decimal.NewFromInt(1)) {
+				amount = amount.Mul(scale).RoundDown(4)
+				res.Throttled++
+			}
+			next := e.With(p.Outcome, amount)
+			open.OpenWorstCase = next.WorstCase(cfg.Commission)
+			if cfg.Budget.Check(open) != nil || reserve.LessThan(cfg.MinReserve.Add(amount)) {
+				res.Rejected++
+				continue
+			}
+			if amount.LessThan(cfg.Params.MinBet) ||
+				next.StakeUp.Add(next.StakeDown).GreaterThan(cfg.Params.MaxExposure) {
+				continue
+			}
+
+			e = next
+			reserve = reserve.Sub(amount)
+			res.Interventions++
+			res.Injected = res.Injected.Add(amount)
+		}
+
+		// ── Settlement ───────────────────────────────────────────────────────
+		// Commission is what the pool keeps after user and MM payouts, as in
+		// ResolutionService.
+		mmPnL := e.PnLIf(m.Winner, cfg.Commission)
+		mmPayout := e.StakeUp.Add(e.StakeDown).Add(mmPnL)
+		reserve = reserve.Add(mmPayout)
+		won := winningPool(bare, m.Winner)
+		userPayout := decimal.Zero
+		if won.IsPositive() {
+			userPayout = domain.PariMutuelPayout(won, winningPool(e, m.Winner), losingPool(e, m.Winner), cfg.Commission)
+		}
+		commission := e.PoolUp.Add(e.PoolDown).Sub(userPayout).Sub(mmPayout)
+
+		res.MMPnL = res.MMPnL.Add(mmPnL)
+		res.Commission = res.Commission.Add(commission)
+		res.MaxMarketExposure = decimal.Max(res.MaxMarketExposure, e.StakeUp.Add(e.StakeDown))
+		if i == 0 || mmPnL.LessThan(res.WorstMarket) {
+			res.WorstMarket = mmPnL
+		}
+		dayPnL[day] = dayPnL[day].Add(mmPnL)
+
+		realized = realized.Add(mmPnL).Add(commission)
+		peak = decimal.Max(peak, realized)
+		res.MaxDrawdown = decimal.Max(res.MaxDrawdown, peak.Sub(realized))
+
+		// ── User odds, with and without the MM ───────────────────────────────
+		if won.IsPositive() {
+			userStakeWon = userStakeWon.Add(won)
+			userPaidWithout = userPaidWithout.Add(
+				domain.PariMutuelPayout(won, won, losingPool(bare, m.Winner), cfg.Commission))
+			userPaidWith = userPaidWith.Add(userPayout)
+			if losingPool(bare, m.Winner).IsZero() {
+				res.OneSidedWithoutMM++
+			}
+		}
+	}
+
+	res.HousePnL = res.MMPnL.Add(res.Commission)
+	first := true
+	for _, v := range dayPnL {
+		if first || v.LessThan(res.WorstDay) {
+			res.WorstDay = v
+			first = false
+		}
+	}
+	if userStakeWon.IsPositive() {
+		res.UserOddsWithMM = userPaidWith.Div(userStakeWon).Round(4)
+		res.UserOddsWithoutMM = userPaidWithout.Div(userStakeWon).Round(4)
+	}
+	return res
+}
+
+// addUserBet grows the pool on the bet's side without touching MM stakes.
+func addUserBet(e domain.MMExposure, b Bet) domain.MMExposure {
+	if b.Outcome == domain.OutcomeUp {
+		e.PoolUp = e.PoolUp.Add(b.Amount)
+	} else {
+		e.PoolDown = e.PoolDown.Add(b.Amount)
+	}
+	return e
+}
+
+func winningPool(e domain.MMExposure, winner domain.Outcome) decimal.Decimal {
+	if winner == domain.OutcomeUp {
+		return e.PoolUp
+	}
+	return e.PoolDown
+}
+
+func losingPool(e domain.MMExposure, winner domain.Outcome) decimal.Decimal {
+	if winner == domain.OutcomeUp {
+		return e.PoolDown
+	}
+	return e.PoolUp
+}
+
+// weekPnL sums the daily PnL from weekStart up to and including day.
+func weekPnL(days map[time.Time]decimal.Decimal, weekStart, day time.Time) decimal.Decimal {
+	total := decimal.Zero
+	for d, v := range days {
+		if !d.Before(weekStart) && !d.After(day) {
+			total = total.Add(v)
+		}
+	}
+	return total
+}
@@ -0,0 +1,107 @@
+package mmsim_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/mm"
+	"github.com/evetabi/prediction/internal/mmsim"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func dec(s string) decimal.Decimal { return decimal.RequireFromString(s) }
+
+func testConfig(t *testing.T) mmsim.Config {
+	t.Helper()
+	strategy, err := mm.Lookup("threshold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mmsim.Config{
+		Strategy: strategy,
+		Params: domain.MMParams{
+			ThresholdRatio: dec("0.8"),
+			MaxExposure:    dec("10000"),
+			MinBet:         dec("10"),
+			SeedRatio:      dec("0.3"),
+			BalanceRatio:   dec("0.2"),
+			Strategy:       "threshold",
+		},
+		Budget: domain.MMRiskBudget{
+			DailyLimit:    dec("50000"),
+			WeeklyLimit:   dec("150000"),
+			ThrottleStart: dec("0.7"),
+		},
+		Commission: dec("0.03"),
+		Reserve:    dec("1000000"),
+		MinReserve: dec("100000"),
+	}
+}
+
+// TestRun_SeedAgainstLoneBet: one 100 TRY UP bet, the MM seeds 30 on DOWN.
+//
+//	UP wins:  MM loses 30; user gets 100 + 30 × 0.97 = 129.1 instead of 100
+//	          back; the house keeps 30 × 0.03 = 0.9.
+func TestRun_SeedAgainstLoneBet(t *testing.T) {
+	opens := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	market := mmsim.Market{
+		ID:       uuid.New(),
+		OpensAt:  opens,
+		ClosesAt: opens.Add(5 * time.Minute),
+		Winner:   domain.OutcomeUp,
+		Bets:     []mmsim.Bet{{At: opens.Add(time.Minute), Outcome: domain.OutcomeUp, Amount: dec("100")}},
+	}
+
+	res := mmsim.Run([]mmsim.Market{market}, testConfig(t))
+
+	checks := []struct {
+		name      string
+		got, want decimal.Decimal
+	}{
+		{"injected", res.Injected, dec("30")},
+		{"mm_pnl", res.MMPnL, dec("-30")},
+		{"commission", res.Commission, dec("0.9")},
+		{"house_pnl", res.HousePnL, dec("-29.1")},
+		{"worst_day", res.WorstDay, dec("-30")},
+		{"odds_with_mm", res.UserOddsWithMM, dec("1.291")},
+		{"odds_without_mm", res.UserOddsWithoutMM, dec("1")},
+	}
+	for _, c := range checks {
+		if !c.got.Equal(c.want) {
+			t.Errorf("%s = %s, want %s", c.name, c.got, c.want)
+		}
+	}
+	if res.Interventions != 1 || res.OneSidedWithoutMM != 1 {
+		t.Errorf("interventions=%d one_sided=%d, want 1 and 1", res.Interventions, res.OneSidedWithoutMM)
+	}
+}
+
+func TestRun_Deterministic(t *testing.T) {
+	markets := mmsim.DefaultSynthetic(42, 200).Generate()
+	a := mmsim.Run(markets, testConfig(t))
+	b := mmsim.Run(mmsim.DefaultSynthetic(42, 200).Generate(), testConfig(t))
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("same seed produced different results:\n%+v\n%+v", a, b)
+	}
+	if a.Markets != 200 || a.Interventions == 0 {
+		t.Errorf("markets=%d interventions=%d, want 200 and > 0", a.Markets, a.Interventions)
+	}
+}
+
+// TestRun_LossBudgetStopsMM: the worst-case check keeps every day's realized
+// MM loss within a tight 20 TRY daily budget.
+func TestRun_LossBudgetStopsMM(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Budget.DailyLimit = dec("20")
+
+	res := mmsim.Run(mmsim.DefaultSynthetic(7, 50).Generate(), cfg)
+	if res.WorstDay.LessThan(dec("-20")) {
+		t.Errorf("worst day %s breached the 20 TRY budget", res.WorstDay)
+	}
+	if res.Rejected == 0 {
+		t.Error("expected proposals to be rejected by the loss budget")
+	}
+}
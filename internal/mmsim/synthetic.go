@@ -0,0 +1,90 @@
+package mmsim
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Synthetic generates reproducible random markets.  Each market draws a
+// crowd bias (probability a bet goes UP) uniformly from [0.5-Skew, 0.5+Skew],
+// a bet count from [MinBets, MaxBets] and log-normal stakes around
+// MedianStake; the winner is a fair coin independent of the crowd.
+type Synthetic struct {
+	Seed        int64
+	Markets     int
+	Window      time.Duration // betting window per market, default 5m
+	MinBets     int
+	MaxBets     int
+	MedianStake float64 // TRY
+	Skew        float64 // 0–0.5
+	Start       time.Time
+}
+
+// DefaultSynthetic returns a generator shaped like the 5-minute BTC series.
+func DefaultSynthetic(seed int64, markets int) Synthetic {
+	return Synthetic{
+		Seed:        seed,
+		Markets:     markets,
+		Window:      5 * time.Minute,
+		MinBets:     2,
+		MaxBets:     40,
+		MedianStake: 50,
+		Skew:        0.35,
+		Start:       time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), // a Monday
+	}
+}
+
+// Generate returns g.Markets markets back to back.
+func (g Synthetic) Generate() []Market {
+	rng := rand.New(rand.NewSource(g.Seed))
+	window := g.Window
+	if window <= 0 {
+		window = 5 * time.Minute
+	}
+
+	markets := make([]Market, 0, g.Markets)
+	opens := g.Start
+	for i := 0; i < g.Markets; i++ {
+		m := Market{
+			ID:       uuid.NewSHA1(uuid.NameSpaceOID, []byte{byte(g.Seed), byte(i >> 16), byte(i >> 8), byte(i)}),
+			Series:   domain.DefaultSeries,
+			OpensAt:  opens,
+			ClosesAt: opens.Add(window),
+			Winner:   domain.OutcomeDown,
+		}
+		if rng.Intn(2) == 0 {
+			m.Winner = domain.OutcomeUp
+		}
+
+		pUp := 0.5 + g.Skew*(2*rng.Float64()-1)
+		n := g.MinBets
+		if g.MaxBets > g.MinBets {
+			n += rng.Intn(g.MaxBets - g.MinBets + 1)
+		}
+		for j := 0; j < n; j++ {
+			b := Bet{
+				At:      opens.Add(time.Duration(rng.Int63n(int64(window)))),
+				Outcome: domain.OutcomeDown,
+				Amount:  decimal.NewFromFloat(g.MedianStake * math.Exp(rng.NormFloat64())).Round(2),
+			}
+			if rng.Float64() < pUp {
+				b.Outcome = domain.OutcomeUp
+			}
+			if b.Amount.LessThan(decimal.NewFromInt(1)) {
+				b.Amount = decimal.NewFromInt(1)
+			}
+			m.Bets = append(m.Bets, b)
+		}
+		sort.Slice(m.Bets, func(a, b int) bool { return m.Bets[a].At.Before(m.Bets[b].At) })
+
+		markets = append(markets, m)
+		opens = m.ClosesAt
+	}
+	return markets
+}
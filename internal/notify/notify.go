@@ -0,0 +1,67 @@
+// Package notify propagates configuration changes between the API server and
+// back-office processes via Postgres LISTEN/NOTIFY.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Channel names.
+const (
+	ChannelMMSwitch = "mm_switch_changed"
+)
+
+// pingInterval keeps idle listener connections from being dropped silently.
+const pingInterval = 90 * time.Second
+
+// Publish queues a notification inside tx; Postgres delivers it to listeners
+// only if tx commits.
+func Publish(ctx context.Context, tx *sqlx.Tx, channel, payload string) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, channel, payload); err != nil {
+		return fmt.Errorf("notify.Publish %s: %w", channel, err)
+	}
+	return nil
+}
+
+// Listen subscribes to channel on a dedicated connection and calls fn for
+// every notification.  After a reconnect fn is called with an empty payload,
+// since notifications sent while disconnected are lost and the caller should
+// resync from the database.  Blocks until ctx is cancelled.
+func Listen(ctx context.Context, dsn, channel string, fn func(payload string)) error {
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("[notify] %s listener event %d: %v", channel, ev, err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(channel); err != nil {
+		return fmt.Errorf("notify.Listen %s: %w", channel, err)
+	}
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-listener.NotificationChannel():
+			if n == nil {
+				fn("") // reconnected — state may have been missed
+				continue
+			}
+			fn(n.Extra)
+		case <-ticker.C:
+			if err := listener.Ping(); err != nil {
+				log.Printf("[notify] %s ping failed: %v", channel, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package payment_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/payment"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const secret = "test-webhook-secret"
+
+func TestMock_WebhookRoundTrip(t *testing.T) {
+	m := payment.NewMock(secret, "http://localhost:8080/")
+	checkout, err := m.CreateCheckout(context.Background(), payment.Intent{
+		DepositID: uuid.New(),
+		Amount:    decimal.NewFromInt(250),
+	})
+	if err != nil {
+		t.Fatalf("CreateCheckout: %v", err)
+	}
+	if want := "http://localhost:8080/api/payments/mock/" + checkout.ProviderRef; checkout.CheckoutURL != want {
+		t.Errorf("CheckoutURL = %q, want %q", checkout.CheckoutURL, want)
+	}
+
+	now := time.Now()
+	header, body := m.Webhook(checkout.ProviderRef, decimal.NewFromInt(250), true, now)
+	ev, err := m.ParseWebhook(header, body, now)
+	if err != nil {
+		t.Fatalf("ParseWebhook: %v", err)
+	}
+	if ev.Type != payment.EventSucceeded || ev.ProviderRef != checkout.ProviderRef || !ev.Amount.Equal(decimal.NewFromInt(250)) {
+		t.Errorf("event = %+v", ev)
+	}
+
+	_, body = m.Webhook(checkout.ProviderRef, decimal.NewFromInt(250), false, now)
+	ev, err = m.ParseWebhook(payment.SignedHeader([]byte(secret), now, body), body, now)
+	if err != nil || ev.Type != payment.EventFailed || ev.Reason == "" {
+		t.Errorf("failed event = %+v, %v", ev, err)
+	}
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"id":"evt_1","type":"payment.succeeded","payment_ref":"mock_1","amount":"10"}`)
+
+	cases := map[string]func() ([]byte, []byte, time.Time){
+		"tampered body": func() ([]byte, []byte, time.Time) {
+			return []byte(secret), []byte(`{"id":"evt_1","type":"payment.succeeded","payment_ref":"mock_1","amount":"10000"}`), now
+		},
+		"wrong secret": func() ([]byte, []byte, time.Time) {
+			return []byte("other-secret"), body, now
+		},
+		"stale timestamp": func() ([]byte, []byte, time.Time) {
+			return []byte(secret), body, now.Add(payment.SignatureTolerance + time.Second)
+		},
+	}
+	header := payment.SignedHeader([]byte(secret), now, body)
+	for name, tc := range cases {
+		key, got, at := tc()
+		if err := payment.Verify(key, header, got, at); !errors.Is(err, domain.ErrInvalidWebhookSignature) {
+			t.Errorf("%s: %v, want ErrInvalidWebhookSignature", name, err)
+		}
+	}
+
+	missing := header.Clone()
+	missing.Del(payment.HeaderSignature)
+	if err := payment.Verify([]byte(secret), missing, body, now); !errors.Is(err, domain.ErrInvalidWebhookSignature) {
+		t.Errorf("missing signature: %v", err)
+	}
+	// A valid signature on a replayed request with a new timestamp header fails.
+	replayed := header.Clone()
+	replayed.Set(payment.HeaderTimestamp, strconv.FormatInt(now.Add(time.Minute).Unix(), 10))
+	if err := payment.Verify([]byte(secret), replayed, body, now); !errors.Is(err, domain.ErrInvalidWebhookSignature) {
+		t.Errorf("re-stamped request: %v", err)
+	}
+	if err := payment.Verify([]byte(secret), header, body, now); err != nil {
+		t.Errorf("valid request: %v", err)
+	}
+}
+
+func TestNew_UnknownProvider(t *testing.T) {
+	if _, err := payment.New("acme", secret, ""); !errors.Is(err, domain.ErrUnknownPaymentProvider) {
+		t.Errorf("New(acme) = %v, want ErrUnknownPaymentProvider", err)
+	}
+}
@@ -0,0 +1,69 @@
+// Package payment abstracts the payment providers that take deposits.  A
+// Provider creates a checkout for a deposit intent and turns the provider's
+// signed webhook into an Event; it never touches the database or the wallet.
+// DepositService records events idempotently and credits the ledger.
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Intent is what a provider needs to open a checkout for a deposit.
+type Intent struct {
+	DepositID uuid.UUID
+	UserID    uuid.UUID
+	Amount    decimal.Decimal // TRY
+}
+
+// Checkout is the provider's answer to an Intent.
+type Checkout struct {
+	ProviderRef string // provider's payment id; webhooks refer to it
+	CheckoutURL string // where the user completes the payment
+}
+
+// EventType is the outcome a webhook reports.
+type EventType string
+
+const (
+	EventSucceeded EventType = "payment.succeeded"
+	EventFailed    EventType = "payment.failed"
+)
+
+// Event is a verified webhook notification.
+type Event struct {
+	ID          string // unique per provider; redeliveries repeat it
+	Type        EventType
+	ProviderRef string          // Checkout.ProviderRef of the payment
+	Amount      decimal.Decimal // amount actually paid (TRY)
+	Reason      string          // failure reason, if any
+}
+
+// Provider is a payment provider integration.
+type Provider interface {
+	// Name identifies the provider in deposits.provider and webhook URLs.
+	Name() string
+	// CreateCheckout opens a payment for the intent.
+	CreateCheckout(ctx context.Context, in Intent) (*Checkout, error)
+	// ParseWebhook verifies the signature of a webhook request and decodes
+	// it.  Returns domain.ErrInvalidWebhookSignature when verification fails.
+	ParseWebhook(header http.Header, body []byte, now time.Time) (*Event, error)
+}
+
+// New returns the provider called name.  secret is the provider's webhook
+// signing secret; publicURL is the externally reachable base URL of the API,
+// used by providers that redirect back to us.
+func New(name, secret, publicURL string) (Provider, error) {
+	switch name {
+	case MockName:
+		return NewMock(secret, publicURL), nil
+	default:
+		return nil, fmt.Errorf("payment.New %q: %w", name, domain.ErrUnknownPaymentProvider)
+	}
+}
@@ -0,0 +1,62 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+)
+
+// Webhook signature headers.  The signature is the hex HMAC-SHA256 of
+// "<timestamp>.<body>" under the provider's webhook secret, so a captured
+// request cannot be replayed with a fresh timestamp.
+const (
+	HeaderSignature = "X-Payment-Signature"
+	HeaderTimestamp = "X-Payment-Timestamp"
+)
+
+// SignatureTolerance is how far a webhook timestamp may be from now.
+const SignatureTolerance = 5 * time.Minute
+
+// Sign returns the signature of body sent at ts.
+func Sign(secret []byte, ts time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(ts.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature headers of a webhook request against body.
+func Verify(secret []byte, header http.Header, body []byte, now time.Time) error {
+	unix, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return domain.ErrInvalidWebhookSignature
+	}
+	ts := time.Unix(unix, 0)
+	if d := now.Sub(ts); d > SignatureTolerance || d < -SignatureTolerance {
+		return domain.ErrInvalidWebhookSignature
+	}
+	got, err := hex.DecodeString(header.Get(HeaderSignature))
+	if err != nil {
+		return domain.ErrInvalidWebhookSignature
+	}
+	want, _ := hex.DecodeString(Sign(secret, ts, body))
+	if !hmac.Equal(got, want) {
+		return domain.ErrInvalidWebhookSignature
+	}
+	return nil
+}
+
+// SignedHeader returns the headers a provider sends with body at ts.
+func SignedHeader(secret []byte, ts time.Time, body []byte) http.Header {
+	h := http.Header{}
+	h.Set(HeaderTimestamp, strconv.FormatInt(ts.Unix(), 10))
+	h.Set(HeaderSignature, Sign(secret, ts, body))
+	h.Set("Content-Type", "application/json")
+	return h
+}
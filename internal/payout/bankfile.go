@@ -0,0 +1,384 @@
+package payout
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Bank files are how finance pays withdrawals in bulk from the bank portal:
+// a batch is exported as CSV or ISO 20022 pain.001 and the bank's result
+// file (CSV or pain.002) is imported back.  Every transfer carries the
+// withdrawal's EndToEndID so results can be matched to it.
+
+// Currency is the currency of every bank payout.
+const Currency = "TRY"
+
+// pain.001 field limits.
+const (
+	maxIDLen   = 35
+	maxNameLen = 70
+	maxInfoLen = 140
+)
+
+// Bank file formats accepted by Write.
+const (
+	FormatCSV     = "csv"
+	FormatPain001 = "pain001"
+)
+
+// ErrUnknownFormat is returned by Write for an unsupported format.
+var ErrUnknownFormat = errors.New("unknown bank file format")
+
+// Debtor is the house account the transfers are paid from.
+type Debtor struct {
+	Name string
+	IBAN string
+	BIC  string // optional
+}
+
+// Transfer is one credit transfer in a bank file.
+type Transfer struct {
+	EndToEndID   string // matches the result line back to the withdrawal
+	Amount       decimal.Decimal
+	CreditorName string
+	IBAN         string
+	Remittance   string // free text shown on the user's statement
+}
+
+// BankFile is a batch of transfers to export.
+type BankFile struct {
+	MsgID         string // unique per file, at most 35 characters
+	CreatedAt     time.Time
+	ExecutionDate time.Time
+	Debtor        Debtor
+	Transfers     []Transfer
+}
+
+// Total is the sum of all transfer amounts.
+func (f *BankFile) Total() decimal.Decimal {
+	total := decimal.Zero
+	for _, t := range f.Transfers {
+		total = total.Add(t.Amount)
+	}
+	return total
+}
+
+// Write writes f in format (FormatCSV or FormatPain001).
+func Write(w io.Writer, format string, f *BankFile) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, f)
+	case FormatPain001:
+		return WritePain001(w, f)
+	default:
+		return fmt.Errorf("payout.Write: %w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// WriteCSV writes f as a CSV bulk-transfer file with a header row.
+func WriteCSV(w io.Writer, f *BankFile) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{{"end_to_end_id", "beneficiary_name", "iban", "amount", "currency", "description"}}
+	for _, t := range f.Transfers {
+		rows = append(rows, []string{
+			t.EndToEndID, csvText(t.CreditorName), t.IBAN, t.Amount.StringFixed(2), Currency, csvText(t.Remittance),
+		})
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("payout.WriteCSV: %w", err)
+	}
+	return nil
+}
+
+// csvText quotes free text that a spreadsheet would evaluate as a formula
+// when finance opens the file.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// ── pain.001 ──────────────────────────────────────────────────────────────────
+
+const pain001NS = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"
+
+type pain001Document struct {
+	XMLName xml.Name        `xml:"Document"`
+	Xmlns   string          `xml:"xmlns,attr"`
+	Init    pain001Initiate `xml:"CstmrCdtTrfInitn"`
+}
+
+type pain001Initiate struct {
+	GrpHdr pain001GroupHeader `xml:"GrpHdr"`
+	PmtInf pain001PaymentInfo `xml:"PmtInf"`
+}
+
+type pain001GroupHeader struct {
+	MsgID    string    `xml:"MsgId"`
+	CreDtTm  string    `xml:"CreDtTm"`
+	NbOfTxs  int       `xml:"NbOfTxs"`
+	CtrlSum  string    `xml:"CtrlSum"`
+	InitgPty partyName `xml:"InitgPty"`
+}
+
+type pain001PaymentInfo struct {
+	PmtInfID    string            `xml:"PmtInfId"`
+	PmtMtd      string            `xml:"PmtMtd"`
+	NbOfTxs     int               `xml:"NbOfTxs"`
+	CtrlSum     string            `xml:"CtrlSum"`
+	ReqdExctnDt string            `xml:"ReqdExctnDt"`
+	Dbtr        partyName         `xml:"Dbtr"`
+	DbtrAcct    account           `xml:"DbtrAcct"`
+	DbtrAgt     agent             `xml:"DbtrAgt"`
+	Txs         []pain001Transfer `xml:"CdtTrfTxInf"`
+}
+
+type pain001Transfer struct {
+	PmtID struct {
+		EndToEndID string `xml:"EndToEndId"`
+	} `xml:"PmtId"`
+	Amt struct {
+		InstdAmt struct {
+			Ccy   string `xml:"Ccy,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"InstdAmt"`
+	} `xml:"Amt"`
+	Cdtr     partyName `xml:"Cdtr"`
+	CdtrAcct account   `xml:"CdtrAcct"`
+	RmtInf   *struct {
+		Ustrd string `xml:"Ustrd"`
+	} `xml:"RmtInf,omitempty"`
+}
+
+type partyName struct {
+	Nm string `xml:"Nm"`
+}
+
+type account struct {
+	IBAN string `xml:"Id>IBAN"`
+	Ccy  string `xml:"Ccy,omitempty"`
+}
+
+type agent struct {
+	BIC   string `xml:"FinInstnId>BIC,omitempty"`
+	Other string `xml:"FinInstnId>Othr>Id,omitempty"`
+}
+
+// WritePain001 writes f as an ISO 20022 pain.001.001.03 credit transfer
+// initiation with a single payment information block.
+func WritePain001(w io.Writer, f *BankFile) error {
+	if len(f.MsgID) > maxIDLen {
+		return fmt.Errorf("payout.WritePain001: MsgID %q longer than %d characters", f.MsgID, maxIDLen)
+	}
+	total := f.Total().StringFixed(2)
+
+	doc := pain001Document{Xmlns: pain001NS}
+	doc.Init.GrpHdr = pain001GroupHeader{
+		MsgID:    f.MsgID,
+		CreDtTm:  f.CreatedAt.UTC().Format("2006-01-02T15:04:05"),
+		NbOfTxs:  len(f.Transfers),
+		CtrlSum:  total,
+		InitgPty: partyName{Nm: truncate(f.Debtor.Name, maxNameLen)},
+	}
+	pi := &doc.Init.PmtInf
+	pi.PmtInfID = f.MsgID
+	pi.PmtMtd = "TRF"
+	pi.NbOfTxs = len(f.Transfers)
+	pi.CtrlSum = total
+	pi.ReqdExctnDt = f.ExecutionDate.Format("2006-01-02")
+	pi.Dbtr = partyName{Nm: truncate(f.Debtor.Name, maxNameLen)}
+	pi.DbtrAcct = account{IBAN: f.Debtor.IBAN, Ccy: Currency}
+	if f.Debtor.BIC != "" {
+		pi.DbtrAgt.BIC = f.Debtor.BIC
+	} else {
+		pi.DbtrAgt.Other = "NOTPROVIDED"
+	}
+
+	for _, t := range f.Transfers {
+		if len(t.EndToEndID) > maxIDLen {
+			return fmt.Errorf("payout.WritePain001: EndToEndId %q longer than %d characters", t.EndToEndID, maxIDLen)
+		}
+		var tx pain001Transfer
+		tx.PmtID.EndToEndID = t.EndToEndID
+		tx.Amt.InstdAmt.Ccy = Currency
+		tx.Amt.InstdAmt.Value = t.Amount.StringFixed(2)
+		tx.Cdtr = partyName{Nm: truncate(t.CreditorName, maxNameLen)}
+		tx.CdtrAcct = account{IBAN: t.IBAN}
+		if t.Remittance != "" {
+			tx.RmtInf = &struct {
+				Ustrd string `xml:"Ustrd"`
+			}{Ustrd: truncate(t.Remittance, maxInfoLen)}
+		}
+		pi.Txs = append(pi.Txs, tx)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("payout.WritePain001: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("payout.WritePain001: %w", err)
+	}
+	return enc.Close()
+}
+
+// truncate cuts s to at most n runes.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
+
+// ── Result files ──────────────────────────────────────────────────────────────
+
+// Outcome is one line of a bank result file.
+type Outcome struct {
+	Line       int // CSV line or pain.002 transaction number, for error reports
+	EndToEndID string
+	Status     Status
+	BankRef    string
+	Reason     string
+}
+
+// ErrInvalidResultFile is returned when a result file cannot be parsed.  The
+// whole file is rejected so that a half-understood file is never applied.
+var ErrInvalidResultFile = errors.New("invalid bank result file")
+
+// ParseResult reads a bank result file: a pain.002 status report if it looks
+// like XML, otherwise CSV with columns end_to_end_id,status[,bank_ref[,reason]]
+// and an optional header row.  Statuses are completed/failed/pending or the
+// ISO 20022 transaction status codes.
+func ParseResult(r io.Reader) ([]Outcome, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("payout.ParseResult: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // Excel's UTF-8 BOM
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '<' {
+		return parsePain002(trimmed)
+	}
+	return parseResultCSV(data)
+}
+
+// resultStatus maps a result status to a payout Status.
+func resultStatus(s string) (Status, bool) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "COMPLETED", "ACSC", "ACCC":
+		return StatusCompleted, true
+	case "FAILED", "RJCT":
+		return StatusFailed, true
+	case "PENDING", "ACTC", "ACCP", "ACSP", "ACWC", "PDNG":
+		return StatusPending, true
+	default:
+		return "", false
+	}
+}
+
+func parseResultCSV(data []byte) ([]Outcome, error) {
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	var out []Outcome
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidResultFile, err)
+		}
+		line, _ := cr.FieldPos(0)
+		if len(rec) == 1 && strings.TrimSpace(rec[0]) == "" {
+			continue
+		}
+		if len(out) == 0 && strings.EqualFold(strings.TrimSpace(rec[0]), "end_to_end_id") {
+			continue
+		}
+		if len(rec) < 2 {
+			return nil, fmt.Errorf("%w: line %d: want end_to_end_id,status", ErrInvalidResultFile, line)
+		}
+		status, ok := resultStatus(rec[1])
+		if !ok {
+			return nil, fmt.Errorf("%w: line %d: unknown status %q", ErrInvalidResultFile, line, rec[1])
+		}
+		o := Outcome{Line: line, EndToEndID: strings.TrimSpace(rec[0]), Status: status}
+		if len(rec) > 2 {
+			o.BankRef = strings.TrimSpace(rec[2])
+		}
+		if len(rec) > 3 {
+			o.Reason = strings.TrimSpace(rec[3])
+		}
+		out = append(out, o)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w: no result lines", ErrInvalidResultFile)
+	}
+	return out, nil
+}
+
+// pain.002.001.03 customer payment status report (only the parts we read).
+type pain002Document struct {
+	Report struct {
+		Groups []struct {
+			Txs []struct {
+				EndToEndID  string `xml:"OrgnlEndToEndId"`
+				TxSts       string `xml:"TxSts"`
+				AcctSvcrRef string `xml:"AcctSvcrRef"`
+				Reasons     []struct {
+					Code     string   `xml:"Rsn>Cd"`
+					AddtlInf []string `xml:"AddtlInf"`
+				} `xml:"StsRsnInf"`
+			} `xml:"TxInfAndSts"`
+		} `xml:"OrgnlPmtInfAndSts"`
+	} `xml:"CstmrPmtStsRpt"`
+}
+
+func parsePain002(data []byte) ([]Outcome, error) {
+	var doc pain002Document
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResultFile, err)
+	}
+
+	var out []Outcome
+	n := 0
+	for _, g := range doc.Report.Groups {
+		for _, tx := range g.Txs {
+			n++
+			status, ok := resultStatus(tx.TxSts)
+			if !ok {
+				return nil, fmt.Errorf("%w: transaction %d: unknown TxSts %q", ErrInvalidResultFile, n, tx.TxSts)
+			}
+			var reasons []string
+			for _, r := range tx.Reasons {
+				if r.Code != "" {
+					reasons = append(reasons, r.Code)
+				}
+				reasons = append(reasons, r.AddtlInf...)
+			}
+			out = append(out, Outcome{
+				Line:       n,
+				EndToEndID: strings.TrimSpace(tx.EndToEndID),
+				Status:     status,
+				BankRef:    strings.TrimSpace(tx.AcctSvcrRef),
+				Reason:     strings.Join(reasons, ": "),
+			})
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w: no TxInfAndSts entries", ErrInvalidResultFile)
+	}
+	return out, nil
+}
@@ -0,0 +1,173 @@
+package payout_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evetabi/prediction/internal/payout"
+	"github.com/shopspring/decimal"
+)
+
+func sampleFile() *payout.BankFile {
+	at := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	return &payout.BankFile{
+		MsgID:         "0f1e2d3c4b5a69788796a5b4c3d2e1f0",
+		CreatedAt:     at,
+		ExecutionDate: at,
+		Debtor:        payout.Debtor{Name: "Evetabi", IBAN: "TR330006100519786457841326"},
+		Transfers: []payout.Transfer{
+			{EndToEndID: "aaaa", Amount: decimal.RequireFromString("150.5"), CreditorName: "ayse", IBAN: "TR320010009999901234567890", Remittance: "Withdrawal aaaa"},
+			{EndToEndID: "bbbb", Amount: decimal.NewFromInt(1000), CreditorName: "mehmet, jr", IBAN: "TR120006200119000006672315"},
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := payout.WriteCSV(&buf, sampleFile()); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read back: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("rows = %d, want header + 2", len(rows))
+	}
+	if got := strings.Join(rows[2], "|"); got != "bbbb|mehmet, jr|TR120006200119000006672315|1000.00|TRY|" {
+		t.Errorf("row = %q", got)
+	}
+}
+
+func TestWriteCSV_EscapesFormulas(t *testing.T) {
+	f := sampleFile()
+	f.Transfers[0].CreditorName = "=HYPERLINK(\"http://x\")"
+	f.Transfers[1].CreditorName = "@SUM(A1)"
+	f.Transfers[1].Remittance = "-1+1"
+
+	var buf bytes.Buffer
+	if err := payout.WriteCSV(&buf, f); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read back: %v", err)
+	}
+	for _, c := range []struct{ got, want string }{
+		{rows[1][1], "'=HYPERLINK(\"http://x\")"},
+		{rows[2][1], "'@SUM(A1)"},
+		{rows[2][5], "'-1+1"},
+	} {
+		if c.got != c.want {
+			t.Errorf("cell = %q, want %q", c.got, c.want)
+		}
+	}
+}
+
+func TestWritePain001(t *testing.T) {
+	var buf bytes.Buffer
+	if err := payout.WritePain001(&buf, sampleFile()); err != nil {
+		t.Fatalf("WritePain001: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03">`,
+		`<NbOfTxs>2</NbOfTxs>`,
+		`<CtrlSum>1150.50</CtrlSum>`,
+		`<CreDtTm>2026-10-18T09:30:00</CreDtTm>`,
+		`<ReqdExctnDt>2026-10-18</ReqdExctnDt>`,
+		`<Id>NOTPROVIDED</Id>`,
+		`<EndToEndId>aaaa</EndToEndId>`,
+		`<InstdAmt Ccy="TRY">150.50</InstdAmt>`,
+		`<IBAN>TR320010009999901234567890</IBAN>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %s", want)
+		}
+	}
+
+	f := sampleFile()
+	f.MsgID = strings.Repeat("x", 36)
+	if err := payout.WritePain001(&bytes.Buffer{}, f); err == nil {
+		t.Error("MsgID over 35 characters accepted")
+	}
+}
+
+func TestParseResult_CSV(t *testing.T) {
+	in := "\xef\xbb\xbfend_to_end_id,status,bank_ref,reason\n" +
+		"aaaa,completed,BNK-1\n" +
+		"bbbb, RJCT ,,account closed\n" +
+		"\n" +
+		"cccc,ACSP\n"
+	got, err := payout.ParseResult(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	want := []payout.Outcome{
+		{Line: 2, EndToEndID: "aaaa", Status: payout.StatusCompleted, BankRef: "BNK-1"},
+		{Line: 3, EndToEndID: "bbbb", Status: payout.StatusFailed, Reason: "account closed"},
+		{Line: 5, EndToEndID: "cccc", Status: payout.StatusPending},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d outcomes, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("outcome %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseResult_Pain002(t *testing.T) {
+	in := `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.002.001.03">
+  <CstmrPmtStsRpt>
+    <GrpHdr><MsgId>R1</MsgId></GrpHdr>
+    <OrgnlGrpInfAndSts><OrgnlMsgId>M1</OrgnlMsgId></OrgnlGrpInfAndSts>
+    <OrgnlPmtInfAndSts>
+      <TxInfAndSts>
+        <OrgnlEndToEndId>aaaa</OrgnlEndToEndId>
+        <TxSts>ACSC</TxSts>
+        <AcctSvcrRef>BNK-1</AcctSvcrRef>
+      </TxInfAndSts>
+      <TxInfAndSts>
+        <OrgnlEndToEndId>bbbb</OrgnlEndToEndId>
+        <TxSts>RJCT</TxSts>
+        <StsRsnInf><Rsn><Cd>AC04</Cd></Rsn><AddtlInf>Closed account</AddtlInf></StsRsnInf>
+      </TxInfAndSts>
+    </OrgnlPmtInfAndSts>
+  </CstmrPmtStsRpt>
+</Document>`
+	got, err := payout.ParseResult(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ParseResult: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d outcomes: %+v", len(got), got)
+	}
+	if got[0].EndToEndID != "aaaa" || got[0].Status != payout.StatusCompleted || got[0].BankRef != "BNK-1" {
+		t.Errorf("outcome 0 = %+v", got[0])
+	}
+	if got[1].Status != payout.StatusFailed || got[1].Reason != "AC04: Closed account" {
+		t.Errorf("outcome 1 = %+v", got[1])
+	}
+}
+
+func TestParseResult_Rejects(t *testing.T) {
+	for name, in := range map[string]string{
+		"empty":          "",
+		"header only":    "end_to_end_id,status\n",
+		"unknown status": "aaaa,paid\n",
+		"missing status": "aaaa\n",
+		"broken xml":     "<Document><CstmrPmtStsRpt>",
+		"unknown TxSts":  `<Document><CstmrPmtStsRpt><OrgnlPmtInfAndSts><TxInfAndSts><OrgnlEndToEndId>a</OrgnlEndToEndId><TxSts>XXXX</TxSts></TxInfAndSts></OrgnlPmtInfAndSts></CstmrPmtStsRpt></Document>`,
+	} {
+		if _, err := payout.ParseResult(strings.NewReader(in)); !errors.Is(err, payout.ErrInvalidResultFile) {
+			t.Errorf("%s: err = %v, want ErrInvalidResultFile", name, err)
+		}
+	}
+}
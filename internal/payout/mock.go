@@ -0,0 +1,32 @@
+package payout
+
+import (
+	"context"
+	"strings"
+)
+
+// MockName is the name of the development provider.
+const MockName = "mock"
+
+// mockFailSuffix makes the Mock reject payouts to IBANs ending in it, so the
+// failure path can be exercised by hand.
+const mockFailSuffix = "0000"
+
+// Mock is a local provider for development: every payout completes at once
+// except those to IBANs ending in 0000, which the "bank" rejects.
+type Mock struct{}
+
+// NewMock creates a Mock.
+func NewMock() *Mock { return &Mock{} }
+
+// Name implements Provider.
+func (*Mock) Name() string { return MockName }
+
+// Send implements Provider.
+func (*Mock) Send(_ context.Context, p Payout) (*Result, error) {
+	ref := "mockpayout_" + strings.ReplaceAll(p.WithdrawalID.String(), "-", "")
+	if strings.HasSuffix(p.IBAN, mockFailSuffix) {
+		return &Result{Ref: ref, Status: StatusFailed, Reason: "beneficiary account closed (mock)"}, nil
+	}
+	return &Result{Ref: ref, Status: StatusCompleted}, nil
+}
@@ -0,0 +1,56 @@
+// Package payout abstracts the bank payout providers that send approved
+// withdrawals to users' bank accounts.  A Provider only moves money at the
+// bank; WithdrawalService owns the request lifecycle and the ledger.
+package payout
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Payout is one transfer to send.
+type Payout struct {
+	WithdrawalID uuid.UUID // idempotency key at the provider
+	UserID       uuid.UUID
+	Amount       decimal.Decimal // TRY
+	IBAN         string
+}
+
+// Status is the provider's view of a payout.
+type Status string
+
+const (
+	StatusCompleted Status = "completed" // money has left the house account
+	StatusPending   Status = "pending"   // accepted; the result arrives later
+	StatusFailed    Status = "failed"    // rejected by the bank; nothing was sent
+)
+
+// Result is the provider's answer to Send.
+type Result struct {
+	Ref    string // provider's transfer id
+	Status Status
+	Reason string // failure reason, if any
+}
+
+// Provider is a bank payout integration.
+type Provider interface {
+	// Name identifies the provider in withdraw_requests.payout_provider.
+	Name() string
+	// Send submits p.  Sending the same WithdrawalID twice must not pay twice.
+	// An error means the outcome is unknown and the payout must be checked
+	// at the provider before it is settled by hand.
+	Send(ctx context.Context, p Payout) (*Result, error)
+}
+
+// New returns the provider called name.
+func New(name string) (Provider, error) {
+	switch name {
+	case MockName:
+		return NewMock(), nil
+	default:
+		return nil, fmt.Errorf("payout.New: unknown provider %q", name)
+	}
+}
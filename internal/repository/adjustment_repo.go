@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// AdjustmentRepository handles the balance_adjustments table.
+type AdjustmentRepository struct {
+	db *sqlx.DB
+}
+
+// NewAdjustmentRepository creates a new AdjustmentRepository.
+func NewAdjustmentRepository(db *sqlx.DB) *AdjustmentRepository {
+	return &AdjustmentRepository{db: db}
+}
+
+// Create inserts a pending adjustment inside tx.
+func (r *AdjustmentRepository) Create(ctx context.Context, tx *sqlx.Tx, a *domain.BalanceAdjustment) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO balance_adjustments (id, user_id, amount, reason, note, status, requested_by, requested_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		a.ID, a.UserID, a.Amount, string(a.Reason), a.Note, string(a.Status), a.RequestedBy, a.RequestedAt)
+	if err != nil {
+		return fmt.Errorf("adjustment_repo.Create: %w", err)
+	}
+	return nil
+}
+
+// Get returns the adjustment with the given ID.
+func (r *AdjustmentRepository) Get(ctx context.Context, id uuid.UUID) (*domain.BalanceAdjustment, error) {
+	var a domain.BalanceAdjustment
+	err := r.db.GetContext(ctx, &a, `SELECT * FROM balance_adjustments WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAdjustmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("adjustment_repo.Get: %w", err)
+	}
+	return &a, nil
+}
+
+// GetForUpdate locks and returns the adjustment inside tx.
+func (r *AdjustmentRepository) GetForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.BalanceAdjustment, error) {
+	var a domain.BalanceAdjustment
+	err := tx.GetContext(ctx, &a, `SELECT * FROM balance_adjustments WHERE id = $1 FOR UPDATE`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAdjustmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("adjustment_repo.GetForUpdate: %w", err)
+	}
+	return &a, nil
+}
+
+// Review records the decision on a pending adjustment inside tx and
+// returns the updated row.  entryID is the posted ledger entry on approval.
+func (r *AdjustmentRepository) Review(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, status domain.AdjustmentStatus, reviewerID uuid.UUID, note string, entryID *uuid.UUID) (*domain.BalanceAdjustment, error) {
+	var a domain.BalanceAdjustment
+	err := tx.GetContext(ctx, &a, `
+		UPDATE balance_adjustments
+		SET status = $2, reviewed_by = $3, reviewed_at = now(), review_note = NULLIF($4, ''), entry_id = $5
+		WHERE id = $1 AND status = 'pending'
+		RETURNING *`,
+		id, string(status), reviewerID, note, entryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAdjustmentState
+	}
+	if err != nil {
+		return nil, fmt.Errorf("adjustment_repo.Review: %w", err)
+	}
+	return &a, nil
+}
+
+// List returns adjustments with the usernames involved, oldest first so
+// the review queue is worked in order.  Nil statuses and userID match
+// everything.
+func (r *AdjustmentRepository) List(ctx context.Context, statuses []domain.AdjustmentStatus, userID *uuid.UUID, limit, offset int) ([]*domain.BalanceAdjustmentItem, int, error) {
+	var filter pq.StringArray
+	if statuses != nil {
+		filter = make(pq.StringArray, len(statuses))
+		for i, s := range statuses {
+			filter[i] = string(s)
+		}
+	}
+	var total int
+	if err := r.db.GetContext(ctx, &total, `
+		SELECT COUNT(*) FROM balance_adjustments
+		WHERE ($1::text[] IS NULL OR status = ANY($1::text[]))
+		  AND ($2::uuid IS NULL OR user_id = $2)`,
+		filter, userID); err != nil {
+		return nil, 0, fmt.Errorf("adjustment_repo.List count: %w", err)
+	}
+	var items []*domain.BalanceAdjustmentItem
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT a.*, u.username, rq.username AS requested_by_name
+		FROM balance_adjustments a
+		JOIN users u  ON u.id = a.user_id
+		JOIN users rq ON rq.id = a.requested_by
+		WHERE ($1::text[] IS NULL OR a.status = ANY($1::text[]))
+		  AND ($2::uuid IS NULL OR a.user_id = $2)
+		ORDER BY a.requested_at
+		LIMIT $3 OFFSET $4`,
+		filter, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("adjustment_repo.List: %w", err)
+	}
+	return items, total, nil
+}
@@ -0,0 +1,312 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evetabi/prediction/internal/aml"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+// AMLRepository handles the aml_cases, aml_alerts and aml_case_events
+// tables and loads the activity the AML rules screen.
+type AMLRepository struct {
+	db *sqlx.DB
+}
+
+// NewAMLRepository creates a new AMLRepository.
+func NewAMLRepository(db *sqlx.DB) *AMLRepository {
+	return &AMLRepository{db: db}
+}
+
+// ── Activity ─────────────────────────────────────────────────────────────────
+
+// Movements returns the user's completed deposits, live or paid withdrawal
+// requests and real-money stakes since since, each oldest first.
+func (r *AMLRepository) Movements(ctx context.Context, q sqlx.QueryerContext, userID uuid.UUID, since time.Time) (deposits, withdrawals, stakes []aml.Movement, err error) {
+	var rows []struct {
+		Kind   string          `db:"kind"`
+		Amount decimal.Decimal `db:"amount"`
+		At     time.Time       `db:"at"`
+	}
+	err = sqlx.SelectContext(ctx, q, &rows, `
+		SELECT 'deposit' AS kind, amount, completed_at AS at
+		FROM deposits
+		WHERE user_id = $1 AND status = 'completed' AND completed_at >= $2
+		UNION ALL
+		SELECT 'withdrawal', amount, requested_at
+		FROM withdraw_requests
+		WHERE user_id = $1 AND status NOT IN ('rejected', 'cancelled', 'failed') AND requested_at >= $2
+		UNION ALL
+		SELECT 'stake', t.amount, t.created_at
+		FROM wallet_transactions t
+		JOIN wallets w ON w.id = t.wallet_id
+		WHERE w.user_id = $1 AND t.type = 'bet_lock' AND t.created_at >= $2
+		ORDER BY at`,
+		userID, since)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("aml_repo.Movements: %w", err)
+	}
+	for _, row := range rows {
+		m := aml.Movement{Amount: row.Amount, At: row.At}
+		switch row.Kind {
+		case "deposit":
+			deposits = append(deposits, m)
+		case "withdrawal":
+			withdrawals = append(withdrawals, m)
+		default:
+			stakes = append(stakes, m)
+		}
+	}
+	return deposits, withdrawals, stakes, nil
+}
+
+// IBANUsers counts the other users who ever saved iban as a bank account or
+// requested a withdrawal to it.
+func (r *AMLRepository) IBANUsers(ctx context.Context, q sqlx.QueryerContext, userID uuid.UUID, iban string) (int, error) {
+	var n int
+	err := sqlx.GetContext(ctx, q, &n, `
+		SELECT COUNT(DISTINCT user_id) FROM (
+			SELECT user_id FROM bank_accounts WHERE iban = $1
+			UNION
+			SELECT user_id FROM withdraw_requests WHERE iban = $1
+		) used
+		WHERE user_id <> $2`,
+		iban, userID)
+	if err != nil {
+		return 0, fmt.Errorf("aml_repo.IBANUsers: %w", err)
+	}
+	return n, nil
+}
+
+// MarketStakes sums the user's open stakes on each side of a market.
+func (r *AMLRepository) MarketStakes(ctx context.Context, q sqlx.QueryerContext, userID, marketID uuid.UUID) (up, down decimal.Decimal, err error) {
+	var row struct {
+		Up   decimal.Decimal `db:"up"`
+		Down decimal.Decimal `db:"down"`
+	}
+	err = sqlx.GetContext(ctx, q, &row, `
+		SELECT
+			COALESCE(SUM(amount) FILTER (WHERE direction = 'UP'), 0)   AS up,
+			COALESCE(SUM(amount) FILTER (WHERE direction = 'DOWN'), 0) AS down
+		FROM bets
+		WHERE user_id = $1 AND market_id = $2 AND status = 'open'`,
+		userID, marketID)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("aml_repo.MarketStakes: %w", err)
+	}
+	return row.Up, row.Down, nil
+}
+
+// ── Cases ────────────────────────────────────────────────────────────────────
+
+// heldByAMLCase matches withdraw_requests rows (aliased w) whose user has a
+// live case holding withdrawals; see domain.AMLCase.HoldsWithdrawals.
+const heldByAMLCase = `EXISTS (
+	SELECT 1 FROM aml_cases c
+	WHERE c.user_id = w.user_id AND c.hold AND c.status IN ('open', 'escalated'))`
+
+// LiveCaseForUpdate locks and returns the user's case that is not closed,
+// or nil when there is none.
+func (r *AMLRepository) LiveCaseForUpdate(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) (*domain.AMLCase, error) {
+	var c domain.AMLCase
+	err := tx.GetContext(ctx, &c, `
+		SELECT * FROM aml_cases
+		WHERE user_id = $1 AND status IN ('open', 'escalated')
+		FOR UPDATE`,
+		userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("aml_repo.LiveCaseForUpdate: %w", err)
+	}
+	return &c, nil
+}
+
+// OpenCase inserts a case inside tx.  It reports false, without error, when
+// the user already has a live case, which a concurrent screening may have
+// opened.
+func (r *AMLRepository) OpenCase(ctx context.Context, tx *sqlx.Tx, c *domain.AMLCase) (bool, error) {
+	res, err := tx.NamedExecContext(ctx, `
+		INSERT INTO aml_cases (id, user_id, status, severity, hold, opened_at, updated_at)
+		VALUES (:id, :user_id, :status, :severity, :hold, :opened_at, :updated_at)
+		ON CONFLICT (user_id) WHERE status IN ('open', 'escalated') DO NOTHING`, c)
+	if err != nil {
+		return false, fmt.Errorf("aml_repo.OpenCase: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// UpdateCase saves a case's mutable fields inside tx.
+func (r *AMLRepository) UpdateCase(ctx context.Context, tx *sqlx.Tx, c *domain.AMLCase) error {
+	_, err := tx.NamedExecContext(ctx, `
+		UPDATE aml_cases
+		SET status = :status, severity = :severity, hold = :hold, assigned_to = :assigned_to,
+		    resolution = :resolution, updated_at = :updated_at, closed_by = :closed_by, closed_at = :closed_at
+		WHERE id = :id`, c)
+	if err != nil {
+		return fmt.Errorf("aml_repo.UpdateCase: %w", err)
+	}
+	return nil
+}
+
+// GetCase returns a case with the user's name and its alert count.
+// Returns domain.ErrAMLCaseNotFound.
+func (r *AMLRepository) GetCase(ctx context.Context, id uuid.UUID) (*domain.AMLCaseItem, error) {
+	var c domain.AMLCaseItem
+	err := r.db.GetContext(ctx, &c, `
+		SELECT c.*, u.username,
+		       (SELECT COUNT(*) FROM aml_alerts a WHERE a.case_id = c.id) AS alert_count
+		FROM aml_cases c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.id = $1`,
+		id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAMLCaseNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("aml_repo.GetCase: %w", err)
+	}
+	return &c, nil
+}
+
+// GetCaseForUpdate locks and returns a case inside tx.  Returns
+// domain.ErrAMLCaseNotFound.
+func (r *AMLRepository) GetCaseForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.AMLCase, error) {
+	var c domain.AMLCase
+	err := tx.GetContext(ctx, &c, `SELECT * FROM aml_cases WHERE id = $1 FOR UPDATE`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAMLCaseNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("aml_repo.GetCaseForUpdate: %w", err)
+	}
+	return &c, nil
+}
+
+// ListCases returns cases in any of statuses (all when nil), optionally of
+// one user, most severe and then oldest first.
+func (r *AMLRepository) ListCases(ctx context.Context, statuses []domain.AMLCaseStatus, userID *uuid.UUID, limit, offset int) ([]*domain.AMLCaseItem, int, error) {
+	var filter pq.StringArray
+	if statuses != nil {
+		filter = make(pq.StringArray, len(statuses))
+		for i, s := range statuses {
+			filter[i] = string(s)
+		}
+	}
+	var total int
+	if err := r.db.GetContext(ctx, &total, `
+		SELECT COUNT(*) FROM aml_cases
+		WHERE ($1::text[] IS NULL OR status = ANY($1::text[]))
+		  AND ($2::uuid IS NULL OR user_id = $2)`,
+		filter, userID); err != nil {
+		return nil, 0, fmt.Errorf("aml_repo.ListCases count: %w", err)
+	}
+	var items []*domain.AMLCaseItem
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT c.*, u.username,
+		       (SELECT COUNT(*) FROM aml_alerts a WHERE a.case_id = c.id) AS alert_count
+		FROM aml_cases c
+		JOIN users u ON u.id = c.user_id
+		WHERE ($1::text[] IS NULL OR c.status = ANY($1::text[]))
+		  AND ($2::uuid IS NULL OR c.user_id = $2)
+		ORDER BY CASE c.severity WHEN 'high' THEN 3 WHEN 'medium' THEN 2 ELSE 1 END DESC,
+		         c.opened_at
+		LIMIT $3 OFFSET $4`,
+		filter, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("aml_repo.ListCases: %w", err)
+	}
+	return items, total, nil
+}
+
+// ── Alerts and history ───────────────────────────────────────────────────────
+
+// AddAlert inserts an alert inside tx.  It reports false, without error,
+// when the rule already raised an alert on the same subject.
+func (r *AMLRepository) AddAlert(ctx context.Context, tx *sqlx.Tx, a *domain.AMLAlert) (bool, error) {
+	res, err := tx.NamedExecContext(ctx, `
+		INSERT INTO aml_alerts
+			(id, case_id, user_id, rule, severity, subject, subject_id, amount, reason, created_at)
+		VALUES
+			(:id, :case_id, :user_id, :rule, :severity, :subject, :subject_id, :amount, :reason, :created_at)
+		ON CONFLICT ON CONSTRAINT aml_alerts_once DO NOTHING`, a)
+	if err != nil {
+		return false, fmt.Errorf("aml_repo.AddAlert: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// AddEvent appends to a case's history inside tx.
+func (r *AMLRepository) AddEvent(ctx context.Context, tx *sqlx.Tx, e *domain.AMLCaseEvent) error {
+	_, err := tx.NamedExecContext(ctx, `
+		INSERT INTO aml_case_events (id, case_id, actor_id, action, note, created_at)
+		VALUES (:id, :case_id, :actor_id, :action, :note, :created_at)`, e)
+	if err != nil {
+		return fmt.Errorf("aml_repo.AddEvent: %w", err)
+	}
+	return nil
+}
+
+// Alerts returns a case's alerts, oldest first.
+func (r *AMLRepository) Alerts(ctx context.Context, caseID uuid.UUID) ([]*domain.AMLAlert, error) {
+	alerts := []*domain.AMLAlert{}
+	err := r.db.SelectContext(ctx, &alerts, `
+		SELECT * FROM aml_alerts WHERE case_id = $1 ORDER BY created_at`, caseID)
+	if err != nil {
+		return nil, fmt.Errorf("aml_repo.Alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+// Events returns a case's history, oldest first.
+func (r *AMLRepository) Events(ctx context.Context, caseID uuid.UUID) ([]*domain.AMLCaseEvent, error) {
+	events := []*domain.AMLCaseEvent{}
+	err := r.db.SelectContext(ctx, &events, `
+		SELECT * FROM aml_case_events WHERE case_id = $1 ORDER BY created_at, id`, caseID)
+	if err != nil {
+		return nil, fmt.Errorf("aml_repo.Events: %w", err)
+	}
+	return events, nil
+}
+
+// ── Withdrawal holds ─────────────────────────────────────────────────────────
+
+// SetWithdrawHold holds or releases all of the user's pending withdrawals
+// inside tx and returns how many changed.
+func (r *AMLRepository) SetWithdrawHold(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, hold bool) (int64, error) {
+	res, err := tx.ExecContext(ctx, `
+		UPDATE withdraw_requests SET aml_hold = $2
+		WHERE user_id = $1 AND status = 'pending' AND aml_hold <> $2`,
+		userID, hold)
+	if err != nil {
+		return 0, fmt.Errorf("aml_repo.SetWithdrawHold: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// PendingWithdrawals returns the user's pending withdrawals, held or not,
+// oldest first.
+func (r *AMLRepository) PendingWithdrawals(ctx context.Context, userID uuid.UUID) ([]*domain.WithdrawRequest, error) {
+	reqs := []*domain.WithdrawRequest{}
+	err := r.db.SelectContext(ctx, &reqs, `
+		SELECT * FROM withdraw_requests
+		WHERE user_id = $1 AND status = 'pending'
+		ORDER BY requested_at`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("aml_repo.PendingWithdrawals: %w", err)
+	}
+	return reqs, nil
+}
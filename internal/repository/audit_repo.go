@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// AuditRepository handles the admin_audit_log table.
+type AuditRepository struct {
+	db *sqlx.DB
+}
+
+// NewAuditRepository creates a new AuditRepository.
+func NewAuditRepository(db *sqlx.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// NewAuditEntry builds an entry with details marshalled to JSON.  actorID may
+// be uuid.Nil for system-initiated changes.
+func NewAuditEntry(actorID uuid.UUID, action, entityType, entityID string, details any) (*domain.AuditEntry, error) {
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return nil, fmt.Errorf("audit_repo.NewAuditEntry: marshal details: %w", err)
+	}
+	e := &domain.AuditEntry{
+		ID:         uuid.New(),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Details:    raw,
+		CreatedAt:  time.Now(),
+	}
+	if actorID != uuid.Nil {
+		e.ActorID = &actorID
+	}
+	return e, nil
+}
+
+// Log inserts an audit entry inside a transaction so it commits or rolls back
+// together with the change it describes.
+func (r *AuditRepository) Log(ctx context.Context, tx *sqlx.Tx, e *domain.AuditEntry) error {
+	query := `
+		INSERT INTO admin_audit_log (id, actor_id, action, entity_type, entity_id, details, created_at)
+		VALUES (:id, :actor_id, :action, :entity_type, :entity_id, :details, :created_at)`
+	if _, err := tx.NamedExecContext(ctx, query, e); err != nil {
+		return fmt.Errorf("audit_repo.Log: %w", err)
+	}
+	return nil
+}
+
+// List returns audit entries, newest first.  Empty filters match everything.
+func (r *AuditRepository) List(ctx context.Context, entityType, entityID string, limit, offset int) ([]*domain.AuditEntry, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `
+		SELECT COUNT(*) FROM admin_audit_log
+		WHERE ($1 = '' OR entity_type = $1)
+		  AND ($2 = '' OR entity_id = $2)`,
+		entityType, entityID); err != nil {
+		return nil, 0, fmt.Errorf("audit_repo.List count: %w", err)
+	}
+	var entries []*domain.AuditEntry
+	err := r.db.SelectContext(ctx, &entries, `
+		SELECT * FROM admin_audit_log
+		WHERE ($1 = '' OR entity_type = $1)
+		  AND ($2 = '' OR entity_id = $2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4`,
+		entityType, entityID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("audit_repo.List: %w", err)
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// BankAccountRepository handles the bank_accounts table.  Removed accounts
+// are soft-deleted and invisible to every method here.
+type BankAccountRepository struct {
+	db *sqlx.DB
+}
+
+// NewBankAccountRepository creates a new BankAccountRepository.
+func NewBankAccountRepository(db *sqlx.DB) *BankAccountRepository {
+	return &BankAccountRepository{db: db}
+}
+
+// Create inserts a bank account inside tx.  Returns domain.ErrBankAccountExists
+// when the IBAN is already saved by anyone.
+func (r *BankAccountRepository) Create(ctx context.Context, tx *sqlx.Tx, a *domain.BankAccount) error {
+	query := `
+		INSERT INTO bank_accounts
+			(id, user_id, iban, holder_name, status, name_match, is_default, usable_from, created_at)
+		VALUES
+			(:id, :user_id, :iban, :holder_name, :status, :name_match, :is_default, :usable_from, :created_at)`
+	if _, err := tx.NamedExecContext(ctx, query, a); err != nil {
+		if isPgUniqueViolation(err, "idx_bank_accounts_iban") {
+			return domain.ErrBankAccountExists
+		}
+		return fmt.Errorf("bank_account_repo.Create: %w", err)
+	}
+	return nil
+}
+
+// CountByUser returns how many accounts a user has saved.
+func (r *BankAccountRepository) CountByUser(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) (int, error) {
+	var n int
+	err := tx.GetContext(ctx, &n, `
+		SELECT COUNT(*) FROM bank_accounts WHERE user_id = $1 AND deleted_at IS NULL`,
+		userID)
+	if err != nil {
+		return 0, fmt.Errorf("bank_account_repo.CountByUser: %w", err)
+	}
+	return n, nil
+}
+
+// ListByUser returns a user's accounts, default first.
+func (r *BankAccountRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.BankAccount, error) {
+	accounts := []*domain.BankAccount{}
+	err := r.db.SelectContext(ctx, &accounts, `
+		SELECT * FROM bank_accounts
+		WHERE user_id = $1 AND deleted_at IS NULL
+		ORDER BY is_default DESC, created_at`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("bank_account_repo.ListByUser: %w", err)
+	}
+	return accounts, nil
+}
+
+// ListByUserForUpdate locks and returns a user's accounts inside tx.
+func (r *BankAccountRepository) ListByUserForUpdate(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) ([]*domain.BankAccount, error) {
+	var accounts []*domain.BankAccount
+	err := tx.SelectContext(ctx, &accounts, `
+		SELECT * FROM bank_accounts
+		WHERE user_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at
+		FOR UPDATE`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("bank_account_repo.ListByUserForUpdate: %w", err)
+	}
+	return accounts, nil
+}
+
+// GetByID returns an account by its ID.
+func (r *BankAccountRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.BankAccount, error) {
+	var a domain.BankAccount
+	err := r.db.GetContext(ctx, &a, `
+		SELECT * FROM bank_accounts WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrBankAccountNotFound
+		}
+		return nil, fmt.Errorf("bank_account_repo.GetByID: %w", err)
+	}
+	return &a, nil
+}
+
+// GetForUpdate locks and returns an account inside tx.
+func (r *BankAccountRepository) GetForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.BankAccount, error) {
+	var a domain.BankAccount
+	err := tx.GetContext(ctx, &a, `
+		SELECT * FROM bank_accounts WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrBankAccountNotFound
+		}
+		return nil, fmt.Errorf("bank_account_repo.GetForUpdate: %w", err)
+	}
+	return &a, nil
+}
+
+// GetDefault returns the user's default account.
+func (r *BankAccountRepository) GetDefault(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) (*domain.BankAccount, error) {
+	var a domain.BankAccount
+	err := tx.GetContext(ctx, &a, `
+		SELECT * FROM bank_accounts
+		WHERE user_id = $1 AND is_default AND deleted_at IS NULL`,
+		userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrBankAccountNotFound
+		}
+		return nil, fmt.Errorf("bank_account_repo.GetDefault: %w", err)
+	}
+	return &a, nil
+}
+
+// SetDefault makes id the user's only default account.
+func (r *BankAccountRepository) SetDefault(ctx context.Context, tx *sqlx.Tx, userID, id uuid.UUID) error {
+	// Clear first: the one-default-per-user index is checked row by row.
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE bank_accounts SET is_default = false
+		WHERE user_id = $1 AND is_default AND id <> $2`,
+		userID, id); err != nil {
+		return fmt.Errorf("bank_account_repo.SetDefault: clear: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE bank_accounts SET is_default = true WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("bank_account_repo.SetDefault: %w", err)
+	}
+	return nil
+}
+
+// Delete soft-deletes an account.
+func (r *BankAccountRepository) Delete(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE bank_accounts SET deleted_at = now(), is_default = false WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("bank_account_repo.Delete: %w", err)
+	}
+	return nil
+}
+
+// Review records finance's verification decision.
+func (r *BankAccountRepository) Review(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, status domain.BankAccountStatus, adminID uuid.UUID, note string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE bank_accounts
+		SET status = $1, reviewed_by = $2, review_note = $3, reviewed_at = now()
+		WHERE id = $4`,
+		string(status), adminID, note, id)
+	if err != nil {
+		return fmt.Errorf("bank_account_repo.Review: %w", err)
+	}
+	return nil
+}
+
+// SetNameMatch records a re-check of an account's holder against the
+// user's name.
+func (r *BankAccountRepository) SetNameMatch(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, match domain.NameMatch, status domain.BankAccountStatus) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE bank_accounts SET name_match = $1, status = $2 WHERE id = $3`,
+		string(match), string(status), id)
+	if err != nil {
+		return fmt.Errorf("bank_account_repo.SetNameMatch: %w", err)
+	}
+	return nil
+}
+
+// List returns accounts for the back office, oldest first so the review
+// queue is worked in order.  Empty filters match everything.
+func (r *BankAccountRepository) List(ctx context.Context, status string, userID *uuid.UUID, limit, offset int) ([]*domain.BankAccount, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `
+		SELECT COUNT(*) FROM bank_accounts
+		WHERE deleted_at IS NULL
+		  AND ($1 = '' OR status = $1)
+		  AND ($2::uuid IS NULL OR user_id = $2)`,
+		status, userID); err != nil {
+		return nil, 0, fmt.Errorf("bank_account_repo.List count: %w", err)
+	}
+	accounts := []*domain.BankAccount{}
+	err := r.db.SelectContext(ctx, &accounts, `
+		SELECT * FROM bank_accounts
+		WHERE deleted_at IS NULL
+		  AND ($1 = '' OR status = $1)
+		  AND ($2::uuid IS NULL OR user_id = $2)
+		ORDER BY created_at
+		LIMIT $3 OFFSET $4`,
+		status, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("bank_account_repo.List: %w", err)
+	}
+	return accounts, total, nil
+}
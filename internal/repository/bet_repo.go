@@ -27,9 +27,9 @@ func NewBetRepository(db *sqlx.DB) *BetRepository {
 func (r *BetRepository) Create(ctx context.Context, tx *sqlx.Tx, b *domain.Bet) error {
 	query := `
 		INSERT INTO bets
-			(id, user_id, market_id, direction, amount, odds_at_entry, status, placed_at)
+			(id, user_id, market_id, direction, amount, bonus_amount, free_bet_id, odds_at_entry, status, placed_at)
 		VALUES
-			(:id, :user_id, :market_id, :direction, :amount, :odds_at_entry, :status, :placed_at)`
+			(:id, :user_id, :market_id, :direction, :amount, :bonus_amount, :free_bet_id, :odds_at_entry, :status, :placed_at)`
 	if _, err := tx.NamedExecContext(ctx, query, b); err != nil {
 		return fmt.Errorf("bet_repo.Create: %w", err)
 	}
@@ -62,15 +62,20 @@ func (r *BetRepository) GetByMarketAndOutcome(ctx context.Context, marketID uuid
 }
 
 // GetByUserID returns a user's bet history, paginated.
-func (r *BetRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Bet, error) {
+func (r *BetRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Bet, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total,
+		`SELECT COUNT(*) FROM bets WHERE user_id = $1`, userID); err != nil {
+		return nil, 0, fmt.Errorf("bet_repo.GetByUserID count: %w", err)
+	}
 	var bets []*domain.Bet
 	err := r.db.SelectContext(ctx, &bets,
 		`SELECT * FROM bets WHERE user_id = $1 ORDER BY placed_at DESC LIMIT $2 OFFSET $3`,
 		userID, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("bet_repo.GetByUserID: %w", err)
+		return nil, 0, fmt.Errorf("bet_repo.GetByUserID: %w", err)
 	}
-	return bets, nil
+	return bets, total, nil
 }
 
 // GetActiveByMarket returns all bets in open status for a given market.
@@ -85,6 +90,19 @@ func (r *BetRepository) GetActiveByMarket(ctx context.Context, marketID uuid.UUI
 	return bets, nil
 }
 
+// GetOpenByMarketTx returns every open bet in a market through q, for use
+// inside the settlement transaction.
+func (r *BetRepository) GetOpenByMarketTx(ctx context.Context, q sqlx.QueryerContext, marketID uuid.UUID) ([]*domain.Bet, error) {
+	var bets []*domain.Bet
+	err := sqlx.SelectContext(ctx, q, &bets,
+		`SELECT * FROM bets WHERE market_id = $1 AND status = 'open' ORDER BY placed_at ASC`,
+		marketID)
+	if err != nil {
+		return nil, fmt.Errorf("bet_repo.GetOpenByMarketTx: %w", err)
+	}
+	return bets, nil
+}
+
 // UpdateStatus sets the status and payout of a bet inside a transaction.
 // Designed for use by the resolution service.
 func (r *BetRepository) UpdateStatus(ctx context.Context, tx *sqlx.Tx, betID uuid.UUID, status domain.BetStatus, payout *decimal.Decimal) error {
@@ -120,36 +138,76 @@ func (r *BetRepository) ExitBet(ctx context.Context, tx *sqlx.Tx, betID uuid.UUI
 	return nil
 }
 
-// GetPlatformDailyExposure sums the platform's open MM positions for today.
-func (r *BetRepository) GetPlatformDailyExposure(ctx context.Context) (decimal.Decimal, error) {
+// GetMMRealizedPnL sums the PnL of MM positions settled at or after since.
+func (r *BetRepository) GetMMRealizedPnL(ctx context.Context, q sqlx.QueryerContext, since time.Time) (decimal.Decimal, error) {
 	var total decimal.Decimal
-	err := r.db.GetContext(ctx, &total, `
-		SELECT COALESCE(SUM(amount), 0)
+	err := sqlx.GetContext(ctx, q, &total, `
+		SELECT COALESCE(SUM(pnl), 0)
 		FROM mm_positions
-		WHERE status = 'open'
-		  AND created_at >= date_trunc('day', now())`)
+		WHERE status IN ('won', 'lost')
+		  AND closed_at >= $1`,
+		since)
 	if err != nil {
-		return decimal.Zero, fmt.Errorf("bet_repo.GetPlatformDailyExposure: %w", err)
+		return decimal.Zero, fmt.Errorf("bet_repo.GetMMRealizedPnL: %w", err)
 	}
 	return total, nil
 }
 
-// CreatePlatformBet inserts an MM liquidity injection record in mm_positions.
-func (r *BetRepository) CreatePlatformBet(ctx context.Context, marketID uuid.UUID, outcome domain.Outcome, amount decimal.Decimal, reason string) error {
+// GetOpenMMExposures returns the open MM stake per side and the current pools
+// for every market where the MM still has open positions.
+func (r *BetRepository) GetOpenMMExposures(ctx context.Context, q sqlx.QueryerContext) ([]domain.MMExposure, error) {
+	var exposures []domain.MMExposure
+	err := sqlx.SelectContext(ctx, q, &exposures, `
+		SELECT p.market_id,
+		       COALESCE(SUM(p.amount) FILTER (WHERE p.direction = 'UP'), 0)   AS stake_up,
+		       COALESCE(SUM(p.amount) FILTER (WHERE p.direction = 'DOWN'), 0) AS stake_down,
+		       m.pool_up,
+		       m.pool_down
+		FROM mm_positions p
+		JOIN markets m ON m.id = p.market_id
+		WHERE p.status = 'open'
+		GROUP BY p.market_id, m.pool_up, m.pool_down`)
+	if err != nil {
+		return nil, fmt.Errorf("bet_repo.GetOpenMMExposures: %w", err)
+	}
+	return exposures, nil
+}
+
+// CreatePlatformBet inserts an MM liquidity injection record in mm_positions
+// inside the injection transaction.
+func (r *BetRepository) CreatePlatformBet(ctx context.Context, tx *sqlx.Tx, marketID uuid.UUID, outcome domain.Outcome, amount decimal.Decimal, reason string) error {
 	query := `
-		INSERT INTO mm_positions (market_id, direction, amount, status, created_at)
-		VALUES ($1, $2, $3, 'open', $4)`
-	if _, err := r.db.ExecContext(ctx, query, marketID, string(outcome), amount, time.Now()); err != nil {
+		INSERT INTO mm_positions (market_id, direction, amount, status, reason, created_at)
+		VALUES ($1, $2, $3, 'open', $4, $5)`
+	if _, err := tx.ExecContext(ctx, query, marketID, string(outcome), amount, reason, time.Now()); err != nil {
 		return fmt.Errorf("bet_repo.CreatePlatformBet: %w", err)
 	}
 	return nil
 }
 
+// GetMMStakesBySide returns the open MM stake on each side of a market.
+func (r *BetRepository) GetMMStakesBySide(ctx context.Context, marketID uuid.UUID) (up, down decimal.Decimal, err error) {
+	var row struct {
+		Up   decimal.Decimal `db:"up"`
+		Down decimal.Decimal `db:"down"`
+	}
+	err = r.db.GetContext(ctx, &row, `
+		SELECT COALESCE(SUM(amount) FILTER (WHERE direction = 'UP'), 0)   AS up,
+		       COALESCE(SUM(amount) FILTER (WHERE direction = 'DOWN'), 0) AS down
+		FROM mm_positions
+		WHERE market_id = $1 AND status = 'open'`,
+		marketID)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("bet_repo.GetMMStakesBySide: %w", err)
+	}
+	return row.Up, row.Down, nil
+}
+
 // GetMMLogsByMarket returns all MM positions for a given market.
 func (r *BetRepository) GetMMLogsByMarket(ctx context.Context, marketID uuid.UUID) ([]*domain.MMLog, error) {
 	var logs []*domain.MMLog
 	err := r.db.SelectContext(ctx, &logs,
-		`SELECT id, market_id, direction, amount, status, pnl, '' AS reason, created_at, closed_at
+		`SELECT id, market_id, direction, amount, status, pnl, reason, created_at, closed_at
 		 FROM mm_positions
 		 WHERE market_id = $1
 		 ORDER BY created_at ASC`,
@@ -160,6 +218,22 @@ func (r *BetRepository) GetMMLogsByMarket(ctx context.Context, marketID uuid.UUI
 	return logs, nil
 }
 
+// GetOpenMMPositionsTx returns the open MM positions of a market through q,
+// for use inside the settlement transaction.
+func (r *BetRepository) GetOpenMMPositionsTx(ctx context.Context, q sqlx.QueryerContext, marketID uuid.UUID) ([]*domain.MMLog, error) {
+	var logs []*domain.MMLog
+	err := sqlx.SelectContext(ctx, q, &logs,
+		`SELECT id, market_id, direction, amount, status, pnl, reason, created_at, closed_at
+		 FROM mm_positions
+		 WHERE market_id = $1 AND status = 'open'
+		 ORDER BY created_at ASC`,
+		marketID)
+	if err != nil {
+		return nil, fmt.Errorf("bet_repo.GetOpenMMPositionsTx: %w", err)
+	}
+	return logs, nil
+}
+
 // UpdateStatusBulk sets status = lost (or refunded) for every open bet of a
 // given direction in a market, inside the resolution transaction.
 // Only touches bets that are still status='open' to avoid double-processing.
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// BonusRepository handles the bonus_grants table.  Bonus balances
+// themselves live in the ledger (ledger.UserBonus).
+type BonusRepository struct {
+	db *sqlx.DB
+}
+
+// NewBonusRepository creates a new BonusRepository.
+func NewBonusRepository(db *sqlx.DB) *BonusRepository {
+	return &BonusRepository{db: db}
+}
+
+// Create inserts a grant inside tx.  Returns domain.ErrBonusActive when the
+// user already has an active grant.
+func (r *BonusRepository) Create(ctx context.Context, tx *sqlx.Tx, g *domain.BonusGrant) error {
+	query := `
+		INSERT INTO bonus_grants
+			(id, user_id, amount, wagering_required, wagered, status, reason, created_by, created_at, expires_at)
+		VALUES
+			(:id, :user_id, :amount, :wagering_required, :wagered, :status, :reason, :created_by, :created_at, :expires_at)`
+	if _, err := tx.NamedExecContext(ctx, query, g); err != nil {
+		if isPgUniqueViolation(err, "idx_bonus_grants_active") {
+			return domain.ErrBonusActive
+		}
+		return fmt.Errorf("bonus_repo.Create: %w", err)
+	}
+	return nil
+}
+
+// GetActive returns the user's active grant.
+func (r *BonusRepository) GetActive(ctx context.Context, userID uuid.UUID) (*domain.BonusGrant, error) {
+	var g domain.BonusGrant
+	err := r.db.GetContext(ctx, &g, `
+		SELECT * FROM bonus_grants WHERE user_id = $1 AND status = 'active'`, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrBonusNotFound
+		}
+		return nil, fmt.Errorf("bonus_repo.GetActive: %w", err)
+	}
+	return &g, nil
+}
+
+// GetActiveForUpdate locks and returns the user's active grant inside tx.
+func (r *BonusRepository) GetActiveForUpdate(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) (*domain.BonusGrant, error) {
+	var g domain.BonusGrant
+	err := tx.GetContext(ctx, &g, `
+		SELECT * FROM bonus_grants WHERE user_id = $1 AND status = 'active' FOR UPDATE`, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrBonusNotFound
+		}
+		return nil, fmt.Errorf("bonus_repo.GetActiveForUpdate: %w", err)
+	}
+	return &g, nil
+}
+
+// GetLatest returns the user's most recent grant in any status.
+func (r *BonusRepository) GetLatest(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) (*domain.BonusGrant, error) {
+	var g domain.BonusGrant
+	err := tx.GetContext(ctx, &g, `
+		SELECT * FROM bonus_grants WHERE user_id = $1
+		ORDER BY created_at DESC LIMIT 1`, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrBonusNotFound
+		}
+		return nil, fmt.Errorf("bonus_repo.GetLatest: %w", err)
+	}
+	return &g, nil
+}
+
+// ListByUser returns a user's grants, newest first.
+func (r *BonusRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.BonusGrant, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total,
+		`SELECT COUNT(*) FROM bonus_grants WHERE user_id = $1`, userID); err != nil {
+		return nil, 0, fmt.Errorf("bonus_repo.ListByUser count: %w", err)
+	}
+	grants := []*domain.BonusGrant{}
+	err := r.db.SelectContext(ctx, &grants, `
+		SELECT * FROM bonus_grants WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`,
+		userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("bonus_repo.ListByUser: %w", err)
+	}
+	return grants, total, nil
+}
+
+// ListExpired returns the users whose active grant is past its expiry.
+func (r *BonusRepository) ListExpired(ctx context.Context) ([]uuid.UUID, error) {
+	userIDs := []uuid.UUID{}
+	err := r.db.SelectContext(ctx, &userIDs, `
+		SELECT user_id FROM bonus_grants
+		WHERE status = 'active' AND expires_at <= now()
+		ORDER BY expires_at`)
+	if err != nil {
+		return nil, fmt.Errorf("bonus_repo.ListExpired: %w", err)
+	}
+	return userIDs, nil
+}
+
+// GrantStakes is what one active grant's owner staked on each side of a
+// settled market.
+type GrantStakes struct {
+	GrantID uuid.UUID       `db:"grant_id"`
+	Up      decimal.Decimal `db:"up"`
+	Down    decimal.Decimal `db:"down"`
+}
+
+// MarketStakes returns, for each active grant whose owner had won or lost
+// bets on the settled market, their stakes per side.  Only bets placed
+// after the grant at odds of at least minOdds count; exits, refunds and
+// free bets never do.  Call it after the bet statuses have been set in tx.
+func (r *BonusRepository) MarketStakes(ctx context.Context, tx *sqlx.Tx, marketID uuid.UUID, minOdds float64) ([]GrantStakes, error) {
+	var stakes []GrantStakes
+	err := tx.SelectContext(ctx, &stakes, `
+		SELECT g.id AS grant_id,
+		       COALESCE(SUM(b.amount) FILTER (WHERE b.direction = 'UP'), 0)   AS up,
+		       COALESCE(SUM(b.amount) FILTER (WHERE b.direction = 'DOWN'), 0) AS down
+		FROM bets b
+		JOIN bonus_grants g ON g.user_id = b.user_id AND g.status = 'active'
+		WHERE b.market_id = $1
+		  AND b.status IN ('won', 'lost')
+		  AND b.odds_at_entry >= $2
+		  AND b.free_bet_id IS NULL
+		  AND b.placed_at >= g.created_at
+		GROUP BY g.id`,
+		marketID, minOdds)
+	if err != nil {
+		return nil, fmt.Errorf("bonus_repo.MarketStakes: %w", err)
+	}
+	return stakes, nil
+}
+
+// AddWagered adds amount to an active grant's wagering inside tx and
+// returns the grant.
+func (r *BonusRepository) AddWagered(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, amount decimal.Decimal) (*domain.BonusGrant, error) {
+	var g domain.BonusGrant
+	err := tx.GetContext(ctx, &g, `
+		UPDATE bonus_grants SET wagered = wagered + $2
+		WHERE id = $1 AND status = 'active'
+		RETURNING *`,
+		id, amount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrBonusNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("bonus_repo.AddWagered: %w", err)
+	}
+	return &g, nil
+}
+
+// Close ends an active grant with status.
+func (r *BonusRepository) Close(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, status domain.BonusStatus) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE bonus_grants SET status = $1, closed_at = now()
+		WHERE id = $2 AND status = 'active'`,
+		string(status), id)
+	if err != nil {
+		return fmt.Errorf("bonus_repo.Close: %w", err)
+	}
+	return nil
+}
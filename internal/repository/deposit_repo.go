@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// DepositRepository handles the deposits and payment_webhook_events tables.
+type DepositRepository struct {
+	db *sqlx.DB
+}
+
+// NewDepositRepository creates a new DepositRepository.
+func NewDepositRepository(db *sqlx.DB) *DepositRepository {
+	return &DepositRepository{db: db}
+}
+
+// Create inserts a new deposit inside tx.
+func (r *DepositRepository) Create(ctx context.Context, tx *sqlx.Tx, d *domain.Deposit) error {
+	query := `
+		INSERT INTO deposits
+			(id, user_id, amount, status, provider, provider_ref, checkout_url, created_at, updated_at)
+		VALUES
+			(:id, :user_id, :amount, :status, :provider, :provider_ref, :checkout_url, :created_at, :updated_at)`
+	if _, err := tx.NamedExecContext(ctx, query, d); err != nil {
+		return fmt.Errorf("deposit_repo.Create: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a deposit by its ID.
+func (r *DepositRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Deposit, error) {
+	var d domain.Deposit
+	err := r.db.GetContext(ctx, &d, `SELECT * FROM deposits WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrDepositNotFound
+		}
+		return nil, fmt.Errorf("deposit_repo.GetByID: %w", err)
+	}
+	return &d, nil
+}
+
+// GetByProviderRef returns the deposit with a provider's payment reference.
+func (r *DepositRepository) GetByProviderRef(ctx context.Context, provider, ref string) (*domain.Deposit, error) {
+	var d domain.Deposit
+	err := r.db.GetContext(ctx, &d, `
+		SELECT * FROM deposits WHERE provider = $1 AND provider_ref = $2`,
+		provider, ref)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrDepositNotFound
+		}
+		return nil, fmt.Errorf("deposit_repo.GetByProviderRef: %w", err)
+	}
+	return &d, nil
+}
+
+// GetByProviderRefForUpdate locks and returns the deposit a provider refers
+// to by its payment reference.
+func (r *DepositRepository) GetByProviderRefForUpdate(ctx context.Context, tx *sqlx.Tx, provider, ref string) (*domain.Deposit, error) {
+	var d domain.Deposit
+	err := tx.GetContext(ctx, &d, `
+		SELECT * FROM deposits
+		WHERE provider = $1 AND provider_ref = $2
+		FOR UPDATE`,
+		provider, ref)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrDepositNotFound
+		}
+		return nil, fmt.Errorf("deposit_repo.GetByProviderRefForUpdate: %w", err)
+	}
+	return &d, nil
+}
+
+// ListByUser returns a user's deposits, newest first.
+func (r *DepositRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Deposit, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total,
+		`SELECT COUNT(*) FROM deposits WHERE user_id = $1`, userID); err != nil {
+		return nil, 0, fmt.Errorf("deposit_repo.ListByUser count: %w", err)
+	}
+	deposits := []*domain.Deposit{}
+	err := r.db.SelectContext(ctx, &deposits, `
+		SELECT * FROM deposits
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`,
+		userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("deposit_repo.ListByUser: %w", err)
+	}
+	return deposits, total, nil
+}
+
+// List returns deposits filtered by status and user, newest first.
+// status="" and userID=nil mean no filter.
+func (r *DepositRepository) List(ctx context.Context, status string, userID *uuid.UUID, limit, offset int) ([]*domain.Deposit, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `
+		SELECT COUNT(*) FROM deposits
+		WHERE ($1 = '' OR status = $1)
+		  AND ($2::uuid IS NULL OR user_id = $2)`,
+		status, userID); err != nil {
+		return nil, 0, fmt.Errorf("deposit_repo.List count: %w", err)
+	}
+	deposits := []*domain.Deposit{}
+	err := r.db.SelectContext(ctx, &deposits, `
+		SELECT * FROM deposits
+		WHERE ($1 = '' OR status = $1)
+		  AND ($2::uuid IS NULL OR user_id = $2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4`,
+		status, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("deposit_repo.List: %w", err)
+	}
+	return deposits, total, nil
+}
+
+// MarkCompleted settles a pending or expired deposit inside tx, recording
+// the ledger entry that credited the wallet.  DepositService.HandleWebhook
+// re-checks the deposit limits before completing an expired deposit.
+func (r *DepositRepository) MarkCompleted(ctx context.Context, tx *sqlx.Tx, id, entryID uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE deposits
+		SET status = 'completed', entry_id = $1, completed_at = now(), updated_at = now()
+		WHERE id = $2 AND status IN ('pending', 'expired')`,
+		entryID, id)
+	if err != nil {
+		return fmt.Errorf("deposit_repo.MarkCompleted: %w", err)
+	}
+	return nil
+}
+
+// SetCheckout records the provider's reference and checkout URL of a
+// deposit stored before its checkout was opened.
+func (r *DepositRepository) SetCheckout(ctx context.Context, id uuid.UUID, ref, url string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE deposits SET provider_ref = $1, checkout_url = $2, updated_at = now()
+		WHERE id = $3`,
+		ref, url, id)
+	if err != nil {
+		return fmt.Errorf("deposit_repo.SetCheckout: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed closes a pending or expired deposit inside tx without crediting
+// it.  Returns domain.ErrDepositState when the deposit is already final.
+func (r *DepositRepository) MarkFailed(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, reason string) error {
+	res, err := tx.ExecContext(ctx, `
+		UPDATE deposits
+		SET status = 'failed', failure_reason = $1, updated_at = now()
+		WHERE id = $2 AND status IN ('pending', 'expired')`,
+		reason, id)
+	if err != nil {
+		return fmt.Errorf("deposit_repo.MarkFailed: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrDepositState
+	}
+	return nil
+}
+
+// HasCompleted reports whether the user has ever completed a deposit.
+func (r *DepositRepository) HasCompleted(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var ok bool
+	err := r.db.GetContext(ctx, &ok, `
+		SELECT EXISTS (SELECT 1 FROM deposits WHERE user_id = $1 AND status = 'completed')`, userID)
+	if err != nil {
+		return false, fmt.Errorf("deposit_repo.HasCompleted: %w", err)
+	}
+	return ok, nil
+}
+
+// ExpirePending marks pending deposits created before cutoff as expired and
+// returns how many there were.
+func (r *DepositRepository) ExpirePending(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE deposits
+		SET status = 'expired', updated_at = now()
+		WHERE status = 'pending' AND created_at < $1`,
+		cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("deposit_repo.ExpirePending: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// RecordWebhookEvent stores a verified webhook event for depositID inside
+// tx.  Returns false when the provider already delivered this event.
+func (r *DepositRepository) RecordWebhookEvent(ctx context.Context, tx *sqlx.Tx, provider, eventID, eventType string, depositID uuid.UUID, payload []byte) (bool, error) {
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO payment_webhook_events (provider, event_id, event_type, deposit_id, payload)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (provider, event_id) DO NOTHING`,
+		provider, eventID, eventType, depositID, string(payload))
+	if err != nil {
+		return false, fmt.Errorf("deposit_repo.RecordWebhookEvent: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// KYCRepository handles the kyc_submissions and kyc_documents tables and
+// users.kyc_status.
+type KYCRepository struct {
+	db *sqlx.DB
+}
+
+// NewKYCRepository creates a new KYCRepository.
+func NewKYCRepository(db *sqlx.DB) *KYCRepository {
+	return &KYCRepository{db: db}
+}
+
+// ── User status ──────────────────────────────────────────────────────────────
+
+// UserStatus returns the user's verification status.
+func (r *KYCRepository) UserStatus(ctx context.Context, q sqlx.QueryerContext, userID uuid.UUID) (domain.KYCStatus, error) {
+	var status domain.KYCStatus
+	err := sqlx.GetContext(ctx, q, &status, `SELECT kyc_status FROM users WHERE id = $1`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", domain.ErrUserNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("kyc_repo.UserStatus: %w", err)
+	}
+	return status, nil
+}
+
+// LockUserStatus locks the user row inside tx and returns the
+// verification status, so that concurrent submissions are serialised.
+func (r *KYCRepository) LockUserStatus(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) (domain.KYCStatus, error) {
+	var status domain.KYCStatus
+	err := tx.GetContext(ctx, &status, `SELECT kyc_status FROM users WHERE id = $1 FOR UPDATE`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", domain.ErrUserNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("kyc_repo.LockUserStatus: %w", err)
+	}
+	return status, nil
+}
+
+// SetUserStatus sets the user's verification status inside tx and, when
+// fullName is not empty, their legal name.  Returns the legal name now on
+// record.
+func (r *KYCRepository) SetUserStatus(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, status domain.KYCStatus, fullName string) (string, error) {
+	var name string
+	err := tx.GetContext(ctx, &name, `
+		UPDATE users
+		SET kyc_status = $2, full_name = COALESCE(NULLIF($3, ''), full_name), updated_at = now()
+		WHERE id = $1
+		RETURNING full_name`,
+		userID, string(status), fullName)
+	if err != nil {
+		return "", fmt.Errorf("kyc_repo.SetUserStatus: %w", err)
+	}
+	return name, nil
+}
+
+// ── Documents ────────────────────────────────────────────────────────────────
+
+// CreateDocument inserts an uploaded document.
+func (r *KYCRepository) CreateDocument(ctx context.Context, d *domain.KYCDocument) error {
+	query := `
+		INSERT INTO kyc_documents
+			(id, user_id, submission_id, kind, storage_key, file_name, content_type, size_bytes, sha256, created_at)
+		VALUES
+			(:id, :user_id, :submission_id, :kind, :storage_key, :file_name, :content_type, :size_bytes, :sha256, :created_at)`
+	if _, err := r.db.NamedExecContext(ctx, query, d); err != nil {
+		return fmt.Errorf("kyc_repo.CreateDocument: %w", err)
+	}
+	return nil
+}
+
+// GetDocument returns a document by ID.
+func (r *KYCRepository) GetDocument(ctx context.Context, id uuid.UUID) (*domain.KYCDocument, error) {
+	var d domain.KYCDocument
+	err := r.db.GetContext(ctx, &d, `SELECT * FROM kyc_documents WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrKYCDocumentNotFound
+		}
+		return nil, fmt.Errorf("kyc_repo.GetDocument: %w", err)
+	}
+	return &d, nil
+}
+
+// UnsubmittedDocuments returns the documents the user uploaded but has not
+// submitted yet, oldest first.
+func (r *KYCRepository) UnsubmittedDocuments(ctx context.Context, q sqlx.QueryerContext, userID uuid.UUID) ([]*domain.KYCDocument, error) {
+	var docs []*domain.KYCDocument
+	err := sqlx.SelectContext(ctx, q, &docs, `
+		SELECT * FROM kyc_documents
+		WHERE user_id = $1 AND submission_id IS NULL
+		ORDER BY created_at`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("kyc_repo.UnsubmittedDocuments: %w", err)
+	}
+	return docs, nil
+}
+
+// SubmissionDocuments returns the documents of a submission.
+func (r *KYCRepository) SubmissionDocuments(ctx context.Context, submissionID uuid.UUID) ([]*domain.KYCDocument, error) {
+	var docs []*domain.KYCDocument
+	err := r.db.SelectContext(ctx, &docs, `
+		SELECT * FROM kyc_documents WHERE submission_id = $1 ORDER BY created_at`,
+		submissionID)
+	if err != nil {
+		return nil, fmt.Errorf("kyc_repo.SubmissionDocuments: %w", err)
+	}
+	return docs, nil
+}
+
+// ── Submissions ──────────────────────────────────────────────────────────────
+
+// CreateSubmission inserts a submission inside tx and attaches the user's
+// unsubmitted documents to it.
+func (r *KYCRepository) CreateSubmission(ctx context.Context, tx *sqlx.Tx, s *domain.KYCSubmission) error {
+	query := `
+		INSERT INTO kyc_submissions (id, user_id, status, submitted_at)
+		VALUES (:id, :user_id, :status, :submitted_at)`
+	if _, err := tx.NamedExecContext(ctx, query, s); err != nil {
+		return fmt.Errorf("kyc_repo.CreateSubmission: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE kyc_documents SET submission_id = $1
+		WHERE user_id = $2 AND submission_id IS NULL`,
+		s.ID, s.UserID); err != nil {
+		return fmt.Errorf("kyc_repo.CreateSubmission: attach documents: %w", err)
+	}
+	return nil
+}
+
+// GetSubmission returns a submission by ID.
+func (r *KYCRepository) GetSubmission(ctx context.Context, id uuid.UUID) (*domain.KYCSubmission, error) {
+	var s domain.KYCSubmission
+	err := r.db.GetContext(ctx, &s, `SELECT * FROM kyc_submissions WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrKYCSubmissionNotFound
+		}
+		return nil, fmt.Errorf("kyc_repo.GetSubmission: %w", err)
+	}
+	return &s, nil
+}
+
+// GetSubmissionForUpdate locks and returns a submission inside tx.
+func (r *KYCRepository) GetSubmissionForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.KYCSubmission, error) {
+	var s domain.KYCSubmission
+	err := tx.GetContext(ctx, &s, `SELECT * FROM kyc_submissions WHERE id = $1 FOR UPDATE`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrKYCSubmissionNotFound
+		}
+		return nil, fmt.Errorf("kyc_repo.GetSubmissionForUpdate: %w", err)
+	}
+	return &s, nil
+}
+
+// LatestSubmission returns the user's most recent submission, or nil when
+// they never submitted.
+func (r *KYCRepository) LatestSubmission(ctx context.Context, userID uuid.UUID) (*domain.KYCSubmission, error) {
+	var s domain.KYCSubmission
+	err := r.db.GetContext(ctx, &s, `
+		SELECT * FROM kyc_submissions
+		WHERE user_id = $1
+		ORDER BY submitted_at DESC
+		LIMIT 1`,
+		userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("kyc_repo.LatestSubmission: %w", err)
+	}
+	return &s, nil
+}
+
+// Review records a decision on a submission inside tx.
+func (r *KYCRepository) Review(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, status domain.KYCStatus, adminID uuid.UUID, note string) (*domain.KYCSubmission, error) {
+	var s domain.KYCSubmission
+	err := tx.GetContext(ctx, &s, `
+		UPDATE kyc_submissions
+		SET status = $2, reviewed_by = $3, reviewed_at = now(), review_note = NULLIF($4, '')
+		WHERE id = $1
+		RETURNING *`,
+		id, string(status), adminID, note)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrKYCSubmissionNotFound
+		}
+		return nil, fmt.Errorf("kyc_repo.Review: %w", err)
+	}
+	return &s, nil
+}
+
+// ListSubmissions returns submissions with the user's details, optionally
+// filtered by status.  Pending ones come oldest first so the queue is
+// worked in order; the rest newest first.
+func (r *KYCRepository) ListSubmissions(ctx context.Context, status string, limit, offset int) ([]*domain.KYCSubmissionItem, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total,
+		`SELECT COUNT(*) FROM kyc_submissions WHERE ($1 = '' OR status = $1)`, status); err != nil {
+		return nil, 0, fmt.Errorf("kyc_repo.ListSubmissions count: %w", err)
+	}
+	var items []*domain.KYCSubmissionItem
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT s.*, u.username, u.email, u.full_name
+		FROM kyc_submissions s
+		JOIN users u ON u.id = s.user_id
+		WHERE ($1 = '' OR s.status = $1)
+		ORDER BY CASE WHEN $1 = 'pending' THEN s.submitted_at END ASC,
+		         s.submitted_at DESC
+		LIMIT $2 OFFSET $3`,
+		status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("kyc_repo.ListSubmissions: %w", err)
+	}
+	return items, total, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/evetabi/prediction/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
 )
 
 // MarketRepository handles all database operations for Markets.
@@ -26,9 +27,9 @@ func NewMarketRepository(db *sqlx.DB) *MarketRepository {
 func (r *MarketRepository) Create(ctx context.Context, m *domain.Market) error {
 	query := `
 		INSERT INTO markets
-			(id, status, open_price, pool_up, pool_down, commission_taken, opens_at, closes_at, created_at, updated_at)
+			(id, series, status, open_price, pool_up, pool_down, commission_taken, opens_at, closes_at, created_at, updated_at)
 		VALUES
-			(:id, :status, :open_price, :pool_up, :pool_down, :commission_taken, :opens_at, :closes_at, :created_at, :updated_at)`
+			(:id, :series, :status, :open_price, :pool_up, :pool_down, :commission_taken, :opens_at, :closes_at, :created_at, :updated_at)`
 	_, err := r.db.NamedExecContext(ctx, query, m)
 	if err != nil {
 		return fmt.Errorf("market_repo.Create: %w", err)
@@ -104,8 +105,10 @@ func (r *MarketRepository) UpdatePools(ctx context.Context, tx *sqlx.Tx, marketI
 	return nil
 }
 
-// Resolve sets close_price, result, status=resolved and resolved_at.
-func (r *MarketRepository) Resolve(ctx context.Context, marketID uuid.UUID, closePrice interface{}, winner domain.Outcome) error {
+// Resolve sets close_price, result, status=resolved and resolved_at inside
+// tx, so the market is only resolved if its settlement commits.  Returns
+// domain.ErrMarketNotFound when the market is not open or closed.
+func (r *MarketRepository) Resolve(ctx context.Context, tx *sqlx.Tx, marketID uuid.UUID, closePrice interface{}, winner domain.Outcome) error {
 	query := `
 		UPDATE markets
 		SET status      = 'resolved',
@@ -114,7 +117,7 @@ func (r *MarketRepository) Resolve(ctx context.Context, marketID uuid.UUID, clos
 		    resolved_at = now(),
 		    updated_at  = now()
 		WHERE id = $3 AND status IN ('open','closed')`
-	res, err := r.db.ExecContext(ctx, query, closePrice, string(winner), marketID)
+	res, err := tx.ExecContext(ctx, query, closePrice, string(winner), marketID)
 	if err != nil {
 		return fmt.Errorf("market_repo.Resolve: %w", err)
 	}
@@ -124,6 +127,34 @@ func (r *MarketRepository) Resolve(ctx context.Context, marketID uuid.UUID, clos
 	return nil
 }
 
+// LockOpen locks an open market inside tx and returns it with its current
+// pools.  Returns domain.ErrMarketNotOpen when it is not open.
+func (r *MarketRepository) LockOpen(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.Market, error) {
+	var m domain.Market
+	err := tx.GetContext(ctx, &m, `SELECT * FROM markets WHERE id = $1 AND status = 'open' FOR UPDATE`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrMarketNotOpen
+		}
+		return nil, fmt.Errorf("market_repo.LockOpen: %w", err)
+	}
+	return &m, nil
+}
+
+// GetByIDTx re-reads a market through q.  Called inside the
+// settlement transaction after Resolve has locked the row, so the pools are
+// final: bets and exits update them under the same lock.
+func (r *MarketRepository) GetByIDTx(ctx context.Context, q sqlx.QueryerContext, id uuid.UUID) (*domain.Market, error) {
+	var m domain.Market
+	if err := sqlx.GetContext(ctx, q, &m, `SELECT * FROM markets WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrMarketNotFound
+		}
+		return nil, fmt.Errorf("market_repo.GetByIDTx: %w", err)
+	}
+	return &m, nil
+}
+
 // Suspend sets the market status to suspended.
 func (r *MarketRepository) Suspend(ctx context.Context, marketID uuid.UUID, reason string) error {
 	query := `
@@ -187,7 +218,11 @@ func (r *MarketRepository) List(ctx context.Context, limit, offset int, status s
 }
 
 // GetHistory returns closed/resolved markets in descending time order.
-func (r *MarketRepository) GetHistory(ctx context.Context, limit, offset int) ([]*domain.Market, error) {
+func (r *MarketRepository) GetHistory(ctx context.Context, limit, offset int) ([]*domain.Market, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM markets WHERE status IN ('resolved','cancelled')`); err != nil {
+		return nil, 0, fmt.Errorf("market_repo.GetHistory count: %w", err)
+	}
 	var markets []*domain.Market
 	err := r.db.SelectContext(ctx, &markets,
 		`SELECT * FROM markets
@@ -196,74 +231,131 @@ func (r *MarketRepository) GetHistory(ctx context.Context, limit, offset int) ([
 		 LIMIT $1 OFFSET $2`,
 		limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("market_repo.GetHistory: %w", err)
+		return nil, 0, fmt.Errorf("market_repo.GetHistory: %w", err)
 	}
-	return markets, nil
+	return markets, total, nil
 }
 
-// FinanceReport holds aggregated financial data for a date range.
+// FinanceReport holds aggregated financial data for a date range.  Amounts
+// are strings to preserve decimal precision in JSON.
 type FinanceReport struct {
 	From             time.Time `json:"from"`
 	To               time.Time `json:"to"`
 	CommissionEarned string    `json:"commission_earned"`
 	MMPnL            string    `json:"mm_pnl"`
 	CashoutFees      string    `json:"cashout_fees"`
+	CashoutPnL       string    `json:"cashout_pnl"` // stake minus gross exit value of early exits
 	NetProfit        string    `json:"net_profit"`
 	TotalUpPool      string    `json:"total_up_pool"`
 	TotalDownPool    string    `json:"total_down_pool"`
 	TotalVolume      string    `json:"total_volume"`
 	MarketCount      int       `json:"market_count"`
+
+	// Reconciliation of treasury MM PnL against the platform MM wallet:
+	// mm_payout − mm_stake transactions referencing the same markets.
+	PlatformWalletMMNet string             `json:"platform_wallet_mm_net"`
+	MMPnLDifference     string             `json:"mm_pnl_difference"` // treasury − wallet
+	Reconciled          bool               `json:"reconciled"`
+	Mismatches          []TreasuryMismatch `json:"mismatches"`
 }
 
-// GetFinanceReport aggregates house_treasury and market data for a date range.
+// TreasuryMismatch is a market whose treasury MM PnL disagrees with the
+// platform wallet movements recorded for it.
+type TreasuryMismatch struct {
+	MarketID      uuid.UUID       `json:"market_id"        db:"market_id"`
+	TreasuryMMPnL decimal.Decimal `json:"treasury_mm_pnl"  db:"treasury_mm_pnl"`
+	WalletMMNet   decimal.Decimal `json:"wallet_mm_net"    db:"wallet_mm_net"`
+}
+
+// GetFinanceReport aggregates house_treasury and market data for markets
+// resolved with a close time in [from, to), and reconciles MM PnL against the
+// platform wallet's mm_stake / mm_payout transactions for those markets.
 func (r *MarketRepository) GetFinanceReport(ctx context.Context, from, to time.Time) (*FinanceReport, error) {
 	type row struct {
 		CommissionEarned string `db:"commission_earned"`
 		MMPnL            string `db:"mm_pnl"`
 		CashoutFees      string `db:"cashout_fees"`
+		CashoutPnL       string `db:"cashout_pnl"`
+		NetProfit        string `db:"net_profit"`
+		TotalUp          string `db:"total_up"`
+		TotalDown        string `db:"total_down"`
+		TotalVolume      string `db:"total_volume"`
+		Count            int    `db:"count"`
 	}
 	var fin row
 	err := r.db.GetContext(ctx, &fin, `
 		SELECT
-			COALESCE(SUM(commission_earned), 0)::text AS commission_earned,
-			COALESCE(SUM(mm_pnl), 0)::text            AS mm_pnl,
-			COALESCE(SUM(cashout_fees_earned), 0)::text AS cashout_fees
-		FROM house_treasury
-		WHERE created_at >= $1 AND created_at < $2`,
+			COALESCE(SUM(h.commission_earned), 0)::text   AS commission_earned,
+			COALESCE(SUM(h.mm_pnl), 0)::text              AS mm_pnl,
+			COALESCE(SUM(h.cashout_fees_earned), 0)::text AS cashout_fees,
+			COALESCE(SUM(h.cashout_pnl), 0)::text         AS cashout_pnl,
+			COALESCE(SUM(h.commission_earned + h.mm_pnl + h.cashout_fees_earned + h.cashout_pnl), 0)::text AS net_profit,
+			COALESCE(SUM(m.pool_up), 0)::text             AS total_up,
+			COALESCE(SUM(m.pool_down), 0)::text           AS total_down,
+			COALESCE(SUM(m.pool_up + m.pool_down), 0)::text AS total_volume,
+			COUNT(*)                                      AS count
+		FROM markets m
+		LEFT JOIN house_treasury h ON h.market_id = m.id
+		WHERE m.status = 'resolved'
+		  AND m.closes_at >= $1 AND m.closes_at < $2`,
 		from, to)
 	if err != nil {
 		return nil, fmt.Errorf("market_repo.GetFinanceReport treasury: %w", err)
 	}
 
-	type mrow struct {
-		TotalUp   string `db:"total_up"`
-		TotalDown string `db:"total_down"`
-		Count     int    `db:"count"`
-	}
-	var mdata mrow
-	err = r.db.GetContext(ctx, &mdata, `
-		SELECT
-			COALESCE(SUM(pool_up), 0)::text   AS total_up,
-			COALESCE(SUM(pool_down), 0)::text AS total_down,
-			COUNT(*)                           AS count
-		FROM markets
-		WHERE status = 'resolved'
-		  AND closes_at >= $1 AND closes_at < $2`,
+	// Per-market reconciliation: treasury mm_pnl vs platform wallet movements.
+	var recon []TreasuryMismatch
+	err = r.db.SelectContext(ctx, &recon, `
+		WITH scope AS (
+			SELECT id FROM markets
+			WHERE status = 'resolved' AND closes_at >= $1 AND closes_at < $2
+		),
+		wallet AS (
+			SELECT wt.ref_id AS market_id,
+			       SUM(CASE WHEN wt.type = 'mm_payout' THEN wt.amount ELSE -wt.amount END) AS net
+			FROM wallet_transactions wt
+			JOIN wallets w ON w.id = wt.wallet_id AND w.wallet_type = 'platform_mm'
+			WHERE wt.type IN ('mm_stake', 'mm_payout')
+			  AND wt.ref_id IN (SELECT id FROM scope)
+			GROUP BY wt.ref_id
+		)
+		SELECT s.id                       AS market_id,
+		       COALESCE(h.mm_pnl, 0)      AS treasury_mm_pnl,
+		       COALESCE(wallet.net, 0)    AS wallet_mm_net
+		FROM scope s
+		LEFT JOIN house_treasury h ON h.market_id = s.id
+		LEFT JOIN wallet ON wallet.market_id = s.id
+		WHERE COALESCE(h.mm_pnl, 0) <> 0 OR COALESCE(wallet.net, 0) <> 0`,
 		from, to)
 	if err != nil {
-		return nil, fmt.Errorf("market_repo.GetFinanceReport markets: %w", err)
+		return nil, fmt.Errorf("market_repo.GetFinanceReport reconcile: %w", err)
+	}
+
+	walletNet, treasuryMM := decimal.Zero, decimal.Zero
+	mismatches := []TreasuryMismatch{}
+	for _, m := range recon {
+		walletNet = walletNet.Add(m.WalletMMNet)
+		treasuryMM = treasuryMM.Add(m.TreasuryMMPnL)
+		if !m.WalletMMNet.Equal(m.TreasuryMMPnL) {
+			mismatches = append(mismatches, m)
+		}
 	}
 
-	// Net profit = commission + cashout fees + mm_pnl
-	// All values kept as strings to preserve decimal precision for JSON.
 	return &FinanceReport{
-		From:             from,
-		To:               to,
-		CommissionEarned: fin.CommissionEarned,
-		MMPnL:            fin.MMPnL,
-		CashoutFees:      fin.CashoutFees,
-		TotalUpPool:      mdata.TotalUp,
-		TotalDownPool:    mdata.TotalDown,
-		MarketCount:      mdata.Count,
+		From:                from,
+		To:                  to,
+		CommissionEarned:    fin.CommissionEarned,
+		MMPnL:               fin.MMPnL,
+		CashoutFees:         fin.CashoutFees,
+		CashoutPnL:          fin.CashoutPnL,
+		NetProfit:           fin.NetProfit,
+		TotalUpPool:         fin.TotalUp,
+		TotalDownPool:       fin.TotalDown,
+		TotalVolume:         fin.TotalVolume,
+		MarketCount:         fin.Count,
+		PlatformWalletMMNet: walletNet.StringFixed(4),
+		MMPnLDifference:     treasuryMM.Sub(walletNet).StringFixed(4),
+		Reconciled:          len(mismatches) == 0,
+		Mismatches:          mismatches,
 	}, nil
 }
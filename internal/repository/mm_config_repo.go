@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// MMConfigRepository handles the mm_config table.  Rows are append-only: a
+// change deactivates the current row for a scope and inserts a new one.
+type MMConfigRepository struct {
+	db *sqlx.DB
+}
+
+// NewMMConfigRepository creates a new MMConfigRepository.
+func NewMMConfigRepository(db *sqlx.DB) *MMConfigRepository {
+	return &MMConfigRepository{db: db}
+}
+
+// scopeFilter matches rows belonging to exactly the given scope.
+const scopeFilter = `market_id IS NOT DISTINCT FROM $1 AND series IS NOT DISTINCT FROM $2`
+
+// GetActiveLayers returns the active global, series and market rows that apply
+// to a market, any of which may be nil.
+func (r *MMConfigRepository) GetActiveLayers(ctx context.Context, marketID uuid.UUID, series string) (global, seriesCfg, market *domain.MMConfig, err error) {
+	var rows []*domain.MMConfig
+	err = r.db.SelectContext(ctx, &rows, `
+		SELECT * FROM mm_config
+		WHERE is_active
+		  AND ((market_id IS NULL AND series IS NULL)
+		       OR series = $1
+		       OR market_id = $2)`,
+		series, marketID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("mm_config_repo.GetActiveLayers: %w", err)
+	}
+	for _, c := range rows {
+		switch c.Scope() {
+		case domain.MMScopeGlobal:
+			global = c
+		case domain.MMScopeSeries:
+			seriesCfg = c
+		case domain.MMScopeMarket:
+			market = c
+		}
+	}
+	return global, seriesCfg, market, nil
+}
+
+// ListActive returns every active override, broadest scope first.
+func (r *MMConfigRepository) ListActive(ctx context.Context) ([]*domain.MMConfig, error) {
+	var rows []*domain.MMConfig
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT * FROM mm_config
+		WHERE is_active
+		ORDER BY (market_id IS NOT NULL), (series IS NOT NULL), created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("mm_config_repo.ListActive: %w", err)
+	}
+	return rows, nil
+}
+
+// GetActiveForUpdate locks and returns the active row for a scope.  Returns
+// domain.ErrMMConfigNotFound when the scope has no active override.
+func (r *MMConfigRepository) GetActiveForUpdate(ctx context.Context, tx *sqlx.Tx, marketID *uuid.UUID, series *string) (*domain.MMConfig, error) {
+	var c domain.MMConfig
+	err := tx.GetContext(ctx, &c,
+		`SELECT * FROM mm_config WHERE is_active AND `+scopeFilter+` FOR UPDATE`,
+		marketID, series)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrMMConfigNotFound
+		}
+		return nil, fmt.Errorf("mm_config_repo.GetActiveForUpdate: %w", err)
+	}
+	return &c, nil
+}
+
+// Deactivate retires a row so a new one can take its place.
+func (r *MMConfigRepository) Deactivate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE mm_config SET is_active = FALSE, deactivated_at = now() WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("mm_config_repo.Deactivate: %w", err)
+	}
+	return nil
+}
+
+// Create inserts a new active override row.
+func (r *MMConfigRepository) Create(ctx context.Context, tx *sqlx.Tx, c *domain.MMConfig) error {
+	query := `
+		INSERT INTO mm_config
+			(id, market_id, series, threshold_ratio, max_exposure_try, min_bet_try,
+			 seed_ratio, balance_ratio, target_odds, strategy, is_active, created_by, created_at)
+		VALUES
+			(:id, :market_id, :series, :threshold_ratio, :max_exposure_try, :min_bet_try,
+			 :seed_ratio, :balance_ratio, :target_odds, :strategy, :is_active, :created_by, :created_at)`
+	if _, err := tx.NamedExecContext(ctx, query, c); err != nil {
+		return fmt.Errorf("mm_config_repo.Create: %w", err)
+	}
+	return nil
+}
+
+// History returns all rows (active and retired) for a scope, newest first.
+func (r *MMConfigRepository) History(ctx context.Context, marketID *uuid.UUID, series *string, limit, offset int) ([]*domain.MMConfig, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total,
+		`SELECT COUNT(*) FROM mm_config WHERE `+scopeFilter, marketID, series); err != nil {
+		return nil, 0, fmt.Errorf("mm_config_repo.History count: %w", err)
+	}
+	var rows []*domain.MMConfig
+	err := r.db.SelectContext(ctx, &rows,
+		`SELECT * FROM mm_config WHERE `+scopeFilter+` ORDER BY created_at DESC LIMIT $3 OFFSET $4`,
+		marketID, series, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("mm_config_repo.History: %w", err)
+	}
+	return rows, total, nil
+}
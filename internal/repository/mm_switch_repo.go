@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// MMSwitchRepository handles the mm_switches table.
+type MMSwitchRepository struct {
+	db *sqlx.DB
+}
+
+// NewMMSwitchRepository creates a new MMSwitchRepository.
+func NewMMSwitchRepository(db *sqlx.DB) *MMSwitchRepository {
+	return &MMSwitchRepository{db: db}
+}
+
+// List returns every switch row, global first.
+func (r *MMSwitchRepository) List(ctx context.Context) ([]*domain.MMSwitch, error) {
+	var rows []*domain.MMSwitch
+	err := r.db.SelectContext(ctx, &rows,
+		`SELECT * FROM mm_switches ORDER BY (market_id IS NOT NULL), changed_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("mm_switch_repo.List: %w", err)
+	}
+	return rows, nil
+}
+
+// Get returns the switch for a scope, or nil when no row exists (enabled).
+func (r *MMSwitchRepository) Get(ctx context.Context, tx *sqlx.Tx, marketID *uuid.UUID) (*domain.MMSwitch, error) {
+	var s domain.MMSwitch
+	err := tx.GetContext(ctx, &s,
+		`SELECT * FROM mm_switches WHERE market_id IS NOT DISTINCT FROM $1 FOR UPDATE`, marketID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("mm_switch_repo.Get: %w", err)
+	}
+	return &s, nil
+}
+
+// Upsert writes the switch for s's scope inside a transaction.
+func (r *MMSwitchRepository) Upsert(ctx context.Context, tx *sqlx.Tx, s *domain.MMSwitch) error {
+	err := tx.GetContext(ctx, &s.ID, `
+		INSERT INTO mm_switches (id, market_id, enabled, reason, changed_by, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT ((COALESCE(market_id::text, ''))) DO UPDATE
+		SET enabled    = EXCLUDED.enabled,
+		    reason     = EXCLUDED.reason,
+		    changed_by = EXCLUDED.changed_by,
+		    changed_at = EXCLUDED.changed_at
+		RETURNING id`,
+		s.ID, s.MarketID, s.Enabled, s.Reason, s.ChangedBy, s.ChangedAt)
+	if err != nil {
+		return fmt.Errorf("mm_switch_repo.Upsert: %w", err)
+	}
+	return nil
+}
+
+// IsEnabled reports whether the MM may act on marketID, reading both the
+// global and the market switch inside tx.  Used as the authoritative check
+// at injection time, independent of any in-process cache.
+func (r *MMSwitchRepository) IsEnabled(ctx context.Context, tx *sqlx.Tx, marketID uuid.UUID) (bool, error) {
+	var disabled bool
+	err := tx.GetContext(ctx, &disabled, `
+		SELECT EXISTS (
+			SELECT 1 FROM mm_switches
+			WHERE NOT enabled AND (market_id IS NULL OR market_id = $1)
+		)`, marketID)
+	if err != nil {
+		return false, fmt.Errorf("mm_switch_repo.IsEnabled: %w", err)
+	}
+	return !disabled, nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// PayoutBatchRepository handles the payout_batches table and the batch
+// membership of withdraw_requests.
+type PayoutBatchRepository struct {
+	db *sqlx.DB
+}
+
+// NewPayoutBatchRepository creates a new PayoutBatchRepository.
+func NewPayoutBatchRepository(db *sqlx.DB) *PayoutBatchRepository {
+	return &PayoutBatchRepository{db: db}
+}
+
+// Create inserts an empty batch inside tx; ClaimApproved fills it.
+func (r *PayoutBatchRepository) Create(ctx context.Context, tx *sqlx.Tx, b *domain.PayoutBatch) error {
+	query := `
+		INSERT INTO payout_batches (id, status, item_count, total_amount, created_by, created_at)
+		VALUES (:id, :status, :item_count, :total_amount, :created_by, :created_at)`
+	if _, err := tx.NamedExecContext(ctx, query, b); err != nil {
+		return fmt.Errorf("payout_batch_repo.Create: %w", err)
+	}
+	return nil
+}
+
+// ClaimApproved moves approved withdrawals into batch batchID and marks them
+// processing, oldest first.  ids restricts the claim to those requests; nil
+// claims every approved request.  Rows locked by a concurrent transaction
+// are skipped, so two batches never share a withdrawal, and so are requests
+// of users whose AML case holds withdrawals.
+func (r *PayoutBatchRepository) ClaimApproved(ctx context.Context, tx *sqlx.Tx, batchID uuid.UUID, ids []uuid.UUID) ([]*domain.WithdrawRequest, error) {
+	var filter pq.StringArray
+	if ids != nil {
+		filter = make(pq.StringArray, len(ids))
+		for i, id := range ids {
+			filter[i] = id.String()
+		}
+	}
+	reqs := []*domain.WithdrawRequest{}
+	err := tx.SelectContext(ctx, &reqs, `
+		WITH picked AS (
+			SELECT w.id FROM withdraw_requests w
+			WHERE w.status = 'approved'
+			  AND NOT w.aml_hold
+			  AND NOT `+heldByAMLCase+`
+			  AND ($2::uuid[] IS NULL OR w.id = ANY($2::uuid[]))
+			ORDER BY w.requested_at
+			FOR UPDATE OF w SKIP LOCKED
+		)
+		UPDATE withdraw_requests w
+		SET status = 'processing', payout_provider = $3, batch_id = $1, processed_at = now()
+		FROM picked
+		WHERE w.id = picked.id
+		RETURNING w.*`,
+		batchID, filter, domain.PayoutBatchProvider)
+	if err != nil {
+		return nil, fmt.Errorf("payout_batch_repo.ClaimApproved: %w", err)
+	}
+	return reqs, nil
+}
+
+// SetTotals records the number and sum of the withdrawals in a batch.
+func (r *PayoutBatchRepository) SetTotals(ctx context.Context, tx *sqlx.Tx, b *domain.PayoutBatch) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE payout_batches SET item_count = $1, total_amount = $2 WHERE id = $3`,
+		b.ItemCount, b.TotalAmount, b.ID)
+	if err != nil {
+		return fmt.Errorf("payout_batch_repo.SetTotals: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a batch by its ID.
+func (r *PayoutBatchRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.PayoutBatch, error) {
+	var b domain.PayoutBatch
+	err := r.db.GetContext(ctx, &b, `SELECT * FROM payout_batches WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrPayoutBatchNotFound
+		}
+		return nil, fmt.Errorf("payout_batch_repo.GetByID: %w", err)
+	}
+	return &b, nil
+}
+
+// List returns batches, newest first.  An empty status matches all.
+func (r *PayoutBatchRepository) List(ctx context.Context, status string, limit, offset int) ([]*domain.PayoutBatch, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total,
+		`SELECT COUNT(*) FROM payout_batches WHERE ($1 = '' OR status = $1)`, status); err != nil {
+		return nil, 0, fmt.Errorf("payout_batch_repo.List count: %w", err)
+	}
+	batches := []*domain.PayoutBatch{}
+	err := r.db.SelectContext(ctx, &batches, `
+		SELECT * FROM payout_batches
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`,
+		status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("payout_batch_repo.List: %w", err)
+	}
+	return batches, total, nil
+}
+
+// Items returns the withdrawals in a batch with their owners' usernames and
+// payees, oldest request first.
+func (r *PayoutBatchRepository) Items(ctx context.Context, batchID uuid.UUID) ([]*domain.PayoutBatchItem, error) {
+	items := []*domain.PayoutBatchItem{}
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT w.*, u.username,
+		       COALESCE(ba.holder_name, u.full_name) AS holder_name,
+		       COALESCE(ba.iban, w.iban)             AS payee_iban
+		FROM withdraw_requests w
+		JOIN users u              ON u.id = w.user_id
+		LEFT JOIN bank_accounts ba ON ba.id = w.bank_account_id
+		WHERE w.batch_id = $1
+		ORDER BY w.requested_at`,
+		batchID)
+	if err != nil {
+		return nil, fmt.Errorf("payout_batch_repo.Items: %w", err)
+	}
+	return items, nil
+}
+
+// MarkExported records the first export of an open batch.  Exporting again
+// leaves the batch unchanged.
+func (r *PayoutBatchRepository) MarkExported(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE payout_batches
+		SET status = 'exported', exported_at = now()
+		WHERE id = $1 AND status = 'open'`,
+		id)
+	if err != nil {
+		return fmt.Errorf("payout_batch_repo.MarkExported: %w", err)
+	}
+	return nil
+}
+
+// RefreshProgress recounts a batch's completed and failed withdrawals and
+// settles it once none is still processing.
+func (r *PayoutBatchRepository) RefreshProgress(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.PayoutBatch, error) {
+	var b domain.PayoutBatch
+	err := tx.GetContext(ctx, &b, `
+		WITH counts AS (
+			SELECT COUNT(*) FILTER (WHERE status = 'completed')  AS completed,
+			       COUNT(*) FILTER (WHERE status = 'failed')     AS failed,
+			       COUNT(*) FILTER (WHERE status = 'processing') AS processing
+			FROM withdraw_requests
+			WHERE batch_id = $1
+		)
+		UPDATE payout_batches b
+		SET completed  = counts.completed,
+		    failed     = counts.failed,
+		    status     = CASE WHEN counts.processing = 0 THEN 'settled' ELSE b.status END,
+		    settled_at = CASE WHEN counts.processing = 0 THEN COALESCE(b.settled_at, now()) ELSE NULL END
+		FROM counts
+		WHERE b.id = $1
+		RETURNING b.*`,
+		id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrPayoutBatchNotFound
+		}
+		return nil, fmt.Errorf("payout_batch_repo.RefreshProgress: %w", err)
+	}
+	return &b, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+// PriceSourceRepository handles the price_sources table.
+type PriceSourceRepository struct {
+	db *sqlx.DB
+}
+
+// NewPriceSourceRepository creates a new PriceSourceRepository.
+func NewPriceSourceRepository(db *sqlx.DB) *PriceSourceRepository {
+	return &PriceSourceRepository{db: db}
+}
+
+// Seed inserts the given sources unless a row with the same name already
+// exists, so edits made from the back-office survive restarts.
+func (r *PriceSourceRepository) Seed(ctx context.Context, sources []*domain.PriceSourceConfig) error {
+	for _, s := range sources {
+		_, err := r.db.ExecContext(ctx, `
+			INSERT INTO price_sources (name, enabled, weight, symbol)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (name) DO NOTHING`,
+			s.Name, s.Enabled, s.Weight, s.Symbol)
+		if err != nil {
+			return fmt.Errorf("price_source_repo.Seed %s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// List returns all configured price sources ordered by name.
+func (r *PriceSourceRepository) List(ctx context.Context) ([]*domain.PriceSourceConfig, error) {
+	var sources []*domain.PriceSourceConfig
+	if err := r.db.SelectContext(ctx, &sources, `SELECT * FROM price_sources ORDER BY name`); err != nil {
+		return nil, fmt.Errorf("price_source_repo.List: %w", err)
+	}
+	return sources, nil
+}
+
+// ListForUpdate returns all price sources and row-locks them, so that two
+// concurrent edits cannot both pass the "at least one active source" check.
+func (r *PriceSourceRepository) ListForUpdate(ctx context.Context, tx *sqlx.Tx) ([]*domain.PriceSourceConfig, error) {
+	var sources []*domain.PriceSourceConfig
+	if err := tx.SelectContext(ctx, &sources, `SELECT * FROM price_sources ORDER BY name FOR UPDATE`); err != nil {
+		return nil, fmt.Errorf("price_source_repo.ListForUpdate: %w", err)
+	}
+	return sources, nil
+}
+
+// Update persists a source's enabled/weight/symbol fields inside a transaction.
+func (r *PriceSourceRepository) Update(ctx context.Context, tx *sqlx.Tx, s *domain.PriceSourceConfig) error {
+	err := tx.GetContext(ctx, &s.UpdatedAt, `
+		UPDATE price_sources
+		SET enabled    = $1,
+		    weight     = $2,
+		    symbol     = $3,
+		    updated_by = $4,
+		    updated_at = now()
+		WHERE name = $5
+		RETURNING updated_at`,
+		s.Enabled, s.Weight, s.Symbol, s.UpdatedBy, s.Name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrPriceSourceNotFound
+		}
+		return fmt.Errorf("price_source_repo.Update: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// PromotionRepository handles the promotions, promotion_redemptions and
+// free_bets tables.
+type PromotionRepository struct {
+	db *sqlx.DB
+}
+
+// NewPromotionRepository creates a new PromotionRepository.
+func NewPromotionRepository(db *sqlx.DB) *PromotionRepository {
+	return &PromotionRepository{db: db}
+}
+
+// ── Campaigns ────────────────────────────────────────────────────────────────
+
+// Create inserts a promotion inside tx.  Returns domain.ErrPromoCodeTaken
+// when another campaign uses the code.
+func (r *PromotionRepository) Create(ctx context.Context, tx *sqlx.Tx, p *domain.Promotion) error {
+	query := `
+		INSERT INTO promotions
+			(id, name, code, reward_type, reward_amount, free_bet_days, starts_at, ends_at,
+			 max_redemptions, per_user_limit, new_users_days, requires_deposit, is_active,
+			 created_by, created_at, updated_at)
+		VALUES
+			(:id, :name, :code, :reward_type, :reward_amount, :free_bet_days, :starts_at, :ends_at,
+			 :max_redemptions, :per_user_limit, :new_users_days, :requires_deposit, :is_active,
+			 :created_by, :created_at, :updated_at)`
+	if _, err := tx.NamedExecContext(ctx, query, p); err != nil {
+		if isPgUniqueViolation(err, "idx_promotions_code") {
+			return domain.ErrPromoCodeTaken
+		}
+		return fmt.Errorf("promotion_repo.Create: %w", err)
+	}
+	return nil
+}
+
+// Update writes the editable fields of p inside tx.  The code, reward and
+// redemption count never change after creation.
+func (r *PromotionRepository) Update(ctx context.Context, tx *sqlx.Tx, p *domain.Promotion) error {
+	query := `
+		UPDATE promotions
+		SET name = :name, starts_at = :starts_at, ends_at = :ends_at,
+		    max_redemptions = :max_redemptions, per_user_limit = :per_user_limit,
+		    new_users_days = :new_users_days, requires_deposit = :requires_deposit,
+		    is_active = :is_active, updated_at = now()
+		WHERE id = :id`
+	if _, err := tx.NamedExecContext(ctx, query, p); err != nil {
+		return fmt.Errorf("promotion_repo.Update: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a promotion by its ID.
+func (r *PromotionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Promotion, error) {
+	var p domain.Promotion
+	err := r.db.GetContext(ctx, &p, `SELECT * FROM promotions WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrPromotionNotFound
+		}
+		return nil, fmt.Errorf("promotion_repo.GetByID: %w", err)
+	}
+	return &p, nil
+}
+
+// GetForUpdate locks and returns a promotion inside tx.
+func (r *PromotionRepository) GetForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.Promotion, error) {
+	var p domain.Promotion
+	err := tx.GetContext(ctx, &p, `SELECT * FROM promotions WHERE id = $1 FOR UPDATE`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrPromotionNotFound
+		}
+		return nil, fmt.Errorf("promotion_repo.GetForUpdate: %w", err)
+	}
+	return &p, nil
+}
+
+// GetByCodeForUpdate locks and returns the promotion with a normalised code.
+// The lock serialises redemptions so the caps cannot be overrun.
+func (r *PromotionRepository) GetByCodeForUpdate(ctx context.Context, tx *sqlx.Tx, code string) (*domain.Promotion, error) {
+	var p domain.Promotion
+	err := tx.GetContext(ctx, &p, `SELECT * FROM promotions WHERE code = $1 FOR UPDATE`, code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrPromotionNotFound
+		}
+		return nil, fmt.Errorf("promotion_repo.GetByCodeForUpdate: %w", err)
+	}
+	return &p, nil
+}
+
+// List returns promotions, newest first.  status is "", "active" or
+// "inactive" (switched off by the is_active flag).
+func (r *PromotionRepository) List(ctx context.Context, status string, limit, offset int) ([]*domain.Promotion, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `
+		SELECT COUNT(*) FROM promotions
+		WHERE ($1 = '' OR is_active = ($1 = 'active'))`,
+		status); err != nil {
+		return nil, 0, fmt.Errorf("promotion_repo.List count: %w", err)
+	}
+	promos := []*domain.Promotion{}
+	err := r.db.SelectContext(ctx, &promos, `
+		SELECT * FROM promotions
+		WHERE ($1 = '' OR is_active = ($1 = 'active'))
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`,
+		status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("promotion_repo.List: %w", err)
+	}
+	return promos, total, nil
+}
+
+// ── Redemptions ──────────────────────────────────────────────────────────────
+
+// CountUserRedemptions returns how often a user has redeemed a promotion.
+func (r *PromotionRepository) CountUserRedemptions(ctx context.Context, tx *sqlx.Tx, promotionID, userID uuid.UUID) (int, error) {
+	var n int
+	err := tx.GetContext(ctx, &n, `
+		SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id = $1 AND user_id = $2`,
+		promotionID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("promotion_repo.CountUserRedemptions: %w", err)
+	}
+	return n, nil
+}
+
+// CreateRedemption records a redemption and counts it against the
+// promotion's global cap inside tx.
+func (r *PromotionRepository) CreateRedemption(ctx context.Context, tx *sqlx.Tx, red *domain.PromotionRedemption) error {
+	query := `
+		INSERT INTO promotion_redemptions
+			(id, promotion_id, user_id, reward_type, amount, bonus_grant_id, free_bet_id, created_at)
+		VALUES
+			(:id, :promotion_id, :user_id, :reward_type, :amount, :bonus_grant_id, :free_bet_id, :created_at)`
+	if _, err := tx.NamedExecContext(ctx, query, red); err != nil {
+		return fmt.Errorf("promotion_repo.CreateRedemption: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE promotions SET redeemed_count = redeemed_count + 1 WHERE id = $1`,
+		red.PromotionID); err != nil {
+		return fmt.Errorf("promotion_repo.CreateRedemption: count: %w", err)
+	}
+	return nil
+}
+
+// Redemptions returns a promotion's redemptions with usernames, newest first.
+func (r *PromotionRepository) Redemptions(ctx context.Context, promotionID uuid.UUID, limit, offset int) ([]*domain.PromotionRedemptionItem, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total,
+		`SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id = $1`, promotionID); err != nil {
+		return nil, 0, fmt.Errorf("promotion_repo.Redemptions count: %w", err)
+	}
+	items := []*domain.PromotionRedemptionItem{}
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT r.*, u.username
+		FROM promotion_redemptions r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.promotion_id = $1
+		ORDER BY r.created_at DESC
+		LIMIT $2 OFFSET $3`,
+		promotionID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("promotion_repo.Redemptions: %w", err)
+	}
+	return items, total, nil
+}
+
+// Report summarises a promotion's redemptions and what became of the
+// rewards.
+func (r *PromotionRepository) Report(ctx context.Context, promotionID uuid.UUID) (*domain.PromotionReport, error) {
+	var rep domain.PromotionReport
+	err := r.db.GetContext(ctx, &rep, `
+		SELECT
+			COUNT(*)                                               AS redemptions,
+			COUNT(DISTINCT r.user_id)                              AS unique_users,
+			COALESCE(SUM(r.amount), 0)                             AS amount_awarded,
+			COUNT(*) FILTER (WHERE f.status = 'used')              AS free_bets_used,
+			COUNT(*) FILTER (WHERE f.status = 'expired')           AS free_bets_expired,
+			COALESCE(SUM(b.payout - b.amount) FILTER (WHERE b.status = 'won'), 0) AS free_bet_winnings,
+			COUNT(*) FILTER (WHERE g.status = 'converted')         AS bonuses_converted
+		FROM promotion_redemptions r
+		LEFT JOIN free_bets f    ON f.id = r.free_bet_id
+		LEFT JOIN bets b         ON b.id = f.bet_id
+		LEFT JOIN bonus_grants g ON g.id = r.bonus_grant_id
+		WHERE r.promotion_id = $1`,
+		promotionID)
+	if err != nil {
+		return nil, fmt.Errorf("promotion_repo.Report: %w", err)
+	}
+	return &rep, nil
+}
+
+// ── Free bets ────────────────────────────────────────────────────────────────
+
+// CreateFreeBet inserts a free-bet token inside tx.
+func (r *PromotionRepository) CreateFreeBet(ctx context.Context, tx *sqlx.Tx, fb *domain.FreeBet) error {
+	query := `
+		INSERT INTO free_bets (id, user_id, promotion_id, amount, status, created_at, expires_at)
+		VALUES (:id, :user_id, :promotion_id, :amount, :status, :created_at, :expires_at)`
+	if _, err := tx.NamedExecContext(ctx, query, fb); err != nil {
+		return fmt.Errorf("promotion_repo.CreateFreeBet: %w", err)
+	}
+	return nil
+}
+
+// UseFreeBet marks the user's available, unexpired token id as used by
+// betID inside tx and returns it.  Returns domain.ErrFreeBetNotFound
+// otherwise.
+func (r *PromotionRepository) UseFreeBet(ctx context.Context, tx *sqlx.Tx, id, userID, betID uuid.UUID) (*domain.FreeBet, error) {
+	var fb domain.FreeBet
+	err := tx.GetContext(ctx, &fb, `
+		UPDATE free_bets
+		SET status = 'used', bet_id = $3, used_at = now()
+		WHERE id = $1 AND user_id = $2 AND status = 'available' AND expires_at > now()
+		RETURNING *`,
+		id, userID, betID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrFreeBetNotFound
+		}
+		return nil, fmt.Errorf("promotion_repo.UseFreeBet: %w", err)
+	}
+	return &fb, nil
+}
+
+// RestoreFreeBet makes a used token available again, for bets refunded by
+// a market cancellation.  Tokens past their expiry are expired by the next
+// ExpireFreeBets run.
+func (r *PromotionRepository) RestoreFreeBet(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE free_bets SET status = 'available', bet_id = NULL, used_at = NULL
+		WHERE id = $1 AND status = 'used'`, id)
+	if err != nil {
+		return fmt.Errorf("promotion_repo.RestoreFreeBet: %w", err)
+	}
+	return nil
+}
+
+// ListFreeBets returns a user's free bets, newest first.
+func (r *PromotionRepository) ListFreeBets(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.FreeBet, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total,
+		`SELECT COUNT(*) FROM free_bets WHERE user_id = $1`, userID); err != nil {
+		return nil, 0, fmt.Errorf("promotion_repo.ListFreeBets count: %w", err)
+	}
+	bets := []*domain.FreeBet{}
+	err := r.db.SelectContext(ctx, &bets, `
+		SELECT * FROM free_bets WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`,
+		userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("promotion_repo.ListFreeBets: %w", err)
+	}
+	return bets, total, nil
+}
+
+// ExpireFreeBets marks available tokens past their expiry as expired and
+// returns how many there were.
+func (r *PromotionRepository) ExpireFreeBets(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE free_bets SET status = 'expired'
+		WHERE status = 'available' AND expires_at <= now()`)
+	if err != nil {
+		return 0, fmt.Errorf("promotion_repo.ExpireFreeBets: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ReconciliationRepository handles wallet drift queries, wallet freezes and
+// the reconciliation_runs table.
+type ReconciliationRepository struct {
+	db *sqlx.DB
+}
+
+// NewReconciliationRepository creates a new ReconciliationRepository.
+func NewReconciliationRepository(db *sqlx.DB) *ReconciliationRepository {
+	return &ReconciliationRepository{db: db}
+}
+
+// walletJournal maps every wallet to its ledger account and the sum of the
+// account's journal lines.  The opening entry of migration 010 carries
+// pre-ledger balances, so the sum is the full history.
+//
+// Journal lines are written by the same ledger.Post call that moves the
+// balance, so the wallet is also replayed from its own wallet_transactions
+// in posting order (seq): the signed amounts must add up to the balance and
+// each row must start from the balance the previous one left.  Balances set
+// before the ledger (pre-ledger history, the platform wallet's seed) have
+// no complete history, so the replay starts from the wallet's opening line
+// and covers only rows posted through the ledger, which carry an entry_id.
+const walletJournal = `
+	WITH journal AS (
+		SELECT account, SUM(amount) AS total
+		FROM ledger_lines
+		WHERE account LIKE 'user:%' OR account = 'house:mm'
+		GROUP BY account
+	),
+	accounts AS (
+		SELECT w.*,
+		       CASE WHEN w.wallet_type = 'platform_mm' THEN 'house:mm'
+		            ELSE 'user:' || w.user_id END AS account
+		FROM wallets w
+	),
+	openings AS (
+		SELECT l.account, SUM(l.amount) AS amount
+		FROM ledger_lines l
+		JOIN ledger_entries e ON e.id = l.entry_id
+		WHERE e.kind = 'opening' AND (l.account LIKE 'user:%' OR l.account = 'house:mm')
+		GROUP BY l.account
+	),
+	chain AS (
+		SELECT wt.wallet_id, wt.id, wt.seq, wt.balance_before, wt.balance_after,
+		       CASE WHEN wt.balance_after >= wt.balance_before THEN wt.amount ELSE -wt.amount END AS signed,
+		       LAG(wt.balance_after, 1, COALESCE(o.amount, 0)) OVER (PARTITION BY wt.wallet_id ORDER BY wt.seq) AS prev_after
+		FROM wallet_transactions wt
+		JOIN accounts a       ON a.id = wt.wallet_id
+		LEFT JOIN openings o  ON o.account = a.account
+		WHERE wt.entry_id IS NOT NULL
+	),
+	history AS (
+		SELECT wallet_id, SUM(signed) AS total FROM chain GROUP BY wallet_id
+	),
+	first_break AS (
+		SELECT DISTINCT ON (wallet_id) wallet_id, id, seq
+		FROM chain
+		WHERE balance_before <> prev_after OR balance_after - balance_before <> signed
+		ORDER BY wallet_id, seq
+	)
+	SELECT a.id                                                      AS wallet_id,
+	       a.user_id,
+	       a.account,
+	       a.balance,
+	       COALESCE(j.total, 0)                                      AS expected,
+	       a.balance - COALESCE(j.total, 0)                          AS drift,
+	       COALESCE(o.amount, 0) + COALESCE(h.total, 0)              AS history,
+	       a.balance - COALESCE(o.amount, 0) - COALESCE(h.total, 0)  AS history_drift,
+	       b.id                                                      AS broken_tx_id,
+	       b.seq                                                     AS broken_seq,
+	       a.frozen
+	FROM accounts a
+	LEFT JOIN journal j     ON j.account = a.account
+	LEFT JOIN openings o    ON o.account = a.account
+	LEFT JOIN history h     ON h.wallet_id = a.id
+	LEFT JOIN first_break b ON b.wallet_id = a.id`
+
+// GetWalletDrifts recomputes every wallet from its ledger history and from
+// its transaction history, and returns the number of wallets checked and
+// those that fail either check.
+func (r *ReconciliationRepository) GetWalletDrifts(ctx context.Context) (int, []domain.WalletDrift, error) {
+	var checked int
+	if err := r.db.GetContext(ctx, &checked, `SELECT COUNT(*) FROM wallets`); err != nil {
+		return 0, nil, fmt.Errorf("reconciliation_repo.GetWalletDrifts count: %w", err)
+	}
+	drifts := []domain.WalletDrift{}
+	err := r.db.SelectContext(ctx, &drifts, `
+		SELECT * FROM (`+walletJournal+`) d
+		WHERE d.drift <> 0 OR d.history_drift <> 0 OR d.broken_tx_id IS NOT NULL
+		ORDER BY abs(d.drift) + abs(d.history_drift) DESC`)
+	if err != nil {
+		return 0, nil, fmt.Errorf("reconciliation_repo.GetWalletDrifts: %w", err)
+	}
+	return checked, drifts, nil
+}
+
+// GetWallet returns a wallet by its ID (user or platform).
+func (r *ReconciliationRepository) GetWallet(ctx context.Context, walletID uuid.UUID) (*domain.Wallet, error) {
+	var w domain.Wallet
+	err := r.db.GetContext(ctx, &w, `SELECT * FROM wallets WHERE id = $1`, walletID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrWalletNotFound
+		}
+		return nil, fmt.Errorf("reconciliation_repo.GetWallet: %w", err)
+	}
+	return &w, nil
+}
+
+// SetFrozen freezes or unfreezes a wallet inside tx.  Returns false when the
+// wallet was already in the requested state.
+func (r *ReconciliationRepository) SetFrozen(ctx context.Context, tx *sqlx.Tx, walletID uuid.UUID, frozen bool, reason string) (bool, error) {
+	res, err := tx.ExecContext(ctx, `
+		UPDATE wallets
+		SET frozen        = $1,
+		    frozen_reason = CASE WHEN $1 THEN $2 ELSE '' END,
+		    frozen_at     = CASE WHEN $1 THEN now() END,
+		    updated_at    = now()
+		WHERE id = $3 AND frozen <> $1`,
+		frozen, reason, walletID)
+	if err != nil {
+		return false, fmt.Errorf("reconciliation_repo.SetFrozen: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// CreateRun stores a finished reconciliation run inside tx.
+func (r *ReconciliationRepository) CreateRun(ctx context.Context, tx *sqlx.Tx, run *domain.ReconciliationRun) error {
+	query := `
+		INSERT INTO reconciliation_runs
+			(id, source, triggered_by, started_at, finished_at, wallets_checked,
+			 mismatched, total_drift, abs_drift, frozen, drifts)
+		VALUES
+			(:id, :source, :triggered_by, :started_at, :finished_at, :wallets_checked,
+			 :mismatched, :total_drift, :abs_drift, :frozen, :drifts)`
+	if _, err := tx.NamedExecContext(ctx, query, run); err != nil {
+		return fmt.Errorf("reconciliation_repo.CreateRun: %w", err)
+	}
+	return nil
+}
+
+// ListRuns returns reconciliation runs, newest first.
+func (r *ReconciliationRepository) ListRuns(ctx context.Context, limit, offset int) ([]*domain.ReconciliationRun, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM reconciliation_runs`); err != nil {
+		return nil, 0, fmt.Errorf("reconciliation_repo.ListRuns count: %w", err)
+	}
+	runs := []*domain.ReconciliationRun{}
+	err := r.db.SelectContext(ctx, &runs, `
+		SELECT * FROM reconciliation_runs
+		ORDER BY started_at DESC
+		LIMIT $1 OFFSET $2`,
+		limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("reconciliation_repo.ListRuns: %w", err)
+	}
+	return runs, total, nil
+}
+
+// GetRun returns one reconciliation run.
+func (r *ReconciliationRepository) GetRun(ctx context.Context, id uuid.UUID) (*domain.ReconciliationRun, error) {
+	var run domain.ReconciliationRun
+	err := r.db.GetContext(ctx, &run, `SELECT * FROM reconciliation_runs WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrReconciliationRunNotFound
+		}
+		return nil, fmt.Errorf("reconciliation_repo.GetRun: %w", err)
+	}
+	return &run, nil
+}
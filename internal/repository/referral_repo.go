@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ReferralRepository handles the referrals and referral_commissions tables.
+type ReferralRepository struct {
+	db *sqlx.DB
+}
+
+// NewReferralRepository creates a new ReferralRepository.
+func NewReferralRepository(db *sqlx.DB) *ReferralRepository {
+	return &ReferralRepository{db: db}
+}
+
+// Create inserts a referral inside tx.
+func (r *ReferralRepository) Create(ctx context.Context, tx *sqlx.Tx, ref *domain.Referral) error {
+	query := `
+		INSERT INTO referrals (id, referrer_id, referred_id, status, flag_reason, signup_ip, created_at)
+		VALUES (:id, :referrer_id, :referred_id, :status, :flag_reason, :signup_ip, :created_at)`
+	if _, err := tx.NamedExecContext(ctx, query, ref); err != nil {
+		return fmt.Errorf("referral_repo.Create: %w", err)
+	}
+	return nil
+}
+
+// IPSeen reports whether ip is the signup IP of the referrer or of a user
+// they already referred.
+func (r *ReferralRepository) IPSeen(ctx context.Context, tx *sqlx.Tx, referrerID uuid.UUID, ip string) (bool, error) {
+	var seen bool
+	err := tx.GetContext(ctx, &seen, `
+		SELECT EXISTS (SELECT 1 FROM users     WHERE id = $1          AND signup_ip = $2)
+		    OR EXISTS (SELECT 1 FROM referrals WHERE referrer_id = $1 AND signup_ip = $2)`,
+		referrerID, ip)
+	if err != nil {
+		return false, fmt.Errorf("referral_repo.IPSeen: %w", err)
+	}
+	return seen, nil
+}
+
+// GetForUpdate locks and returns a referral inside tx.
+func (r *ReferralRepository) GetForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.Referral, error) {
+	var ref domain.Referral
+	err := tx.GetContext(ctx, &ref, `SELECT * FROM referrals WHERE id = $1 FOR UPDATE`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrReferralNotFound
+		}
+		return nil, fmt.Errorf("referral_repo.GetForUpdate: %w", err)
+	}
+	return &ref, nil
+}
+
+// Review records a risk decision on a referral inside tx.
+func (r *ReferralRepository) Review(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, status domain.ReferralStatus, adminID uuid.UUID, note string) (*domain.Referral, error) {
+	var ref domain.Referral
+	err := tx.GetContext(ctx, &ref, `
+		UPDATE referrals
+		SET status = $2, reviewed_by = $3, reviewed_at = now(), review_note = NULLIF($4, '')
+		WHERE id = $1
+		RETURNING *`,
+		id, string(status), adminID, note)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrReferralNotFound
+		}
+		return nil, fmt.Errorf("referral_repo.Review: %w", err)
+	}
+	return &ref, nil
+}
+
+// List returns referrals with both usernames, newest first, optionally
+// filtered by status and referrer.
+func (r *ReferralRepository) List(ctx context.Context, status string, referrerID *uuid.UUID, limit, offset int) ([]*domain.ReferralItem, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `
+		SELECT COUNT(*) FROM referrals
+		WHERE ($1 = '' OR status = $1)
+		  AND ($2::uuid IS NULL OR referrer_id = $2)`,
+		status, referrerID); err != nil {
+		return nil, 0, fmt.Errorf("referral_repo.List count: %w", err)
+	}
+	var items []*domain.ReferralItem
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT rf.*, ur.username AS referrer_username, ud.username AS referred_username
+		FROM referrals rf
+		JOIN users ur ON ur.id = rf.referrer_id
+		JOIN users ud ON ud.id = rf.referred_id
+		WHERE ($1 = '' OR rf.status = $1)
+		  AND ($2::uuid IS NULL OR rf.referrer_id = $2)
+		ORDER BY rf.created_at DESC
+		LIMIT $3 OFFSET $4`,
+		status, referrerID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("referral_repo.List: %w", err)
+	}
+	return items, total, nil
+}
+
+// Stats counts a referrer's referrals by status and sums what they earned.
+func (r *ReferralRepository) Stats(ctx context.Context, referrerID uuid.UUID) (*domain.ReferralStats, error) {
+	var st domain.ReferralStats
+	err := r.db.GetContext(ctx, &st, `
+		SELECT
+			COUNT(*)                                  AS referred,
+			COUNT(*) FILTER (WHERE status = 'active')  AS active,
+			COUNT(*) FILTER (WHERE status = 'flagged') AS pending,
+			COALESCE((SELECT SUM(amount) FROM referral_commissions WHERE referrer_id = $1), 0) AS earned
+		FROM referrals
+		WHERE referrer_id = $1`,
+		referrerID)
+	if err != nil {
+		return nil, fmt.Errorf("referral_repo.Stats: %w", err)
+	}
+	return &st, nil
+}
+
+// Report aggregates referrals and commission paid per referrer, highest
+// earners first.
+func (r *ReferralRepository) Report(ctx context.Context, limit, offset int) ([]*domain.ReferrerReport, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(DISTINCT referrer_id) FROM referrals`); err != nil {
+		return nil, 0, fmt.Errorf("referral_repo.Report count: %w", err)
+	}
+	var rows []*domain.ReferrerReport
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT
+			rf.referrer_id,
+			u.username,
+			COUNT(*)                                     AS referred,
+			COUNT(*) FILTER (WHERE rf.status = 'active')  AS active,
+			COUNT(*) FILTER (WHERE rf.status = 'flagged') AS flagged,
+			COUNT(*) FILTER (WHERE rf.status = 'blocked') AS blocked,
+			COALESCE((SELECT SUM(c.amount) FROM referral_commissions c
+			          WHERE c.referrer_id = rf.referrer_id), 0) AS commission_paid
+		FROM referrals rf
+		JOIN users u ON u.id = rf.referrer_id
+		GROUP BY rf.referrer_id, u.username
+		ORDER BY commission_paid DESC, referred DESC
+		LIMIT $1 OFFSET $2`,
+		limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("referral_repo.Report: %w", err)
+	}
+	return rows, total, nil
+}
+
+// ── Commissions ──────────────────────────────────────────────────────────────
+
+// Accruals returns, for every active referral without a commission row for
+// the day [from, to), the house commission attributed to the referred
+// user's bets in markets resolved that day.  A market's commission is
+// attributed in proportion to the real-money stake of each won or lost
+// bet; bonus stakes and free bets earn nothing.  Only ReferralID,
+// ReferrerID, PeriodStart and CommissionBase are set.
+func (r *ReferralRepository) Accruals(ctx context.Context, from, to time.Time) ([]*domain.ReferralCommission, error) {
+	var rows []*domain.ReferralCommission
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT rf.id AS referral_id, rf.referrer_id, $3::date AS period_start,
+		       SUM(h.commission_earned * (b.amount - b.bonus_amount) / (m.pool_up + m.pool_down)) AS commission_base
+		FROM referrals rf
+		JOIN bets b           ON b.user_id = rf.referred_id
+		JOIN markets m        ON m.id = b.market_id
+		JOIN house_treasury h ON h.market_id = m.id
+		WHERE rf.status = 'active'
+		  AND b.status IN ('won', 'lost')
+		  AND b.free_bet_id IS NULL
+		  AND m.pool_up + m.pool_down > 0
+		  AND m.resolved_at >= $1 AND m.resolved_at < $2
+		  AND NOT EXISTS (
+		      SELECT 1 FROM referral_commissions c
+		      WHERE c.referral_id = rf.id AND c.period_start = $3::date)
+		GROUP BY rf.id, rf.referrer_id
+		HAVING SUM(h.commission_earned * (b.amount - b.bonus_amount)) > 0`,
+		from, to, from.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("referral_repo.Accruals: %w", err)
+	}
+	return rows, nil
+}
+
+// CreateCommission inserts a commission row inside tx.  Returns false when
+// the referral was already paid for that day.
+func (r *ReferralRepository) CreateCommission(ctx context.Context, tx *sqlx.Tx, c *domain.ReferralCommission) (bool, error) {
+	res, err := tx.NamedExecContext(ctx, `
+		INSERT INTO referral_commissions
+			(id, referral_id, referrer_id, period_start, commission_base, share_rate, amount, created_at)
+		VALUES
+			(:id, :referral_id, :referrer_id, :period_start, :commission_base, :share_rate, :amount, :created_at)
+		ON CONFLICT (referral_id, period_start) DO NOTHING`, c)
+	if err != nil {
+		return false, fmt.Errorf("referral_repo.CreateCommission: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// Commissions returns a referrer's commission rows, newest day first.
+func (r *ReferralRepository) Commissions(ctx context.Context, referrerID uuid.UUID, limit, offset int) ([]*domain.ReferralCommission, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total,
+		`SELECT COUNT(*) FROM referral_commissions WHERE referrer_id = $1`, referrerID); err != nil {
+		return nil, 0, fmt.Errorf("referral_repo.Commissions count: %w", err)
+	}
+	var rows []*domain.ReferralCommission
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT * FROM referral_commissions
+		WHERE referrer_id = $1
+		ORDER BY period_start DESC, created_at DESC
+		LIMIT $2 OFFSET $3`,
+		referrerID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("referral_repo.Commissions: %w", err)
+	}
+	return rows, total, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// RefreshTokenRepository handles the refresh_tokens table.
+type RefreshTokenRepository struct {
+	db *sqlx.DB
+}
+
+// NewRefreshTokenRepository creates a new RefreshTokenRepository.
+func NewRefreshTokenRepository(db *sqlx.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+// Create inserts an issued token, inside a transaction or not.
+func (r *RefreshTokenRepository) Create(ctx context.Context, e sqlx.ExecerContext, t *domain.RefreshToken) error {
+	_, err := e.ExecContext(ctx, `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		t.ID, t.UserID, t.FamilyID, t.TokenHash, t.ExpiresAt, t.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("refresh_token_repo.Create: %w", err)
+	}
+	return nil
+}
+
+// GetByHash returns the token with the given hash.  Returns
+// domain.ErrTokenInvalid when there is none.
+func (r *RefreshTokenRepository) GetByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	var t domain.RefreshToken
+	err := r.db.GetContext(ctx, &t, `SELECT * FROM refresh_tokens WHERE token_hash = $1`, hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTokenInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("refresh_token_repo.GetByHash: %w", err)
+	}
+	return &t, nil
+}
+
+// GetByHashForUpdate locks and returns the token with the given hash
+// inside tx, so that concurrent refreshes with one token are serialised.
+// Returns domain.ErrTokenInvalid when there is none.
+func (r *RefreshTokenRepository) GetByHashForUpdate(ctx context.Context, tx *sqlx.Tx, hash string) (*domain.RefreshToken, error) {
+	var t domain.RefreshToken
+	err := tx.GetContext(ctx, &t, `SELECT * FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE`, hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTokenInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("refresh_token_repo.GetByHashForUpdate: %w", err)
+	}
+	return &t, nil
+}
+
+// MarkRotated revokes the token inside tx, recording its successor.
+func (r *RefreshTokenRepository) MarkRotated(ctx context.Context, tx *sqlx.Tx, id, replacedBy uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE refresh_tokens SET revoked = TRUE, revoked_at = now(), replaced_by = $2
+		WHERE id = $1`,
+		id, replacedBy)
+	if err != nil {
+		return fmt.Errorf("refresh_token_repo.MarkRotated: %w", err)
+	}
+	return nil
+}
+
+// RevokeFamily revokes every live token of a login and returns how many
+// there were.
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, e sqlx.ExecerContext, familyID uuid.UUID) (int64, error) {
+	res, err := e.ExecContext(ctx, `
+		UPDATE refresh_tokens SET revoked = TRUE, revoked_at = now()
+		WHERE family_id = $1 AND NOT revoked`,
+		familyID)
+	if err != nil {
+		return 0, fmt.Errorf("refresh_token_repo.RevokeFamily: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// RevokeUser revokes every live token of the user and returns how many
+// there were.
+func (r *RefreshTokenRepository) RevokeUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE refresh_tokens SET revoked = TRUE, revoked_at = now()
+		WHERE user_id = $1 AND NOT revoked`,
+		userID)
+	if err != nil {
+		return 0, fmt.Errorf("refresh_token_repo.RevokeUser: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// DeleteExpired deletes tokens that expired before cutoff and returns how
+// many.  Expired tokens are refused anyway; callers pass a cutoff in the
+// past so they are kept a while to recognise reuse of a stolen one.
+func (r *RefreshTokenRepository) DeleteExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM refresh_tokens WHERE expires_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("refresh_token_repo.DeleteExpired: %w", err)
+	}
+	return res.RowsAffected()
+}
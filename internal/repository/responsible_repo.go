@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// ResponsibleRepository handles the gambling_limits and self_exclusions
+// tables and the usage sums the limits are checked against.
+type ResponsibleRepository struct {
+	db *sqlx.DB
+}
+
+// NewResponsibleRepository creates a new ResponsibleRepository.
+func NewResponsibleRepository(db *sqlx.DB) *ResponsibleRepository {
+	return &ResponsibleRepository{db: db}
+}
+
+// ── Limits ───────────────────────────────────────────────────────────────────
+
+// Limits returns all of a user's limits.
+func (r *ResponsibleRepository) Limits(ctx context.Context, userID uuid.UUID) ([]*domain.GamblingLimit, error) {
+	var limits []*domain.GamblingLimit
+	err := r.db.SelectContext(ctx, &limits, `
+		SELECT * FROM gambling_limits
+		WHERE user_id = $1
+		ORDER BY kind, CASE period WHEN 'daily' THEN 1 WHEN 'weekly' THEN 2 ELSE 3 END`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("responsible_repo.Limits: %w", err)
+	}
+	return limits, nil
+}
+
+// GetLimitForUpdate locks and returns one limit inside tx, or nil when the
+// user has not set it.
+func (r *ResponsibleRepository) GetLimitForUpdate(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, kind domain.LimitKind, period domain.LimitPeriod) (*domain.GamblingLimit, error) {
+	var l domain.GamblingLimit
+	err := tx.GetContext(ctx, &l, `
+		SELECT * FROM gambling_limits
+		WHERE user_id = $1 AND kind = $2 AND period = $3
+		FOR UPDATE`,
+		userID, string(kind), string(period))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("responsible_repo.GetLimitForUpdate: %w", err)
+	}
+	return &l, nil
+}
+
+// SaveLimit inserts or replaces a limit inside tx.
+func (r *ResponsibleRepository) SaveLimit(ctx context.Context, tx *sqlx.Tx, l *domain.GamblingLimit) error {
+	query := `
+		INSERT INTO gambling_limits
+			(user_id, kind, period, amount, pending_amount, pending_from, created_at, updated_at)
+		VALUES
+			(:user_id, :kind, :period, :amount, :pending_amount, :pending_from, :created_at, :updated_at)
+		ON CONFLICT (user_id, kind, period) DO UPDATE
+		SET amount = EXCLUDED.amount, pending_amount = EXCLUDED.pending_amount,
+		    pending_from = EXCLUDED.pending_from, updated_at = EXCLUDED.updated_at`
+	if _, err := tx.NamedExecContext(ctx, query, l); err != nil {
+		return fmt.Errorf("responsible_repo.SaveLimit: %w", err)
+	}
+	return nil
+}
+
+// DeleteLimit removes a limit inside tx.
+func (r *ResponsibleRepository) DeleteLimit(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, kind domain.LimitKind, period domain.LimitPeriod) error {
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM gambling_limits WHERE user_id = $1 AND kind = $2 AND period = $3`,
+		userID, string(kind), string(period))
+	if err != nil {
+		return fmt.Errorf("responsible_repo.DeleteLimit: %w", err)
+	}
+	return nil
+}
+
+// ── Usage ────────────────────────────────────────────────────────────────────
+
+// LockDeposits takes a per-user advisory lock held until tx ends, so that
+// deposit intents of one user are checked against the limit one at a time.
+func (r *ResponsibleRepository) LockDeposits(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) error {
+	if _, err := tx.ExecContext(ctx,
+		`SELECT pg_advisory_xact_lock(hashtext('deposit_limit:' || $1::text))`, userID); err != nil {
+		return fmt.Errorf("responsible_repo.LockDeposits: %w", err)
+	}
+	return nil
+}
+
+// DepositTotal sums the deposits the user requested since since; pending
+// intents count so that a limit cannot be bypassed by opening several.
+// Expired intents do not, so a payment confirmed after expiry is checked
+// against the limits again before it is credited.
+func (r *ResponsibleRepository) DepositTotal(ctx context.Context, q sqlx.QueryerContext, userID uuid.UUID, since time.Time) (decimal.Decimal, error) {
+	var total decimal.Decimal
+	err := sqlx.GetContext(ctx, q, &total, `
+		SELECT COALESCE(SUM(amount), 0) FROM deposits
+		WHERE user_id = $1 AND created_at >= $2 AND status IN ('pending', 'completed')`,
+		userID, since)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("responsible_repo.DepositTotal: %w", err)
+	}
+	return total, nil
+}
+
+// PlayTotals sums the user's wallet journal since since: staked is the real
+// money put on bets, loss is staked minus what came back as payouts,
+// cash-outs and bet refunds (negative when the user is up).  Refunds of
+// failed withdrawals are not winnings and are left out.  Bonus money and
+// free bets never touch the wallet and are not counted.
+func (r *ResponsibleRepository) PlayTotals(ctx context.Context, q sqlx.QueryerContext, userID uuid.UUID, since time.Time) (staked, loss decimal.Decimal, err error) {
+	var row struct {
+		Staked decimal.Decimal `db:"staked"`
+		Loss   decimal.Decimal `db:"loss"`
+	}
+	err = sqlx.GetContext(ctx, q, &row, `
+		SELECT
+			COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'bet_lock'), 0) AS staked,
+			COALESCE(SUM(CASE WHEN t.type = 'bet_lock' THEN t.amount ELSE -t.amount END), 0) AS loss
+		FROM wallet_transactions t
+		JOIN wallets w ON w.id = t.wallet_id
+		WHERE w.user_id = $1 AND t.created_at >= $2
+		  AND t.type IN ('bet_lock', 'bet_unlock', 'payout', 'cashout', 'refund')
+		  AND (t.type <> 'refund' OR EXISTS (SELECT 1 FROM bets b WHERE b.id = t.ref_id))`,
+		userID, since)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("responsible_repo.PlayTotals: %w", err)
+	}
+	return row.Staked, row.Loss, nil
+}
+
+// ── Exclusions ───────────────────────────────────────────────────────────────
+
+// ActiveExclusion returns the strictest exclusion in force for the user at
+// now, or nil when there is none.
+func (r *ResponsibleRepository) ActiveExclusion(ctx context.Context, userID uuid.UUID, now time.Time) (*domain.SelfExclusion, error) {
+	var e domain.SelfExclusion
+	err := r.db.GetContext(ctx, &e, `
+		SELECT * FROM self_exclusions
+		WHERE user_id = $1 AND starts_at <= $2 AND (ends_at IS NULL OR ends_at > $2)
+		ORDER BY kind = 'self_exclusion' DESC, ends_at DESC NULLS FIRST
+		LIMIT 1`,
+		userID, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("responsible_repo.ActiveExclusion: %w", err)
+	}
+	return &e, nil
+}
+
+// CreateExclusion inserts an exclusion inside tx.
+func (r *ResponsibleRepository) CreateExclusion(ctx context.Context, tx *sqlx.Tx, e *domain.SelfExclusion) error {
+	query := `
+		INSERT INTO self_exclusions (id, user_id, kind, starts_at, ends_at, created_at)
+		VALUES (:id, :user_id, :kind, :starts_at, :ends_at, :created_at)`
+	if _, err := tx.NamedExecContext(ctx, query, e); err != nil {
+		return fmt.Errorf("responsible_repo.CreateExclusion: %w", err)
+	}
+	return nil
+}
+
+// Exclusions returns a user's exclusions, newest first.
+func (r *ResponsibleRepository) Exclusions(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.SelfExclusion, error) {
+	var rows []*domain.SelfExclusion
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT * FROM self_exclusions
+		WHERE user_id = $1
+		ORDER BY starts_at DESC
+		LIMIT $2 OFFSET $3`,
+		userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("responsible_repo.Exclusions: %w", err)
+	}
+	return rows, nil
+}
+
+// ListActiveExclusions returns exclusions in force at now with the user's
+// name, optionally of one kind, ending soonest first.
+func (r *ResponsibleRepository) ListActiveExclusions(ctx context.Context, kind string, now time.Time, limit, offset int) ([]*domain.SelfExclusionItem, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `
+		SELECT COUNT(*) FROM self_exclusions
+		WHERE ($1 = '' OR kind = $1)
+		  AND starts_at <= $2 AND (ends_at IS NULL OR ends_at > $2)`,
+		kind, now); err != nil {
+		return nil, 0, fmt.Errorf("responsible_repo.ListActiveExclusions count: %w", err)
+	}
+	var rows []*domain.SelfExclusionItem
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT e.*, u.username
+		FROM self_exclusions e
+		JOIN users u ON u.id = e.user_id
+		WHERE ($1 = '' OR e.kind = $1)
+		  AND e.starts_at <= $2 AND (e.ends_at IS NULL OR e.ends_at > $2)
+		ORDER BY e.ends_at ASC NULLS LAST
+		LIMIT $3 OFFSET $4`,
+		kind, now, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("responsible_repo.ListActiveExclusions: %w", err)
+	}
+	return rows, total, nil
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+// StatementRepository handles the account_statements table and the wallet
+// history statements are built from.
+type StatementRepository struct {
+	db *sqlx.DB
+}
+
+// NewStatementRepository creates a new StatementRepository.
+func NewStatementRepository(db *sqlx.DB) *StatementRepository {
+	return &StatementRepository{db: db}
+}
+
+// StatementUser is a user due a statement.
+type StatementUser struct {
+	UserID   uuid.UUID `db:"user_id"`
+	Username string    `db:"username"`
+}
+
+// ── Building ─────────────────────────────────────────────────────────────────
+
+// Candidates returns the active users who had wallet transactions in
+// [from, to) or a balance left at its start: those who get a statement for
+// month.  userIDs, when not nil, limits the search to those users;
+// missingOnly skips users who already have the month's statement.
+func (r *StatementRepository) Candidates(ctx context.Context, month string, from, to time.Time, userIDs []uuid.UUID, missingOnly bool) ([]StatementUser, error) {
+	var ids pq.StringArray
+	if userIDs != nil {
+		ids = make(pq.StringArray, len(userIDs))
+		for i, id := range userIDs {
+			ids[i] = id.String()
+		}
+	}
+	var users []StatementUser
+	err := r.db.SelectContext(ctx, &users, `
+		SELECT u.id AS user_id, u.username
+		FROM users u
+		JOIN wallets w ON w.user_id = u.id
+		WHERE u.is_active
+		  AND ($4::uuid[] IS NULL OR u.id = ANY($4::uuid[]))
+		  AND (NOT $5 OR NOT EXISTS (
+		        SELECT 1 FROM account_statements s WHERE s.user_id = u.id AND s.month = $1))
+		  AND (EXISTS (
+		        SELECT 1 FROM wallet_transactions wt
+		        WHERE wt.wallet_id = w.id AND wt.created_at >= $2 AND wt.created_at < $3)
+		       OR COALESCE((
+		        SELECT wt.balance_after FROM wallet_transactions wt
+		        WHERE wt.wallet_id = w.id AND wt.created_at < $2
+		        ORDER BY wt.seq DESC
+		        LIMIT 1), 0) <> 0)
+		ORDER BY u.username`,
+		month, from, to, ids, missingOnly)
+	if err != nil {
+		return nil, fmt.Errorf("statement_repo.Candidates: %w", err)
+	}
+	return users, nil
+}
+
+// BalanceAt returns the user's wallet balance just before at: the balance
+// after their last transaction before it, zero when there is none.  Rows
+// one DB transaction posted share created_at, so "last" goes by seq.
+func (r *StatementRepository) BalanceAt(ctx context.Context, userID uuid.UUID, at time.Time) (decimal.Decimal, error) {
+	var bal decimal.Decimal
+	err := r.db.GetContext(ctx, &bal, `
+		SELECT COALESCE((
+			SELECT wt.balance_after
+			FROM wallet_transactions wt
+			JOIN wallets w ON w.id = wt.wallet_id
+			WHERE w.user_id = $1 AND wt.created_at < $2
+			ORDER BY wt.seq DESC
+			LIMIT 1), 0)`,
+		userID, at)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("statement_repo.BalanceAt: %w", err)
+	}
+	return bal, nil
+}
+
+// Transactions returns the user's transactions in [from, to) in the order
+// they were posted.
+func (r *StatementRepository) Transactions(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*domain.Transaction, error) {
+	var txns []*domain.Transaction
+	err := r.db.SelectContext(ctx, &txns, `
+		SELECT wt.*
+		FROM wallet_transactions wt
+		JOIN wallets w ON w.id = wt.wallet_id
+		WHERE w.user_id = $1 AND wt.created_at >= $2 AND wt.created_at < $3
+		ORDER BY wt.seq`,
+		userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("statement_repo.Transactions: %w", err)
+	}
+	return txns, nil
+}
+
+// CashoutFees sums the fees on the user's bets cashed out in [from, to).
+func (r *StatementRepository) CashoutFees(ctx context.Context, userID uuid.UUID, from, to time.Time) (decimal.Decimal, error) {
+	var fees decimal.Decimal
+	err := r.db.GetContext(ctx, &fees, `
+		SELECT COALESCE(SUM(b.cashout_fee), 0)
+		FROM wallet_transactions wt
+		JOIN wallets w ON w.id = wt.wallet_id
+		JOIN bets b ON b.id = wt.ref_id
+		WHERE w.user_id = $1 AND wt.type = 'cashout'
+		  AND wt.created_at >= $2 AND wt.created_at < $3`,
+		userID, from, to)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("statement_repo.CashoutFees: %w", err)
+	}
+	return fees, nil
+}
+
+// ── Statements ───────────────────────────────────────────────────────────────
+
+// Upsert stores s, replacing the user's statement for the month if there
+// is one; s.ID is set to the stored row's ID.
+func (r *StatementRepository) Upsert(ctx context.Context, s *domain.AccountStatement) error {
+	err := r.db.GetContext(ctx, &s.ID, `
+		INSERT INTO account_statements
+			(id, user_id, month, period_start, period_end, opening_balance, closing_balance,
+			 deposits, withdrawals, stakes, payouts, bonuses, other, fees, tx_count,
+			 pdf_key, csv_key, generated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		ON CONFLICT (user_id, month) DO UPDATE SET
+			period_start = EXCLUDED.period_start, period_end = EXCLUDED.period_end,
+			opening_balance = EXCLUDED.opening_balance, closing_balance = EXCLUDED.closing_balance,
+			deposits = EXCLUDED.deposits, withdrawals = EXCLUDED.withdrawals,
+			stakes = EXCLUDED.stakes, payouts = EXCLUDED.payouts, bonuses = EXCLUDED.bonuses,
+			other = EXCLUDED.other, fees = EXCLUDED.fees, tx_count = EXCLUDED.tx_count,
+			pdf_key = EXCLUDED.pdf_key, csv_key = EXCLUDED.csv_key,
+			generated_at = EXCLUDED.generated_at
+		RETURNING id`,
+		s.ID, s.UserID, s.Month, s.PeriodStart, s.PeriodEnd, s.OpeningBalance, s.ClosingBalance,
+		s.Deposits, s.Withdrawals, s.Stakes, s.Payouts, s.Bonuses, s.Other, s.Fees, s.TxCount,
+		s.PDFKey, s.CSVKey, s.GeneratedAt)
+	if err != nil {
+		return fmt.Errorf("statement_repo.Upsert: %w", err)
+	}
+	return nil
+}
+
+// Get returns the user's statement for month.
+func (r *StatementRepository) Get(ctx context.Context, userID uuid.UUID, month string) (*domain.AccountStatement, error) {
+	var s domain.AccountStatement
+	err := r.db.GetContext(ctx, &s,
+		`SELECT * FROM account_statements WHERE user_id = $1 AND month = $2`, userID, month)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrStatementNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("statement_repo.Get: %w", err)
+	}
+	return &s, nil
+}
+
+// List returns a page of the user's statements, newest month first, and
+// the total number of them.
+func (r *StatementRepository) List(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.AccountStatement, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total,
+		`SELECT COUNT(*) FROM account_statements WHERE user_id = $1`, userID); err != nil {
+		return nil, 0, fmt.Errorf("statement_repo.List count: %w", err)
+	}
+	var items []*domain.AccountStatement
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT * FROM account_statements
+		WHERE user_id = $1
+		ORDER BY month DESC
+		LIMIT $2 OFFSET $3`,
+		userID, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("statement_repo.List select: %w", err)
+	}
+	return items, total, nil
+}
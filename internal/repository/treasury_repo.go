@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// TreasuryRepository maintains the single house_treasury row per market.
+type TreasuryRepository struct {
+	db *sqlx.DB
+}
+
+// NewTreasuryRepository creates a TreasuryRepository.
+func NewTreasuryRepository(db *sqlx.DB) *TreasuryRepository {
+	return &TreasuryRepository{db: db}
+}
+
+// add upserts the market's row, adding the given amounts to its totals.
+func (r *TreasuryRepository) add(ctx context.Context, tx *sqlx.Tx, marketID uuid.UUID, commission, mmPnL, cashoutFees, cashoutPnL decimal.Decimal) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO house_treasury (market_id, commission_earned, mm_pnl, cashout_fees_earned, cashout_pnl, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, now(), now())
+		ON CONFLICT (market_id) DO UPDATE
+		SET commission_earned   = house_treasury.commission_earned   + EXCLUDED.commission_earned,
+		    mm_pnl              = house_treasury.mm_pnl              + EXCLUDED.mm_pnl,
+		    cashout_fees_earned = house_treasury.cashout_fees_earned + EXCLUDED.cashout_fees_earned,
+		    cashout_pnl         = house_treasury.cashout_pnl         + EXCLUDED.cashout_pnl,
+		    updated_at          = now()`,
+		marketID, commission, mmPnL, cashoutFees, cashoutPnL)
+	return err
+}
+
+// RecordSettlement adds the commission retained and the MM PnL realized when
+// a market is resolved.
+func (r *TreasuryRepository) RecordSettlement(ctx context.Context, tx *sqlx.Tx, marketID uuid.UUID, commission, mmPnL decimal.Decimal) error {
+	if err := r.add(ctx, tx, marketID, commission, mmPnL, decimal.Zero, decimal.Zero); err != nil {
+		return fmt.Errorf("treasury_repo.RecordSettlement: %w", err)
+	}
+	return nil
+}
+
+// AddCashout adds an early exit's fee and the house's PnL on it (stake
+// minus gross exit value, negative when the exit pays more than the stake)
+// to the market's row.
+func (r *TreasuryRepository) AddCashout(ctx context.Context, tx *sqlx.Tx, marketID uuid.UUID, fee, pnl decimal.Decimal) error {
+	if err := r.add(ctx, tx, marketID, decimal.Zero, decimal.Zero, fee, pnl); err != nil {
+		return fmt.Errorf("treasury_repo.AddCashout: %w", err)
+	}
+	return nil
+}
+
+// GetByMarket returns the market's treasury row, or a zero entry when the
+// market has produced no house income yet.
+func (r *TreasuryRepository) GetByMarket(ctx context.Context, marketID uuid.UUID) (*domain.TreasuryEntry, error) {
+	var e domain.TreasuryEntry
+	err := r.db.GetContext(ctx, &e, `SELECT * FROM house_treasury WHERE market_id = $1`, marketID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &domain.TreasuryEntry{MarketID: marketID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("treasury_repo.GetByMarket: %w", err)
+	}
+	return &e, nil
+}
@@ -24,8 +24,10 @@ func NewUserRepository(db *sqlx.DB) *UserRepository {
 // Create inserts a new user row.
 func (r *UserRepository) Create(ctx context.Context, u *domain.User) error {
 	query := `
-		INSERT INTO users (id, email, username, password_hash, role, is_active, created_at, updated_at)
-		VALUES (:id, :email, :username, :password_hash, :role, :is_active, :created_at, :updated_at)`
+		INSERT INTO users
+			(id, email, username, full_name, password_hash, role, is_active, referral_code, signup_ip, created_at, updated_at)
+		VALUES
+			(:id, :email, :username, :full_name, :password_hash, :role, :is_active, :referral_code, :signup_ip, :created_at, :updated_at)`
 	if _, err := r.db.NamedExecContext(ctx, query, u); err != nil {
 		// Detect unique constraint violations and surface as domain errors
 		if isPgUniqueViolation(err, "users_email_key") {
@@ -78,6 +80,45 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*d
 	return &u, nil
 }
 
+// GetByReferralCode fetches the active user owning an invite code.
+// Returns domain.ErrReferralCodeNotFound when there is none.
+func (r *UserRepository) GetByReferralCode(ctx context.Context, code string) (*domain.User, error) {
+	var u domain.User
+	err := r.db.GetContext(ctx, &u,
+		`SELECT * FROM users WHERE referral_code = $1 AND is_active`, code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrReferralCodeNotFound
+		}
+		return nil, fmt.Errorf("user_repo.GetByReferralCode: %w", err)
+	}
+	return &u, nil
+}
+
+// EnsureReferralCode returns the user's invite code, generating one for
+// accounts created before codes existed.
+func (r *UserRepository) EnsureReferralCode(ctx context.Context, userID uuid.UUID) (string, error) {
+	for attempt := 0; attempt < 5; attempt++ {
+		var code string
+		err := r.db.GetContext(ctx, &code, `
+			UPDATE users SET referral_code = COALESCE(referral_code, $2)
+			WHERE id = $1
+			RETURNING referral_code`,
+			userID, domain.NewReferralCode())
+		if isPgUniqueViolation(err, "idx_users_referral_code") {
+			continue // another user drew the same code; draw again
+		}
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", domain.ErrUserNotFound
+			}
+			return "", fmt.Errorf("user_repo.EnsureReferralCode: %w", err)
+		}
+		return code, nil
+	}
+	return "", errors.New("user_repo.EnsureReferralCode: no free code after 5 attempts")
+}
+
 // List returns a paginated list of all users.
 // Returns (users, totalCount, error).
 func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*domain.User, int, error) {
@@ -94,18 +135,23 @@ func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*domain
 	return users, total, nil
 }
 
-// UpdateRole changes a user's role (back-office operation).
-func (r *UserRepository) UpdateRole(ctx context.Context, userID uuid.UUID, role domain.UserRole) error {
-	res, err := r.db.ExecContext(ctx,
-		`UPDATE users SET role = $1, updated_at = now() WHERE id = $2`,
+// UpdateRole changes a user's role inside tx (back-office operation) and
+// returns the role they had before.
+func (r *UserRepository) UpdateRole(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, role domain.UserRole) (domain.UserRole, error) {
+	var old domain.UserRole
+	err := tx.GetContext(ctx, &old, `
+		WITH old AS (SELECT id, role FROM users WHERE id = $2 FOR UPDATE)
+		UPDATE users u SET role = $1, updated_at = now()
+		FROM old WHERE u.id = old.id
+		RETURNING old.role`,
 		string(role), userID)
-	if err != nil {
-		return fmt.Errorf("user_repo.UpdateRole: %w", err)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", domain.ErrUserNotFound
 	}
-	if n, _ := res.RowsAffected(); n == 0 {
-		return domain.ErrUserNotFound
+	if err != nil {
+		return "", fmt.Errorf("user_repo.UpdateRole: %w", err)
 	}
-	return nil
+	return old, nil
 }
 
 // SetActive activates or deactivates a user account.
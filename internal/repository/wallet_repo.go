@@ -5,14 +5,17 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/evetabi/prediction/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/shopspring/decimal"
 )
 
 // WalletRepository handles all database operations for Wallets and Transactions.
+// Balances are changed only by posting ledger entries (internal/ledger).
 type WalletRepository struct {
 	db *sqlx.DB
 }
@@ -48,207 +51,286 @@ func (r *WalletRepository) GetPlatformWallet(ctx context.Context) (*domain.Walle
 	return &w, nil
 }
 
-// DeductBalance subtracts amount from a user's balance inside a transaction.
-// Uses FOR UPDATE to prevent races; returns ErrInsufficientBalance when the
-// available balance (balance - locked) would go negative.
-// `amount` must be a shopspring/decimal.Decimal (passed as interface{} for
-// sqlx compatibility with the PostgreSQL driver's decimal handling).
-func (r *WalletRepository) DeductBalance(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount decimal.Decimal) error {
-	// Lock the row and check available balance atomically
-	var available decimal.Decimal
-	err := tx.GetContext(ctx, &available,
-		`SELECT (balance - locked) FROM wallets WHERE user_id = $1 FOR UPDATE`,
-		userID)
+// LockPlatformWallet locks the platform MM wallet row inside tx and returns
+// it.  Holding the lock serialises MM injections across markets.
+func (r *WalletRepository) LockPlatformWallet(ctx context.Context, tx *sqlx.Tx) (*domain.Wallet, error) {
+	var w domain.Wallet
+	err := tx.GetContext(ctx, &w, `SELECT * FROM wallets WHERE wallet_type = 'platform_mm' FOR UPDATE`)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return domain.ErrWalletNotFound
+			return nil, domain.ErrWalletNotFound
 		}
-		return fmt.Errorf("wallet_repo.DeductBalance lock: %w", err)
+		return nil, fmt.Errorf("wallet_repo.LockPlatformWallet: %w", err)
 	}
-
-	if available.LessThan(amount) {
-		return domain.ErrInsufficientBalance
-	}
-
-	_, err = tx.ExecContext(ctx,
-		`UPDATE wallets SET balance = balance - $1, updated_at = now() WHERE user_id = $2`,
-		amount, userID)
-	if err != nil {
-		return fmt.Errorf("wallet_repo.DeductBalance update: %w", err)
-	}
-	return nil
+	return &w, nil
 }
 
-// AddBalance credits amount to a user's wallet inside a transaction.
-func (r *WalletRepository) AddBalance(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount decimal.Decimal) error {
-	_, err := tx.ExecContext(ctx,
-		`UPDATE wallets SET balance = balance + $1, updated_at = now() WHERE user_id = $2`,
+// LockBalance holds amount of the available balance for a pending
+// withdrawal inside tx.  Returns domain.ErrInsufficientBalance,
+// domain.ErrWalletFrozen or domain.ErrWalletNotFound when it cannot.
+func (r *WalletRepository) LockBalance(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount decimal.Decimal) error {
+	res, err := tx.ExecContext(ctx, `
+		UPDATE wallets SET locked = locked + $1, updated_at = now()
+		WHERE user_id = $2 AND NOT frozen AND balance - locked >= $1`,
 		amount, userID)
 	if err != nil {
-		return fmt.Errorf("wallet_repo.AddBalance: %w", err)
+		return fmt.Errorf("wallet_repo.LockBalance: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return nil
 	}
-	return nil
-}
 
-// LockBalance increments the locked field (funds reserved for an open bet).
-func (r *WalletRepository) LockBalance(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount decimal.Decimal) error {
-	_, err := tx.ExecContext(ctx,
-		`UPDATE wallets SET locked = locked + $1, updated_at = now() WHERE user_id = $2`,
-		amount, userID)
-	if err != nil {
+	var frozen bool
+	err = tx.GetContext(ctx, &frozen, `SELECT frozen FROM wallets WHERE user_id = $1`, userID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return domain.ErrWalletNotFound
+	case err != nil:
 		return fmt.Errorf("wallet_repo.LockBalance: %w", err)
+	case frozen:
+		return domain.ErrWalletFrozen
 	}
-	return nil
+	return domain.ErrInsufficientBalance
 }
 
-// UnlockBalance decrements the locked field (when a bet is settled or cancelled).
+// UnlockBalance releases a withdrawal hold inside tx.  Returns
+// domain.ErrInsufficientLocked when less than amount is locked.
 func (r *WalletRepository) UnlockBalance(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount decimal.Decimal) error {
-	_, err := tx.ExecContext(ctx,
-		`UPDATE wallets SET locked = GREATEST(locked - $1, 0), updated_at = now() WHERE user_id = $2`,
+	res, err := tx.ExecContext(ctx, `
+		UPDATE wallets SET locked = locked - $1, updated_at = now()
+		WHERE user_id = $2 AND locked >= $1`,
 		amount, userID)
 	if err != nil {
 		return fmt.Errorf("wallet_repo.UnlockBalance: %w", err)
 	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("wallet_repo.UnlockBalance: user %s: %w", userID, domain.ErrInsufficientLocked)
+	}
 	return nil
 }
 
-// LogTransaction inserts an audit record into wallet_transactions inside a transaction.
-func (r *WalletRepository) LogTransaction(ctx context.Context, tx *sqlx.Tx, txn *domain.Transaction) error {
-	query := `
-		INSERT INTO wallet_transactions
-			(id, wallet_id, type, amount, balance_before, balance_after, ref_id, description, created_at)
-		VALUES
-			(:id, :wallet_id, :type, :amount, :balance_before, :balance_after, :ref_id, :description, :created_at)`
-	if _, err := tx.NamedExecContext(ctx, query, txn); err != nil {
-		return fmt.Errorf("wallet_repo.LogTransaction: %w", err)
+// txFilterWhere restricts wallet_transactions wt, joined to wallets w, to
+// a user's history matching a TxFilter; bind it with txFilterArgs.
+const txFilterWhere = `
+		WHERE w.user_id = $1
+		  AND ($2::text[] IS NULL OR wt.type = ANY($2::text[]))
+		  AND ($3::timestamptz IS NULL OR wt.created_at >= $3)
+		  AND ($4::timestamptz IS NULL OR wt.created_at < $4)
+		  AND ($5::uuid IS NULL OR wt.ref_id = $5)`
+
+func txFilterArgs(userID uuid.UUID, f domain.TxFilter) []interface{} {
+	var types pq.StringArray
+	if f.Types != nil {
+		types = make(pq.StringArray, len(f.Types))
+		for i, t := range f.Types {
+			types[i] = string(t)
+		}
 	}
-	return nil
+	return []interface{}{userID, types, f.From, f.To, f.RefID}
 }
 
-// GetTransactions returns paginated transaction history for a user's wallet.
-func (r *WalletRepository) GetTransactions(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Transaction, error) {
+// ListTransactions returns a page of a user's transaction history matching
+// f, newest first in posting order.  With after set the page starts past that cursor;
+// callers paging by cursor pass offset 0.
+func (r *WalletRepository) ListTransactions(ctx context.Context, userID uuid.UUID, f domain.TxFilter, after *domain.TxCursor, limit, offset int) ([]*domain.Transaction, error) {
+	var afterSeq *int64
+	if after != nil {
+		afterSeq = &after.Seq
+	}
+	args := append(txFilterArgs(userID, f), afterSeq, limit, offset)
+
 	var txns []*domain.Transaction
 	err := r.db.SelectContext(ctx, &txns, `
 		SELECT wt.*
 		FROM wallet_transactions wt
-		JOIN wallets w ON w.id = wt.wallet_id
-		WHERE w.user_id = $1
-		ORDER BY wt.created_at DESC
-		LIMIT $2 OFFSET $3`,
-		userID, limit, offset)
+		JOIN wallets w ON w.id = wt.wallet_id`+txFilterWhere+`
+		  AND ($6::bigint IS NULL OR wt.seq < $6)
+		ORDER BY wt.seq DESC
+		LIMIT $7 OFFSET $8`,
+		args...)
 	if err != nil {
-		return nil, fmt.Errorf("wallet_repo.GetTransactions: %w", err)
+		return nil, fmt.Errorf("wallet_repo.ListTransactions: %w", err)
 	}
 	return txns, nil
 }
 
-// GetDailyWithdrawTotal sums approved/completed withdrawals for a user today.
-func (r *WalletRepository) GetDailyWithdrawTotal(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error) {
+// CountTransactions counts a user's transactions matching f.
+func (r *WalletRepository) CountTransactions(ctx context.Context, userID uuid.UUID, f domain.TxFilter) (int, error) {
+	var n int
+	err := r.db.GetContext(ctx, &n, `
+		SELECT COUNT(*)
+		FROM wallet_transactions wt
+		JOIN wallets w ON w.id = wt.wallet_id`+txFilterWhere,
+		txFilterArgs(userID, f)...)
+	if err != nil {
+		return 0, fmt.Errorf("wallet_repo.CountTransactions: %w", err)
+	}
+	return n, nil
+}
+
+// GetDailyWithdrawTotal sums the user's withdrawal requests made since
+// dayStart, the start of the business day, that are still live or paid out
+// (everything but rejected, cancelled and failed).
+// Run inside tx after LockBalance so concurrent requests are serialised on
+// the wallet row.
+func (r *WalletRepository) GetDailyWithdrawTotal(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, dayStart time.Time) (decimal.Decimal, error) {
 	var total decimal.Decimal
-	err := r.db.GetContext(ctx, &total, `
+	err := tx.GetContext(ctx, &total, `
 		SELECT COALESCE(SUM(amount), 0)
-		FROM wallet_transactions
-		WHERE wallet_id = (SELECT id FROM wallets WHERE user_id = $1)
-		  AND type = 'withdraw'
-		  AND created_at >= date_trunc('day', now())`,
-		userID)
+		FROM withdraw_requests
+		WHERE user_id = $1
+		  AND status NOT IN ('rejected', 'cancelled', 'failed')
+		  AND requested_at >= $2`,
+		userID, dayStart)
 	if err != nil {
 		return decimal.Zero, fmt.Errorf("wallet_repo.GetDailyWithdrawTotal: %w", err)
 	}
 	return total, nil
 }
 
-// CreateWithdrawRequest inserts a new withdrawal request.
-func (r *WalletRepository) CreateWithdrawRequest(ctx context.Context, req *domain.WithdrawRequest) error {
+// GetWithdrawTotal returns the user's total withdrawal requests of all time
+// that were not rejected, cancelled or failed.
+func (r *WalletRepository) GetWithdrawTotal(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) (decimal.Decimal, error) {
+	var total decimal.Decimal
+	err := tx.GetContext(ctx, &total, `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM withdraw_requests
+		WHERE user_id = $1
+		  AND status NOT IN ('rejected', 'cancelled', 'failed')`,
+		userID)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("wallet_repo.GetWithdrawTotal: %w", err)
+	}
+	return total, nil
+}
+
+// CreateWithdrawRequest inserts a new withdrawal request inside tx.
+func (r *WalletRepository) CreateWithdrawRequest(ctx context.Context, tx *sqlx.Tx, req *domain.WithdrawRequest) error {
 	query := `
 		INSERT INTO withdraw_requests
-			(id, user_id, amount, status, iban, note, requested_at)
+			(id, user_id, amount, status, iban, bank_account_id, note, aml_hold, requested_at)
 		VALUES
-			(:id, :user_id, :amount, :status, :iban, :note, :requested_at)`
-	if _, err := r.db.NamedExecContext(ctx, query, req); err != nil {
+			(:id, :user_id, :amount, :status, :iban, :bank_account_id, :note, :aml_hold, :requested_at)`
+	if _, err := tx.NamedExecContext(ctx, query, req); err != nil {
 		return fmt.Errorf("wallet_repo.CreateWithdrawRequest: %w", err)
 	}
 	return nil
 }
 
-// GetWithdrawRequests returns paginated withdraw requests filtered by status.
-// status="" means all statuses.
-func (r *WalletRepository) GetWithdrawRequests(ctx context.Context, status string, limit, offset int) ([]*domain.WithdrawRequest, error) {
+// GetWithdrawRequests returns paginated withdraw requests filtered by status
+// and the total number of them.  status="" means all statuses.
+func (r *WalletRepository) GetWithdrawRequests(ctx context.Context, status string, limit, offset int) ([]*domain.WithdrawRequest, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total,
+		`SELECT COUNT(*) FROM withdraw_requests WHERE ($1 = '' OR status = $1)`, status); err != nil {
+		return nil, 0, fmt.Errorf("wallet_repo.GetWithdrawRequests count: %w", err)
+	}
 	var reqs []*domain.WithdrawRequest
-	var err error
-	if status != "" {
-		err = r.db.SelectContext(ctx, &reqs, `
-			SELECT * FROM withdraw_requests
-			WHERE status = $1
-			ORDER BY requested_at DESC
-			LIMIT $2 OFFSET $3`,
-			status, limit, offset)
-	} else {
-		err = r.db.SelectContext(ctx, &reqs, `
-			SELECT * FROM withdraw_requests
-			ORDER BY requested_at DESC
-			LIMIT $1 OFFSET $2`,
-			limit, offset)
+	err := r.db.SelectContext(ctx, &reqs, `
+		SELECT * FROM withdraw_requests
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY requested_at DESC
+		LIMIT $2 OFFSET $3`,
+		status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("wallet_repo.GetWithdrawRequests: %w", err)
 	}
+	return reqs, total, nil
+}
+
+// GetWithdrawRequest returns a withdrawal request by ID.
+func (r *WalletRepository) GetWithdrawRequest(ctx context.Context, id uuid.UUID) (*domain.WithdrawRequest, error) {
+	var req domain.WithdrawRequest
+	err := r.db.GetContext(ctx, &req, `SELECT * FROM withdraw_requests WHERE id = $1`, id)
 	if err != nil {
-		return nil, fmt.Errorf("wallet_repo.GetWithdrawRequests: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrWithdrawalNotFound
+		}
+		return nil, fmt.Errorf("wallet_repo.GetWithdrawRequest: %w", err)
+	}
+	return &req, nil
+}
+
+// GetWithdrawRequestForUpdate locks and returns a withdrawal request.
+func (r *WalletRepository) GetWithdrawRequestForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.WithdrawRequest, error) {
+	var req domain.WithdrawRequest
+	err := tx.GetContext(ctx, &req, `SELECT * FROM withdraw_requests WHERE id = $1 FOR UPDATE`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrWithdrawalNotFound
+		}
+		return nil, fmt.Errorf("wallet_repo.GetWithdrawRequestForUpdate: %w", err)
 	}
-	return reqs, nil
+	return &req, nil
 }
 
-// UpdateWithdrawStatus changes the status of a withdrawal request (admin action).
-func (r *WalletRepository) UpdateWithdrawStatus(ctx context.Context, id uuid.UUID, status domain.WithdrawStatus, adminNote string, adminID uuid.UUID) error {
-	res, err := r.db.ExecContext(ctx, `
+// GetUserWithdrawRequests returns a user's withdrawal requests, newest first.
+func (r *WalletRepository) GetUserWithdrawRequests(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.WithdrawRequest, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total,
+		`SELECT COUNT(*) FROM withdraw_requests WHERE user_id = $1`, userID); err != nil {
+		return nil, 0, fmt.Errorf("wallet_repo.GetUserWithdrawRequests count: %w", err)
+	}
+	reqs := []*domain.WithdrawRequest{}
+	err := r.db.SelectContext(ctx, &reqs, `
+		SELECT * FROM withdraw_requests
+		WHERE user_id = $1
+		ORDER BY requested_at DESC
+		LIMIT $2 OFFSET $3`,
+		userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("wallet_repo.GetUserWithdrawRequests: %w", err)
+	}
+	return reqs, total, nil
+}
+
+// UpdateWithdrawStatus moves a withdrawal request to status inside tx.
+// adminID is uuid.Nil for transitions made by the user or the payout step;
+// a reviewer's note and ID are recorded only for approve and reject.
+func (r *WalletRepository) UpdateWithdrawStatus(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, status domain.WithdrawStatus, adminNote string, adminID uuid.UUID) error {
+	var reviewer *uuid.UUID
+	if adminID != uuid.Nil {
+		reviewer = &adminID
+	}
+	_, err := tx.ExecContext(ctx, `
 		UPDATE withdraw_requests
 		SET status      = $1,
-		    review_note = $2,
-		    reviewed_by = $3,
-		    reviewed_at = now()
+		    review_note = CASE WHEN $3::uuid IS NULL THEN review_note ELSE $2 END,
+		    reviewed_by = COALESCE($3, reviewed_by),
+		    reviewed_at = CASE WHEN $3::uuid IS NULL THEN reviewed_at ELSE now() END
 		WHERE id = $4`,
-		string(status), adminNote, adminID, id)
+		string(status), adminNote, reviewer, id)
 	if err != nil {
 		return fmt.Errorf("wallet_repo.UpdateWithdrawStatus: %w", err)
 	}
-	if n, _ := res.RowsAffected(); n == 0 {
-		return domain.ErrWalletNotFound
-	}
 	return nil
 }
 
-// ── Platform wallet helpers ──────────────────────────────────────────────────
-// The platform MM wallet has user_id = NULL; all operations use wallet_type.
-
-// DeductPlatformBalance subtracts amount from the platform MM wallet inside a
-// transaction.  Uses FOR UPDATE to prevent concurrent over-draws.
-func (r *WalletRepository) DeductPlatformBalance(ctx context.Context, tx *sqlx.Tx, amount decimal.Decimal) error {
-	var available decimal.Decimal
-	err := tx.GetContext(ctx, &available,
-		`SELECT balance FROM wallets WHERE wallet_type = 'platform_mm' FOR UPDATE`)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return domain.ErrWalletNotFound
-		}
-		return fmt.Errorf("wallet_repo.DeductPlatformBalance lock: %w", err)
-	}
-	if available.LessThan(amount) {
-		return domain.ErrInsufficientBalance
-	}
-	_, err = tx.ExecContext(ctx,
-		`UPDATE wallets SET balance = balance - $1, updated_at = now() WHERE wallet_type = 'platform_mm'`,
-		amount)
+// MarkWithdrawProcessing records that a withdrawal was handed to provider.
+func (r *WalletRepository) MarkWithdrawProcessing(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, provider string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE withdraw_requests
+		SET status = 'processing', payout_provider = $1, processed_at = now()
+		WHERE id = $2`,
+		provider, id)
 	if err != nil {
-		return fmt.Errorf("wallet_repo.DeductPlatformBalance update: %w", err)
+		return fmt.Errorf("wallet_repo.MarkWithdrawProcessing: %w", err)
 	}
 	return nil
 }
 
-// AddPlatformBalance credits amount to the platform MM wallet inside a transaction.
-func (r *WalletRepository) AddPlatformBalance(ctx context.Context, tx *sqlx.Tx, amount decimal.Decimal) error {
-	_, err := tx.ExecContext(ctx,
-		`UPDATE wallets SET balance = balance + $1, updated_at = now() WHERE wallet_type = 'platform_mm'`,
-		amount)
+// FinishWithdrawRequest records a payout's outcome (completed or failed).
+func (r *WalletRepository) FinishWithdrawRequest(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, status domain.WithdrawStatus, payoutRef, reason string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE withdraw_requests
+		SET status         = $1,
+		    payout_ref     = COALESCE(NULLIF($2, ''), payout_ref),
+		    failure_reason = $3,
+		    completed_at   = now()
+		WHERE id = $4`,
+		string(status), payoutRef, reason, id)
 	if err != nil {
-		return fmt.Errorf("wallet_repo.AddPlatformBalance: %w", err)
+		return fmt.Errorf("wallet_repo.FinishWithdrawRequest: %w", err)
 	}
 	return nil
 }
@@ -268,48 +350,28 @@ func (r *WalletRepository) GetMarketMMExposure(ctx context.Context, marketID uui
 	return total, nil
 }
 
-// ── Admin helpers ─────────────────────────────────────────────────────────────
-
-// AdminAdjustBalance applies a signed decimal adjustment to a user's balance
-// directly (positive = credit, negative = debit).  Used only by back-office.
-func (r *WalletRepository) AdminAdjustBalance(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) error {
-	_, err := r.db.ExecContext(ctx,
-		`UPDATE wallets SET balance = balance + $1, updated_at = now() WHERE user_id = $2`,
-		amount, userID)
-	if err != nil {
-		return fmt.Errorf("wallet_repo.AdminAdjustBalance: %w", err)
-	}
-	return nil
-}
-
-// LogTransactionDirect writes an audit record outside of a transaction (e.g.
-// admin adjustments that run without an explicit tx).
-func (r *WalletRepository) LogTransactionDirect(ctx context.Context, txn *domain.Transaction) error {
-	query := `
-		INSERT INTO wallet_transactions
-			(id, wallet_id, type, amount, balance_before, balance_after, ref_id, description, created_at)
-		VALUES
-			(:id, :wallet_id, :type, :amount, :balance_before, :balance_after, :ref_id, :description, :created_at)`
-	if _, err := r.db.NamedExecContext(ctx, query, txn); err != nil {
-		return fmt.Errorf("wallet_repo.LogTransactionDirect: %w", err)
-	}
-	return nil
-}
-
 // GetPlatformTransactions returns recent wallet_transactions for the platform
-// MM wallet, ordered by descending time.
-func (r *WalletRepository) GetPlatformTransactions(ctx context.Context, limit, offset int) ([]*domain.Transaction, error) {
+// MM wallet, newest first in posting order.
+func (r *WalletRepository) GetPlatformTransactions(ctx context.Context, limit, offset int) ([]*domain.Transaction, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `
+		SELECT COUNT(*)
+		FROM wallet_transactions wt
+		JOIN wallets w ON w.id = wt.wallet_id
+		WHERE w.wallet_type = 'platform_mm'`); err != nil {
+		return nil, 0, fmt.Errorf("wallet_repo.GetPlatformTransactions count: %w", err)
+	}
 	var txns []*domain.Transaction
 	err := r.db.SelectContext(ctx, &txns, `
 		SELECT wt.*
 		FROM wallet_transactions wt
 		JOIN wallets w ON w.id = wt.wallet_id
 		WHERE w.wallet_type = 'platform_mm'
-		ORDER BY wt.created_at DESC
+		ORDER BY wt.seq DESC
 		LIMIT $1 OFFSET $2`,
 		limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("wallet_repo.GetPlatformTransactions: %w", err)
+		return nil, 0, fmt.Errorf("wallet_repo.GetPlatformTransactions: %w", err)
 	}
-	return txns, nil
+	return txns, total, nil
 }
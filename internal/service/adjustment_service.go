@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/ledger"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// AdjustmentService runs manual balance adjustments under maker-checker
+// control.  Admin, finance, risk or ops staff request one; a finance or
+// admin user other than the requester approves it, which posts it to the
+// wallet, or rejects it.  Every step is audited in the transaction that makes it.
+type AdjustmentService struct {
+	db        *sqlx.DB
+	repo      *repository.AdjustmentRepository
+	userRepo  *repository.UserRepository
+	ledger    *ledger.Ledger
+	auditRepo *repository.AuditRepository
+}
+
+// NewAdjustmentService creates an AdjustmentService.
+func NewAdjustmentService(
+	db *sqlx.DB,
+	repo *repository.AdjustmentRepository,
+	userRepo *repository.UserRepository,
+	l *ledger.Ledger,
+	auditRepo *repository.AuditRepository,
+) *AdjustmentService {
+	return &AdjustmentService{
+		db:        db,
+		repo:      repo,
+		userRepo:  userRepo,
+		ledger:    l,
+		auditRepo: auditRepo,
+	}
+}
+
+// Request records a pending adjustment of the user's balance by amount
+// (negative to debit).  Nothing is posted until it is approved.  Returns
+// domain.ErrInvalidAdjustment, domain.ErrSelfApproval for the admin's own
+// balance or domain.ErrUserNotFound.
+func (s *AdjustmentService) Request(ctx context.Context, adminID, userID uuid.UUID, amount decimal.Decimal, reason domain.AdjustmentReason, note string) (*domain.BalanceAdjustment, error) {
+	a, err := domain.NewBalanceAdjustment(userID, adminID, amount, reason, note)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("adjustment_service.Request: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if txErr = s.repo.Create(ctx, tx, a); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.audit(ctx, tx, adminID, "adjustment.request", a, ""); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("adjustment_service.Request: commit: %w", txErr)
+	}
+	log.Printf("[adjustment] %s requested %s for user %s (%s)", adminID, amount, userID, reason)
+	return a, nil
+}
+
+// Approve posts a pending adjustment to the wallet as a ledger entry of
+// type adjustment, against house:adjustments, and marks it approved.
+// Returns domain.ErrAdjustmentNotFound, domain.ErrAdjustmentState,
+// domain.ErrSelfApproval when adminID requested it or either admin is the
+// adjusted user, or the ledger's error
+// when the wallet cannot take it (ErrInsufficientBalance, ErrWalletFrozen).
+func (s *AdjustmentService) Approve(ctx context.Context, id, adminID uuid.UUID, note string) (*domain.BalanceAdjustment, error) {
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("adjustment_service.Approve: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	before, txErr := s.repo.GetForUpdate(ctx, tx, id)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if before.Status != domain.AdjustmentPending {
+		txErr = domain.ErrAdjustmentState
+		return nil, txErr
+	}
+	if txErr = before.CheckApprover(adminID); txErr != nil {
+		return nil, txErr
+	}
+
+	desc := fmt.Sprintf("Balance adjustment (%s): %s", before.Reason, before.Note)
+	entry := ledger.NewEntry(domain.TxAdjustment, id, desc).
+		Transfer(ledger.HouseAdjustments, ledger.User(before.UserID), before.Amount)
+	entry.CreatedBy = &adminID
+	if txErr = s.ledger.Post(ctx, tx, entry); txErr != nil {
+		return nil, txErr
+	}
+	a, txErr := s.repo.Review(ctx, tx, id, domain.AdjustmentApproved, adminID, note, &entry.ID)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.audit(ctx, tx, adminID, "adjustment.approve", a, note); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("adjustment_service.Approve: commit: %w", txErr)
+	}
+	log.Printf("[adjustment] %s approved adjustment %s of %s for user %s", adminID, id, a.Amount, a.UserID)
+	return a, nil
+}
+
+// Reject marks a pending adjustment rejected without posting it.  The
+// requester may reject, i.e. withdraw, their own request.  Returns
+// domain.ErrAdjustmentNotFound or domain.ErrAdjustmentState.
+func (s *AdjustmentService) Reject(ctx context.Context, id, adminID uuid.UUID, note string) (*domain.BalanceAdjustment, error) {
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("adjustment_service.Reject: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	before, txErr := s.repo.GetForUpdate(ctx, tx, id)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if before.Status != domain.AdjustmentPending {
+		txErr = domain.ErrAdjustmentState
+		return nil, txErr
+	}
+	a, txErr := s.repo.Review(ctx, tx, id, domain.AdjustmentRejected, adminID, note, nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.audit(ctx, tx, adminID, "adjustment.reject", a, note); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("adjustment_service.Reject: commit: %w", txErr)
+	}
+	log.Printf("[adjustment] %s rejected adjustment %s", adminID, id)
+	return a, nil
+}
+
+// List returns adjustments, optionally filtered by status and user.
+func (s *AdjustmentService) List(ctx context.Context, statuses []domain.AdjustmentStatus, userID *uuid.UUID, limit, offset int) ([]*domain.BalanceAdjustmentItem, int, error) {
+	return s.repo.List(ctx, statuses, userID, limit, offset)
+}
+
+// Get returns an adjustment.  Returns domain.ErrAdjustmentNotFound.
+func (s *AdjustmentService) Get(ctx context.Context, id uuid.UUID) (*domain.BalanceAdjustment, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// audit logs action on a inside tx.
+func (s *AdjustmentService) audit(ctx context.Context, tx *sqlx.Tx, adminID uuid.UUID, action string, a *domain.BalanceAdjustment, note string) error {
+	details := map[string]any{
+		"user_id": a.UserID,
+		"amount":  a.Amount,
+		"reason":  a.Reason,
+	}
+	if note != "" {
+		details["note"] = note
+	}
+	entry, err := repository.NewAuditEntry(adminID, action, "balance_adjustment", a.ID.String(), details)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.Log(ctx, tx, entry)
+}
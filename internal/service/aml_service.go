@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/evetabi/prediction/internal/aml"
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// AMLService screens deposits, bets and withdrawal requests with the rules
+// in internal/aml.  Hits become alerts on the user's live case, opening one
+// when needed; a rule that calls for it puts the case on hold, which holds
+// the user's pending withdrawals until finance clears the case.  Every
+// change to a case is kept in its history and, for back-office actions, in
+// the audit log.
+type AMLService struct {
+	db        *sqlx.DB
+	repo      *repository.AMLRepository
+	auditRepo *repository.AuditRepository
+	cfg       *config.Config
+}
+
+// NewAMLService creates an AMLService.
+func NewAMLService(
+	db *sqlx.DB,
+	repo *repository.AMLRepository,
+	auditRepo *repository.AuditRepository,
+	cfg *config.Config,
+) *AMLService {
+	return &AMLService{
+		db:        db,
+		repo:      repo,
+		auditRepo: auditRepo,
+		cfg:       cfg,
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Screening
+// ──────────────────────────────────────────────────────────────────────────────
+
+// Screen runs the rules on ev inside tx and records any hits on the user's
+// case.  It reports whether the user's withdrawals are on hold afterwards,
+// so a withdrawal request screened before it is stored can be held too.
+// Withdrawal requests are screened this way; deposits and bets go through
+// HoldsWithdrawals reports whether the user's live case holds their
+// withdrawals.  It locks the case inside tx, so a hold being placed
+// concurrently is waited for.
+func (s *AMLService) HoldsWithdrawals(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) (bool, error) {
+	c, err := s.repo.LiveCaseForUpdate(ctx, tx, userID)
+	if err != nil {
+		return false, err
+	}
+	return c.HoldsWithdrawals(), nil
+}
+
+// ScreenAsync.
+func (s *AMLService) Screen(ctx context.Context, tx *sqlx.Tx, ev aml.Event) (bool, error) {
+	if !s.cfg.AML.Enabled {
+		return false, nil
+	}
+	p := s.params()
+	act, err := s.activity(ctx, tx, ev, p)
+	if err != nil {
+		return false, err
+	}
+	hits := aml.Evaluate(ev, act, p)
+
+	c, err := s.repo.LiveCaseForUpdate(ctx, tx, ev.UserID)
+	if err != nil {
+		return false, err
+	}
+	if len(hits) == 0 {
+		return c != nil && c.Hold, nil
+	}
+
+	now := time.Now().UTC()
+	if c == nil {
+		if c, err = s.openCase(ctx, tx, ev.UserID, hits, now); err != nil {
+			return false, err
+		}
+	}
+	wasHeld := c.Hold
+	for _, h := range hits {
+		added, err := s.repo.AddAlert(ctx, tx, &domain.AMLAlert{
+			ID:        uuid.New(),
+			CaseID:    c.ID,
+			UserID:    ev.UserID,
+			Rule:      h.Rule,
+			Severity:  h.Severity,
+			Subject:   ev.Subject,
+			SubjectID: ev.SubjectID,
+			Amount:    ev.Amount,
+			Reason:    h.Reason,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return false, err
+		}
+		if !added {
+			continue
+		}
+		if h.Severity.Rank() > c.Severity.Rank() {
+			c.Severity = h.Severity
+		}
+		if err = s.event(ctx, tx, c.ID, nil, "alert", h.Rule+": "+h.Reason, now); err != nil {
+			return false, err
+		}
+		if h.Hold && s.cfg.AML.AutoHold && !c.Hold {
+			c.Hold = true
+			if err = s.event(ctx, tx, c.ID, nil, "hold", "held by rule "+h.Rule, now); err != nil {
+				return false, err
+			}
+		}
+	}
+	c.UpdatedAt = now
+	if err = s.repo.UpdateCase(ctx, tx, c); err != nil {
+		return false, err
+	}
+	if c.Hold && !wasHeld {
+		if _, err = s.repo.SetWithdrawHold(ctx, tx, ev.UserID, true); err != nil {
+			return false, err
+		}
+	}
+	log.Printf("[aml] %d hit(s) on %s %s of user %s; case %s (%s, hold=%t)",
+		len(hits), ev.Subject, ev.SubjectID, ev.UserID, c.ID, c.Severity, c.Hold)
+	return c.Hold, nil
+}
+
+// ScreenAsync screens ev in its own transaction, for activity that should
+// neither wait for nor fail on screening.  Errors are logged.
+func (s *AMLService) ScreenAsync(ev aml.Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("[aml] screen %s %s: begin tx: %v", ev.Subject, ev.SubjectID, err)
+		return
+	}
+	if _, err = s.Screen(ctx, tx, ev); err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		log.Printf("[aml] screen %s %s: %v", ev.Subject, ev.SubjectID, err)
+	}
+}
+
+// params resolves the rule thresholds from config.  Structuring is measured
+// against the largest single deposit and the KYC withdrawal threshold.
+func (s *AMLService) params() aml.Params {
+	c := s.cfg.AML
+	return aml.Params{
+		QuickWithdrawWindow: c.QuickWithdrawWindow,
+		MinTurnoverRatio:    decimal.NewFromFloat(c.MinTurnoverRatio),
+		StructuringWindow:   c.StructuringWindow,
+		StructuringBand:     decimal.NewFromFloat(c.StructuringBand),
+		StructuringCount:    c.StructuringCount,
+		DepositLimit:        decimal.NewFromFloat(s.cfg.Payment.MaxDeposit),
+		WithdrawLimit:       decimal.NewFromFloat(s.cfg.KYC.WithdrawThreshold),
+		TurnoverWindow:      c.TurnoverWindow,
+		TurnoverMinAmount:   decimal.NewFromFloat(c.TurnoverMinAmount),
+		TurnoverRatio:       decimal.NewFromFloat(c.TurnoverRatio),
+		HedgeRatio:          decimal.NewFromFloat(c.HedgeRatio),
+		HedgeMinAmount:      decimal.NewFromFloat(c.HedgeMinAmount),
+	}
+}
+
+// activity loads what the rules need to screen ev.
+func (s *AMLService) activity(ctx context.Context, tx *sqlx.Tx, ev aml.Event, p aml.Params) (aml.Activity, error) {
+	var (
+		act aml.Activity
+		err error
+	)
+	act.Deposits, act.Withdrawals, act.Stakes, err = s.repo.Movements(ctx, tx, ev.UserID, ev.At.Add(-p.Lookback()))
+	if err != nil {
+		return act, err
+	}
+	switch ev.Subject {
+	case domain.AMLSubjectWithdrawal:
+		// Screened before the request is stored.
+		act.Withdrawals = append(act.Withdrawals, aml.Movement{Amount: ev.Amount, At: ev.At})
+		act.IBANUsers, err = s.repo.IBANUsers(ctx, tx, ev.UserID, ev.IBAN)
+	case domain.AMLSubjectBet:
+		act.MarketUp, act.MarketDown, err = s.repo.MarketStakes(ctx, tx, ev.UserID, ev.MarketID)
+	}
+	return act, err
+}
+
+// openCase opens a case for the user at the highest severity of hits, or
+// returns the live one a concurrent screening opened first.
+func (s *AMLService) openCase(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, hits []aml.Hit, now time.Time) (*domain.AMLCase, error) {
+	c := &domain.AMLCase{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Status:    domain.AMLCaseOpen,
+		OpenedAt:  now,
+		UpdatedAt: now,
+	}
+	for _, h := range hits {
+		if h.Severity.Rank() > c.Severity.Rank() {
+			c.Severity = h.Severity
+		}
+	}
+	created, err := s.repo.OpenCase(ctx, tx, c)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		live, err := s.repo.LiveCaseForUpdate(ctx, tx, userID)
+		if err == nil && live == nil {
+			err = fmt.Errorf("aml_service.openCase: live case of user %s closed concurrently", userID)
+		}
+		return live, err
+	}
+	return c, s.event(ctx, tx, c.ID, nil, "opened", "", now)
+}
+
+// event appends to a case's history.  actorID is nil for the rules engine.
+func (s *AMLService) event(ctx context.Context, tx *sqlx.Tx, caseID uuid.UUID, actorID *uuid.UUID, action, note string, now time.Time) error {
+	return s.repo.AddEvent(ctx, tx, &domain.AMLCaseEvent{
+		ID:        uuid.New(),
+		CaseID:    caseID,
+		ActorID:   actorID,
+		Action:    action,
+		Note:      note,
+		CreatedAt: now,
+	})
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Back office
+// ──────────────────────────────────────────────────────────────────────────────
+
+// List returns cases in any of statuses (all when nil), optionally of one
+// user.
+func (s *AMLService) List(ctx context.Context, statuses []domain.AMLCaseStatus, userID *uuid.UUID, limit, offset int) ([]*domain.AMLCaseItem, int, error) {
+	return s.repo.ListCases(ctx, statuses, userID, limit, offset)
+}
+
+// Get returns a case with its alerts, history and the user's pending
+// withdrawals.  Returns domain.ErrAMLCaseNotFound.
+func (s *AMLService) Get(ctx context.Context, id uuid.UUID) (*domain.AMLCaseDetail, error) {
+	c, err := s.repo.GetCase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	alerts, err := s.repo.Alerts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.repo.Events(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	withdrawals, err := s.repo.PendingWithdrawals(ctx, c.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AMLCaseDetail{Case: c, Alerts: alerts, Events: events, Withdrawals: withdrawals}, nil
+}
+
+// Assign gives a live case to assignee.
+func (s *AMLService) Assign(ctx context.Context, id, adminID, assignee uuid.UUID) (*domain.AMLCase, error) {
+	return s.update(ctx, id, adminID, "assign", assignee.String(), func(_ *sqlx.Tx, c *domain.AMLCase) error {
+		if c.Status.IsClosed() {
+			return domain.ErrAMLCaseState
+		}
+		c.AssignedTo = &assignee
+		return nil
+	})
+}
+
+// Note adds a note to a case's history; closed cases accept notes too.
+func (s *AMLService) Note(ctx context.Context, id, adminID uuid.UUID, note string) (*domain.AMLCase, error) {
+	return s.update(ctx, id, adminID, "note", note, func(*sqlx.Tx, *domain.AMLCase) error { return nil })
+}
+
+// Escalate passes an open case to the compliance officer.
+func (s *AMLService) Escalate(ctx context.Context, id, adminID uuid.UUID, note string) (*domain.AMLCase, error) {
+	return s.update(ctx, id, adminID, "escalate", note, func(_ *sqlx.Tx, c *domain.AMLCase) error {
+		if c.Status != domain.AMLCaseOpen {
+			return domain.ErrAMLCaseState
+		}
+		c.Status = domain.AMLCaseEscalated
+		return nil
+	})
+}
+
+// SetHold holds or releases the pending withdrawals of a live case's user.
+func (s *AMLService) SetHold(ctx context.Context, id, adminID uuid.UUID, hold bool, note string) (*domain.AMLCase, error) {
+	action := "release"
+	if hold {
+		action = "hold"
+	}
+	return s.update(ctx, id, adminID, action, note, func(tx *sqlx.Tx, c *domain.AMLCase) error {
+		if c.Status.IsClosed() || c.Hold == hold {
+			return domain.ErrAMLCaseState
+		}
+		c.Hold = hold
+		_, err := s.repo.SetWithdrawHold(ctx, tx, c.UserID, hold)
+		return err
+	})
+}
+
+// Close closes a live case.  Cleared releases the user's withdrawals;
+// reported keeps any hold in place so the held requests are rejected by
+// finance rather than paid.
+func (s *AMLService) Close(ctx context.Context, id, adminID uuid.UUID, report bool, note string) (*domain.AMLCase, error) {
+	action, status := "clear", domain.AMLCaseCleared
+	if report {
+		action, status = "report", domain.AMLCaseReported
+	}
+	return s.update(ctx, id, adminID, action, note, func(tx *sqlx.Tx, c *domain.AMLCase) error {
+		if c.Status.IsClosed() {
+			return domain.ErrAMLCaseState
+		}
+		now := time.Now().UTC()
+		c.Status = status
+		c.Resolution = note
+		c.ClosedBy = &adminID
+		c.ClosedAt = &now
+		if !report && c.Hold {
+			c.Hold = false
+			if _, err := s.repo.SetWithdrawHold(ctx, tx, c.UserID, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// update locks case id, lets apply change it and records the action in the
+// case history and the audit log.  Domain errors from apply are returned
+// as-is.
+func (s *AMLService) update(ctx context.Context, id, adminID uuid.UUID, action, note string, apply func(tx *sqlx.Tx, c *domain.AMLCase) error) (*domain.AMLCase, error) {
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("aml_service.%s: begin tx: %w", action, txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	c, txErr := s.repo.GetCaseForUpdate(ctx, tx, id)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if txErr = apply(tx, c); txErr != nil {
+		return nil, txErr
+	}
+	now := time.Now().UTC()
+	c.UpdatedAt = now
+	if txErr = s.repo.UpdateCase(ctx, tx, c); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.event(ctx, tx, id, &adminID, action, note, now); txErr != nil {
+		return nil, txErr
+	}
+	entry, txErr := repository.NewAuditEntry(adminID, "aml."+action, "aml_case", id.String(), map[string]any{
+		"user_id": c.UserID, "status": c.Status, "hold": c.Hold, "note": note,
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.auditRepo.Log(ctx, tx, entry); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("aml_service.%s: commit: %w", action, txErr)
+	}
+	log.Printf("[aml] %s: %s case %s (%s, hold=%t)", adminID, action, id, c.Status, c.Hold)
+	return c, nil
+}
@@ -2,7 +2,13 @@ package service
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
@@ -16,15 +22,25 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// refreshTokenPurgeInterval is how often expired refresh tokens are deleted.
+const refreshTokenPurgeInterval = time.Hour
+
+// refreshTokenReuseGrace is how long expired refresh tokens are kept, so
+// that presenting a rotated one still revokes its family.
+const refreshTokenReuseGrace = 30 * 24 * time.Hour
+
 // ──────────────────────────────────────────────────────────────────────────────
 // Request / Response types
 // ──────────────────────────────────────────────────────────────────────────────
 
 // RegisterRequest contains the fields required to create a new user account.
 type RegisterRequest struct {
-	Username string `json:"username" binding:"required,min=3,max=50"`
-	Email    string `json:"email"    binding:"required,email"`
-	Password string `json:"password" binding:"required,min=8"`
+	Username     string `json:"username"      binding:"required,min=3,max=50"`
+	Email        string `json:"email"         binding:"required,email"`
+	Password     string `json:"password"      binding:"required,min=8"`
+	FullName     string `json:"full_name"     binding:"omitempty,max=100"` // legal name, matched against bank account holders
+	ReferralCode string `json:"referral_code" binding:"omitempty,max=16"`  // invite code of the referring user
+	IP           string `json:"-"`                                         // client IP, set by the handler
 }
 
 // RegisterResponse is returned on successful registration.
@@ -41,10 +57,11 @@ type LoginResponse struct {
 	RefreshToken string       `json:"refresh_token"`
 }
 
-// TokenPair holds both tokens returned by generateTokenPair.
+// TokenPair holds both tokens returned by issueTokens.
 type TokenPair struct {
 	AccessToken  string
 	RefreshToken string
+	refreshID    uuid.UUID // refresh_tokens row of RefreshToken
 }
 
 // ──────────────────────────────────────────────────────────────────────────────
@@ -55,33 +72,46 @@ type TokenPair struct {
 type AppClaims struct {
 	jwt.RegisteredClaims
 	Role      string `json:"role"`
-	TokenType string `json:"type"` // "access" or "refresh"
+	TokenType string `json:"type"` // "access"; refresh tokens are opaque (see issueTokens)
 }
 
 // ──────────────────────────────────────────────────────────────────────────────
 // AuthService
 // ──────────────────────────────────────────────────────────────────────────────
 
-// AuthService handles user registration, login, and JWT token operations.
+// AuthService handles user registration, login, and token operations.
+// Access tokens are stateless JWTs.  Refresh tokens are opaque, stored as a
+// keyed hash and rotated on every refresh; all tokens descending from one
+// login form a family that is revoked together on logout or when a rotated
+// token is presented again.
 type AuthService struct {
-	db         *sqlx.DB
-	userRepo   *repository.UserRepository
-	walletRepo *repository.WalletRepository
-	cfg        *config.Config
+	db          *sqlx.DB
+	userRepo    *repository.UserRepository
+	tokenRepo   *repository.RefreshTokenRepository
+	bonusSvc    *BonusService
+	referralSvc *ReferralService
+	rgSvc       *ResponsibleService
+	cfg         *config.Config
 }
 
 // NewAuthService creates an AuthService.
 func NewAuthService(
 	db *sqlx.DB,
 	userRepo *repository.UserRepository,
-	walletRepo *repository.WalletRepository,
+	tokenRepo *repository.RefreshTokenRepository,
+	bonusSvc *BonusService,
+	referralSvc *ReferralService,
+	rgSvc *ResponsibleService,
 	cfg *config.Config,
 ) *AuthService {
 	return &AuthService{
-		db:         db,
-		userRepo:   userRepo,
-		walletRepo: walletRepo,
-		cfg:        cfg,
+		db:          db,
+		userRepo:    userRepo,
+		tokenRepo:   tokenRepo,
+		bonusSvc:    bonusSvc,
+		referralSvc: referralSvc,
+		rgSvc:       rgSvc,
+		cfg:         cfg,
 	}
 }
 
@@ -89,10 +119,21 @@ func NewAuthService(
 // Register
 // ──────────────────────────────────────────────────────────────────────────────
 
-// Register creates a new user account with a wallet seeded with a 1 000 TRY
-// registration bonus.  The user row, wallet row, and audit log are all written
-// in a single atomic transaction.
+// Register creates a new user account with an empty wallet and grants the
+// configured signup bonus to the bonus balance, where it must be wagered
+// before it can be withdrawn.  A user signing up with an invite code is
+// attributed to its owner (see ReferralService.Attribute).  The user row,
+// wallet row, bonus grant, referral and refresh token are all written in a
+// single atomic transaction.  Returns domain.ErrReferralCodeNotFound for an unknown code.
 func (s *AuthService) Register(ctx context.Context, req RegisterRequest) (*RegisterResponse, error) {
+	var referrer *domain.User
+	if req.ReferralCode != "" {
+		var err error
+		if referrer, err = s.referralSvc.Referrer(ctx, req.ReferralCode); err != nil {
+			return nil, err
+		}
+	}
+
 	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), 12)
 	if err != nil {
 		return nil, fmt.Errorf("auth_service.Register: hash: %w", err)
@@ -103,14 +144,21 @@ func (s *AuthService) Register(ctx context.Context, req RegisterRequest) (*Regis
 		ID:           uuid.New(),
 		Email:        req.Email,
 		Username:     req.Username,
+		FullName:     strings.TrimSpace(req.FullName),
 		PasswordHash: string(hash),
 		Role:         domain.RoleUser,
 		IsActive:     true,
+		KYCStatus:    domain.KYCUnverified,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
+	code := domain.NewReferralCode()
+	user.ReferralCode = &code
+	if req.IP != "" {
+		user.SignupIP = &req.IP
+	}
 
-	bonus := decimal.NewFromInt(1000)
+	bonus := decimal.NewFromFloat(s.cfg.Bonus.SignupAmount)
 
 	tx, txErr := s.db.BeginTxx(ctx, nil)
 	if txErr != nil {
@@ -127,39 +175,32 @@ func (s *AuthService) Register(ctx context.Context, req RegisterRequest) (*Regis
 		return nil, txErr
 	}
 
-	// Create wallet with bonus balance
-	walletID := uuid.New()
+	// Create an empty wallet, then grant the signup bonus
 	if _, txErr = tx.ExecContext(ctx, `
 		INSERT INTO wallets (id, user_id, balance, locked, created_at, updated_at)
-		VALUES ($1, $2, $3, 0, $4, $4)`,
-		walletID, user.ID, bonus, now); txErr != nil {
+		VALUES ($1, $2, 0, 0, $3, $3)`,
+		uuid.New(), user.ID, now); txErr != nil {
 		return nil, fmt.Errorf("auth_service.Register: create wallet: %w", txErr)
 	}
-
-	// Bonus audit log
-	txn := &domain.Transaction{
-		ID:            uuid.New(),
-		WalletID:      walletID,
-		Type:          domain.TxBonus,
-		Amount:        bonus,
-		BalanceBefore: decimal.Zero,
-		BalanceAfter:  bonus,
-		Description:   "Kayıt bonusu",
-		CreatedAt:     now,
+	if bonus.IsPositive() {
+		if _, txErr = s.bonusSvc.Grant(ctx, tx, user.ID, bonus, domain.BonusReasonSignup, nil); txErr != nil {
+			return nil, fmt.Errorf("auth_service.Register: grant bonus: %w", txErr)
+		}
+	}
+	if referrer != nil {
+		if _, txErr = s.referralSvc.Attribute(ctx, tx, referrer, user, req.IP); txErr != nil {
+			return nil, fmt.Errorf("auth_service.Register: attribute referral: %w", txErr)
+		}
 	}
-	if txErr = s.walletRepo.LogTransaction(ctx, tx, txn); txErr != nil {
-		return nil, fmt.Errorf("auth_service.Register: log bonus: %w", txErr)
+	pair, txErr := s.issueTokens(ctx, tx, user, uuid.New())
+	if txErr != nil {
+		return nil, fmt.Errorf("auth_service.Register: tokens: %w", txErr)
 	}
 
 	if txErr = tx.Commit(); txErr != nil {
 		return nil, fmt.Errorf("auth_service.Register: commit: %w", txErr)
 	}
 
-	pair, err := s.generateTokenPair(user.ID, string(user.Role))
-	if err != nil {
-		return nil, fmt.Errorf("auth_service.Register: tokens: %w", err)
-	}
-
 	return &RegisterResponse{
 		User:         user,
 		AccessToken:  pair.AccessToken,
@@ -170,8 +211,10 @@ func (s *AuthService) Register(ctx context.Context, req RegisterRequest) (*Regis
 // insertUserTx inserts a user row within an existing transaction.
 func (s *AuthService) insertUserTx(ctx context.Context, tx *sqlx.Tx, u *domain.User) error {
 	query := `
-		INSERT INTO users (id, email, username, password_hash, role, is_active, created_at, updated_at)
-		VALUES (:id, :email, :username, :password_hash, :role, :is_active, :created_at, :updated_at)`
+		INSERT INTO users
+			(id, email, username, full_name, password_hash, role, is_active, referral_code, signup_ip, created_at, updated_at)
+		VALUES
+			(:id, :email, :username, :full_name, :password_hash, :role, :is_active, :referral_code, :signup_ip, :created_at, :updated_at)`
 	if _, err := tx.NamedExecContext(ctx, query, u); err != nil {
 		errStr := err.Error()
 		if strings.Contains(errStr, "users_email_key") {
@@ -189,7 +232,9 @@ func (s *AuthService) insertUserTx(ctx context.Context, tx *sqlx.Tx, u *domain.U
 // Login
 // ──────────────────────────────────────────────────────────────────────────────
 
-// Login validates credentials and returns a fresh token pair.
+// Login validates credentials and returns a fresh token pair, starting a new
+// refresh-token family.  A self-excluded user gets an error wrapping
+// domain.ErrSelfExcluded.
 func (s *AuthService) Login(ctx context.Context, email, password string) (*LoginResponse, error) {
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
@@ -204,8 +249,11 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*Login
 	if !user.IsActive {
 		return nil, domain.ErrUserInactive
 	}
+	if err = s.rgSvc.CheckLogin(ctx, user.ID); err != nil {
+		return nil, err
+	}
 
-	pair, err := s.generateTokenPair(user.ID, string(user.Role))
+	pair, err := s.issueTokens(ctx, s.db, user, uuid.New())
 	if err != nil {
 		return nil, fmt.Errorf("auth_service.Login: tokens: %w", err)
 	}
@@ -221,47 +269,152 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*Login
 // RefreshToken
 // ──────────────────────────────────────────────────────────────────────────────
 
-// RefreshToken validates a refresh token and issues a new token pair.
+// RefreshToken exchanges a refresh token for a new token pair and revokes
+// it.  Presenting a token that was already exchanged revokes its whole
+// family and returns domain.ErrTokenReused: either the legitimate client or
+// whoever copied the token is the second to use it, and neither can be
+// trusted to hold the successor.  A user who self-excluded after logging
+// in cannot refresh.
 func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (string, string, error) {
-	claims, err := s.parseToken(refreshToken)
-	if err != nil {
-		return "", "", domain.ErrTokenInvalid
-	}
-	if claims.TokenType != "refresh" {
-		return "", "", domain.ErrTokenInvalid
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return "", "", fmt.Errorf("auth_service.RefreshToken: begin tx: %w", txErr)
 	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
 
-	userID, err := uuid.Parse(claims.Subject)
-	if err != nil {
-		return "", "", domain.ErrTokenInvalid
+	old, txErr := s.tokenRepo.GetByHashForUpdate(ctx, tx, s.hashRefreshToken(refreshToken))
+	if txErr != nil {
+		return "", "", txErr
+	}
+	if old.Rotated() {
+		if _, txErr = s.tokenRepo.RevokeFamily(ctx, tx, old.FamilyID); txErr != nil {
+			return "", "", txErr
+		}
+		if txErr = tx.Commit(); txErr != nil {
+			return "", "", fmt.Errorf("auth_service.RefreshToken: commit: %w", txErr)
+		}
+		log.Printf("[auth] refresh token reuse for user %s: revoked token family %s", old.UserID, old.FamilyID)
+		return "", "", domain.ErrTokenReused
+	}
+	if old.Revoked {
+		txErr = domain.ErrTokenInvalid
+		return "", "", txErr
+	}
+	if !old.ExpiresAt.After(time.Now()) {
+		txErr = domain.ErrTokenExpired
+		return "", "", txErr
 	}
 
-	user, err := s.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		return "", "", domain.ErrUserNotFound
+	user, txErr := s.userRepo.GetByID(ctx, old.UserID)
+	if txErr != nil {
+		return "", "", txErr
 	}
 	if !user.IsActive {
-		return "", "", domain.ErrUserInactive
+		txErr = domain.ErrUserInactive
+		return "", "", txErr
+	}
+	if txErr = s.rgSvc.CheckLogin(ctx, user.ID); txErr != nil {
+		return "", "", txErr
 	}
 
-	pair, err := s.generateTokenPair(user.ID, string(user.Role))
-	if err != nil {
-		return "", "", fmt.Errorf("auth_service.RefreshToken: %w", err)
+	pair, txErr := s.issueTokens(ctx, tx, user, old.FamilyID)
+	if txErr != nil {
+		return "", "", fmt.Errorf("auth_service.RefreshToken: %w", txErr)
+	}
+	if txErr = s.tokenRepo.MarkRotated(ctx, tx, old.ID, pair.refreshID); txErr != nil {
+		return "", "", txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return "", "", fmt.Errorf("auth_service.RefreshToken: commit: %w", txErr)
 	}
 	return pair.AccessToken, pair.RefreshToken, nil
 }
 
+// ──────────────────────────────────────────────────────────────────────────────
+// Logout
+// ──────────────────────────────────────────────────────────────────────────────
+
+// Logout revokes the refresh token and every other token of its login.
+// Access tokens already issued stay valid until they expire (AccessTTL).
+// Returns domain.ErrTokenInvalid for an unknown token.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	t, err := s.tokenRepo.GetByHash(ctx, s.hashRefreshToken(refreshToken))
+	if err != nil {
+		return err
+	}
+	_, err = s.tokenRepo.RevokeFamily(ctx, s.db, t.FamilyID)
+	return err
+}
+
+// LogoutAll revokes every refresh token of the user, ending all of their
+// sessions once the access tokens expire, and returns how many were live.
+func (s *AuthService) LogoutAll(ctx context.Context, userID uuid.UUID) (int64, error) {
+	n, err := s.tokenRepo.RevokeUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	log.Printf("[auth] user %s logged out everywhere: revoked %d refresh tokens", userID, n)
+	return n, nil
+}
+
+// PurgeExpiredTokens deletes refresh tokens that expired more than
+// refreshTokenReuseGrace ago until ctx is cancelled.  Run it in its own
+// goroutine.
+func (s *AuthService) PurgeExpiredTokens(ctx context.Context) {
+	ticker := time.NewTicker(refreshTokenPurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.tokenRepo.DeleteExpired(ctx, time.Now().Add(-refreshTokenReuseGrace)); err != nil && ctx.Err() == nil {
+				log.Printf("[auth] purge expired refresh tokens: %v", err)
+			}
+		}
+	}
+}
+
 // ──────────────────────────────────────────────────────────────────────────────
 // Token helpers
 // ──────────────────────────────────────────────────────────────────────────────
 
-// generateTokenPair creates a signed access token (AccessTTL) and a signed
-// refresh token (RefreshTTL) for the given user.
-func (s *AuthService) generateTokenPair(userID uuid.UUID, role string) (TokenPair, error) {
+// issueTokens signs an access token for the user and issues a refresh
+// token in family, storing its hash through e.
+func (s *AuthService) issueTokens(ctx context.Context, e sqlx.ExecerContext, user *domain.User, family uuid.UUID) (TokenPair, error) {
+	access, err := s.signAccessToken(user.ID, string(user.Role))
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	raw := make([]byte, 32)
+	if _, err = rand.Read(raw); err != nil {
+		return TokenPair{}, fmt.Errorf("generate refresh token: %w", err)
+	}
+	refresh := base64.RawURLEncoding.EncodeToString(raw)
 	now := time.Now().UTC()
-	secret := []byte(s.cfg.JWT.AccessSecret) // same secret for both; type claim differentiates
+	t := &domain.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  family,
+		TokenHash: s.hashRefreshToken(refresh),
+		ExpiresAt: now.Add(s.cfg.JWT.RefreshTTL),
+		CreatedAt: now,
+	}
+	if err = s.tokenRepo.Create(ctx, e, t); err != nil {
+		return TokenPair{}, err
+	}
+	return TokenPair{AccessToken: access, RefreshToken: refresh, refreshID: t.ID}, nil
+}
 
-	accessClaims := AppClaims{
+// signAccessToken creates a signed access token (AccessTTL) for the user.
+func (s *AuthService) signAccessToken(userID uuid.UUID, role string) (string, error) {
+	now := time.Now().UTC()
+	claims := AppClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   userID.String(),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -270,25 +423,20 @@ func (s *AuthService) generateTokenPair(userID uuid.UUID, role string) (TokenPai
 		Role:      role,
 		TokenType: "access",
 	}
-	access, err := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims).SignedString(secret)
+	access, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.cfg.JWT.AccessSecret))
 	if err != nil {
-		return TokenPair{}, fmt.Errorf("sign access token: %w", err)
-	}
-
-	refreshClaims := AppClaims{
-		RegisteredClaims: jwt.RegisteredClaims{
-			Subject:   userID.String(),
-			IssuedAt:  jwt.NewNumericDate(now),
-			ExpiresAt: jwt.NewNumericDate(now.Add(s.cfg.JWT.RefreshTTL)),
-		},
-		TokenType: "refresh",
-	}
-	refresh, err := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).SignedString(secret)
-	if err != nil {
-		return TokenPair{}, fmt.Errorf("sign refresh token: %w", err)
+		return "", fmt.Errorf("sign access token: %w", err)
 	}
+	return access, nil
+}
 
-	return TokenPair{AccessToken: access, RefreshToken: refresh}, nil
+// hashRefreshToken returns the stored form of a refresh token: its
+// HMAC-SHA256 under the refresh secret, so a leaked table cannot be
+// replayed.
+func (s *AuthService) hashRefreshToken(token string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.JWT.RefreshSecret))
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
 }
 
 // parseToken validates the token signature, algorithm, and expiry.
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// BankAccountService manages the IBANs users save for withdrawals.  A new
+// account is verified at once when its holder matches the user's
+// KYC-verified name and otherwise waits for finance; either way it can
+// receive withdrawals only after the cooling-off period.
+type BankAccountService struct {
+	db        *sqlx.DB
+	repo      *repository.BankAccountRepository
+	userRepo  *repository.UserRepository
+	auditRepo *repository.AuditRepository
+	cfg       *config.Config
+}
+
+// NewBankAccountService creates a BankAccountService.
+func NewBankAccountService(
+	db *sqlx.DB,
+	repo *repository.BankAccountRepository,
+	userRepo *repository.UserRepository,
+	auditRepo *repository.AuditRepository,
+	cfg *config.Config,
+) *BankAccountService {
+	return &BankAccountService{db: db, repo: repo, userRepo: userRepo, auditRepo: auditRepo, cfg: cfg}
+}
+
+// Add saves an IBAN for userID.  The user's first account becomes the
+// default.  Returns domain.ErrInvalidIBAN (wrapped), domain.ErrBankAccountLimit
+// or domain.ErrBankAccountExists.
+func (s *BankAccountService) Add(ctx context.Context, userID uuid.UUID, iban, holderName string) (*domain.BankAccount, error) {
+	iban, err := domain.ValidateIBAN(iban)
+	if err != nil {
+		return nil, err
+	}
+	// Withdrawals are paid in TRY through Turkish banks only.
+	if !strings.HasPrefix(iban, "TR") {
+		return nil, fmt.Errorf("%w: only TR IBANs are accepted", domain.ErrInvalidIBAN)
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	holderName = strings.Join(strings.Fields(holderName), " ")
+	acct := &domain.BankAccount{
+		ID:         uuid.New(),
+		UserID:     userID,
+		IBAN:       iban,
+		HolderName: holderName,
+		NameMatch:  domain.MatchHolderName(holderName, user.FullName),
+		UsableFrom: now.Add(s.cfg.Wallet.BankAccountCoolingOff),
+		CreatedAt:  now,
+	}
+	acct.Status = domain.InitialBankAccountStatus(acct.NameMatch, user.KYCStatus)
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("bank_account_service.Add: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	n, txErr := s.repo.CountByUser(ctx, tx, userID)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if n >= s.cfg.Wallet.MaxBankAccounts {
+		txErr = domain.ErrBankAccountLimit
+		return nil, txErr
+	}
+	acct.IsDefault = n == 0
+	if txErr = s.repo.Create(ctx, tx, acct); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("bank_account_service.Add: commit: %w", txErr)
+	}
+	log.Printf("[bank] user %s added %s (name %s, %s)", userID, maskIBAN(iban), acct.NameMatch, acct.Status)
+	return acct, nil
+}
+
+// List returns the user's saved accounts, default first.
+func (s *BankAccountService) List(ctx context.Context, userID uuid.UUID) ([]*domain.BankAccount, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// SetDefault makes one of the user's accounts the default withdrawal target.
+func (s *BankAccountService) SetDefault(ctx context.Context, userID, id uuid.UUID) (*domain.BankAccount, error) {
+	return s.change(ctx, id, "SetDefault", func(tx *sqlx.Tx, acct *domain.BankAccount) error {
+		if acct.UserID != userID {
+			return domain.ErrBankAccountNotFound
+		}
+		return s.repo.SetDefault(ctx, tx, userID, id)
+	})
+}
+
+// Remove deletes one of the user's accounts.  Withdrawals already requested
+// to it keep their copy of the IBAN.  Removing the default account makes the
+// most recent verified one the default, so withdrawals without an explicit
+// account keep working.
+func (s *BankAccountService) Remove(ctx context.Context, userID, id uuid.UUID) error {
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return fmt.Errorf("bank_account_service.Remove: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	acct, txErr := s.repo.GetForUpdate(ctx, tx, id)
+	if txErr != nil {
+		return txErr
+	}
+	if acct.UserID != userID {
+		txErr = domain.ErrBankAccountNotFound
+		return txErr
+	}
+	if txErr = s.repo.Delete(ctx, tx, id); txErr != nil {
+		return txErr
+	}
+	if acct.IsDefault {
+		rest, err := s.repo.ListByUserForUpdate(ctx, tx, userID)
+		if err != nil {
+			txErr = err
+			return txErr
+		}
+		if next := domain.NextDefault(rest); next != nil {
+			if txErr = s.repo.SetDefault(ctx, tx, userID, next.ID); txErr != nil {
+				return txErr
+			}
+		}
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return fmt.Errorf("bank_account_service.Remove: commit: %w", txErr)
+	}
+	return nil
+}
+
+// Review records finance's decision on an account's holder.  Verifying does
+// not shorten the cooling-off period.
+func (s *BankAccountService) Review(ctx context.Context, id, adminID uuid.UUID, verify bool, note string) (*domain.BankAccount, error) {
+	return s.change(ctx, id, "Review", func(tx *sqlx.Tx, acct *domain.BankAccount) error {
+		status, action := domain.BankAccountRejected, "bank_account.reject"
+		if verify {
+			status, action = domain.BankAccountVerified, "bank_account.verify"
+		}
+		if err := s.repo.Review(ctx, tx, id, status, adminID, note); err != nil {
+			return err
+		}
+		entry, err := repository.NewAuditEntry(adminID, action, "bank_account", id.String(), map[string]any{
+			"user_id":     acct.UserID,
+			"iban":        maskIBAN(acct.IBAN),
+			"holder_name": acct.HolderName,
+			"name_match":  acct.NameMatch,
+			"note":        note,
+		})
+		if err != nil {
+			return err
+		}
+		return s.auditRepo.Log(ctx, tx, entry)
+	})
+}
+
+// ListAccounts returns accounts for the back office.
+func (s *BankAccountService) ListAccounts(ctx context.Context, status string, userID *uuid.UUID, limit, offset int) ([]*domain.BankAccount, int, error) {
+	return s.repo.List(ctx, status, userID, limit, offset)
+}
+
+// change locks account id, lets apply modify it inside a transaction and
+// returns the updated account.  Domain errors from apply are returned as-is.
+func (s *BankAccountService) change(ctx context.Context, id uuid.UUID, op string, apply func(tx *sqlx.Tx, acct *domain.BankAccount) error) (*domain.BankAccount, error) {
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("bank_account_service.%s: begin tx: %w", op, txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	acct, txErr := s.repo.GetForUpdate(ctx, tx, id)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if txErr = apply(tx, acct); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("bank_account_service.%s: commit: %w", op, txErr)
+	}
+	return s.repo.GetByID(ctx, id)
+}
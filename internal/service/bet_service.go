@@ -2,11 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/evetabi/prediction/internal/aml"
 	"github.com/evetabi/prediction/internal/config"
 	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/ledger"
 	"github.com/evetabi/prediction/internal/repository"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -18,9 +21,9 @@ import (
 // ──────────────────────────────────────────────────────────────────────────────
 
 // Rebalancer is the minimal interface BetService needs from MMService.
-// Implemented by MMService (Step 10).
+// Implemented by MMService (Step 10).  ScheduleRebalance must not block.
 type Rebalancer interface {
-	Rebalance(ctx context.Context, marketID uuid.UUID) error
+	ScheduleRebalance(marketID uuid.UUID)
 }
 
 // Broadcaster is the minimal interface BetService needs from the WS hub.
@@ -34,12 +37,18 @@ type Broadcaster interface {
 // ──────────────────────────────────────────────────────────────────────────────
 
 // BetService orchestrates bet placement and early cashout (bozdur).
-// All money movement happens inside a single PostgreSQL transaction.
+// All money movement is posted to the ledger inside a single PostgreSQL
+// transaction.
 type BetService struct {
 	db          *sqlx.DB
 	betRepo     *repository.BetRepository
 	marketRepo  *repository.MarketRepository
-	walletRepo  *repository.WalletRepository
+	ledger      *ledger.Ledger
+	treasury    *repository.TreasuryRepository
+	bonusSvc    *BonusService
+	promoRepo   *repository.PromotionRepository
+	rgSvc       *ResponsibleService
+	amlSvc      *AMLService
 	cfg         *config.Config
 	rebalancer  Rebalancer  // injected after MMService is built
 	broadcaster Broadcaster // injected after WS Hub is built
@@ -50,14 +59,24 @@ func NewBetService(
 	db *sqlx.DB,
 	betRepo *repository.BetRepository,
 	marketRepo *repository.MarketRepository,
-	walletRepo *repository.WalletRepository,
+	ledger *ledger.Ledger,
+	treasury *repository.TreasuryRepository,
+	bonusSvc *BonusService,
+	promoRepo *repository.PromotionRepository,
+	rgSvc *ResponsibleService,
+	amlSvc *AMLService,
 	cfg *config.Config,
 ) *BetService {
 	return &BetService{
 		db:         db,
 		betRepo:    betRepo,
 		marketRepo: marketRepo,
-		walletRepo: walletRepo,
+		ledger:     ledger,
+		treasury:   treasury,
+		bonusSvc:   bonusSvc,
+		promoRepo:  promoRepo,
+		rgSvc:      rgSvc,
+		amlSvc:     amlSvc,
 		cfg:        cfg,
 	}
 }
@@ -72,23 +91,27 @@ func (s *BetService) SetBroadcaster(b Broadcaster) { s.broadcaster = b }
 // PlaceBet
 // ──────────────────────────────────────────────────────────────────────────────
 
-// PlaceBet validates the request, atomically deducts the user's balance,
-// updates the market pool, records the bet, and writes an audit log entry —
-// all inside a single PostgreSQL transaction.
+// PlaceBet validates the request, moves the stake from the user's wallet (or
+// bonus balance, or the house for a free bet) to the market escrow, updates
+// the market pool and records the bet — all inside a single PostgreSQL
+// transaction.  Users in a cooling-off or self-exclusion period cannot bet,
+// and a real-money stake that would take the user over a stake or loss
+// limit is rejected with an error wrapping domain.ErrLimitExceeded.
 //
 // After a successful commit it asynchronously triggers MM rebalancing and
 // a WS broadcast of the updated odds.
 func (s *BetService) PlaceBet(ctx context.Context, req domain.PlaceBetRequest) (*domain.Bet, error) {
 	// ── 1. Input validation ──────────────────────────────────────────────────
-	minBet := decimal.NewFromFloat(s.cfg.Wallet.CommissionRate). // reuse config sensibly
-									Mul(decimal.Zero) // placeholder; overridden next line
-	minBet = decimal.NewFromInt(10) // hard floor: 10 TRY minimum
-	if req.Amount.LessThan(minBet) {
+	// A free bet stakes the token's amount, which is at least the minimum.
+	if req.FreeBetID == nil && req.Amount.LessThan(domain.MinBetAmount) {
 		return nil, domain.ErrBetTooSmall
 	}
 	if !req.Direction.IsValid() {
 		return nil, domain.ErrInvalidOutcome
 	}
+	if err := s.rgSvc.CheckPlay(ctx, req.UserID); err != nil {
+		return nil, err
+	}
 
 	// ── 2. Begin transaction ─────────────────────────────────────────────────
 	tx, err := s.db.BeginTxx(ctx, nil)
@@ -101,42 +124,81 @@ func (s *BetService) PlaceBet(ctx context.Context, req domain.PlaceBetRequest) (
 		}
 	}()
 
-	// ── 3. Lock wallet and check balance ─────────────────────────────────────
-	// DeductBalance acquires FOR UPDATE internally, checks available balance,
-	// and deducts atomically.
-	if err = s.walletRepo.DeductBalance(ctx, tx, req.UserID, req.Amount); err != nil {
-		return nil, fmt.Errorf("bet_service.PlaceBet: deduct: %w", err)
-	}
-
-	// ── 4. Load market and verify it is still open ───────────────────────────
+	// ── 3. Load market and verify it is still open ───────────────────────────
 	market, err := s.marketRepo.GetByID(ctx, req.MarketID)
 	if err != nil {
 		return nil, fmt.Errorf("bet_service.PlaceBet: get market: %w", err)
 	}
 	if !market.IsOpen() {
-		return nil, domain.ErrMarketNotOpen
+		err = domain.ErrMarketNotOpen
+		return nil, err
 	}
 
-	// ── 5. Capture current odds (before this bet changes the pool) ───────────
+	// ── 4. Capture current odds (before this bet changes the pool) ───────────
 	oddsAtEntry := market.OddsFor(req.Direction)
 	if oddsAtEntry.IsZero() {
 		// Pool is empty on this side; seed with 1:1 odds so the bet can proceed
 		oddsAtEntry = decimal.NewFromInt(1)
 	}
 
-	// ── 6. Update market pool ────────────────────────────────────────────────
+	// ── 5. Build the stake transfer ──────────────────────────────────────────
+	// Real money is staked first and any shortfall comes from the bonus
+	// balance.  A free bet is staked by the house instead.
+	betID := uuid.New()
+	escrow := ledger.MarketEscrow(req.MarketID)
+	entry := ledger.NewEntry(domain.TxBetLock, betID, fmt.Sprintf("Bet placed: %s", string(req.Direction)))
+	bonusStake, realStake := decimal.Zero, decimal.Zero
+	if req.FreeBetID != nil {
+		var fb *domain.FreeBet
+		if fb, err = s.promoRepo.UseFreeBet(ctx, tx, *req.FreeBetID, req.UserID, betID); err != nil {
+			return nil, err
+		}
+		req.Amount = fb.Amount
+		entry.Transfer(ledger.HouseFreeBets, escrow, req.Amount)
+	} else {
+		if realStake, bonusStake, err = s.bonusSvc.SplitStake(ctx, req.UserID, req.Amount); err != nil {
+			return nil, fmt.Errorf("bet_service.PlaceBet: split stake: %w", err)
+		}
+		entry.Transfer(ledger.User(req.UserID), escrow, realStake).
+			Transfer(ledger.UserBonus(req.UserID), escrow, bonusStake)
+	}
+
+	// ── 6. Update market pool, then move the stake into escrow ───────────────
+	// Lock order: the market row before any wallet or escrow account, as in
+	// settlement, so a bet racing the resolution waits for it instead of
+	// deadlocking.  The ledger locks the wallet row and checks the available
+	// balance.
 	if err = s.marketRepo.UpdatePools(ctx, tx, req.MarketID, req.Direction, req.Amount); err != nil {
 		return nil, fmt.Errorf("bet_service.PlaceBet: update pools: %w", err)
 	}
+	if err = s.ledger.Post(ctx, tx, entry); err != nil {
+		if errors.Is(err, domain.ErrInsufficientBalance) {
+			err = domain.ErrInsufficientBalance
+			return nil, err
+		}
+		if errors.Is(err, domain.ErrWalletFrozen) {
+			err = domain.ErrWalletFrozen
+			return nil, err
+		}
+		return nil, fmt.Errorf("bet_service.PlaceBet: post stake: %w", err)
+	}
+	// Checked after posting so that the totals include this stake.
+	if realStake.IsPositive() {
+		if err = s.rgSvc.CheckStake(ctx, tx, req.UserID); err != nil {
+			return nil, err
+		}
+	}
 
 	// ── 7. Persist the bet ───────────────────────────────────────────────────
 	now := time.Now().UTC()
 	bet := &domain.Bet{
-		ID:          uuid.New(),
+		ID:          betID,
 		UserID:      req.UserID,
 		MarketID:    req.MarketID,
 		Direction:   req.Direction,
 		Amount:      req.Amount,
+		BonusAmount: bonusStake,
+		FreeBetID:   req.FreeBetID,
 		OddsAtEntry: oddsAtEntry,
 		Status:      domain.BetStatusActive,
 		PlacedAt:    now,
@@ -145,34 +207,21 @@ func (s *BetService) PlaceBet(ctx context.Context, req domain.PlaceBetRequest) (
 		return nil, fmt.Errorf("bet_service.PlaceBet: create bet: %w", err)
 	}
 
-	// ── 8. Audit log ─────────────────────────────────────────────────────────
-	wallet, err := s.walletRepo.GetByUserID(ctx, req.UserID)
-	if err != nil {
-		return nil, fmt.Errorf("bet_service.PlaceBet: get wallet for log: %w", err)
-	}
-	betIDCopy := bet.ID
-	txn := &domain.Transaction{
-		ID:            uuid.New(),
-		WalletID:      wallet.ID,
-		Type:          domain.TxBetLock,
-		Amount:        req.Amount,
-		BalanceBefore: wallet.Balance,
-		BalanceAfter:  wallet.Balance.Sub(req.Amount),
-		RefID:         &betIDCopy,
-		Description:   fmt.Sprintf("Bet placed: %s", string(req.Direction)),
-		CreatedAt:     now,
-	}
-	if err = s.walletRepo.LogTransaction(ctx, tx, txn); err != nil {
-		return nil, fmt.Errorf("bet_service.PlaceBet: log tx: %w", err)
-	}
-
-	// ── 9. Commit ─────────────────────────────────────────────────────────────
+	// ── 8. Commit ─────────────────────────────────────────────────────────────
 	if err = tx.Commit(); err != nil {
 		return nil, fmt.Errorf("bet_service.PlaceBet: commit: %w", err)
 	}
 
-	// ── 10. Async: MM rebalance + WS broadcast ────────────────────────────────
+	// ── 9. Async: MM rebalance + WS broadcast + AML screening ─────────────────
 	go s.postBetAsync(req.MarketID)
+	go s.amlSvc.ScreenAsync(aml.Event{
+		Subject:   domain.AMLSubjectBet,
+		SubjectID: bet.ID,
+		UserID:    bet.UserID,
+		Amount:    bet.Amount,
+		At:        now,
+		MarketID:  bet.MarketID,
+	})
 
 	return bet, nil
 }
@@ -184,9 +233,7 @@ func (s *BetService) postBetAsync(marketID uuid.UUID) {
 	defer cancel()
 
 	if s.rebalancer != nil {
-		if err := s.rebalancer.Rebalance(ctx, marketID); err != nil {
-			_ = err // logged by MMService internally
-		}
+		s.rebalancer.ScheduleRebalance(marketID)
 	}
 
 	if s.broadcaster != nil {
@@ -219,6 +266,9 @@ func (s *BetService) ExitBet(ctx context.Context, betID uuid.UUID, userID uuid.U
 	if !bet.IsActive() {
 		return nil, domain.ErrBetNotActive
 	}
+	if bet.IsFreeBet() {
+		return nil, domain.ErrFreeBetNoExit
+	}
 
 	// ── 2. Load market and confirm it is still open ──────────────────────────
 	market, err := s.marketRepo.GetByID(ctx, bet.MarketID)
@@ -248,43 +298,46 @@ func (s *BetService) ExitBet(ctx context.Context, betID uuid.UUID, userID uuid.U
 		}
 	}()
 
-	// ── 5. Mark bet as cashed_out (idempotent — WHERE status='open') ─────────
-	if err = s.betRepo.ExitBet(ctx, tx, betID, exitAmount, cashoutFee); err != nil {
-		return nil, fmt.Errorf("bet_service.ExitBet: exit bet: %w", err)
-	}
-
-	// ── 6. Remove original stake from market pool ────────────────────────────
-	// Pass as negative to subtract from the pool
+	// ── 5. Remove original stake from market pool ────────────────────────────
+	// Pass as negative to subtract from the pool.  This takes the market lock
+	// before the bet row, in the same order as settlement, so an exit racing
+	// the resolution waits for it instead of deadlocking.
 	negAmount := bet.Amount.Neg()
 	if err = s.marketRepo.UpdatePools(ctx, tx, bet.MarketID, bet.Direction, negAmount); err != nil {
 		return nil, fmt.Errorf("bet_service.ExitBet: update pools: %w", err)
 	}
 
-	// ── 7. Credit exit amount to user wallet ─────────────────────────────────
-	if err = s.walletRepo.AddBalance(ctx, tx, userID, exitAmount); err != nil {
-		return nil, fmt.Errorf("bet_service.ExitBet: add balance: %w", err)
+	// ── 6. Mark bet as cashed_out (idempotent — WHERE status='open') ─────────
+	if err = s.betRepo.ExitBet(ctx, tx, betID, exitAmount, cashoutFee); err != nil {
+		return nil, fmt.Errorf("bet_service.ExitBet: exit bet: %w", err)
 	}
 
-	// ── 8. Audit log ─────────────────────────────────────────────────────────
-	wallet, err := s.walletRepo.GetByUserID(ctx, userID)
-	if err != nil {
-		return nil, fmt.Errorf("bet_service.ExitBet: get wallet for log: %w", err)
+	// ── 7. Pay the exit out of escrow ────────────────────────────────────────
+	// The stake leaves escrow; the user gets the exit amount, the house the
+	// fee, and the difference between stake and gross exit value is the
+	// house's cashout PnL (negative when the exit pays more than the stake).
+	// The bonus-funded share of the exit goes back to the bonus balance.
+	escrow := ledger.MarketEscrow(bet.MarketID)
+	bonusPart := bet.BonusShare(exitAmount)
+	cashoutPnL := bet.Amount.Sub(exitAmount).Sub(cashoutFee)
+	entry := ledger.NewEntry(domain.TxCashout, betID,
+		fmt.Sprintf("Bet cashed out: %s, fee: %s TRY", string(bet.Direction), cashoutFee.StringFixed(4))).
+		Transfer(escrow, ledger.User(userID), exitAmount.Sub(bonusPart)).
+		Transfer(escrow, ledger.UserBonus(userID), bonusPart).
+		Transfer(escrow, ledger.HouseCashoutFees, cashoutFee).
+		Transfer(escrow, ledger.HouseCashoutPnL, cashoutPnL)
+	if err = s.ledger.Post(ctx, tx, entry); err != nil {
+		return nil, fmt.Errorf("bet_service.ExitBet: post cashout: %w", err)
+	}
+	if bonusPart.IsPositive() {
+		if err = s.bonusSvc.Sweep(ctx, tx, userID); err != nil {
+			return nil, fmt.Errorf("bet_service.ExitBet: %w", err)
+		}
 	}
-	now := time.Now().UTC()
-	betIDCopy := betID
-	cashoutTxn := &domain.Transaction{
-		ID:            uuid.New(),
-		WalletID:      wallet.ID,
-		Type:          domain.TxCashout,
-		Amount:        exitAmount,
-		BalanceBefore: wallet.Balance,
-		BalanceAfter:  wallet.Balance.Add(exitAmount),
-		RefID:         &betIDCopy,
-		Description:   fmt.Sprintf("Bet cashed out: %s, fee: %s TRY", string(bet.Direction), cashoutFee.StringFixed(4)),
-		CreatedAt:     now,
-	}
-	if err = s.walletRepo.LogTransaction(ctx, tx, cashoutTxn); err != nil {
-		return nil, fmt.Errorf("bet_service.ExitBet: log tx: %w", err)
+
+	// ── 8. Book the cashout fee and PnL to the market's house treasury row ───
+	if err = s.treasury.AddCashout(ctx, tx, bet.MarketID, cashoutFee, cashoutPnL); err != nil {
+		return nil, fmt.Errorf("bet_service.ExitBet: %w", err)
 	}
 
 	// ── 9. Commit ────────────────────────────────────────────────────────────
@@ -311,13 +364,13 @@ func (s *BetService) ExitBet(ctx context.Context, betID uuid.UUID, userID uuid.U
 // Query helpers
 // ──────────────────────────────────────────────────────────────────────────────
 
-// GetMyBets returns paginated bets for a user.
-func (s *BetService) GetMyBets(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Bet, error) {
-	bets, err := s.betRepo.GetByUserID(ctx, userID, limit, offset)
+// GetMyBets returns paginated bets for a user and the total count.
+func (s *BetService) GetMyBets(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Bet, int, error) {
+	bets, total, err := s.betRepo.GetByUserID(ctx, userID, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("bet_service.GetMyBets: %w", err)
+		return nil, 0, fmt.Errorf("bet_service.GetMyBets: %w", err)
 	}
-	return bets, nil
+	return bets, total, nil
 }
 
 // GetBetByID returns a single bet only if it belongs to userID.
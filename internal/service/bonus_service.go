@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/ledger"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// bonusExpiryInterval is how often grants past their expiry are closed.
+const bonusExpiryInterval = time.Minute
+
+// BonusService manages bonus balances.  A grant credits ledger.UserBonus,
+// which can be staked but not withdrawn; settled stakes on qualifying bets
+// count towards the grant's wagering requirement and once it is met the
+// whole bonus balance moves to the wallet.  Grants that expire or are
+// forfeited return their balance to house:bonus.
+//
+// A positive bonus balance always belongs to the user's active grant: money
+// that comes back to the bonus account after the grant closed is swept at
+// once by Sweep.
+type BonusService struct {
+	db         *sqlx.DB
+	repo       *repository.BonusRepository
+	walletRepo *repository.WalletRepository
+	ledger     *ledger.Ledger
+	cfg        *config.Config
+}
+
+// NewBonusService creates a BonusService.
+func NewBonusService(
+	db *sqlx.DB,
+	repo *repository.BonusRepository,
+	walletRepo *repository.WalletRepository,
+	ledger *ledger.Ledger,
+	cfg *config.Config,
+) *BonusService {
+	return &BonusService{db: db, repo: repo, walletRepo: walletRepo, ledger: ledger, cfg: cfg}
+}
+
+// Grant credits amount to the user's bonus balance inside tx with the
+// configured wagering requirement and validity.  createdBy is nil for
+// automatic grants.  Returns domain.ErrBonusActive when the user is still
+// wagering another bonus.
+func (s *BonusService) Grant(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount decimal.Decimal, reason string, createdBy *uuid.UUID) (*domain.BonusGrant, error) {
+	now := time.Now().UTC()
+	g := &domain.BonusGrant{
+		ID:               uuid.New(),
+		UserID:           userID,
+		Amount:           amount,
+		WageringRequired: amount.Mul(decimal.NewFromFloat(s.cfg.Bonus.WageringMultiplier)).Round(4),
+		Wagered:          decimal.Zero,
+		Status:           domain.BonusActive,
+		Reason:           reason,
+		CreatedBy:        createdBy,
+		CreatedAt:        now,
+		ExpiresAt:        now.Add(s.cfg.Bonus.Validity),
+	}
+	if err := s.repo.Create(ctx, tx, g); err != nil {
+		return nil, err
+	}
+	entry := ledger.NewEntry(domain.TxBonus, g.ID, "Bonus: "+reason).
+		Transfer(ledger.HouseBonus, ledger.UserBonus(userID), amount)
+	if err := s.ledger.Post(ctx, tx, entry); err != nil {
+		return nil, fmt.Errorf("bonus_service.Grant: %w", err)
+	}
+	return g, nil
+}
+
+// SplitStake returns how much of a stake of amount is paid from the wallet
+// and how much from the bonus balance; see domain.SplitStake.  The balances
+// are read without locks: the ledger rejects the stake if either changed.
+func (s *BonusService) SplitStake(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) (real, bonus decimal.Decimal, err error) {
+	wallet, err := s.walletRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	bal, err := s.ledger.Balance(ctx, ledger.UserBonus(userID))
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	real, bonus = domain.SplitStake(amount, wallet.Available(), bal)
+	return real, bonus, nil
+}
+
+// Settled updates bonuses after market marketID is settled inside tx: it
+// credits wagering with each user's net one-sided stake (see
+// domain.WageringStake), converts grants whose requirement is now met and
+// sweeps the bonus accounts of userIDs, the users who got bonus money back.
+// It must run once per market, after the bet statuses have been set.
+func (s *BonusService) Settled(ctx context.Context, tx *sqlx.Tx, marketID uuid.UUID, userIDs []uuid.UUID) error {
+	stakes, err := s.repo.MarketStakes(ctx, tx, marketID, s.cfg.Bonus.MinOdds)
+	if err != nil {
+		return err
+	}
+	for _, st := range stakes {
+		amount := domain.WageringStake(st.Up, st.Down)
+		if amount.IsZero() {
+			continue
+		}
+		g, err := s.repo.AddWagered(ctx, tx, st.GrantID, amount)
+		if err != nil {
+			return err
+		}
+		if !g.WageringMet() {
+			continue
+		}
+		if err := s.close(ctx, tx, g, domain.BonusConverted); err != nil {
+			return err
+		}
+	}
+	return s.Sweep(ctx, tx, userIDs...)
+}
+
+// Sweep moves bonus money that came back to a user after their grant closed,
+// e.g. the payout of a bonus stake, to where the grant's balance went: the
+// wallet when it converted, house:bonus otherwise.  Users with an active
+// grant are left alone.
+func (s *BonusService) Sweep(ctx context.Context, tx *sqlx.Tx, userIDs ...uuid.UUID) error {
+	seen := make(map[uuid.UUID]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		g, err := s.repo.GetLatest(ctx, tx, userID)
+		if errors.Is(err, domain.ErrBonusNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if g.Status == domain.BonusActive {
+			continue
+		}
+		if err := s.release(ctx, tx, userID, g.ID, g.Status == domain.BonusConverted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForfeitActive gives up the user's active grant inside tx and returns its
+// balance to the house.  Returns domain.ErrBonusNotFound when there is none.
+func (s *BonusService) ForfeitActive(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) error {
+	g, err := s.repo.GetActiveForUpdate(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	return s.close(ctx, tx, g, domain.BonusForfeited)
+}
+
+// HasActive reports whether the user is wagering a bonus.
+func (s *BonusService) HasActive(ctx context.Context, userID uuid.UUID) (bool, error) {
+	_, err := s.repo.GetActive(ctx, userID)
+	if errors.Is(err, domain.ErrBonusNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Summary returns the user's bonus balance and active grant.
+func (s *BonusService) Summary(ctx context.Context, userID uuid.UUID) (*domain.BonusSummary, error) {
+	bal, err := s.ledger.Balance(ctx, ledger.UserBonus(userID))
+	if err != nil {
+		return nil, err
+	}
+	sum := &domain.BonusSummary{Balance: bal}
+	sum.Active, err = s.repo.GetActive(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrBonusNotFound) {
+		return nil, err
+	}
+	return sum, nil
+}
+
+// List returns the user's grants, newest first.
+func (s *BonusService) List(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.BonusGrant, int, error) {
+	return s.repo.ListByUser(ctx, userID, limit, offset)
+}
+
+// ExpireStale closes grants past their expiry until ctx is cancelled.  Run
+// it in its own goroutine.
+func (s *BonusService) ExpireStale(ctx context.Context) {
+	ticker := time.NewTicker(bonusExpiryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			userIDs, err := s.repo.ListExpired(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("[bonus] expire: %v", err)
+				}
+				continue
+			}
+			for _, userID := range userIDs {
+				if err := s.expire(ctx, userID); err != nil && ctx.Err() == nil {
+					log.Printf("[bonus] expire user %s: %v", userID, err)
+				}
+			}
+		}
+	}
+}
+
+// expire closes the user's active grant if it is still past its expiry.
+func (s *BonusService) expire(ctx context.Context, userID uuid.UUID) error {
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return fmt.Errorf("bonus_service.expire: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	g, txErr := s.repo.GetActiveForUpdate(ctx, tx, userID)
+	if txErr != nil {
+		if errors.Is(txErr, domain.ErrBonusNotFound) {
+			return nil
+		}
+		return txErr
+	}
+	if time.Now().Before(g.ExpiresAt) {
+		txErr = tx.Rollback()
+		return txErr
+	}
+	if txErr = s.close(ctx, tx, g, domain.BonusExpired); txErr != nil {
+		return txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return fmt.Errorf("bonus_service.expire: commit: %w", txErr)
+	}
+	return nil
+}
+
+// close ends grant g with status and releases the bonus balance: to the
+// wallet when converted, to house:bonus otherwise.
+func (s *BonusService) close(ctx context.Context, tx *sqlx.Tx, g *domain.BonusGrant, status domain.BonusStatus) error {
+	if err := s.repo.Close(ctx, tx, g.ID, status); err != nil {
+		return err
+	}
+	if err := s.release(ctx, tx, g.UserID, g.ID, status == domain.BonusConverted); err != nil {
+		return err
+	}
+	log.Printf("[bonus] grant %s of user %s %s (wagered %s/%s)", g.ID, g.UserID, status, g.Wagered, g.WageringRequired)
+	return nil
+}
+
+// release empties the user's bonus account into the wallet or house:bonus.
+func (s *BonusService) release(ctx context.Context, tx *sqlx.Tx, userID, grantID uuid.UUID, toWallet bool) error {
+	account := ledger.UserBonus(userID)
+	bal, err := s.ledger.LockBalance(ctx, tx, account)
+	if err != nil {
+		return err
+	}
+	if !bal.IsPositive() {
+		return nil
+	}
+	entry := ledger.NewEntry(domain.TxBonus, grantID, "Bonus forfeited").
+		Transfer(account, ledger.HouseBonus, bal)
+	if toWallet {
+		entry = ledger.NewEntry(domain.TxBonus, grantID, "Bonus converted to balance").
+			Transfer(account, ledger.User(userID), bal)
+	}
+	if err := s.ledger.Post(ctx, tx, entry); err != nil {
+		return fmt.Errorf("bonus_service.release: %w", err)
+	}
+	return nil
+}
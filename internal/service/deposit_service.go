@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/evetabi/prediction/internal/aml"
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/ledger"
+	"github.com/evetabi/prediction/internal/payment"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// depositExpiryInterval is how often stale pending deposits are expired.
+const depositExpiryInterval = time.Minute
+
+// DepositService creates deposit intents with the payment provider and
+// settles them from the provider's webhooks.  A wallet is credited only by a
+// verified webhook, exactly once per deposit.
+type DepositService struct {
+	db          *sqlx.DB
+	depositRepo *repository.DepositRepository
+	ledger      *ledger.Ledger
+	provider    payment.Provider
+	rgSvc       *ResponsibleService
+	amlSvc      *AMLService
+	cfg         *config.Config
+}
+
+// NewDepositService creates a DepositService.
+func NewDepositService(
+	db *sqlx.DB,
+	depositRepo *repository.DepositRepository,
+	ledger *ledger.Ledger,
+	provider payment.Provider,
+	rgSvc *ResponsibleService,
+	amlSvc *AMLService,
+	cfg *config.Config,
+) *DepositService {
+	return &DepositService{
+		db:          db,
+		depositRepo: depositRepo,
+		ledger:      ledger,
+		provider:    provider,
+		rgSvc:       rgSvc,
+		amlSvc:      amlSvc,
+		cfg:         cfg,
+	}
+}
+
+// CreateDeposit stores a pending deposit and opens a checkout for it with
+// the provider.  Returns domain.ErrInvalidDepositAmount when amount is
+// outside the configured limits, or an error wrapping domain.ErrCoolingOff,
+// domain.ErrSelfExcluded or domain.ErrLimitExceeded when the user's
+// responsible-gambling settings forbid it.
+func (s *DepositService) CreateDeposit(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) (*domain.Deposit, error) {
+	minDep := decimal.NewFromFloat(s.cfg.Payment.MinDeposit)
+	maxDep := decimal.NewFromFloat(s.cfg.Payment.MaxDeposit)
+	if amount.LessThan(minDep) || amount.GreaterThan(maxDep) {
+		return nil, domain.ErrInvalidDepositAmount
+	}
+
+	now := time.Now().UTC()
+	d := &domain.Deposit{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Amount:    amount,
+		Status:    domain.DepositPending,
+		Provider:  s.provider.Name(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	// Until the checkout is open the deposit's own ID stands in for the
+	// provider reference, which must be unique per provider.
+	d.ProviderRef = "pending:" + d.ID.String()
+
+	// The limit check holds a per-user lock until the pending deposit is
+	// committed, so concurrent intents cannot each fit under the limit
+	// alone.  The provider is called only after the commit so that a slow
+	// provider does not hold the lock.
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("deposit_service.CreateDeposit: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if txErr = s.rgSvc.CheckDeposit(ctx, tx, userID, amount); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.depositRepo.Create(ctx, tx, d); txErr != nil {
+		return nil, fmt.Errorf("deposit_service.CreateDeposit: %w", txErr)
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("deposit_service.CreateDeposit: commit: %w", txErr)
+	}
+
+	checkout, err := s.provider.CreateCheckout(ctx, payment.Intent{
+		DepositID: d.ID,
+		UserID:    userID,
+		Amount:    amount,
+	})
+	if err != nil {
+		s.abandon(d.ID, fmt.Sprintf("checkout: %v", err))
+		return nil, fmt.Errorf("deposit_service.CreateDeposit: checkout: %w", err)
+	}
+	if err := s.depositRepo.SetCheckout(ctx, d.ID, checkout.ProviderRef, checkout.CheckoutURL); err != nil {
+		s.abandon(d.ID, "checkout not recorded")
+		return nil, fmt.Errorf("deposit_service.CreateDeposit: %w", err)
+	}
+	d.ProviderRef = checkout.ProviderRef
+	d.CheckoutURL = checkout.CheckoutURL
+	return d, nil
+}
+
+// abandon closes a deposit whose checkout could not be opened or recorded,
+// so it no longer counts against the user's deposit limits.
+func (s *DepositService) abandon(id uuid.UUID, reason string) {
+	ctx := context.Background()
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err == nil {
+		if err = s.depositRepo.MarkFailed(ctx, tx, id, reason); err == nil {
+			err = tx.Commit()
+		} else {
+			_ = tx.Rollback()
+		}
+	}
+	if err != nil {
+		log.Printf("[deposit] close deposit %s without checkout: %v", id, err)
+	}
+}
+
+// HandleWebhook verifies and applies a webhook from providerName.  A
+// succeeded event credits the wallet from external:payments; a failed event
+// closes the deposit.  A payment confirmed after its intent expired was
+// still received: it is credited, and logged, only when it still fits the
+// user's deposit limits, and otherwise closed for finance to refund.
+// Redelivered events and events for deposits that are already final are
+// acknowledged without effect.  A credited deposit is screened for AML once
+// committed.  Returns domain.ErrInvalidWebhookSignature,
+// domain.ErrUnknownPaymentProvider or domain.ErrDepositNotFound for
+// requests that should be rejected.
+func (s *DepositService) HandleWebhook(ctx context.Context, providerName string, header http.Header, body []byte) error {
+	if providerName != s.provider.Name() {
+		return domain.ErrUnknownPaymentProvider
+	}
+	ev, err := s.provider.ParseWebhook(header, body, time.Now())
+	if err != nil {
+		return err
+	}
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return fmt.Errorf("deposit_service.HandleWebhook: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	d, txErr := s.depositRepo.GetByProviderRefForUpdate(ctx, tx, providerName, ev.ProviderRef)
+	if txErr != nil {
+		return txErr
+	}
+	fresh, txErr := s.depositRepo.RecordWebhookEvent(ctx, tx, providerName, ev.ID, string(ev.Type), d.ID, body)
+	if txErr != nil {
+		return fmt.Errorf("deposit_service.HandleWebhook: %w", txErr)
+	}
+	if !fresh {
+		txErr = tx.Commit()
+		return txErr
+	}
+
+	credited := false
+	switch {
+	case d.Status == domain.DepositCompleted || d.Status == domain.DepositFailed:
+		log.Printf("[deposit] %s event %s for %s deposit %s ignored", ev.Type, ev.ID, d.Status, d.ID)
+
+	case ev.Type == payment.EventFailed:
+		if txErr = s.depositRepo.MarkFailed(ctx, tx, d.ID, ev.Reason); txErr != nil {
+			return fmt.Errorf("deposit_service.HandleWebhook: %w", txErr)
+		}
+
+	case !ev.Amount.Equal(d.Amount):
+		// Never credit a different amount than the user asked for; finance
+		// settles the difference with the provider by hand.
+		log.Printf("[deposit] ALARM: deposit %s paid %s TRY, expected %s TRY",
+			d.ID, ev.Amount.StringFixed(4), d.Amount.StringFixed(4))
+		reason := fmt.Sprintf("amount mismatch: paid %s", ev.Amount.StringFixed(2))
+		if txErr = s.depositRepo.MarkFailed(ctx, tx, d.ID, reason); txErr != nil {
+			return fmt.Errorf("deposit_service.HandleWebhook: %w", txErr)
+		}
+
+	case d.Status == domain.DepositExpired:
+		// An expired intent no longer counts against the deposit limits, so
+		// a late payment is checked against them again before crediting;
+		// one that no longer fits is closed and settled by finance.
+		err := s.rgSvc.CheckDeposit(ctx, tx, d.UserID, d.Amount)
+		switch {
+		case errors.Is(err, domain.ErrLimitExceeded), errors.Is(err, domain.ErrCoolingOff), errors.Is(err, domain.ErrSelfExcluded):
+			log.Printf("[deposit] ALARM: expired deposit %s paid %s TRY but refused: %v",
+				d.ID, d.Amount.StringFixed(4), err)
+			if txErr = s.depositRepo.MarkFailed(ctx, tx, d.ID, fmt.Sprintf("paid after expiry: %v", err)); txErr != nil {
+				return fmt.Errorf("deposit_service.HandleWebhook: %w", txErr)
+			}
+		case err != nil:
+			txErr = err
+			return fmt.Errorf("deposit_service.HandleWebhook: %w", txErr)
+		default:
+			log.Printf("[deposit] expired deposit %s paid after expiry: crediting %s TRY",
+				d.ID, d.Amount.StringFixed(4))
+			if txErr = s.credit(ctx, tx, d, providerName); txErr != nil {
+				return txErr
+			}
+			credited = true
+		}
+
+	default:
+		if txErr = s.credit(ctx, tx, d, providerName); txErr != nil {
+			return txErr
+		}
+		credited = true
+	}
+
+	if txErr = tx.Commit(); txErr != nil {
+		return fmt.Errorf("deposit_service.HandleWebhook: commit: %w", txErr)
+	}
+	if credited {
+		go s.amlSvc.ScreenAsync(aml.Event{
+			Subject:   domain.AMLSubjectDeposit,
+			SubjectID: d.ID,
+			UserID:    d.UserID,
+			Amount:    d.Amount,
+			At:        time.Now().UTC(),
+		})
+	}
+	return nil
+}
+
+// credit moves a confirmed deposit from external:payments into the user's
+// wallet and completes it inside tx.
+func (s *DepositService) credit(ctx context.Context, tx *sqlx.Tx, d *domain.Deposit, providerName string) error {
+	entry := ledger.NewEntry(domain.TxDeposit, d.ID, fmt.Sprintf("Deposit via %s", providerName)).
+		Transfer(ledger.ExternalPayments, ledger.User(d.UserID), d.Amount)
+	if err := s.ledger.Post(ctx, tx, entry); err != nil {
+		return fmt.Errorf("deposit_service.HandleWebhook: credit: %w", err)
+	}
+	if err := s.depositRepo.MarkCompleted(ctx, tx, d.ID, entry.ID); err != nil {
+		return fmt.Errorf("deposit_service.HandleWebhook: %w", err)
+	}
+	return nil
+}
+
+// ExpireStale expires pending deposits older than cfg.Payment.DepositTTL
+// every minute.  Blocks until ctx is cancelled.
+func (s *DepositService) ExpireStale(ctx context.Context) {
+	ticker := time.NewTicker(depositExpiryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.depositRepo.ExpirePending(ctx, time.Now().Add(-s.cfg.Payment.DepositTTL))
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("[deposit] expire: %v", err)
+				}
+				continue
+			}
+			if n > 0 {
+				log.Printf("[deposit] expired %d pending deposits", n)
+			}
+		}
+	}
+}
+
+// GetUserDeposit returns one of the user's deposits.
+func (s *DepositService) GetUserDeposit(ctx context.Context, userID, id uuid.UUID) (*domain.Deposit, error) {
+	d, err := s.depositRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.UserID != userID {
+		return nil, domain.ErrDepositNotFound
+	}
+	return d, nil
+}
+
+// ListUserDeposits returns the user's deposits, newest first.
+func (s *DepositService) ListUserDeposits(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Deposit, int, error) {
+	return s.depositRepo.ListByUser(ctx, userID, limit, offset)
+}
+
+// GetDeposit returns any deposit (back-office).
+func (s *DepositService) GetDeposit(ctx context.Context, id uuid.UUID) (*domain.Deposit, error) {
+	return s.depositRepo.GetByID(ctx, id)
+}
+
+// ListDeposits returns deposits filtered by status and user (back-office).
+func (s *DepositService) ListDeposits(ctx context.Context, status string, userID *uuid.UUID, limit, offset int) ([]*domain.Deposit, int, error) {
+	return s.depositRepo.List(ctx, status, userID, limit, offset)
+}
+
+// MockPay simulates the user completing (or abandoning) a checkout with the
+// mock provider: it builds the provider's signed webhook and applies it
+// through HandleWebhook.  Returns domain.ErrUnknownPaymentProvider when the
+// mock provider is not configured.
+func (s *DepositService) MockPay(ctx context.Context, ref string, succeed bool) (*domain.Deposit, error) {
+	mock, ok := s.provider.(*payment.Mock)
+	if !ok {
+		return nil, domain.ErrUnknownPaymentProvider
+	}
+	d, err := s.depositRepo.GetByProviderRef(ctx, mock.Name(), ref)
+	if err != nil {
+		return nil, err
+	}
+
+	header, body := mock.Webhook(ref, d.Amount, succeed, time.Now())
+	if err = s.HandleWebhook(ctx, mock.Name(), header, body); err != nil {
+		return nil, err
+	}
+	return s.depositRepo.GetByID(ctx, d.ID)
+}
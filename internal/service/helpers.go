@@ -1,6 +1,8 @@
 package service
 
-import "github.com/shopspring/decimal"
+import (
+	"github.com/shopspring/decimal"
+)
 
 // decimalZero returns a fresh decimal.Zero value.
 // Using a helper avoids repeating decimal.NewFromInt(0) callsites.
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/evetabi/prediction/internal/storage"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// KYCService runs identity verification.  Users upload documents, which
+// are kept in file storage, and submit them; risk reviews the submission
+// and sets users.kyc_status to verified or rejected.  Until verified, a
+// user's withdrawals are capped by cfg.KYC (see CheckWithdraw).
+type KYCService struct {
+	db         *sqlx.DB
+	repo       *repository.KYCRepository
+	walletRepo *repository.WalletRepository
+	bankRepo   *repository.BankAccountRepository
+	store      storage.Store
+	auditRepo  *repository.AuditRepository
+	cfg        *config.Config
+}
+
+// NewKYCService creates a KYCService.
+func NewKYCService(
+	db *sqlx.DB,
+	repo *repository.KYCRepository,
+	walletRepo *repository.WalletRepository,
+	bankRepo *repository.BankAccountRepository,
+	store storage.Store,
+	auditRepo *repository.AuditRepository,
+	cfg *config.Config,
+) *KYCService {
+	return &KYCService{
+		db:         db,
+		repo:       repo,
+		walletRepo: walletRepo,
+		bankRepo:   bankRepo,
+		store:      store,
+		auditRepo:  auditRepo,
+		cfg:        cfg,
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// User API
+// ──────────────────────────────────────────────────────────────────────────────
+
+// Overview returns the user's status, latest submission and the documents
+// uploaded since.
+func (s *KYCService) Overview(ctx context.Context, userID uuid.UUID) (*domain.KYCOverview, error) {
+	status, err := s.repo.UserStatus(ctx, s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.repo.LatestSubmission(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := s.repo.UnsubmittedDocuments(ctx, s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.KYCOverview{Status: status, Submission: sub, Documents: docs}, nil
+}
+
+// Upload stores a document for the user's next submission.  The file type
+// is sniffed from the content and must be JPEG, PNG or PDF.  Returns
+// domain.ErrKYCState while a submission is pending or once verified,
+// domain.ErrKYCInvalidDocument or domain.ErrKYCDocumentTooLarge.
+func (s *KYCService) Upload(ctx context.Context, userID uuid.UUID, kind domain.KYCDocumentKind, fileName string, r io.Reader) (*domain.KYCDocument, error) {
+	if !kind.IsValid() {
+		return nil, domain.ErrKYCInvalidDocument
+	}
+	status, err := s.repo.UserStatus(ctx, s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !status.CanSubmit() {
+		return nil, domain.ErrKYCState
+	}
+
+	maxBytes := int64(s.cfg.KYC.MaxDocumentBytes)
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("kyc_service.Upload: read: %w", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, domain.ErrKYCDocumentTooLarge
+	}
+	if len(data) == 0 {
+		return nil, domain.ErrKYCInvalidDocument
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := domain.KYCContentTypes[contentType]
+	if !ok {
+		return nil, domain.ErrKYCInvalidDocument
+	}
+	sum := sha256.Sum256(data)
+
+	id := uuid.New()
+	doc := &domain.KYCDocument{
+		ID:          id,
+		UserID:      userID,
+		Kind:        kind,
+		StorageKey:  "kyc/" + userID.String() + "/" + id.String() + ext,
+		FileName:    cleanFileName(fileName, string(kind)+ext),
+		ContentType: contentType,
+		SizeBytes:   int64(len(data)),
+		SHA256:      hex.EncodeToString(sum[:]),
+		CreatedAt:   time.Now().UTC(),
+	}
+	if _, err = s.store.Put(ctx, doc.StorageKey, bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("kyc_service.Upload: %w", err)
+	}
+	if err = s.repo.CreateDocument(ctx, doc); err != nil {
+		if derr := s.store.Delete(ctx, doc.StorageKey); derr != nil {
+			log.Printf("[kyc] remove orphaned %s: %v", doc.StorageKey, derr)
+		}
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Submit sends the user's uploaded documents for review and sets their
+// status to pending.  Returns domain.ErrKYCState while a submission is
+// pending or once verified, or domain.ErrKYCIdentityMissing when no photo
+// identity document was uploaded.
+func (s *KYCService) Submit(ctx context.Context, userID uuid.UUID) (*domain.KYCSubmission, error) {
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("kyc_service.Submit: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	status, txErr := s.repo.LockUserStatus(ctx, tx, userID)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if !status.CanSubmit() {
+		txErr = domain.ErrKYCState
+		return nil, txErr
+	}
+	docs, txErr := s.repo.UnsubmittedDocuments(ctx, tx, userID)
+	if txErr != nil {
+		return nil, txErr
+	}
+	hasIdentity := false
+	for _, d := range docs {
+		hasIdentity = hasIdentity || d.Kind.IsIdentity()
+	}
+	if !hasIdentity {
+		txErr = domain.ErrKYCIdentityMissing
+		return nil, txErr
+	}
+
+	sub := &domain.KYCSubmission{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Status:      domain.KYCPending,
+		SubmittedAt: time.Now().UTC(),
+	}
+	if txErr = s.repo.CreateSubmission(ctx, tx, sub); txErr != nil {
+		return nil, txErr
+	}
+	if _, txErr = s.repo.SetUserStatus(ctx, tx, userID, domain.KYCPending, ""); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("kyc_service.Submit: commit: %w", txErr)
+	}
+	log.Printf("[kyc] user %s submitted %d documents", userID, len(docs))
+	return sub, nil
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Withdrawal gate
+// ──────────────────────────────────────────────────────────────────────────────
+
+// CheckWithdraw returns domain.ErrKYCRequired when an unverified user's
+// withdrawal of amount is above cfg.KYC.WithdrawThreshold or takes their
+// total withdrawals above cfg.KYC.TotalWithdrawThreshold.  Call it inside
+// the request transaction after the wallet row is locked, so concurrent
+// requests see each other.
+func (s *KYCService) CheckWithdraw(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount decimal.Decimal) error {
+	status, err := s.repo.UserStatus(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	if status == domain.KYCVerified {
+		return nil
+	}
+	withdrawn, err := s.walletRepo.GetWithdrawTotal(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	if domain.KYCRequired(amount, withdrawn,
+		decimal.NewFromFloat(s.cfg.KYC.WithdrawThreshold),
+		decimal.NewFromFloat(s.cfg.KYC.TotalWithdrawThreshold)) {
+		return domain.ErrKYCRequired
+	}
+	return nil
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Back office
+// ──────────────────────────────────────────────────────────────────────────────
+
+// List returns submissions, optionally filtered by status.
+func (s *KYCService) List(ctx context.Context, status string, limit, offset int) ([]*domain.KYCSubmissionItem, int, error) {
+	return s.repo.ListSubmissions(ctx, status, limit, offset)
+}
+
+// Get returns a submission with its documents.  Returns
+// domain.ErrKYCSubmissionNotFound.
+func (s *KYCService) Get(ctx context.Context, id uuid.UUID) (*domain.KYCSubmission, []*domain.KYCDocument, error) {
+	sub, err := s.repo.GetSubmission(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	docs, err := s.repo.SubmissionDocuments(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sub, docs, nil
+}
+
+// OpenDocument returns a document and its file for review.  The caller
+// closes the file.  Returns domain.ErrKYCDocumentNotFound.
+func (s *KYCService) OpenDocument(ctx context.Context, id uuid.UUID) (*domain.KYCDocument, io.ReadCloser, error) {
+	doc, err := s.repo.GetDocument(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := s.store.Open(ctx, doc.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		log.Printf("[kyc] document %s missing from %s storage", id, s.store.Name())
+		return nil, nil, domain.ErrKYCDocumentNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("kyc_service.OpenDocument: %w", err)
+	}
+	return doc, f, nil
+}
+
+// Review approves or rejects a pending submission and sets the user's
+// status to match.  On approval fullName, when given, replaces the user's
+// legal name with the one on the documents.  Returns
+// domain.ErrKYCSubmissionNotFound or domain.ErrKYCState when the
+// submission was already reviewed.
+func (s *KYCService) Review(ctx context.Context, id, adminID uuid.UUID, approve bool, note, fullName string) (*domain.KYCSubmission, error) {
+	status, action := domain.KYCRejected, "kyc.reject"
+	if approve {
+		status, action = domain.KYCVerified, "kyc.approve"
+	} else {
+		fullName = ""
+	}
+	fullName = strings.TrimSpace(fullName)
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("kyc_service.Review: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	before, txErr := s.repo.GetSubmissionForUpdate(ctx, tx, id)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if before.Status != domain.KYCPending {
+		txErr = domain.ErrKYCState
+		return nil, txErr
+	}
+	sub, txErr := s.repo.Review(ctx, tx, id, status, adminID, note)
+	if txErr != nil {
+		return nil, txErr
+	}
+	legalName, txErr := s.repo.SetUserStatus(ctx, tx, sub.UserID, status, fullName)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.recheckBankAccounts(ctx, tx, sub.UserID, legalName, status); txErr != nil {
+		return nil, txErr
+	}
+	details := map[string]any{"user_id": sub.UserID, "note": note}
+	if fullName != "" {
+		details["full_name"] = fullName
+	}
+	entry, txErr := repository.NewAuditEntry(adminID, action, "kyc_submission", id.String(), details)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.auditRepo.Log(ctx, tx, entry); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("kyc_service.Review: commit: %w", txErr)
+	}
+	log.Printf("[kyc] %s set submission %s to %s", adminID, id, status)
+	return sub, nil
+}
+
+// recheckBankAccounts compares the user's saved bank accounts with the legal
+// name on record after a review.  Accounts that were verified automatically
+// go back to pending when the name no longer matches, and pending ones that
+// match are verified once the name is KYC-confirmed.
+func (s *KYCService) recheckBankAccounts(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, fullName string, status domain.KYCStatus) error {
+	accounts, err := s.bankRepo.ListByUserForUpdate(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	for _, a := range accounts {
+		match, st := a.Recheck(fullName, status)
+		if match == a.NameMatch && st == a.Status {
+			continue
+		}
+		if err = s.bankRepo.SetNameMatch(ctx, tx, a.ID, match, st); err != nil {
+			return err
+		}
+		log.Printf("[kyc] bank account %s of user %s re-checked: name %s, %s", a.ID, userID, match, st)
+	}
+	return nil
+}
+
+// cleanFileName keeps the base name of an uploaded file for display,
+// falling back to def when there is none.
+func cleanFileName(name, def string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	if name == "." || name == "/" || name == "" {
+		return def
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
@@ -78,6 +78,7 @@ func (s *MarketService) CreateMarket(ctx context.Context, startTime, endTime tim
 	now := time.Now().UTC()
 	m := &domain.Market{
 		ID:              uuid.New(),
+		Series:          domain.DefaultSeries,
 		Status:          domain.StatusOpen,
 		OpenPrice:       &price,
 		PoolUp:          decimalZero(),
@@ -159,13 +160,14 @@ func (s *MarketService) ListMarkets(ctx context.Context, limit, offset int, stat
 	return markets, total, nil
 }
 
-// GetMarketHistory returns resolved/cancelled markets in descending order.
-func (s *MarketService) GetMarketHistory(ctx context.Context, limit, offset int) ([]*domain.Market, error) {
-	markets, err := s.marketRepo.GetHistory(ctx, limit, offset)
+// GetMarketHistory returns resolved/cancelled markets in descending order
+// and the total count.
+func (s *MarketService) GetMarketHistory(ctx context.Context, limit, offset int) ([]*domain.Market, int, error) {
+	markets, total, err := s.marketRepo.GetHistory(ctx, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("market_service.GetMarketHistory: %w", err)
+		return nil, 0, fmt.Errorf("market_service.GetMarketHistory: %w", err)
 	}
-	return markets, nil
+	return markets, total, nil
 }
 
 // ──────────────────────────────────────────────────────────────────────────────
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/mm"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// ──────────────────────────────────────────────────────────────────────────────
+// MMConfigService
+// ──────────────────────────────────────────────────────────────────────────────
+
+// MMConfigService manages scoped MM parameter overrides stored in mm_config
+// and resolves the effective parameters for a market
+// (market override → series override → global override → env defaults).
+type MMConfigService struct {
+	db         *sqlx.DB
+	repo       *repository.MMConfigRepository
+	marketRepo *repository.MarketRepository
+	auditRepo  *repository.AuditRepository
+	cfg        *config.Config
+}
+
+// NewMMConfigService creates an MMConfigService.
+func NewMMConfigService(
+	db *sqlx.DB,
+	repo *repository.MMConfigRepository,
+	marketRepo *repository.MarketRepository,
+	auditRepo *repository.AuditRepository,
+	cfg *config.Config,
+) *MMConfigService {
+	return &MMConfigService{
+		db:         db,
+		repo:       repo,
+		marketRepo: marketRepo,
+		auditRepo:  auditRepo,
+		cfg:        cfg,
+	}
+}
+
+// Defaults returns the env-derived parameters that apply when no override
+// exists at any scope.
+func (s *MMConfigService) Defaults() domain.MMParams {
+	return domain.MMParams{
+		ThresholdRatio: decimal.NewFromFloat(s.cfg.MM.TriggerThreshold),
+		MaxExposure:    decimal.NewFromFloat(s.cfg.MM.MaxExposurePerMarket),
+		MinBet:         decimal.NewFromFloat(s.cfg.MM.MinMMBet),
+		SeedRatio:      decimal.NewFromFloat(s.cfg.MM.SeedRatio),
+		BalanceRatio:   decimal.NewFromFloat(s.cfg.MM.BalanceRatio),
+		TargetOdds:     decimal.NewFromFloat(s.cfg.MM.TargetOdds),
+		Strategy:       s.cfg.MM.Strategy,
+	}
+}
+
+// Effective resolves the parameters the MM should use for market.
+func (s *MMConfigService) Effective(ctx context.Context, market *domain.Market) (domain.MMParams, error) {
+	global, series, override, err := s.repo.GetActiveLayers(ctx, market.ID, market.Series)
+	if err != nil {
+		return domain.MMParams{}, fmt.Errorf("mm_config_service.Effective: %w", err)
+	}
+	return domain.ResolveMMParams(s.Defaults(), global, series, override), nil
+}
+
+// EffectiveForMarket loads the market and resolves its parameters.
+func (s *MMConfigService) EffectiveForMarket(ctx context.Context, marketID uuid.UUID) (domain.MMParams, error) {
+	market, err := s.marketRepo.GetByID(ctx, marketID)
+	if err != nil {
+		return domain.MMParams{}, fmt.Errorf("mm_config_service.EffectiveForMarket: %w", err)
+	}
+	return s.Effective(ctx, market)
+}
+
+// EffectiveForSeries resolves the parameters a new market of series would get.
+func (s *MMConfigService) EffectiveForSeries(ctx context.Context, series string) (domain.MMParams, error) {
+	global, seriesCfg, _, err := s.repo.GetActiveLayers(ctx, uuid.Nil, series)
+	if err != nil {
+		return domain.MMParams{}, fmt.Errorf("mm_config_service.EffectiveForSeries: %w", err)
+	}
+	return domain.ResolveMMParams(s.Defaults(), global, seriesCfg), nil
+}
+
+// ListActive returns every active override.
+func (s *MMConfigService) ListActive(ctx context.Context) ([]*domain.MMConfig, error) {
+	return s.repo.ListActive(ctx)
+}
+
+// History returns the versioned rows for one scope, newest first.
+func (s *MMConfigService) History(ctx context.Context, marketID *uuid.UUID, series *string, limit, offset int) ([]*domain.MMConfig, int, error) {
+	return s.repo.History(ctx, marketID, series, limit, offset)
+}
+
+// Set replaces the override for c's scope with c.  Fields left nil inherit
+// from the next broader scope.  The previous row is retired rather than
+// updated so the full history is preserved.
+func (s *MMConfigService) Set(ctx context.Context, c *domain.MMConfig, adminID uuid.UUID) (*domain.MMConfig, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if c.Strategy != nil {
+		if _, err := mm.Lookup(*c.Strategy); err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidMMConfig, err)
+		}
+	}
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("mm_config_service.Set: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	prev, txErr := s.repo.GetActiveForUpdate(ctx, tx, c.MarketID, c.Series)
+	switch {
+	case errors.Is(txErr, domain.ErrMMConfigNotFound):
+		prev, txErr = nil, nil
+	case txErr != nil:
+		return nil, fmt.Errorf("mm_config_service.Set: %w", txErr)
+	default:
+		if txErr = s.repo.Deactivate(ctx, tx, prev.ID); txErr != nil {
+			return nil, fmt.Errorf("mm_config_service.Set: %w", txErr)
+		}
+	}
+
+	c.ID = uuid.New()
+	c.IsActive = true
+	c.CreatedAt = time.Now()
+	if adminID != uuid.Nil {
+		c.CreatedBy = &adminID
+	}
+	if txErr = s.repo.Create(ctx, tx, c); txErr != nil {
+		return nil, fmt.Errorf("mm_config_service.Set: %w", txErr)
+	}
+
+	if txErr = s.audit(ctx, tx, adminID, "mm_config.set", c.ScopeKey(), prev, c); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("mm_config_service.Set: commit: %w", txErr)
+	}
+
+	log.Printf("[mm] config override for %s set by %s", c.ScopeKey(), adminID)
+	return c, nil
+}
+
+// Clear retires the active override for a scope so it inherits again.
+func (s *MMConfigService) Clear(ctx context.Context, marketID *uuid.UUID, series *string, adminID uuid.UUID) error {
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return fmt.Errorf("mm_config_service.Clear: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	prev, txErr := s.repo.GetActiveForUpdate(ctx, tx, marketID, series)
+	if txErr != nil {
+		return txErr
+	}
+	if txErr = s.repo.Deactivate(ctx, tx, prev.ID); txErr != nil {
+		return fmt.Errorf("mm_config_service.Clear: %w", txErr)
+	}
+	if txErr = s.audit(ctx, tx, adminID, "mm_config.clear", prev.ScopeKey(), prev, nil); txErr != nil {
+		return txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return fmt.Errorf("mm_config_service.Clear: commit: %w", txErr)
+	}
+
+	log.Printf("[mm] config override for %s cleared by %s", prev.ScopeKey(), adminID)
+	return nil
+}
+
+// audit records a before/after snapshot of an override change.
+func (s *MMConfigService) audit(ctx context.Context, tx *sqlx.Tx, adminID uuid.UUID, action, scope string, before, after *domain.MMConfig) error {
+	entry, err := repository.NewAuditEntry(adminID, action, "mm_config", scope,
+		map[string]any{"before": before, "after": after})
+	if err != nil {
+		return fmt.Errorf("mm_config_service: %w", err)
+	}
+	if err = s.auditRepo.Log(ctx, tx, entry); err != nil {
+		return fmt.Errorf("mm_config_service: %w", err)
+	}
+	return nil
+}
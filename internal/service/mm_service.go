@@ -2,12 +2,17 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"os"
 	"sync"
+	"time"
 
 	"github.com/evetabi/prediction/internal/config"
 	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/ledger"
+	"github.com/evetabi/prediction/internal/mm"
 	"github.com/evetabi/prediction/internal/repository"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -20,107 +25,228 @@ type MMStats struct {
 	DailyPnL           decimal.Decimal `json:"daily_pnl"`           // realized profit - loss today
 	TotalInterventions int             `json:"total_interventions"` // count today
 	PlatformReserve    decimal.Decimal `json:"platform_reserve"`    // current balance
+	Risk               domain.MMPnL    `json:"risk"`                // realized + open PnL feeding the loss budget
+	BudgetUsage        decimal.Decimal `json:"budget_usage"`        // 0–1 share of the tighter loss budget consumed
+	ThrottleScale      decimal.Decimal `json:"throttle_scale"`      // factor applied to new injections (1 = none)
 }
 
 // ──────────────────────────────────────────────────────────────────────────────
 // MMService
 // ──────────────────────────────────────────────────────────────────────────────
 
+// Rebalance decisions, counted per process and reported by RebalanceStats.
+const (
+	DecisionInjected = "injected"  // proposal passed every limit and was placed
+	DecisionNoAction = "no_action" // strategy found the pool balanced enough
+	DecisionLimited  = "limited"   // proposal trimmed to nothing by min bet / market cap
+	DecisionRejected = "rejected"  // proposal blocked by a hard risk limit or error
+	DecisionSkipped  = "skipped"   // market closed or kill switch off
+)
+
+// MMRebalanceStats reports the rebalance queue and decision counters of one
+// process.
+type MMRebalanceStats struct {
+	Queue     RebalanceQueueStats `json:"queue"`
+	Decisions map[string]int64    `json:"decisions"`
+}
+
 // MMService implements the Rebalancer interface (declared in bet_service.go).
 // It monitors pool imbalances and injects liquidity from the platform wallet
 // to keep odds competitive on both sides.
 type MMService struct {
-	db         *sqlx.DB
-	betRepo    *repository.BetRepository
-	marketRepo *repository.MarketRepository
-	walletRepo *repository.WalletRepository
-	cfg        *config.Config
-	mu         sync.Mutex // prevents concurrent rebalances for the same market
+	db          *sqlx.DB
+	betRepo     *repository.BetRepository
+	marketRepo  *repository.MarketRepository
+	walletRepo  *repository.WalletRepository
+	ledger      *ledger.Ledger
+	switchRepo  *repository.MMSwitchRepository
+	auditRepo   *repository.AuditRepository
+	mmConfigSvc *MMConfigService
+	cfg         *config.Config
+	queue       *RebalanceQueue // one rebalance per market at a time
+
+	decisionMu sync.Mutex
+	decisions  map[string]int64
+
+	// kill-switch cache, refreshed from mm_switches (see mm_switch.go)
+	switchMu        sync.RWMutex
+	globalEnabled   bool
+	disabledMarkets map[uuid.UUID]bool
 }
 
-// NewMMService creates an MMService.
+// NewMMService creates an MMService.  The kill-switch cache starts enabled;
+// call LoadSwitches before serving traffic, and StartRebalancer in the
+// process that handles bets.
 func NewMMService(
 	db *sqlx.DB,
 	betRepo *repository.BetRepository,
 	marketRepo *repository.MarketRepository,
 	walletRepo *repository.WalletRepository,
+	ledger *ledger.Ledger,
+	switchRepo *repository.MMSwitchRepository,
+	auditRepo *repository.AuditRepository,
+	mmConfigSvc *MMConfigService,
 	cfg *config.Config,
 ) *MMService {
-	return &MMService{
-		db:         db,
-		betRepo:    betRepo,
-		marketRepo: marketRepo,
-		walletRepo: walletRepo,
-		cfg:        cfg,
+	s := &MMService{
+		db:              db,
+		betRepo:         betRepo,
+		marketRepo:      marketRepo,
+		walletRepo:      walletRepo,
+		ledger:          ledger,
+		switchRepo:      switchRepo,
+		auditRepo:       auditRepo,
+		mmConfigSvc:     mmConfigSvc,
+		cfg:             cfg,
+		globalEnabled:   true,
+		disabledMarkets: map[uuid.UUID]bool{},
+		decisions:       map[string]int64{},
 	}
+	s.queue = NewRebalanceQueue(s.Rebalance, cfg.MM.RebalanceWorkers)
+	return s
 }
 
 // ──────────────────────────────────────────────────────────────────────────────
 // Rebalance — implements Rebalancer interface
 // ──────────────────────────────────────────────────────────────────────────────
 
-// Rebalance inspects the current market pool and injects liquidity on the
-// thin side if the imbalance exceeds the configured thresholds.
-// Uses TryLock so overlapping async calls are silently skipped.
+// StartRebalancer starts the rebalance queue workers; they stop with ctx.
+func (s *MMService) StartRebalancer(ctx context.Context) {
+	s.queue.Start(ctx)
+}
+
+// ScheduleRebalance queues a rebalance for marketID.  Requests for a market
+// that is already waiting collapse into one run.
+func (s *MMService) ScheduleRebalance(marketID uuid.UUID) {
+	s.queue.Enqueue(marketID)
+}
+
+// Rebalance asks the market's configured Strategy for a proposal and passes
+// it through placePlatformBet's risk limits.  Parameters and strategy are
+// resolved per market by MMConfigService.
+// Called by the rebalance queue, which guarantees one run per market at a
+// time; bets should go through ScheduleRebalance.
 func (s *MMService) Rebalance(ctx context.Context, marketID uuid.UUID) error {
-	// TryLock — if another rebalance is already running, skip this call.
-	if !s.mu.TryLock() {
-		return nil
-	}
-	defer s.mu.Unlock()
+	decision, err := s.rebalance(ctx, marketID)
+	s.decisionMu.Lock()
+	s.decisions[decision]++
+	s.decisionMu.Unlock()
+	return err
+}
 
+func (s *MMService) rebalance(ctx context.Context, marketID uuid.UUID) (string, error) {
 	// Load market and confirm it is still open.
 	market, err := s.marketRepo.GetByID(ctx, marketID)
 	if err != nil {
-		return fmt.Errorf("mm_service.Rebalance: get market: %w", err)
+		return DecisionRejected, fmt.Errorf("mm_service.Rebalance: get market: %w", err)
 	}
 	if !market.IsOpen() {
-		return nil // market already closed — nothing to do
+		return DecisionSkipped, nil // market already closed — nothing to do
+	}
+	if !s.MMEnabled(marketID) {
+		return DecisionSkipped, nil // kill switch off
 	}
 
-	up := market.PoolUp
-	down := market.PoolDown
+	params, err := s.mmConfigSvc.Effective(ctx, market)
+	if err != nil {
+		return DecisionRejected, fmt.Errorf("mm_service.Rebalance: %w", err)
+	}
 
-	// ── Decision tree ────────────────────────────────────────────────────────
+	snap := mm.SnapshotOf(market, time.Now())
+	snap.MMStakeUp, snap.MMStakeDown, err = s.betRepo.GetMMStakesBySide(ctx, marketID)
+	if err != nil {
+		return DecisionRejected, fmt.Errorf("mm_service.Rebalance: %w", err)
+	}
 
-	triggerThreshold := decimal.NewFromFloat(s.cfg.MM.TriggerThreshold)
-	minBalanceRatio := decimal.NewFromFloat(0.20) // target thin side at 20 % of thick
-	seedRatio := decimal.NewFromFloat(0.30)       // seed at 30 % of existing side
-	minBet := decimal.NewFromFloat(s.cfg.MM.MinMMBet)
+	// ── Strategy proposes, risk limits dispose ───────────────────────────────
+	proposal := s.strategyFor(params).Propose(snap, params)
+	if proposal == nil {
+		return DecisionNoAction, nil // balanced enough — nothing to do
+	}
 
-	switch {
-	// (a) DOWN pool is completely empty, UP has bets → seed DOWN
-	case down.IsZero() && !up.IsZero():
-		seed := up.Mul(seedRatio).RoundDown(4)
-		_ = s.placePlatformBet(ctx, marketID, domain.OutcomeDown, seed, "seed_down")
+	placed, err := s.placePlatformBet(ctx, params, market, proposal.Outcome, proposal.Amount, proposal.Reason)
+	if err != nil {
+		log.Printf("[mm] proposal rejected for market %s (%s %s on %s): %v",
+			marketID, proposal.Reason, proposal.Amount.StringFixed(4), proposal.Outcome, err)
+		return DecisionRejected, err
+	}
+	if !placed {
+		return DecisionLimited, nil
+	}
+	return DecisionInjected, nil
+}
 
-	// (b) UP pool is completely empty, DOWN has bets → seed UP
-	case up.IsZero() && !down.IsZero():
-		seed := down.Mul(seedRatio).RoundDown(4)
-		_ = s.placePlatformBet(ctx, marketID, domain.OutcomeUp, seed, "seed_up")
+// RebalanceStats returns this process's queue metrics and decision counts.
+func (s *MMService) RebalanceStats() MMRebalanceStats {
+	s.decisionMu.Lock()
+	decisions := make(map[string]int64, len(s.decisions))
+	for k, v := range s.decisions {
+		decisions[k] = v
+	}
+	s.decisionMu.Unlock()
 
-	// (c) DOWN severely under-represented relative to UP
-	case !up.IsZero() && down.Div(up).LessThan(triggerThreshold):
-		target := up.Mul(minBalanceRatio).RoundDown(4)
-		needed := target.Sub(down)
-		if needed.GreaterThan(minBet) {
-			_ = s.placePlatformBet(ctx, marketID, domain.OutcomeDown, needed, "rebalance_down")
-		}
+	return MMRebalanceStats{Queue: s.queue.Stats(), Decisions: decisions}
+}
 
-	// (d) UP severely under-represented relative to DOWN
-	case !down.IsZero() && up.Div(down).LessThan(triggerThreshold):
-		target := down.Mul(minBalanceRatio).RoundDown(4)
-		needed := target.Sub(up)
-		if needed.GreaterThan(minBet) {
-			_ = s.placePlatformBet(ctx, marketID, domain.OutcomeUp, needed, "rebalance_up")
+// rebalanceReportInterval is how often ReportRebalanceStats publishes.
+const rebalanceReportInterval = 15 * time.Second
+
+// ReportRebalanceStats periodically writes RebalanceStats to
+// mm_rebalance_stats so the back-office can read them.  Blocks until ctx is
+// cancelled.
+func (s *MMService) ReportRebalanceStats(ctx context.Context) {
+	host, _ := os.Hostname()
+	instance := fmt.Sprintf("%s:%d", host, os.Getpid())
+	startedAt := time.Now()
+
+	ticker := time.NewTicker(rebalanceReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats, err := json.Marshal(s.RebalanceStats())
+			if err != nil {
+				log.Printf("[mm] marshal rebalance stats: %v", err)
+				continue
+			}
+			_, err = s.db.ExecContext(ctx, `
+				INSERT INTO mm_rebalance_stats (instance, stats, started_at, updated_at)
+				VALUES ($1, $2, $3, now())
+				ON CONFLICT (instance) DO UPDATE
+				SET stats = EXCLUDED.stats, updated_at = now()`,
+				instance, stats, startedAt)
+			if err != nil {
+				log.Printf("[mm] publish rebalance stats: %v", err)
+			}
 		}
+	}
+}
 
-	// (e) Pools are balanced — nothing to do
-	default:
-		return nil
+// RebalanceReports returns the latest snapshot from every API server process
+// seen within maxAge, newest first.
+func (s *MMService) RebalanceReports(ctx context.Context, maxAge time.Duration) ([]*domain.MMRebalanceReport, error) {
+	var reports []*domain.MMRebalanceReport
+	err := s.db.SelectContext(ctx, &reports, `
+		SELECT * FROM mm_rebalance_stats
+		WHERE updated_at >= $1
+		ORDER BY updated_at DESC`, time.Now().Add(-maxAge))
+	if err != nil {
+		return nil, fmt.Errorf("mm_service.RebalanceReports: %w", err)
 	}
+	return reports, nil
+}
 
-	return nil
+// strategyFor returns the strategy selected by params, falling back to the
+// default when the configured name is unknown.
+func (s *MMService) strategyFor(params domain.MMParams) mm.Strategy {
+	strategy, err := mm.Lookup(params.Strategy)
+	if err != nil {
+		log.Printf("[mm] %v — falling back to %s", err, mm.DefaultStrategy)
+		strategy, _ = mm.Lookup(mm.DefaultStrategy)
+	}
+	return strategy
 }
 
 // ──────────────────────────────────────────────────────────────────────────────
@@ -129,61 +255,72 @@ func (s *MMService) Rebalance(ctx context.Context, marketID uuid.UUID) error {
 
 // placePlatformBet applies all limit guards, then deducts from the platform
 // wallet, updates the market pool, and records the MM position — all within
-// a single PostgreSQL transaction.
+// a single PostgreSQL transaction.  placed is false when the soft limits
+// (minimum bet, per-market cap) leave nothing to inject.
 func (s *MMService) placePlatformBet(
 	ctx context.Context,
-	marketID uuid.UUID,
+	params domain.MMParams,
+	market *domain.Market,
 	outcome domain.Outcome,
 	amount decimal.Decimal,
 	reason string,
-) error {
-	minBet := decimal.NewFromFloat(s.cfg.MM.MinMMBet)
-	maxExposure := decimal.NewFromFloat(s.cfg.MM.MaxExposurePerMarket)
-	maxDailyLoss := decimal.NewFromFloat(s.cfg.MM.MaxDailyLoss)
+) (placed bool, err error) {
+	marketID := market.ID
+	minBet := params.MinBet
+	maxExposure := params.MaxExposure
 	minReserve := decimal.NewFromFloat(s.cfg.MM.MinReserve)
 
-	// Guard 1: below minimum bet size
-	if amount.LessThan(minBet) {
-		return nil
-	}
-
-	// Guard 2: daily loss limit
-	dailyExposure, err := s.betRepo.GetPlatformDailyExposure(ctx)
+	// Guard 1: loss budget — throttle as it is consumed, then make sure the
+	// worst case including this injection still fits
+	budget := s.riskBudget()
+	pnl, exposures, err := s.riskPnL(ctx, s.db)
 	if err != nil {
-		return fmt.Errorf("mm_service.placePlatformBet: daily exposure: %w", err)
+		return false, fmt.Errorf("mm_service.placePlatformBet: %w", err)
 	}
-	if dailyExposure.Add(amount).GreaterThan(maxDailyLoss) {
-		log.Printf("[mm] DAILY LOSS LIMIT REACHED: exposure=%s limit=%s — suspending MM",
-			dailyExposure.StringFixed(4), maxDailyLoss.StringFixed(4))
-		return domain.ErrMMDailyLossExceeded
+	if scale := budget.Scale(pnl); scale.LessThan(decimal.NewFromInt(1)) {
+		log.Printf("[mm] risk budget %s%% used — throttling %s to %s%%",
+			budget.Usage(pnl).Mul(decimal.NewFromInt(100)).StringFixed(1),
+			amount.StringFixed(4), scale.Mul(decimal.NewFromInt(100)).StringFixed(1))
+		amount = amount.Mul(scale).RoundDown(4)
+	}
+	if err = budget.Check(s.withProposal(pnl, exposures, market, outcome, amount)); err != nil {
+		log.Printf("[mm] LOSS LIMIT REACHED for market %s: realized day=%s week=%s open worst=%s — %v",
+			marketID, pnl.RealizedDay.StringFixed(4), pnl.RealizedWeek.StringFixed(4),
+			pnl.OpenWorstCase.StringFixed(4), err)
+		return false, err
+	}
+
+	// Guard 2: below minimum bet size
+	if amount.LessThan(minBet) {
+		return false, nil
 	}
 
 	// Guard 3: platform reserve check
 	platformWallet, err := s.walletRepo.GetPlatformWallet(ctx)
 	if err != nil {
-		return fmt.Errorf("mm_service.placePlatformBet: platform wallet: %w", err)
+		return false, fmt.Errorf("mm_service.placePlatformBet: platform wallet: %w", err)
 	}
 	if platformWallet.Balance.LessThan(minReserve.Add(amount)) {
 		log.Printf("[mm] ALARM: platform reserve %s below minimum %s — MM blocked",
 			platformWallet.Balance.StringFixed(4), minReserve.StringFixed(4))
-		return domain.ErrMMReserveInsufficient
+		return false, domain.ErrMMReserveInsufficient
 	}
 
 	// Guard 4: per-market exposure cap
 	marketExposure, err := s.walletRepo.GetMarketMMExposure(ctx, marketID)
 	if err != nil {
-		return fmt.Errorf("mm_service.placePlatformBet: market exposure: %w", err)
+		return false, fmt.Errorf("mm_service.placePlatformBet: market exposure: %w", err)
 	}
 	if marketExposure.Add(amount).GreaterThan(maxExposure) {
 		log.Printf("[mm] per-market cap reached for market %s: exposure=%s cap=%s",
 			marketID, marketExposure.StringFixed(4), maxExposure.StringFixed(4))
-		return nil
+		return false, nil
 	}
 
 	// ── Atomic transaction ────────────────────────────────────────────────────
 	tx, txErr := s.db.BeginTxx(ctx, nil)
 	if txErr != nil {
-		return fmt.Errorf("mm_service.placePlatformBet: begin tx: %w", txErr)
+		return false, fmt.Errorf("mm_service.placePlatformBet: begin tx: %w", txErr)
 	}
 	defer func() {
 		if txErr != nil {
@@ -191,28 +328,135 @@ func (s *MMService) placePlatformBet(
 		}
 	}()
 
-	// Deduct from platform wallet (FOR UPDATE internally)
-	if txErr = s.walletRepo.DeductPlatformBalance(ctx, tx, amount); txErr != nil {
-		return fmt.Errorf("mm_service.placePlatformBet: deduct platform: %w", txErr)
+	// Lock order: the market row before the platform wallet and the escrow
+	// account, as in settlement, so an injection racing the resolution waits
+	// for it instead of deadlocking.  The budget is re-checked below against
+	// the pools read under this lock.
+	if market, txErr = s.marketRepo.LockOpen(ctx, tx, marketID); txErr != nil {
+		return false, txErr
+	}
+
+	// Re-check the kill switch inside the tx: the cache may lag a NOTIFY
+	enabled, txErr := s.switchRepo.IsEnabled(ctx, tx, marketID)
+	if txErr != nil {
+		return false, fmt.Errorf("mm_service.placePlatformBet: %w", txErr)
+	}
+	if !enabled {
+		txErr = domain.ErrMMDisabled
+		return false, txErr
+	}
+
+	// Re-check the loss budget and reserve under the platform wallet lock:
+	// rebalance workers inject on several markets at once, and each one
+	// passed the guards above without seeing the others' stakes
+	platformWallet, txErr = s.walletRepo.LockPlatformWallet(ctx, tx)
+	if txErr != nil {
+		return false, fmt.Errorf("mm_service.placePlatformBet: lock platform wallet: %w", txErr)
+	}
+	pnl, exposures, txErr = s.riskPnL(ctx, tx)
+	if txErr != nil {
+		return false, fmt.Errorf("mm_service.placePlatformBet: %w", txErr)
+	}
+	if txErr = budget.Check(s.withProposal(pnl, exposures, market, outcome, amount)); txErr != nil {
+		log.Printf("[mm] LOSS LIMIT REACHED for market %s under lock: open worst=%s — %v",
+			marketID, pnl.OpenWorstCase.StringFixed(4), txErr)
+		return false, txErr
+	}
+	if platformWallet.Balance.LessThan(minReserve.Add(amount)) {
+		log.Printf("[mm] ALARM: platform reserve %s below minimum %s — MM blocked",
+			platformWallet.Balance.StringFixed(4), minReserve.StringFixed(4))
+		txErr = domain.ErrMMReserveInsufficient
+		return false, txErr
 	}
 
 	// Add amount to the market pool
 	if txErr = s.marketRepo.UpdatePools(ctx, tx, marketID, outcome, amount); txErr != nil {
-		return fmt.Errorf("mm_service.placePlatformBet: update pools: %w", txErr)
+		return false, fmt.Errorf("mm_service.placePlatformBet: update pools: %w", txErr)
+	}
+
+	// Move the stake from the platform wallet into the market escrow
+	entry := ledger.NewEntry(domain.TxMMStake, marketID,
+		fmt.Sprintf("MM %s on %s (%s)", amount.StringFixed(4), outcome, reason)).
+		Transfer(ledger.HouseMM, ledger.MarketEscrow(marketID), amount)
+	if txErr = s.ledger.Post(ctx, tx, entry); txErr != nil {
+		return false, fmt.Errorf("mm_service.placePlatformBet: post stake: %w", txErr)
 	}
 
 	// Record the MM position (mm_positions table)
-	if txErr = s.betRepo.CreatePlatformBet(ctx, marketID, outcome, amount, reason); txErr != nil {
-		return fmt.Errorf("mm_service.placePlatformBet: create platform bet: %w", txErr)
+	if txErr = s.betRepo.CreatePlatformBet(ctx, tx, marketID, outcome, amount, reason); txErr != nil {
+		return false, fmt.Errorf("mm_service.placePlatformBet: create platform bet: %w", txErr)
 	}
 
 	if txErr = tx.Commit(); txErr != nil {
-		return fmt.Errorf("mm_service.placePlatformBet: commit: %w", txErr)
+		return false, fmt.Errorf("mm_service.placePlatformBet: commit: %w", txErr)
 	}
 
 	log.Printf("[mm] injected %s TRY on %s for market %s (reason=%s)",
 		amount.StringFixed(4), outcome, marketID, reason)
-	return nil
+	return true, nil
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Loss budget
+// ──────────────────────────────────────────────────────────────────────────────
+
+// riskBudget builds the MM loss budget from configuration.
+func (s *MMService) riskBudget() domain.MMRiskBudget {
+	return domain.MMRiskBudget{
+		DailyLimit:    decimal.NewFromFloat(s.cfg.MM.MaxDailyLoss),
+		WeeklyLimit:   decimal.NewFromFloat(s.cfg.MM.MaxWeeklyLoss),
+		ThrottleStart: decimal.NewFromFloat(s.cfg.MM.ThrottleStart),
+	}
+}
+
+// riskPnL loads realized PnL for the current day and week plus the
+// mark-to-market and worst-case value of every open MM position, reading
+// through q (the DB or a transaction).
+func (s *MMService) riskPnL(ctx context.Context, q sqlx.QueryerContext) (domain.MMPnL, []domain.MMExposure, error) {
+	now := time.Now().In(s.cfg.Location())
+	var pnl domain.MMPnL
+	var err error
+
+	if pnl.RealizedDay, err = s.betRepo.GetMMRealizedPnL(ctx, q, domain.StartOfDay(now)); err != nil {
+		return pnl, nil, err
+	}
+	if pnl.RealizedWeek, err = s.betRepo.GetMMRealizedPnL(ctx, q, domain.StartOfWeek(now)); err != nil {
+		return pnl, nil, err
+	}
+	exposures, err := s.betRepo.GetOpenMMExposures(ctx, q)
+	if err != nil {
+		return pnl, nil, err
+	}
+
+	commission := decimal.NewFromFloat(s.cfg.Wallet.CommissionRate)
+	for _, e := range exposures {
+		pnl.OpenMTM = pnl.OpenMTM.Add(e.MarkToMarket(commission))
+		pnl.OpenWorstCase = pnl.OpenWorstCase.Add(e.WorstCase(commission))
+	}
+	return pnl, exposures, nil
+}
+
+// withProposal returns pnl with the open worst case recomputed as if amount
+// had been injected on outcome in market.
+func (s *MMService) withProposal(
+	pnl domain.MMPnL,
+	exposures []domain.MMExposure,
+	market *domain.Market,
+	outcome domain.Outcome,
+	amount decimal.Decimal,
+) domain.MMPnL {
+	commission := decimal.NewFromFloat(s.cfg.Wallet.CommissionRate)
+	current := domain.MMExposure{MarketID: market.ID, PoolUp: market.PoolUp, PoolDown: market.PoolDown}
+	for _, e := range exposures {
+		if e.MarketID == market.ID {
+			current = e
+			break
+		}
+	}
+	pnl.OpenWorstCase = pnl.OpenWorstCase.
+		Sub(current.WorstCase(commission)).
+		Add(current.With(outcome, amount).WorstCase(commission))
+	return pnl
 }
 
 // ──────────────────────────────────────────────────────────────────────────────
@@ -221,33 +465,31 @@ func (s *MMService) placePlatformBet(
 
 // GetMMStats returns aggregated MM statistics for the monitoring dashboard.
 func (s *MMService) GetMMStats(ctx context.Context) (*MMStats, error) {
+	today := domain.StartOfDay(time.Now().In(s.cfg.Location()))
+
 	// Daily spend = sum of all open + closed mm_positions today
 	var dailySpend decimal.Decimal
 	err := s.db.GetContext(ctx, &dailySpend, `
 		SELECT COALESCE(SUM(amount), 0)
 		FROM mm_positions
-		WHERE created_at >= date_trunc('day', now())`)
+		WHERE created_at >= $1`, today)
 	if err != nil {
 		return nil, fmt.Errorf("mm_service.GetMMStats: daily spend: %w", err)
 	}
 
-	// Daily P&L = sum of pnl for positions closed today
-	var dailyPnL decimal.Decimal
-	err = s.db.GetContext(ctx, &dailyPnL, `
-		SELECT COALESCE(SUM(pnl), 0)
-		FROM mm_positions
-		WHERE closed_at >= date_trunc('day', now())
-		  AND status IN ('won', 'lost')`)
+	// Realized and open P&L as seen by the loss budget
+	risk, _, err := s.riskPnL(ctx, s.db)
 	if err != nil {
-		return nil, fmt.Errorf("mm_service.GetMMStats: daily pnl: %w", err)
+		return nil, fmt.Errorf("mm_service.GetMMStats: risk: %w", err)
 	}
+	budget := s.riskBudget()
 
 	// Total interventions today
 	var interventions int
 	err = s.db.GetContext(ctx, &interventions, `
 		SELECT COUNT(*)
 		FROM mm_positions
-		WHERE created_at >= date_trunc('day', now())`)
+		WHERE created_at >= $1`, today)
 	if err != nil {
 		return nil, fmt.Errorf("mm_service.GetMMStats: interventions: %w", err)
 	}
@@ -260,8 +502,11 @@ func (s *MMService) GetMMStats(ctx context.Context) (*MMStats, error) {
 
 	return &MMStats{
 		DailySpend:         dailySpend,
-		DailyPnL:           dailyPnL,
+		DailyPnL:           risk.RealizedDay,
 		TotalInterventions: interventions,
 		PlatformReserve:    platformWallet.Balance,
+		Risk:               risk,
+		BudgetUsage:        budget.Usage(risk),
+		ThrottleScale:      budget.Scale(risk),
 	}, nil
 }
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/notify"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/google/uuid"
+)
+
+// switchResyncInterval is a safety net in case a NOTIFY is missed.
+const switchResyncInterval = 30 * time.Second
+
+// ──────────────────────────────────────────────────────────────────────────────
+// MM kill switch
+// ──────────────────────────────────────────────────────────────────────────────
+
+// MMEnabled reports whether the MM may act on marketID according to the
+// in-process cache.  The global switch overrides per-market switches.
+func (s *MMService) MMEnabled(marketID uuid.UUID) bool {
+	s.switchMu.RLock()
+	defer s.switchMu.RUnlock()
+	return s.globalEnabled && !s.disabledMarkets[marketID]
+}
+
+// GlobalMMEnabled reports the cached state of the global switch.
+func (s *MMService) GlobalMMEnabled() bool {
+	s.switchMu.RLock()
+	defer s.switchMu.RUnlock()
+	return s.globalEnabled
+}
+
+// LoadSwitches refreshes the kill-switch cache from mm_switches.
+func (s *MMService) LoadSwitches(ctx context.Context) error {
+	rows, err := s.switchRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("mm_service.LoadSwitches: %w", err)
+	}
+
+	global := true
+	disabled := make(map[uuid.UUID]bool)
+	for _, sw := range rows {
+		switch {
+		case sw.MarketID == nil:
+			global = sw.Enabled
+		case !sw.Enabled:
+			disabled[*sw.MarketID] = true
+		}
+	}
+
+	s.switchMu.Lock()
+	s.globalEnabled = global
+	s.disabledMarkets = disabled
+	s.switchMu.Unlock()
+	return nil
+}
+
+// WatchSwitches reloads the cache whenever another process toggles a switch
+// (Postgres NOTIFY) and every switchResyncInterval as a fallback.  Blocks
+// until ctx is cancelled.
+func (s *MMService) WatchSwitches(ctx context.Context) {
+	reload := func() {
+		if err := s.LoadSwitches(ctx); err != nil {
+			log.Printf("[mm] kill-switch reload failed: %v", err)
+		}
+	}
+
+	go func() {
+		for ctx.Err() == nil {
+			err := notify.Listen(ctx, s.cfg.DB.DSN, notify.ChannelMMSwitch, func(string) { reload() })
+			if err != nil {
+				log.Printf("[mm] kill-switch listener: %v", err)
+				time.Sleep(5 * time.Second)
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(switchResyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reload()
+		}
+	}
+}
+
+// Switches returns every persisted switch row.
+func (s *MMService) Switches(ctx context.Context) ([]*domain.MMSwitch, error) {
+	return s.switchRepo.List(ctx)
+}
+
+// SetEnabled persists a kill-switch change for the global scope (marketID
+// nil) or one market, records who made it, and notifies every process.
+func (s *MMService) SetEnabled(
+	ctx context.Context,
+	marketID *uuid.UUID,
+	enabled bool,
+	reason string,
+	adminID uuid.UUID,
+) (*domain.MMSwitch, error) {
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("mm_service.SetEnabled: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	prev, txErr := s.switchRepo.Get(ctx, tx, marketID)
+	if txErr != nil {
+		return nil, fmt.Errorf("mm_service.SetEnabled: %w", txErr)
+	}
+
+	sw := &domain.MMSwitch{
+		ID:        uuid.New(),
+		MarketID:  marketID,
+		Enabled:   enabled,
+		Reason:    reason,
+		ChangedAt: time.Now(),
+	}
+	if adminID != uuid.Nil {
+		sw.ChangedBy = &adminID
+	}
+	if txErr = s.switchRepo.Upsert(ctx, tx, sw); txErr != nil {
+		return nil, fmt.Errorf("mm_service.SetEnabled: %w", txErr)
+	}
+
+	entry, txErr := repository.NewAuditEntry(adminID, "mm_switch.set", "mm_switch", sw.ScopeKey(),
+		map[string]any{"before": prev, "after": sw})
+	if txErr != nil {
+		return nil, fmt.Errorf("mm_service.SetEnabled: %w", txErr)
+	}
+	if txErr = s.auditRepo.Log(ctx, tx, entry); txErr != nil {
+		return nil, fmt.Errorf("mm_service.SetEnabled: %w", txErr)
+	}
+	if txErr = notify.Publish(ctx, tx, notify.ChannelMMSwitch, sw.ScopeKey()); txErr != nil {
+		return nil, fmt.Errorf("mm_service.SetEnabled: %w", txErr)
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("mm_service.SetEnabled: commit: %w", txErr)
+	}
+
+	// Apply locally right away rather than waiting for our own NOTIFY.
+	if err := s.LoadSwitches(ctx); err != nil {
+		log.Printf("[mm] kill-switch reload failed: %v", err)
+	}
+	log.Printf("[mm] kill switch %s set enabled=%v by %s (reason=%q)", sw.ScopeKey(), enabled, adminID, reason)
+	return sw, nil
+}
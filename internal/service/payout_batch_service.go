@@ -0,0 +1,295 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/payout"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// PayoutBatchService pays approved withdrawals through bank files.  Creating
+// a batch moves its withdrawals to processing, so they can no longer be
+// executed one by one; the batch is exported for the bank portal and the
+// bank's result file is imported to settle each withdrawal through
+// WithdrawalService.Settle (failures are refunded to the wallet).
+type PayoutBatchService struct {
+	db            *sqlx.DB
+	batchRepo     *repository.PayoutBatchRepository
+	withdrawalSvc *WithdrawalService
+	walletRepo    *repository.WalletRepository
+	auditRepo     *repository.AuditRepository
+	cfg           *config.Config
+}
+
+// NewPayoutBatchService creates a PayoutBatchService.
+func NewPayoutBatchService(
+	db *sqlx.DB,
+	batchRepo *repository.PayoutBatchRepository,
+	withdrawalSvc *WithdrawalService,
+	walletRepo *repository.WalletRepository,
+	auditRepo *repository.AuditRepository,
+	cfg *config.Config,
+) *PayoutBatchService {
+	return &PayoutBatchService{
+		db:            db,
+		batchRepo:     batchRepo,
+		withdrawalSvc: withdrawalSvc,
+		walletRepo:    walletRepo,
+		auditRepo:     auditRepo,
+		cfg:           cfg,
+	}
+}
+
+// Create puts approved withdrawals into a new batch: those in ids, or every
+// approved withdrawal when ids is nil.  IDs that are not approved (or are
+// being batched concurrently) are left out.  Returns
+// domain.ErrPayoutBatchEmpty when nothing could be claimed.
+func (s *PayoutBatchService) Create(ctx context.Context, adminID uuid.UUID, ids []uuid.UUID) (*domain.PayoutBatch, error) {
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("payout_batch_service.Create: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	batch := &domain.PayoutBatch{
+		ID:          uuid.New(),
+		Status:      domain.BatchOpen,
+		TotalAmount: decimal.Zero,
+		CreatedBy:   &adminID,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if txErr = s.batchRepo.Create(ctx, tx, batch); txErr != nil {
+		return nil, txErr
+	}
+	reqs, txErr := s.batchRepo.ClaimApproved(ctx, tx, batch.ID, ids)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if len(reqs) == 0 {
+		txErr = domain.ErrPayoutBatchEmpty
+		return nil, txErr
+	}
+	for _, req := range reqs {
+		batch.ItemCount++
+		batch.TotalAmount = batch.TotalAmount.Add(req.Amount)
+	}
+	if txErr = s.batchRepo.SetTotals(ctx, tx, batch); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.audit(ctx, tx, adminID, "payout_batch.create", batch.ID, map[string]any{
+		"item_count": batch.ItemCount, "total_amount": batch.TotalAmount,
+	}); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("payout_batch_service.Create: commit: %w", txErr)
+	}
+	log.Printf("[payout] batch %s created with %d withdrawals (%s TRY)", batch.ID, batch.ItemCount, batch.TotalAmount.StringFixed(2))
+	return batch, nil
+}
+
+// Get returns a batch and its withdrawals.
+func (s *PayoutBatchService) Get(ctx context.Context, id uuid.UUID) (*domain.PayoutBatch, []*domain.PayoutBatchItem, error) {
+	batch, err := s.batchRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	items, err := s.batchRepo.Items(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return batch, items, nil
+}
+
+// List returns batches, newest first.
+func (s *PayoutBatchService) List(ctx context.Context, status string, limit, offset int) ([]*domain.PayoutBatch, int, error) {
+	return s.batchRepo.List(ctx, status, limit, offset)
+}
+
+// Export renders the batch's withdrawals that are still processing as a
+// bank file in format (payout.FormatCSV or payout.FormatPain001).  Settled
+// withdrawals are left out so that re-uploading a later export can never pay
+// one twice.  Payees are the holders of the saved bank accounts; returns
+// domain.ErrPayoutHolderMissing when a withdrawal has no holder name.
+func (s *PayoutBatchService) Export(ctx context.Context, id, adminID uuid.UUID, format string) ([]byte, error) {
+	batch, items, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	file := &payout.BankFile{
+		MsgID:         compactID(batch.ID),
+		CreatedAt:     now,
+		ExecutionDate: now,
+		Debtor: payout.Debtor{
+			Name: s.cfg.Payment.PayoutDebtorName,
+			IBAN: s.cfg.Payment.PayoutDebtorIBAN,
+			BIC:  s.cfg.Payment.PayoutDebtorBIC,
+		},
+	}
+	for _, item := range items {
+		if item.Status != domain.WithdrawProcessing {
+			continue
+		}
+		if item.HolderName == "" {
+			return nil, fmt.Errorf("%w: withdrawal %s", domain.ErrPayoutHolderMissing, item.ID)
+		}
+		ref := compactID(item.ID)
+		file.Transfers = append(file.Transfers, payout.Transfer{
+			EndToEndID:   ref,
+			Amount:       item.Amount,
+			CreditorName: item.HolderName,
+			IBAN:         item.PayeeIBAN,
+			Remittance:   s.cfg.Payment.PayoutDebtorName + " withdrawal " + ref[:8],
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := payout.Write(&buf, format, file); err != nil {
+		return nil, err
+	}
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("payout_batch_service.Export: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	if txErr = s.batchRepo.MarkExported(ctx, tx, id); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.audit(ctx, tx, adminID, "payout_batch.export", id, map[string]any{
+		"format": format, "transfers": len(file.Transfers), "total_amount": file.Total(),
+	}); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("payout_batch_service.Export: commit: %w", txErr)
+	}
+	return buf.Bytes(), nil
+}
+
+// Import applies a bank result file to batch id.  Each completed or failed
+// line settles its withdrawal in its own transaction; pending lines are
+// counted and left for a later file.  Lines for withdrawals outside the
+// batch or already settled are reported as skipped, so importing the same
+// file twice is harmless.  Returns payout.ErrInvalidResultFile (wrapped)
+// before anything is applied when the file cannot be parsed.
+func (s *PayoutBatchService) Import(ctx context.Context, id, adminID uuid.UUID, r io.Reader) (*domain.PayoutImportResult, error) {
+	if _, err := s.batchRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	outcomes, err := payout.ParseResult(r)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &domain.PayoutImportResult{Skipped: []domain.PayoutImportSkip{}}
+	skip := func(o payout.Outcome, reason string) {
+		res.Skipped = append(res.Skipped, domain.PayoutImportSkip{Line: o.Line, Ref: o.EndToEndID, Reason: reason})
+	}
+	for _, o := range outcomes {
+		wid, err := uuid.Parse(o.EndToEndID)
+		if err != nil {
+			skip(o, "not a withdrawal reference")
+			continue
+		}
+		req, err := s.walletRepo.GetWithdrawRequest(ctx, wid)
+		if err != nil {
+			if errors.Is(err, domain.ErrWithdrawalNotFound) {
+				skip(o, "withdrawal not found")
+				continue
+			}
+			return nil, err
+		}
+		if req.BatchID == nil || *req.BatchID != id {
+			skip(o, "withdrawal is not in this batch")
+			continue
+		}
+		if o.Status == payout.StatusPending {
+			res.Pending++
+			continue
+		}
+		if req.Status != domain.WithdrawProcessing {
+			skip(o, "withdrawal already "+string(req.Status))
+			continue
+		}
+
+		success := o.Status == payout.StatusCompleted
+		reason := o.Reason
+		if !success && reason == "" {
+			reason = "rejected by bank"
+		}
+		if _, err := s.withdrawalSvc.Settle(ctx, wid, success, o.BankRef, reason, adminID); err != nil {
+			if errors.Is(err, domain.ErrWithdrawalState) {
+				skip(o, "withdrawal settled concurrently")
+				continue
+			}
+			return nil, err
+		}
+		if success {
+			res.Completed++
+		} else {
+			res.Failed++
+		}
+	}
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("payout_batch_service.Import: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	if res.Batch, txErr = s.batchRepo.RefreshProgress(ctx, tx, id); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.audit(ctx, tx, adminID, "payout_batch.import", id, map[string]any{
+		"completed": res.Completed, "failed": res.Failed, "pending": res.Pending, "skipped": len(res.Skipped),
+	}); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("payout_batch_service.Import: commit: %w", txErr)
+	}
+	log.Printf("[payout] batch %s import: %d completed, %d failed, %d pending, %d skipped",
+		id, res.Completed, res.Failed, res.Pending, len(res.Skipped))
+	return res, nil
+}
+
+// audit logs a batch action.
+func (s *PayoutBatchService) audit(ctx context.Context, tx *sqlx.Tx, actorID uuid.UUID, action string, batchID uuid.UUID, details map[string]any) error {
+	entry, err := repository.NewAuditEntry(actorID, action, "payout_batch", batchID.String(), details)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.Log(ctx, tx, entry)
+}
+
+// compactID is id without dashes: 32 characters, within the 35-character
+// limit of ISO 20022 reference fields.
+func compactID(id uuid.UUID) string {
+	return strings.ReplaceAll(id.String(), "-", "")
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
 	"sync"
 	"time"
 
@@ -24,11 +25,25 @@ const (
 	exchangeOKX     = "okx"
 )
 
+// Default instrument symbols per exchange, used when seeding price_sources.
+const (
+	defaultBinanceSymbol = "BTCUSDT"
+	defaultBybitSymbol   = "BTCUSDT"
+	defaultOKXSymbol     = "BTC-USDT"
+)
+
 // exchangeDef describes a single price-feed source.
 type exchangeDef struct {
-	name   string
-	weight decimal.Decimal // 0–100
-	fetch  func(ctx context.Context) (decimal.Decimal, error)
+	name    string
+	weight  decimal.Decimal // 0–100
+	symbol  string
+	enabled bool
+	fetch   func(ctx context.Context, symbol string) (decimal.Decimal, error)
+}
+
+// live reports whether the source should take part in the weighted average.
+func (ex exchangeDef) live() bool {
+	return ex.enabled && ex.weight.IsPositive()
 }
 
 // ──────────────────────────────────────────────────────────────────────────────
@@ -36,7 +51,8 @@ type exchangeDef struct {
 // ──────────────────────────────────────────────────────────────────────────────
 
 // PriceService fetches BTC/USDT prices from multiple exchanges in parallel,
-// computes a weighted average, and caches the result.
+// computes a weighted average, and caches the result.  Source weights, symbols
+// and enabled flags can be swapped at runtime via ApplySources.
 type PriceService struct {
 	client *http.Client
 	cfg    *config.PriceConfig
@@ -50,7 +66,10 @@ type PriceService struct {
 	// per-exchange last-success timestamp (for ExchangeStatus)
 	statusMu    sync.RWMutex
 	lastSuccess map[string]time.Time
-	exchanges   []exchangeDef
+
+	// exchange set; replaced wholesale by ApplySources
+	exMu      sync.RWMutex
+	exchanges []exchangeDef
 }
 
 // NewPriceService constructs a PriceService from the given config.
@@ -67,25 +86,84 @@ func NewPriceService(cfg *config.Config) *PriceService {
 
 	ps.exchanges = []exchangeDef{
 		{
-			name:   exchangeBinance,
-			weight: decimal.NewFromInt(int64(cfg.Price.BinanceWeight)),
-			fetch:  ps.fetchBinance,
+			name:    exchangeBinance,
+			weight:  decimal.NewFromInt(int64(cfg.Price.BinanceWeight)),
+			symbol:  defaultBinanceSymbol,
+			enabled: true,
+			fetch:   ps.fetchBinance,
 		},
 		{
-			name:   exchangeBybit,
-			weight: decimal.NewFromInt(int64(cfg.Price.BybitWeight)),
-			fetch:  ps.fetchBybit,
+			name:    exchangeBybit,
+			weight:  decimal.NewFromInt(int64(cfg.Price.BybitWeight)),
+			symbol:  defaultBybitSymbol,
+			enabled: true,
+			fetch:   ps.fetchBybit,
 		},
 		{
-			name:   exchangeOKX,
-			weight: decimal.NewFromInt(int64(cfg.Price.OKXWeight)),
-			fetch:  ps.fetchOKX,
+			name:    exchangeOKX,
+			weight:  decimal.NewFromInt(int64(cfg.Price.OKXWeight)),
+			symbol:  defaultOKXSymbol,
+			enabled: true,
+			fetch:   ps.fetchOKX,
 		},
 	}
 
 	return ps
 }
 
+// DefaultSources returns the env-derived source configuration used to seed
+// the price_sources table on first boot.
+func DefaultSources(cfg *config.Config) []*domain.PriceSourceConfig {
+	return []*domain.PriceSourceConfig{
+		{Name: exchangeBinance, Enabled: true, Weight: cfg.Price.BinanceWeight, Symbol: defaultBinanceSymbol},
+		{Name: exchangeBybit, Enabled: true, Weight: cfg.Price.BybitWeight, Symbol: defaultBybitSymbol},
+		{Name: exchangeOKX, Enabled: true, Weight: cfg.Price.OKXWeight, Symbol: defaultOKXSymbol},
+	}
+}
+
+// ApplySources replaces the weight, symbol and enabled flag of every known
+// exchange with the given configuration.  Unknown names are ignored; known
+// exchanges missing from sources keep their current settings.  The price
+// cache is invalidated so the next read reflects the new weights.
+func (ps *PriceService) ApplySources(sources []*domain.PriceSourceConfig) {
+	byName := make(map[string]*domain.PriceSourceConfig, len(sources))
+	for _, src := range sources {
+		byName[src.Name] = src
+	}
+
+	ps.exMu.Lock()
+	next := make([]exchangeDef, len(ps.exchanges))
+	for i, ex := range ps.exchanges {
+		if src, ok := byName[ex.name]; ok {
+			ex.weight = decimal.NewFromInt(int64(src.Weight))
+			ex.symbol = src.Symbol
+			ex.enabled = src.Enabled
+		}
+		next[i] = ex
+	}
+	ps.exchanges = next
+	ps.exMu.Unlock()
+
+	ps.mu.Lock()
+	ps.cacheTime = time.Time{}
+	ps.mu.Unlock()
+}
+
+// liveExchanges returns a snapshot of the sources currently taking part in
+// the weighted average.
+func (ps *PriceService) liveExchanges() []exchangeDef {
+	ps.exMu.RLock()
+	defer ps.exMu.RUnlock()
+
+	live := make([]exchangeDef, 0, len(ps.exchanges))
+	for _, ex := range ps.exchanges {
+		if ex.live() {
+			live = append(live, ex)
+		}
+	}
+	return live
+}
+
 // ──────────────────────────────────────────────────────────────────────────────
 // Public API
 // ──────────────────────────────────────────────────────────────────────────────
@@ -118,21 +196,26 @@ func (ps *PriceService) GetWeightedPrice(ctx context.Context) (decimal.Decimal,
 		err   error
 	}
 
+	exchanges := ps.liveExchanges()
+	if len(exchanges) == 0 {
+		return decimal.Zero, nil, fmt.Errorf("price_service: no enabled price sources")
+	}
+
 	fetchCtx, cancel := context.WithTimeout(ctx, ps.client.Timeout)
 	defer cancel()
 
-	resultCh := make(chan result, len(ps.exchanges))
-	for _, ex := range ps.exchanges {
+	resultCh := make(chan result, len(exchanges))
+	for _, ex := range exchanges {
 		ex := ex // capture
 		go func() {
-			p, err := ex.fetch(fetchCtx)
+			p, err := ex.fetch(fetchCtx, ex.symbol)
 			resultCh <- result{name: ex.name, price: p, err: err}
 		}()
 	}
 
 	// Collect results
-	rawResults := make(map[string]result, len(ps.exchanges))
-	for range ps.exchanges {
+	rawResults := make(map[string]result, len(exchanges))
+	for range exchanges {
 		r := <-resultCh
 		rawResults[r.name] = r
 	}
@@ -142,7 +225,7 @@ func (ps *PriceService) GetWeightedPrice(ctx context.Context) (decimal.Decimal,
 	var sumWeighted, sumWeights decimal.Decimal
 	now := time.Now()
 
-	for _, ex := range ps.exchanges {
+	for _, ex := range exchanges {
 		r := rawResults[ex.name]
 		if r.err != nil || r.price.IsZero() {
 			continue
@@ -208,12 +291,12 @@ func (ps *PriceService) ExchangeStatus() map[string]bool {
 // Exchange fetchers
 // ──────────────────────────────────────────────────────────────────────────────
 
-// fetchBinance fetches the BTC/USDT spot price from Binance REST API.
+// fetchBinance fetches the spot price for symbol from Binance REST API.
 //
 //	GET /api/v3/ticker/price?symbol=BTCUSDT
 //	{"symbol":"BTCUSDT","price":"87350.00"}
-func (ps *PriceService) fetchBinance(ctx context.Context) (decimal.Decimal, error) {
-	url := ps.cfg.BinanceURL + "/api/v3/ticker/price?symbol=BTCUSDT"
+func (ps *PriceService) fetchBinance(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	url := ps.cfg.BinanceURL + "/api/v3/ticker/price?symbol=" + neturl.QueryEscape(symbol)
 	body, err := ps.doGet(ctx, url)
 	if err != nil {
 		return decimal.Zero, fmt.Errorf("binance: %w", err)
@@ -235,12 +318,12 @@ func (ps *PriceService) fetchBinance(ctx context.Context) (decimal.Decimal, erro
 	return price, nil
 }
 
-// fetchBybit fetches the BTC/USDT spot price from Bybit REST API.
+// fetchBybit fetches the spot price for symbol from Bybit REST API.
 //
 //	GET /v5/market/tickers?category=spot&symbol=BTCUSDT
 //	{"result":{"list":[{"lastPrice":"87350.00",...}]}}
-func (ps *PriceService) fetchBybit(ctx context.Context) (decimal.Decimal, error) {
-	url := ps.cfg.BybitURL + "/v5/market/tickers?category=spot&symbol=BTCUSDT"
+func (ps *PriceService) fetchBybit(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	url := ps.cfg.BybitURL + "/v5/market/tickers?category=spot&symbol=" + neturl.QueryEscape(symbol)
 	body, err := ps.doGet(ctx, url)
 	if err != nil {
 		return decimal.Zero, fmt.Errorf("bybit: %w", err)
@@ -266,12 +349,12 @@ func (ps *PriceService) fetchBybit(ctx context.Context) (decimal.Decimal, error)
 	return price, nil
 }
 
-// fetchOKX fetches the BTC/USDT spot price from OKX REST API.
+// fetchOKX fetches the spot price for symbol from OKX REST API.
 //
 //	GET /api/v5/market/ticker?instId=BTC-USDT
 //	{"data":[{"last":"87350.00",...}]}
-func (ps *PriceService) fetchOKX(ctx context.Context) (decimal.Decimal, error) {
-	url := ps.cfg.OKXURL + "/api/v5/market/ticker?instId=BTC-USDT"
+func (ps *PriceService) fetchOKX(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	url := ps.cfg.OKXURL + "/api/v5/market/ticker?instId=" + neturl.QueryEscape(symbol)
 	body, err := ps.doGet(ctx, url)
 	if err != nil {
 		return decimal.Zero, fmt.Errorf("okx: %w", err)
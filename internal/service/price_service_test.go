@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
 	"github.com/evetabi/prediction/internal/service"
 	"github.com/shopspring/decimal"
 )
@@ -211,3 +213,49 @@ func TestPriceService_CacheExpires(t *testing.T) {
 		t.Error("with TTL=0, cache should be considered expired immediately")
 	}
 }
+
+// TestPriceService_ApplySources confirms a runtime weight change takes effect
+// on the next read and that a disabled source is not queried at all.
+func TestPriceService_ApplySources(t *testing.T) {
+	var binanceHits atomic.Int32
+	sBinance := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		binanceHits.Add(1)
+		mockBinanceOK(90000).ServeHTTP(w, r)
+	}))
+	defer sBinance.Close()
+	sBybit := httptest.NewServer(mockBybitOK(91000))
+	defer sBybit.Close()
+	sOKX := httptest.NewServer(mockOKXOK(92000))
+	defer sOKX.Close()
+
+	cfg := buildPriceConfig(sBinance.URL, sBybit.URL, sOKX.URL, 60*time.Second)
+	svc := service.NewPriceService(cfg)
+
+	// Warm the cache with env weights; ApplySources must invalidate it.
+	if _, _, err := svc.GetWeightedPrice(context.Background()); err != nil {
+		t.Fatalf("warm fetch failed: %v", err)
+	}
+
+	svc.ApplySources([]*domain.PriceSourceConfig{
+		{Name: "binance", Enabled: false, Weight: 50, Symbol: "BTCUSDT"},
+		{Name: "bybit", Enabled: true, Weight: 50, Symbol: "BTCUSDT"},
+		{Name: "okx", Enabled: true, Weight: 50, Symbol: "BTC-USDT"},
+	})
+	hitsBefore := binanceHits.Load()
+
+	price, sources, err := svc.GetWeightedPrice(context.Background())
+	if err != nil {
+		t.Fatalf("fetch after ApplySources failed: %v", err)
+	}
+	if binanceHits.Load() != hitsBefore {
+		t.Error("disabled source should not be fetched")
+	}
+	if len(sources) != 2 {
+		t.Errorf("expected 2 sources, got %d", len(sources))
+	}
+	// Equal weights: (91000 + 92000) / 2 = 91500
+	want := decimal.NewFromFloat(91500)
+	if price.Sub(want).Abs().GreaterThan(decimal.NewFromFloat(1)) {
+		t.Errorf("price = %s, want ~%s", price, want)
+	}
+}
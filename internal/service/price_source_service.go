@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// PriceSourceUpdate is a partial update to one price source; nil fields are
+// left unchanged.
+type PriceSourceUpdate struct {
+	Enabled *bool   `json:"enabled"`
+	Weight  *int    `json:"weight"`
+	Symbol  *string `json:"symbol"`
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// PriceSourceService
+// ──────────────────────────────────────────────────────────────────────────────
+
+// PriceSourceService owns the persisted price-source configuration and keeps
+// the in-process PriceService in sync with it.  Both the API server and the
+// back-office run Watch, so an edit made in one process reaches the other
+// within PRICE_SOURCE_REFRESH.
+type PriceSourceService struct {
+	db        *sqlx.DB
+	repo      *repository.PriceSourceRepository
+	auditRepo *repository.AuditRepository
+	priceSvc  *PriceService
+	cfg       *config.Config
+}
+
+// NewPriceSourceService creates a PriceSourceService.
+func NewPriceSourceService(
+	db *sqlx.DB,
+	repo *repository.PriceSourceRepository,
+	auditRepo *repository.AuditRepository,
+	priceSvc *PriceService,
+	cfg *config.Config,
+) *PriceSourceService {
+	return &PriceSourceService{
+		db:        db,
+		repo:      repo,
+		auditRepo: auditRepo,
+		priceSvc:  priceSvc,
+		cfg:       cfg,
+	}
+}
+
+// Init seeds price_sources from the env defaults (no-op for existing rows)
+// and applies the stored configuration to the PriceService.
+func (s *PriceSourceService) Init(ctx context.Context) error {
+	if err := s.repo.Seed(ctx, DefaultSources(s.cfg)); err != nil {
+		return fmt.Errorf("price_source_service.Init: %w", err)
+	}
+	return s.Reload(ctx)
+}
+
+// Reload reads price_sources and applies it to the PriceService.
+func (s *PriceSourceService) Reload(ctx context.Context) error {
+	sources, err := s.repo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("price_source_service.Reload: %w", err)
+	}
+	s.priceSvc.ApplySources(sources)
+	return nil
+}
+
+// Watch re-reads the configuration every PRICE_SOURCE_REFRESH until ctx is
+// cancelled.  Errors are logged and the previous configuration stays active.
+func (s *PriceSourceService) Watch(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Price.SourceRefresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Reload(ctx); err != nil {
+				log.Printf("[price] source reload failed: %v", err)
+			}
+		}
+	}
+}
+
+// List returns the persisted configuration of every price source.
+func (s *PriceSourceService) List(ctx context.Context) ([]*domain.PriceSourceConfig, error) {
+	return s.repo.List(ctx)
+}
+
+// Update applies a partial update to one source, validates the resulting set,
+// writes an audit entry in the same transaction and applies the new set to the
+// local PriceService immediately.
+func (s *PriceSourceService) Update(
+	ctx context.Context,
+	name string,
+	upd PriceSourceUpdate,
+	adminID uuid.UUID,
+) (*domain.PriceSourceConfig, error) {
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("price_source_service.Update: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	sources, txErr := s.repo.ListForUpdate(ctx, tx)
+	if txErr != nil {
+		return nil, fmt.Errorf("price_source_service.Update: %w", txErr)
+	}
+
+	var target *domain.PriceSourceConfig
+	for _, src := range sources {
+		if src.Name == name {
+			target = src
+			break
+		}
+	}
+	if target == nil {
+		txErr = domain.ErrPriceSourceNotFound
+		return nil, txErr
+	}
+
+	before := *target
+	if upd.Enabled != nil {
+		target.Enabled = *upd.Enabled
+	}
+	if upd.Weight != nil {
+		target.Weight = *upd.Weight
+	}
+	if upd.Symbol != nil {
+		target.Symbol = *upd.Symbol
+	}
+	if adminID != uuid.Nil {
+		target.UpdatedBy = &adminID
+	}
+
+	if txErr = domain.ValidatePriceSourceSet(sources); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.repo.Update(ctx, tx, target); txErr != nil {
+		return nil, fmt.Errorf("price_source_service.Update: %w", txErr)
+	}
+
+	entry, txErr := repository.NewAuditEntry(adminID, "price_source.update", "price_source", name,
+		map[string]any{"before": before, "after": target})
+	if txErr != nil {
+		return nil, fmt.Errorf("price_source_service.Update: %w", txErr)
+	}
+	if txErr = s.auditRepo.Log(ctx, tx, entry); txErr != nil {
+		return nil, fmt.Errorf("price_source_service.Update: %w", txErr)
+	}
+
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("price_source_service.Update: commit: %w", txErr)
+	}
+
+	s.priceSvc.ApplySources(sources)
+	log.Printf("[price] source %s updated by %s: enabled=%v weight=%d symbol=%s",
+		name, adminID, target.Enabled, target.Weight, target.Symbol)
+	return target, nil
+}
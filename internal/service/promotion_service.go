@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// freeBetExpiryInterval is how often free bets past their expiry are closed.
+const freeBetExpiryInterval = time.Minute
+
+// PromotionUpdate carries the campaign fields the back office may change;
+// nil fields are left as they are.  The code and reward are fixed once
+// created because users may already have redeemed them.
+type PromotionUpdate struct {
+	Name            *string    `json:"name"`
+	StartsAt        *time.Time `json:"starts_at"`
+	EndsAt          *time.Time `json:"ends_at"`
+	MaxRedemptions  *int       `json:"max_redemptions"`
+	PerUserLimit    *int       `json:"per_user_limit"`
+	NewUsersDays    *int       `json:"new_users_days"`
+	RequiresDeposit *bool      `json:"requires_deposit"`
+	IsActive        *bool      `json:"is_active"`
+}
+
+// PromotionService runs marketing campaigns: marketing defines promotions
+// in the back office and users redeem their promo codes for a bonus grant
+// (see BonusService) or a free-bet token that pays the stake of one bet.
+type PromotionService struct {
+	db          *sqlx.DB
+	repo        *repository.PromotionRepository
+	userRepo    *repository.UserRepository
+	depositRepo *repository.DepositRepository
+	bonusSvc    *BonusService
+	auditRepo   *repository.AuditRepository
+	cfg         *config.Config
+}
+
+// NewPromotionService creates a PromotionService.
+func NewPromotionService(
+	db *sqlx.DB,
+	repo *repository.PromotionRepository,
+	userRepo *repository.UserRepository,
+	depositRepo *repository.DepositRepository,
+	bonusSvc *BonusService,
+	auditRepo *repository.AuditRepository,
+	cfg *config.Config,
+) *PromotionService {
+	return &PromotionService{
+		db:          db,
+		repo:        repo,
+		userRepo:    userRepo,
+		depositRepo: depositRepo,
+		bonusSvc:    bonusSvc,
+		auditRepo:   auditRepo,
+		cfg:         cfg,
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Back office
+// ──────────────────────────────────────────────────────────────────────────────
+
+// Create validates and stores a new campaign.  Returns domain.ErrInvalidPromotion
+// (wrapped) or domain.ErrPromoCodeTaken.
+func (s *PromotionService) Create(ctx context.Context, adminID uuid.UUID, p *domain.Promotion) (*domain.Promotion, error) {
+	code, err := domain.NormalizePromoCode(p.Code)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	p.ID = uuid.New()
+	p.Code = code
+	p.Name = strings.TrimSpace(p.Name)
+	if p.StartsAt.IsZero() {
+		p.StartsAt = now
+	}
+	if p.RewardType == domain.RewardBonus {
+		p.FreeBetDays = 0
+	}
+	p.RedeemedCount = 0
+	p.CreatedBy = &adminID
+	p.CreatedAt, p.UpdatedAt = now, now
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("promotion_service.Create: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if txErr = s.repo.Create(ctx, tx, p); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.audit(ctx, tx, adminID, "promotion.create", p.ID, map[string]any{
+		"code":          p.Code,
+		"reward_type":   p.RewardType,
+		"reward_amount": p.RewardAmount,
+	}); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("promotion_service.Create: commit: %w", txErr)
+	}
+	log.Printf("[promo] %s created campaign %s (%s %s)", adminID, p.Code, p.RewardType, p.RewardAmount)
+	return p, nil
+}
+
+// Update applies u to campaign id.  Returns domain.ErrPromotionNotFound or
+// domain.ErrInvalidPromotion (wrapped).
+func (s *PromotionService) Update(ctx context.Context, id, adminID uuid.UUID, u PromotionUpdate) (*domain.Promotion, error) {
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("promotion_service.Update: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	p, txErr := s.repo.GetForUpdate(ctx, tx, id)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if u.Name != nil {
+		p.Name = strings.TrimSpace(*u.Name)
+	}
+	if u.StartsAt != nil {
+		p.StartsAt = *u.StartsAt
+	}
+	if u.EndsAt != nil {
+		p.EndsAt = u.EndsAt
+	}
+	if u.MaxRedemptions != nil {
+		p.MaxRedemptions = *u.MaxRedemptions
+	}
+	if u.PerUserLimit != nil {
+		p.PerUserLimit = *u.PerUserLimit
+	}
+	if u.NewUsersDays != nil {
+		p.NewUsersDays = *u.NewUsersDays
+	}
+	if u.RequiresDeposit != nil {
+		p.RequiresDeposit = *u.RequiresDeposit
+	}
+	if u.IsActive != nil {
+		p.IsActive = *u.IsActive
+	}
+	if txErr = p.Validate(); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.repo.Update(ctx, tx, p); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.audit(ctx, tx, adminID, "promotion.update", id, map[string]any{
+		"code":    p.Code,
+		"changes": u,
+	}); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("promotion_service.Update: commit: %w", txErr)
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// Get returns a campaign.
+func (s *PromotionService) Get(ctx context.Context, id uuid.UUID) (*domain.Promotion, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// List returns campaigns, newest first; status is "", "active" or "inactive".
+func (s *PromotionService) List(ctx context.Context, status string, limit, offset int) ([]*domain.Promotion, int, error) {
+	return s.repo.List(ctx, status, limit, offset)
+}
+
+// Report summarises a campaign's redemptions.
+func (s *PromotionService) Report(ctx context.Context, id uuid.UUID) (*domain.PromotionReport, error) {
+	return s.repo.Report(ctx, id)
+}
+
+// Redemptions lists a campaign's redemptions, newest first.
+func (s *PromotionService) Redemptions(ctx context.Context, id uuid.UUID, limit, offset int) ([]*domain.PromotionRedemptionItem, int, error) {
+	return s.repo.Redemptions(ctx, id, limit, offset)
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Users
+// ──────────────────────────────────────────────────────────────────────────────
+
+// Redeem applies promo code for userID and hands out its reward.  Returns
+// domain.ErrInvalidPromotion (wrapped, malformed code),
+// domain.ErrPromotionNotFound, domain.ErrPromotionNotActive,
+// domain.ErrPromotionExhausted, domain.ErrPromotionAlreadyRedeemed,
+// domain.ErrPromotionNotEligible (wrapped) or, for bonus rewards,
+// domain.ErrBonusActive.
+func (s *PromotionService) Redeem(ctx context.Context, userID uuid.UUID, code string) (*domain.PromotionRedemption, error) {
+	code, err := domain.NormalizePromoCode(code)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	hasDeposit, err := s.depositRepo.HasCompleted(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("promotion_service.Redeem: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	p, txErr := s.repo.GetByCodeForUpdate(ctx, tx, code)
+	if txErr != nil {
+		return nil, txErr
+	}
+	now := time.Now().UTC()
+	if txErr = p.CheckRedeemable(now); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = p.CheckEligible(user, hasDeposit, now); txErr != nil {
+		return nil, txErr
+	}
+	used, txErr := s.repo.CountUserRedemptions(ctx, tx, p.ID, userID)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if used >= p.PerUserLimit {
+		txErr = domain.ErrPromotionAlreadyRedeemed
+		return nil, txErr
+	}
+
+	red := &domain.PromotionRedemption{
+		ID:          uuid.New(),
+		PromotionID: p.ID,
+		UserID:      userID,
+		RewardType:  p.RewardType,
+		Amount:      p.RewardAmount,
+		CreatedAt:   now,
+	}
+	switch p.RewardType {
+	case domain.RewardBonus:
+		var g *domain.BonusGrant
+		if g, txErr = s.bonusSvc.Grant(ctx, tx, userID, p.RewardAmount, "promo:"+p.Code, nil); txErr != nil {
+			return nil, txErr
+		}
+		red.BonusGrantID = &g.ID
+	case domain.RewardFreeBet:
+		fb := &domain.FreeBet{
+			ID:          uuid.New(),
+			UserID:      userID,
+			PromotionID: p.ID,
+			Amount:      p.RewardAmount,
+			Status:      domain.FreeBetAvailable,
+			CreatedAt:   now,
+			ExpiresAt:   now.AddDate(0, 0, p.FreeBetDays),
+		}
+		if txErr = s.repo.CreateFreeBet(ctx, tx, fb); txErr != nil {
+			return nil, txErr
+		}
+		red.FreeBetID = &fb.ID
+	default:
+		txErr = fmt.Errorf("promotion_service.Redeem: %w: unknown reward type %q", domain.ErrInvalidPromotion, p.RewardType)
+		return nil, txErr
+	}
+	if txErr = s.repo.CreateRedemption(ctx, tx, red); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("promotion_service.Redeem: commit: %w", txErr)
+	}
+	log.Printf("[promo] user %s redeemed %s (%s %s)", userID, p.Code, p.RewardType, p.RewardAmount)
+	return red, nil
+}
+
+// FreeBets returns the user's free-bet tokens, newest first.
+func (s *PromotionService) FreeBets(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.FreeBet, int, error) {
+	return s.repo.ListFreeBets(ctx, userID, limit, offset)
+}
+
+// ExpireFreeBets closes free bets past their expiry until ctx is cancelled.
+// Run it in its own goroutine.  Unused tokens were never funded, so
+// expiring one moves no money.
+func (s *PromotionService) ExpireFreeBets(ctx context.Context) {
+	ticker := time.NewTicker(freeBetExpiryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.repo.ExpireFreeBets(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("[promo] expire free bets: %v", err)
+				}
+				continue
+			}
+			if n > 0 {
+				log.Printf("[promo] expired %d free bets", n)
+			}
+		}
+	}
+}
+
+// audit logs a campaign action.
+func (s *PromotionService) audit(ctx context.Context, tx *sqlx.Tx, actorID uuid.UUID, action string, id uuid.UUID, details map[string]any) error {
+	entry, err := repository.NewAuditEntry(actorID, action, "promotion", id.String(), details)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.Log(ctx, tx, entry)
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// rebalanceTimeout bounds a single rebalance run.
+const rebalanceTimeout = 5 * time.Second
+
+// queueState is a market's position in the RebalanceQueue.
+type queueState int
+
+const (
+	stateIdle    queueState = iota // not tracked
+	stateQueued                    // waiting for a worker
+	stateRunning                   // a worker is evaluating it
+	stateRerun                     // running, and new bets arrived meanwhile
+)
+
+// RebalanceQueueStats is a point-in-time view of a RebalanceQueue.
+type RebalanceQueueStats struct {
+	Depth     int   `json:"depth"`     // markets waiting for a worker
+	Running   int   `json:"running"`   // markets being evaluated right now
+	Scheduled int64 `json:"scheduled"` // Enqueue calls
+	Coalesced int64 `json:"coalesced"` // requests folded into an already pending run
+	Executed  int64 `json:"executed"`  // runs completed
+	Failed    int64 `json:"failed"`    // runs that returned an error
+}
+
+// RebalanceQueue runs at most one rebalance per market at a time and folds
+// bursts of requests into a single follow-up run.  A request that arrives
+// while its market is being evaluated always causes one more run, so the
+// latest pool state is never skipped.  Different markets run in parallel on
+// a fixed pool of workers.
+type RebalanceQueue struct {
+	run     func(ctx context.Context, marketID uuid.UUID) error
+	workers int
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	ready  []uuid.UUID // FIFO of queued markets
+	state  map[uuid.UUID]queueState
+	closed bool
+
+	scheduled atomic.Int64
+	coalesced atomic.Int64
+	executed  atomic.Int64
+	failed    atomic.Int64
+}
+
+// NewRebalanceQueue creates a queue that calls run for each scheduled
+// market.  workers < 1 is treated as 1.
+func NewRebalanceQueue(run func(ctx context.Context, marketID uuid.UUID) error, workers int) *RebalanceQueue {
+	if workers < 1 {
+		workers = 1
+	}
+	q := &RebalanceQueue{
+		run:     run,
+		workers: workers,
+		state:   make(map[uuid.UUID]queueState),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// Start launches the workers.  They exit once ctx is cancelled; queued
+// markets that have not started by then are dropped.
+func (q *RebalanceQueue) Start(ctx context.Context) {
+	for i := 0; i < q.workers; i++ {
+		go q.worker(ctx)
+	}
+	go func() {
+		<-ctx.Done()
+		q.mu.Lock()
+		q.closed = true
+		q.mu.Unlock()
+		q.cond.Broadcast()
+	}()
+}
+
+// Enqueue schedules a rebalance for marketID.  Never blocks.
+func (q *RebalanceQueue) Enqueue(marketID uuid.UUID) {
+	q.scheduled.Add(1)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+
+	switch q.state[marketID] {
+	case stateQueued, stateRerun:
+		q.coalesced.Add(1)
+	case stateRunning:
+		q.state[marketID] = stateRerun
+	default:
+		q.state[marketID] = stateQueued
+		q.ready = append(q.ready, marketID)
+		q.cond.Signal()
+	}
+}
+
+// Stats returns the current queue metrics.
+func (q *RebalanceQueue) Stats() RebalanceQueueStats {
+	q.mu.Lock()
+	depth := len(q.ready)
+	running := len(q.state) - depth
+	q.mu.Unlock()
+
+	return RebalanceQueueStats{
+		Depth:     depth,
+		Running:   running,
+		Scheduled: q.scheduled.Load(),
+		Coalesced: q.coalesced.Load(),
+		Executed:  q.executed.Load(),
+		Failed:    q.failed.Load(),
+	}
+}
+
+func (q *RebalanceQueue) worker(ctx context.Context) {
+	for {
+		q.mu.Lock()
+		for len(q.ready) == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if q.closed {
+			q.mu.Unlock()
+			return
+		}
+		marketID := q.ready[0]
+		q.ready = q.ready[1:]
+		q.state[marketID] = stateRunning
+		q.mu.Unlock()
+
+		q.execute(ctx, marketID)
+
+		q.mu.Lock()
+		if q.state[marketID] == stateRerun && !q.closed {
+			q.state[marketID] = stateQueued
+			q.ready = append(q.ready, marketID)
+			q.cond.Signal()
+		} else {
+			delete(q.state, marketID)
+		}
+		q.mu.Unlock()
+	}
+}
+
+func (q *RebalanceQueue) execute(ctx context.Context, marketID uuid.UUID) {
+	runCtx, cancel := context.WithTimeout(ctx, rebalanceTimeout)
+	defer cancel()
+
+	err := q.run(runCtx, marketID)
+	q.executed.Add(1)
+	if err != nil {
+		q.failed.Add(1)
+		log.Printf("[mm] rebalance failed for market %s: %v", marketID, err)
+	}
+}
@@ -0,0 +1,104 @@
+package service_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evetabi/prediction/internal/service"
+	"github.com/google/uuid"
+)
+
+// waitFor polls cond until it holds or the deadline passes.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestRebalanceQueue_CoalescesBurst checks that requests arriving while a
+// market is being evaluated collapse into exactly one follow-up run.
+func TestRebalanceQueue_CoalescesBurst(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gate := make(chan struct{})
+	var mu sync.Mutex
+	runs := 0
+
+	q := service.NewRebalanceQueue(func(ctx context.Context, _ uuid.UUID) error {
+		mu.Lock()
+		runs++
+		first := runs == 1
+		mu.Unlock()
+		if first {
+			<-gate // hold the first run until the burst has been queued
+		}
+		return nil
+	}, 4)
+	q.Start(ctx)
+
+	market := uuid.New()
+	q.Enqueue(market)
+	waitFor(t, func() bool { return q.Stats().Running == 1 })
+
+	for i := 0; i < 5; i++ {
+		q.Enqueue(market)
+	}
+	close(gate)
+
+	waitFor(t, func() bool { return q.Stats().Executed == 2 })
+	time.Sleep(20 * time.Millisecond) // give a spurious third run time to show up
+
+	st := q.Stats()
+	mu.Lock()
+	defer mu.Unlock()
+	if runs != 2 {
+		t.Errorf("runs = %d, want 2", runs)
+	}
+	if st.Scheduled != 6 || st.Coalesced != 4 {
+		t.Errorf("scheduled=%d coalesced=%d, want 6 and 4", st.Scheduled, st.Coalesced)
+	}
+	if st.Depth != 0 || st.Running != 0 {
+		t.Errorf("depth=%d running=%d, want an empty queue", st.Depth, st.Running)
+	}
+}
+
+// TestRebalanceQueue_MarketsIndependent checks that a slow market does not
+// block rebalances for another market.
+func TestRebalanceQueue_MarketsIndependent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	slow, fast := uuid.New(), uuid.New()
+	gate := make(chan struct{})
+	fastDone := make(chan struct{})
+
+	q := service.NewRebalanceQueue(func(ctx context.Context, id uuid.UUID) error {
+		switch id {
+		case slow:
+			<-gate
+		case fast:
+			close(fastDone)
+		}
+		return nil
+	}, 2)
+	q.Start(ctx)
+
+	q.Enqueue(slow)
+	q.Enqueue(fast)
+
+	select {
+	case <-fastDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("fast market blocked behind slow market")
+	}
+	close(gate)
+	waitFor(t, func() bool { return q.Stats().Executed == 2 })
+}
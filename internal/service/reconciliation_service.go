@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// reconLockKey is the advisory lock that keeps reconciliation passes from
+// overlapping across processes.
+const reconLockKey int64 = 0x7265636f6e // "recon"
+
+// ReconciliationService recomputes every wallet's balance from its ledger
+// history and its transaction history, records each pass in
+// reconciliation_runs and optionally freezes wallets that do not reconcile.
+type ReconciliationService struct {
+	db        *sqlx.DB
+	reconRepo *repository.ReconciliationRepository
+	auditRepo *repository.AuditRepository
+	cfg       *config.Config
+}
+
+// NewReconciliationService creates a ReconciliationService.
+func NewReconciliationService(
+	db *sqlx.DB,
+	reconRepo *repository.ReconciliationRepository,
+	auditRepo *repository.AuditRepository,
+	cfg *config.Config,
+) *ReconciliationService {
+	return &ReconciliationService{
+		db:        db,
+		reconRepo: reconRepo,
+		auditRepo: auditRepo,
+		cfg:       cfg,
+	}
+}
+
+// Run performs one reconciliation pass.  source is domain.ReconScheduled or
+// domain.ReconManual; actorID is uuid.Nil for scheduled runs.  With freeze
+// set, every drifting user wallet that is not frozen yet is frozen in the
+// same transaction that stores the run; the platform wallet never is.  Returns domain.ErrReconciliationRunning
+// when another pass holds the lock.
+func (s *ReconciliationService) Run(ctx context.Context, source string, actorID uuid.UUID, freeze bool) (*domain.ReconciliationRun, error) {
+	run := &domain.ReconciliationRun{
+		ID:        uuid.New(),
+		Source:    source,
+		StartedAt: time.Now().UTC(),
+	}
+	if actorID != uuid.Nil {
+		run.TriggeredBy = &actorID
+	}
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("reconciliation_service.Run: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var locked bool
+	if txErr = tx.GetContext(ctx, &locked, `SELECT pg_try_advisory_xact_lock($1)`, reconLockKey); txErr != nil {
+		return nil, fmt.Errorf("reconciliation_service.Run: lock: %w", txErr)
+	}
+	if !locked {
+		txErr = domain.ErrReconciliationRunning
+		return nil, txErr
+	}
+
+	checked, drifts, txErr := s.reconRepo.GetWalletDrifts(ctx)
+	if txErr != nil {
+		return nil, fmt.Errorf("reconciliation_service.Run: %w", txErr)
+	}
+	run.WalletsChecked = checked
+	run.Mismatched = len(drifts)
+
+	for i := range drifts {
+		d := &drifts[i]
+		run.TotalDrift = run.TotalDrift.Add(d.Drift)
+		run.AbsDrift = run.AbsDrift.Add(d.Drift.Abs())
+		if !freeze || d.Frozen {
+			continue
+		}
+		// Freezing the platform wallet would stop every MM stake; its drift
+		// is left to finance.
+		if d.IsPlatform() {
+			log.Printf("[recon] ALARM: run %s: platform wallet does not reconcile (%s), not frozen", run.ID, d.Summary())
+			continue
+		}
+		reason := fmt.Sprintf("Reconciliation %s: %s", run.ID, d.Summary())
+		changed, err := s.setFrozen(ctx, tx, d.WalletID, true, reason, actorID, map[string]any{
+			"run_id": run.ID, "account": d.Account, "drift": d.Drift,
+			"history_drift": d.HistoryDrift, "broken_tx_id": d.BrokenTxID,
+		})
+		if err != nil {
+			txErr = err
+			return nil, fmt.Errorf("reconciliation_service.Run: %w", txErr)
+		}
+		if changed {
+			d.Frozen = true
+			run.Frozen++
+		}
+	}
+
+	raw, txErr := json.Marshal(drifts)
+	if txErr != nil {
+		return nil, fmt.Errorf("reconciliation_service.Run: marshal drifts: %w", txErr)
+	}
+	run.Drifts = types.JSONText(raw)
+	run.FinishedAt = time.Now().UTC()
+
+	if txErr = s.reconRepo.CreateRun(ctx, tx, run); txErr != nil {
+		return nil, fmt.Errorf("reconciliation_service.Run: %w", txErr)
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("reconciliation_service.Run: commit: %w", txErr)
+	}
+
+	if run.Reconciled() {
+		log.Printf("[recon] %s run %s: %d wallets reconciled", source, run.ID, checked)
+	} else {
+		log.Printf("[recon] ALARM: %s run %s: %d of %d wallets drift (net %s, abs %s TRY), %d frozen",
+			source, run.ID, run.Mismatched, checked,
+			run.TotalDrift.StringFixed(4), run.AbsDrift.StringFixed(4), run.Frozen)
+	}
+	return run, nil
+}
+
+// RunScheduled runs a pass every cfg.Wallet.ReconInterval, freezing drifting
+// wallets when cfg.Wallet.ReconAutoFreeze is set.  A zero interval disables
+// it.  Blocks until ctx is cancelled.
+func (s *ReconciliationService) RunScheduled(ctx context.Context) {
+	interval := s.cfg.Wallet.ReconInterval
+	if interval <= 0 {
+		log.Printf("[recon] scheduled reconciliation disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := s.Run(ctx, domain.ReconScheduled, uuid.Nil, s.cfg.Wallet.ReconAutoFreeze)
+			if err != nil && !errors.Is(err, domain.ErrReconciliationRunning) && ctx.Err() == nil {
+				log.Printf("[recon] scheduled run failed: %v", err)
+			}
+		}
+	}
+}
+
+// ListRuns returns reconciliation runs, newest first.
+func (s *ReconciliationService) ListRuns(ctx context.Context, limit, offset int) ([]*domain.ReconciliationRun, int, error) {
+	return s.reconRepo.ListRuns(ctx, limit, offset)
+}
+
+// GetRun returns one reconciliation run with its drifting wallets.
+func (s *ReconciliationService) GetRun(ctx context.Context, id uuid.UUID) (*domain.ReconciliationRun, error) {
+	return s.reconRepo.GetRun(ctx, id)
+}
+
+// SetWalletFrozen freezes or unfreezes a wallet by hand and returns it.
+func (s *ReconciliationService) SetWalletFrozen(ctx context.Context, walletID uuid.UUID, frozen bool, reason string, adminID uuid.UUID) (*domain.Wallet, error) {
+	if _, err := s.reconRepo.GetWallet(ctx, walletID); err != nil {
+		return nil, err
+	}
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("reconciliation_service.SetWalletFrozen: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, txErr = s.setFrozen(ctx, tx, walletID, frozen, reason, adminID, nil); txErr != nil {
+		return nil, fmt.Errorf("reconciliation_service.SetWalletFrozen: %w", txErr)
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("reconciliation_service.SetWalletFrozen: commit: %w", txErr)
+	}
+	return s.reconRepo.GetWallet(ctx, walletID)
+}
+
+// setFrozen changes the freeze flag and audits the change when there was one.
+func (s *ReconciliationService) setFrozen(ctx context.Context, tx *sqlx.Tx, walletID uuid.UUID, frozen bool, reason string, actorID uuid.UUID, details map[string]any) (bool, error) {
+	changed, err := s.reconRepo.SetFrozen(ctx, tx, walletID, frozen, reason)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	action := "wallet.unfreeze"
+	if frozen {
+		action = "wallet.freeze"
+	}
+	if details == nil {
+		details = map[string]any{}
+	}
+	details["reason"] = reason
+	entry, err := repository.NewAuditEntry(actorID, action, "wallet", walletID.String(), details)
+	if err != nil {
+		return false, err
+	}
+	if err = s.auditRepo.Log(ctx, tx, entry); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/ledger"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// referralCatchUpDays is how many past days each payout run settles, so
+// that days missed while no server was running are still paid.
+const referralCatchUpDays = 7
+
+// ReferralService runs the referral program: users who sign up with an
+// invite code are attributed to its owner, who is paid a share of the
+// commission the house makes on the referred user's bets.  Shares are
+// accrued per referred user and day and credited from house:commission to
+// the referrer's wallet.  Referrals that look like self-referrals or share
+// a signup IP are flagged and earn nothing until risk approves them.
+type ReferralService struct {
+	db        *sqlx.DB
+	repo      *repository.ReferralRepository
+	userRepo  *repository.UserRepository
+	ledger    *ledger.Ledger
+	auditRepo *repository.AuditRepository
+	cfg       *config.Config
+}
+
+// NewReferralService creates a ReferralService.
+func NewReferralService(
+	db *sqlx.DB,
+	repo *repository.ReferralRepository,
+	userRepo *repository.UserRepository,
+	ledger *ledger.Ledger,
+	auditRepo *repository.AuditRepository,
+	cfg *config.Config,
+) *ReferralService {
+	return &ReferralService{
+		db:        db,
+		repo:      repo,
+		userRepo:  userRepo,
+		ledger:    ledger,
+		auditRepo: auditRepo,
+		cfg:       cfg,
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Attribution
+// ──────────────────────────────────────────────────────────────────────────────
+
+// Referrer returns the active user owning an invite code, or
+// domain.ErrReferralCodeNotFound.
+func (s *ReferralService) Referrer(ctx context.Context, code string) (*domain.User, error) {
+	return s.userRepo.GetByReferralCode(ctx, domain.NormalizeReferralCode(code))
+}
+
+// Attribute records inside tx that user signed up from ip with referrer's
+// invite code.  The referral is flagged for review when the two accounts
+// look like one person or the IP was already used by the referrer or
+// another user they referred.
+func (s *ReferralService) Attribute(ctx context.Context, tx *sqlx.Tx, referrer, user *domain.User, ip string) (*domain.Referral, error) {
+	sameIP := false
+	if ip != "" {
+		var err error
+		if sameIP, err = s.repo.IPSeen(ctx, tx, referrer.ID, ip); err != nil {
+			return nil, err
+		}
+	}
+
+	ref := &domain.Referral{
+		ID:         uuid.New(),
+		ReferrerID: referrer.ID,
+		ReferredID: user.ID,
+		Status:     domain.ReferralActive,
+		CreatedAt:  user.CreatedAt,
+	}
+	if ip != "" {
+		ref.SignupIP = &ip
+	}
+	if reason := domain.ReferralFlag(referrer, user, sameIP); reason != "" {
+		ref.Status = domain.ReferralFlagged
+		ref.FlagReason = &reason
+		log.Printf("[referral] %s referred by %s flagged: %s", user.ID, referrer.ID, reason)
+	}
+	if err := s.repo.Create(ctx, tx, ref); err != nil {
+		return nil, err
+	}
+	return ref, nil
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// User API
+// ──────────────────────────────────────────────────────────────────────────────
+
+// Stats returns the user's invite code, generating it on first use, and
+// their referral counts and earnings.
+func (s *ReferralService) Stats(ctx context.Context, userID uuid.UUID) (*domain.ReferralStats, error) {
+	code, err := s.userRepo.EnsureReferralCode(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	st, err := s.repo.Stats(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	st.Code = code
+	st.ShareRate = decimal.NewFromFloat(s.cfg.Referral.ShareRate)
+	return st, nil
+}
+
+// Commissions returns the commission shares paid to the user, newest first.
+func (s *ReferralService) Commissions(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.ReferralCommission, int, error) {
+	return s.repo.Commissions(ctx, userID, limit, offset)
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Back office
+// ──────────────────────────────────────────────────────────────────────────────
+
+// List returns referrals, optionally filtered by status and referrer.
+func (s *ReferralService) List(ctx context.Context, status string, referrerID *uuid.UUID, limit, offset int) ([]*domain.ReferralItem, int, error) {
+	return s.repo.List(ctx, status, referrerID, limit, offset)
+}
+
+// Report returns referral counts and commission paid per referrer.
+func (s *ReferralService) Report(ctx context.Context, limit, offset int) ([]*domain.ReferrerReport, int, error) {
+	return s.repo.Report(ctx, limit, offset)
+}
+
+// Review approves (status active) or blocks a referral.  An approved
+// referral earns from the next payout run, which also settles the days it
+// missed within the catch-up window; a blocked one never earns again.
+// Returns domain.ErrReferralNotFound.
+func (s *ReferralService) Review(ctx context.Context, id, adminID uuid.UUID, approve bool, note string) (*domain.Referral, error) {
+	status, action := domain.ReferralBlocked, "referral.block"
+	if approve {
+		status, action = domain.ReferralActive, "referral.approve"
+	}
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("referral_service.Review: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	before, txErr := s.repo.GetForUpdate(ctx, tx, id)
+	if txErr != nil {
+		return nil, txErr
+	}
+	ref, txErr := s.repo.Review(ctx, tx, id, status, adminID, note)
+	if txErr != nil {
+		return nil, txErr
+	}
+	entry, txErr := repository.NewAuditEntry(adminID, action, "referral", id.String(), map[string]any{
+		"from": before.Status,
+		"note": note,
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.auditRepo.Log(ctx, tx, entry); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("referral_service.Review: commit: %w", txErr)
+	}
+	log.Printf("[referral] %s set referral %s to %s", adminID, id, status)
+	return ref, nil
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Commission payouts
+// ──────────────────────────────────────────────────────────────────────────────
+
+// RunPayouts credits referral commission every cfg.Referral.PayoutInterval
+// until ctx is cancelled.  A zero interval or share rate disables it.  Run
+// it in its own goroutine.
+func (s *ReferralService) RunPayouts(ctx context.Context) {
+	interval := s.cfg.Referral.PayoutInterval
+	if interval <= 0 || s.cfg.Referral.ShareRate <= 0 {
+		log.Printf("[referral] commission payouts disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.PayCommissions(ctx, time.Now().UTC())
+			if err != nil && ctx.Err() == nil {
+				log.Printf("[referral] payout run: %v", err)
+			}
+			if n > 0 {
+				log.Printf("[referral] paid %d commission shares", n)
+			}
+		}
+	}
+}
+
+// PayCommissions settles every completed day of the catch-up window before
+// now and returns the number of shares paid.  Each share is credited in its
+// own transaction; a day already paid for a referral is skipped and a share
+// that fails is logged and retried by the next run.
+func (s *ReferralService) PayCommissions(ctx context.Context, now time.Time) (int, error) {
+	rate := decimal.NewFromFloat(s.cfg.Referral.ShareRate)
+	today := domain.StartOfDay(now.In(s.cfg.Location()))
+	paid := 0
+	for d := referralCatchUpDays; d >= 1; d-- {
+		from := today.AddDate(0, 0, -d)
+		accruals, err := s.repo.Accruals(ctx, from, from.AddDate(0, 0, 1))
+		if err != nil {
+			return paid, err
+		}
+		for _, c := range accruals {
+			c.ID = uuid.New()
+			c.ShareRate = rate
+			c.Amount = domain.ReferralShare(c.CommissionBase, rate)
+			c.CreatedAt = now
+			if !c.Amount.IsPositive() {
+				continue
+			}
+			ok, err := s.payCommission(ctx, c)
+			if err != nil {
+				// Retried on the next run; keep paying the others.
+				log.Printf("[referral] pay %s for referral %s: %v", c.PeriodStart.Format("2006-01-02"), c.ReferralID, err)
+				continue
+			}
+			if ok {
+				paid++
+			}
+		}
+	}
+	return paid, nil
+}
+
+// payCommission records c and credits its amount to the referrer.  Returns
+// false when another run already paid it.
+func (s *ReferralService) payCommission(ctx context.Context, c *domain.ReferralCommission) (bool, error) {
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return false, fmt.Errorf("referral_service.payCommission: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	created, txErr := s.repo.CreateCommission(ctx, tx, c)
+	if txErr != nil {
+		return false, txErr
+	}
+	if !created {
+		_ = tx.Rollback()
+		return false, nil
+	}
+	entry := ledger.NewEntry(domain.TxReferral, c.ID,
+		fmt.Sprintf("Referral commission: %s", c.PeriodStart.Format("2006-01-02"))).
+		Transfer(ledger.HouseCommission, ledger.User(c.ReferrerID), c.Amount)
+	if txErr = s.ledger.Post(ctx, tx, entry); txErr != nil {
+		return false, fmt.Errorf("referral_service.payCommission: %w", txErr)
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return false, fmt.Errorf("referral_service.payCommission: commit: %w", txErr)
+	}
+	return true, nil
+}
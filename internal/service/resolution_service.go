@@ -8,6 +8,7 @@ import (
 
 	"github.com/evetabi/prediction/internal/config"
 	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/ledger"
 	"github.com/evetabi/prediction/internal/repository"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -15,16 +16,19 @@ import (
 )
 
 // ResolutionService handles market settlement: determines the winner,
-// distributes pari-mutuel payouts, settles MM positions, and handles
-// full refunds when a market is cancelled.
+// distributes pari-mutuel payouts out of the market escrow, settles MM
+// positions, and handles full refunds when a market is cancelled.
 //
 // It implements the Refunder interface declared in market_service.go.
 type ResolutionService struct {
 	db           *sqlx.DB
 	marketRepo   *repository.MarketRepository
 	betRepo      *repository.BetRepository
-	walletRepo   *repository.WalletRepository
+	ledger       *ledger.Ledger
+	treasuryRepo *repository.TreasuryRepository
 	priceService *PriceService
+	bonusSvc     *BonusService
+	promoRepo    *repository.PromotionRepository
 	cfg          *config.Config
 }
 
@@ -33,16 +37,22 @@ func NewResolutionService(
 	db *sqlx.DB,
 	marketRepo *repository.MarketRepository,
 	betRepo *repository.BetRepository,
-	walletRepo *repository.WalletRepository,
+	ledger *ledger.Ledger,
+	treasuryRepo *repository.TreasuryRepository,
 	priceService *PriceService,
+	bonusSvc *BonusService,
+	promoRepo *repository.PromotionRepository,
 	cfg *config.Config,
 ) *ResolutionService {
 	return &ResolutionService{
 		db:           db,
 		marketRepo:   marketRepo,
 		betRepo:      betRepo,
-		walletRepo:   walletRepo,
+		ledger:       ledger,
+		treasuryRepo: treasuryRepo,
 		priceService: priceService,
+		bonusSvc:     bonusSvc,
+		promoRepo:    promoRepo,
 		cfg:          cfg,
 	}
 }
@@ -97,28 +107,9 @@ func (s *ResolutionService) resolveMarket(ctx context.Context, market *domain.Ma
 		loser = domain.OutcomeUp
 	}
 
-	// ── Step 3: Pool arithmetic ──────────────────────────────────────────────
-	commission := decimal.NewFromFloat(s.cfg.Wallet.CommissionRate)
-	one := decimal.NewFromInt(1)
+	escrow := ledger.MarketEscrow(market.ID)
 
-	winnerPool := market.PoolUp
-	loserPool := market.PoolDown
-	if winner == domain.OutcomeDown {
-		winnerPool = market.PoolDown
-		loserPool = market.PoolUp
-	}
-
-	totalPool := market.TotalPool()
-	commissionAmt := totalPool.Mul(commission)
-	distributable := loserPool.Mul(one.Sub(commission)) // loser pool after commission
-
-	// ── Step 4: Fetch winning user bets ─────────────────────────────────────
-	winningBets, err := s.betRepo.GetByMarketAndOutcome(ctx, market.ID, winner)
-	if err != nil {
-		return fmt.Errorf("resolution_service.resolveMarket: get winning bets: %w", err)
-	}
-
-	// ── Step 5: Atomic settlement transaction ────────────────────────────────
+	// ── Step 3: Atomic settlement transaction ────────────────────────────────
 	tx, txErr := s.db.BeginTxx(ctx, nil)
 	if txErr != nil {
 		return fmt.Errorf("resolution_service.resolveMarket: begin tx: %w", txErr)
@@ -129,38 +120,56 @@ func (s *ResolutionService) resolveMarket(ctx context.Context, market *domain.Ma
 		}
 	}()
 
-	// --- Pay out winners ----------------------------------------------------
-	for _, bet := range winningBets {
-		payout, payErr := s.calculatePayout(bet, winnerPool, distributable)
-		if payErr != nil {
-			txErr = fmt.Errorf("calculate payout for bet %s: %w", bet.ID, payErr)
-			return txErr
-		}
+	// --- Close the market ---------------------------------------------------
+	// First, so that the status guard stops a concurrent resolution before
+	// it pays anything; a failed settlement rolls this back too.  The row
+	// lock also waits out any bet or exit still updating the pools.
+	if txErr = s.marketRepo.Resolve(ctx, tx, market.ID, closePrice, winner); txErr != nil {
+		return fmt.Errorf("resolution_service: resolve market: %w", txErr)
+	}
+
+	// --- Re-read the settled state -------------------------------------------
+	// The market passed in was scanned before the lock, so bets placed or
+	// exited since are only visible from here on.
+	if market, txErr = s.marketRepo.GetByIDTx(ctx, tx, market.ID); txErr != nil {
+		return fmt.Errorf("resolution_service: reload market: %w", txErr)
+	}
+	openBets, txErr := s.betRepo.GetOpenByMarketTx(ctx, tx, market.ID)
+	if txErr != nil {
+		return fmt.Errorf("resolution_service: get open bets: %w", txErr)
+	}
+	positions, txErr := s.betRepo.GetOpenMMPositionsTx(ctx, tx, market.ID)
+	if txErr != nil {
+		return fmt.Errorf("resolution_service: get mm positions: %w", txErr)
+	}
+	settlement := market.Settle(winner, openBets, positions, decimal.NewFromFloat(s.cfg.Wallet.CommissionRate))
 
-		if txErr = s.walletRepo.AddBalance(ctx, tx, bet.UserID, payout); txErr != nil {
-			return fmt.Errorf("resolution_service: add balance (bet %s): %w", bet.ID, txErr)
+	// --- Pay out winners ----------------------------------------------------
+	// The bonus-funded share of a payout goes back to the bonus balance; a
+	// free bet's stake goes back to the house and the user keeps the rest.
+	var bonusUsers []uuid.UUID
+	for _, bet := range openBets {
+		payout, won := settlement.Payouts[bet.ID]
+		if !won {
+			continue
 		}
 
-		now := time.Now().UTC()
-		betIDCopy := bet.ID
-		wallet, wErr := s.walletRepo.GetByUserID(ctx, bet.UserID)
-		if wErr != nil {
-			txErr = fmt.Errorf("resolution_service: get wallet (bet %s): %w", bet.ID, wErr)
-			return txErr
+		bonusPart := bet.BonusShare(payout)
+		entry := ledger.NewEntry(domain.TxPayout, bet.ID,
+			fmt.Sprintf("Payout: market %s, won %s TRY", market.ID, payout.StringFixed(4)))
+		if bet.IsFreeBet() {
+			stake := decimal.Min(bet.Amount, payout)
+			entry.Transfer(escrow, ledger.HouseFreeBets, stake).
+				Transfer(escrow, ledger.User(bet.UserID), payout.Sub(stake))
+		} else {
+			entry.Transfer(escrow, ledger.User(bet.UserID), payout.Sub(bonusPart)).
+				Transfer(escrow, ledger.UserBonus(bet.UserID), bonusPart)
 		}
-		txn := &domain.Transaction{
-			ID:            uuid.New(),
-			WalletID:      wallet.ID,
-			Type:          domain.TxPayout,
-			Amount:        payout,
-			BalanceBefore: wallet.Balance,
-			BalanceAfter:  wallet.Balance.Add(payout),
-			RefID:         &betIDCopy,
-			Description:   fmt.Sprintf("Payout: market %s, won %s TRY", market.ID, payout.StringFixed(4)),
-			CreatedAt:     now,
+		if txErr = s.ledger.Post(ctx, tx, entry); txErr != nil {
+			return fmt.Errorf("resolution_service: post payout (bet %s): %w", bet.ID, txErr)
 		}
-		if txErr = s.walletRepo.LogTransaction(ctx, tx, txn); txErr != nil {
-			return fmt.Errorf("resolution_service: log payout tx (bet %s): %w", bet.ID, txErr)
+		if bonusPart.IsPositive() {
+			bonusUsers = append(bonusUsers, bet.UserID)
 		}
 
 		payoutCopy := payout
@@ -174,132 +183,110 @@ func (s *ResolutionService) resolveMarket(ctx context.Context, market *domain.Ma
 		return fmt.Errorf("resolution_service: bulk mark lost: %w", txErr)
 	}
 
+	// --- Bonus wagering -----------------------------------------------------
+	if txErr = s.bonusSvc.Settled(ctx, tx, market.ID, bonusUsers); txErr != nil {
+		return fmt.Errorf("resolution_service: bonus wagering: %w", txErr)
+	}
+
 	// --- Settle MM platform positions ---------------------------------------
-	if txErr = s.resolvePlatformBets(ctx, tx, market, winner, winnerPool, distributable); txErr != nil {
+	mmPnL, txErr := s.resolvePlatformBets(ctx, tx, market.ID, positions, settlement)
+	if txErr != nil {
 		return fmt.Errorf("resolution_service: settle platform bets: %w", txErr)
 	}
 
-	// --- Record commission in house treasury --------------------------------
-	if txErr = s.recordCommission(ctx, tx, market.ID, commissionAmt); txErr != nil {
-		return fmt.Errorf("resolution_service: record commission: %w", txErr)
+	// --- Record commission and MM PnL in house treasury ---------------------
+	commissionAmt := settlement.Commission
+	if !commissionAmt.IsZero() {
+		entry := ledger.NewEntry(domain.TxCommission, market.ID,
+			fmt.Sprintf("Commission: market %s", market.ID)).
+			Transfer(escrow, ledger.HouseCommission, commissionAmt)
+		if txErr = s.ledger.Post(ctx, tx, entry); txErr != nil {
+			return fmt.Errorf("resolution_service: post commission: %w", txErr)
+		}
 	}
-
-	// --- Close the market ---------------------------------------------------
-	if txErr = s.marketRepo.Resolve(ctx, market.ID, closePrice, winner); txErr != nil {
-		return fmt.Errorf("resolution_service: resolve market: %w", txErr)
+	if txErr = s.treasuryRepo.RecordSettlement(ctx, tx, market.ID, commissionAmt, mmPnL); txErr != nil {
+		return fmt.Errorf("resolution_service: record treasury: %w", txErr)
 	}
 
 	if txErr = tx.Commit(); txErr != nil {
 		return fmt.Errorf("resolution_service.resolveMarket: commit: %w", txErr)
 	}
 
-	log.Printf("[resolution] market %s resolved: winner=%s close=%.2f commission=%s",
-		market.ID, winner, closePrice.InexactFloat64(), commissionAmt.StringFixed(4))
+	log.Printf("[resolution] market %s resolved: winner=%s close=%.2f commission=%s mm_pnl=%s",
+		market.ID, winner, closePrice.InexactFloat64(), commissionAmt.StringFixed(4), mmPnL.StringFixed(4))
 
 	return nil
 }
 
-// ── Payout helper ────────────────────────────────────────────────────────────
-
-// calculatePayout computes refund + profit for a single winning bet.
-//
-//	share      = bet.Amount / winnerPool
-//	profit     = share × distributable
-//	payout     = bet.Amount + profit
-func (s *ResolutionService) calculatePayout(bet *domain.Bet, winnerPool, distributable decimal.Decimal) (decimal.Decimal, error) {
-	if winnerPool.IsZero() {
-		return decimal.Zero, fmt.Errorf("winner pool is zero for bet %s", bet.ID)
-	}
-	share := bet.Amount.Div(winnerPool)
-	profit := share.Mul(distributable)
-	return bet.Amount.Add(profit).RoundDown(4), nil
-}
-
 // ── Platform MM position settlement ─────────────────────────────────────────
 
-// resolvePlatformBets iterates over all open mm_positions for the market and
-// credits / debits the platform MM wallet accordingly.
+// resolvePlatformBets closes each open MM position of the market with its
+// PnL and pays the winning ones from the escrow to the platform MM wallet,
+// as computed by settlement.  Returns the net MM PnL.
 func (s *ResolutionService) resolvePlatformBets(
 	ctx context.Context,
 	tx *sqlx.Tx,
-	market *domain.Market,
-	winner domain.Outcome,
-	winnerPool, distributable decimal.Decimal,
-) error {
-	positions, err := s.betRepo.GetMMLogsByMarket(ctx, market.ID)
-	if err != nil {
-		return fmt.Errorf("resolvePlatformBets: get positions: %w", err)
-	}
-
-	platformWallet, err := s.walletRepo.GetPlatformWallet(ctx)
-	if err != nil {
-		return fmt.Errorf("resolvePlatformBets: get platform wallet: %w", err)
-	}
-
+	marketID uuid.UUID,
+	positions []*domain.MMLog,
+	settlement domain.Settlement,
+) (pnlTotal decimal.Decimal, err error) {
+	paid := decimal.Zero
 	for _, pos := range positions {
-		if pos.Status != "open" {
-			continue
-		}
-
 		var pnl decimal.Decimal
 		var finalStatus string
 
-		if pos.Direction == winner {
-			// Platform bet on the winning side → payout
-			payout, calcErr := s.calculatePayout(
-				&domain.Bet{Amount: pos.Amount, ID: pos.ID, UserID: platformWallet.UserID},
-				winnerPool, distributable,
-			)
-			if calcErr != nil {
-				return fmt.Errorf("resolvePlatformBets: calc payout pos %s: %w", pos.ID, calcErr)
-			}
+		if payout, won := settlement.MMPayouts[pos.ID]; won {
 			pnl = payout.Sub(pos.Amount)
 			finalStatus = "won"
-			if err = s.walletRepo.AddBalance(ctx, tx, platformWallet.UserID, payout); err != nil {
-				return fmt.Errorf("resolvePlatformBets: add win to platform wallet: %w", err)
-			}
+			paid = paid.Add(payout)
 		} else {
-			// Platform bet on the losing side → loss equals the stake
+			// Platform bet on the losing side → loss equals the stake, which
+			// was already deducted when the MM injected liquidity.
 			pnl = pos.Amount.Neg()
 			finalStatus = "lost"
-			// No balance adjustment needed: the stake was already deducted when
-			// MM injected liquidity (Step 10 will handle that).
 		}
+		pnlTotal = pnlTotal.Add(pnl)
 
 		if err = s.betRepo.UpdateMMPositionStatus(ctx, tx, pos.ID, finalStatus, pnl); err != nil {
-			return fmt.Errorf("resolvePlatformBets: update position %s: %w", pos.ID, err)
+			return pnlTotal, fmt.Errorf("resolvePlatformBets: update position %s: %w", pos.ID, err)
 		}
 	}
 
-	return nil
-}
-
-// ── Commission ledger ────────────────────────────────────────────────────────
-
-func (s *ResolutionService) recordCommission(ctx context.Context, tx *sqlx.Tx, marketID uuid.UUID, amount decimal.Decimal) error {
-	_, err := tx.ExecContext(ctx, `
-		INSERT INTO house_treasury (market_id, commission_earned, mm_pnl, cashout_fees_earned, created_at)
-		VALUES ($1, $2, 0, 0, now())
-		ON CONFLICT DO NOTHING`,
-		marketID, amount)
-	if err != nil {
-		return fmt.Errorf("recordCommission: %w", err)
+	if paid.IsPositive() {
+		entry := ledger.NewEntry(domain.TxMMPayout, marketID,
+			fmt.Sprintf("MM payout: market %s, won %s TRY", marketID, paid.StringFixed(4))).
+			Transfer(ledger.MarketEscrow(marketID), ledger.HouseMM, paid)
+		if err = s.ledger.Post(ctx, tx, entry); err != nil {
+			return pnlTotal, fmt.Errorf("resolvePlatformBets: post payout: %w", err)
+		}
 	}
-	return nil
+	return pnlTotal, nil
 }
 
 // ──────────────────────────────────────────────────────────────────────────────
 // RefundAll — implements the Refunder interface (used by MarketService.CancelMarket)
 // ──────────────────────────────────────────────────────────────────────────────
 
-// RefundAll refunds every active bet in a cancelled market back to their owners.
-// All refunds happen inside a single transaction so the operation is atomic.
+// RefundAll refunds every active bet in a cancelled market back to their
+// owners and returns open MM positions to the platform wallet, emptying the
+// market escrow.  All refunds happen inside a single transaction so the
+// operation is atomic.
 func (s *ResolutionService) RefundAll(ctx context.Context, marketID uuid.UUID) error {
 	activeBets, err := s.betRepo.GetActiveByMarket(ctx, marketID)
 	if err != nil {
 		return fmt.Errorf("resolution_service.RefundAll: get active bets: %w", err)
 	}
-	if len(activeBets) == 0 {
+	positions, err := s.betRepo.GetMMLogsByMarket(ctx, marketID)
+	if err != nil {
+		return fmt.Errorf("resolution_service.RefundAll: get mm positions: %w", err)
+	}
+	var openPositions []*domain.MMLog
+	for _, pos := range positions {
+		if pos.Status == "open" {
+			openPositions = append(openPositions, pos)
+		}
+	}
+	if len(activeBets) == 0 && len(openPositions) == 0 {
 		return nil // nothing to refund
 	}
 
@@ -313,33 +300,26 @@ func (s *ResolutionService) RefundAll(ctx context.Context, marketID uuid.UUID) e
 		}
 	}()
 
+	escrow := ledger.MarketEscrow(marketID)
+	var bonusUsers []uuid.UUID
 	for _, bet := range activeBets {
-		// Credit original stake back to user
-		if txErr = s.walletRepo.AddBalance(ctx, tx, bet.UserID, bet.Amount); txErr != nil {
-			return fmt.Errorf("resolution_service.RefundAll: add balance (bet %s): %w", bet.ID, txErr)
-		}
-
-		// Audit record
-		wallet, wErr := s.walletRepo.GetByUserID(ctx, bet.UserID)
-		if wErr != nil {
-			txErr = wErr
-			return fmt.Errorf("resolution_service.RefundAll: get wallet (bet %s): %w", bet.ID, wErr)
+		// Return the original stake to the balances it came from; a free
+		// bet's stake goes back to the house and the token to the user.
+		entry := ledger.NewEntry(domain.TxRefund, bet.ID, fmt.Sprintf("Refund: market %s cancelled", marketID))
+		if bet.IsFreeBet() {
+			entry.Transfer(escrow, ledger.HouseFreeBets, bet.Amount)
+			if txErr = s.promoRepo.RestoreFreeBet(ctx, tx, *bet.FreeBetID); txErr != nil {
+				return fmt.Errorf("resolution_service.RefundAll: %w", txErr)
+			}
+		} else {
+			entry.Transfer(escrow, ledger.User(bet.UserID), bet.Amount.Sub(bet.BonusAmount)).
+				Transfer(escrow, ledger.UserBonus(bet.UserID), bet.BonusAmount)
 		}
-		now := time.Now().UTC()
-		betIDCopy := bet.ID
-		txn := &domain.Transaction{
-			ID:            uuid.New(),
-			WalletID:      wallet.ID,
-			Type:          domain.TxRefund,
-			Amount:        bet.Amount,
-			BalanceBefore: wallet.Balance,
-			BalanceAfter:  wallet.Balance.Add(bet.Amount),
-			RefID:         &betIDCopy,
-			Description:   fmt.Sprintf("Refund: market %s cancelled", marketID),
-			CreatedAt:     now,
+		if txErr = s.ledger.Post(ctx, tx, entry); txErr != nil {
+			return fmt.Errorf("resolution_service.RefundAll: post refund (bet %s): %w", bet.ID, txErr)
 		}
-		if txErr = s.walletRepo.LogTransaction(ctx, tx, txn); txErr != nil {
-			return fmt.Errorf("resolution_service.RefundAll: log refund tx (bet %s): %w", bet.ID, txErr)
+		if bet.BonusAmount.IsPositive() {
+			bonusUsers = append(bonusUsers, bet.UserID)
 		}
 
 		// Mark bet as refunded (cancelled)
@@ -349,10 +329,30 @@ func (s *ResolutionService) RefundAll(ctx context.Context, marketID uuid.UUID) e
 		}
 	}
 
+	if txErr = s.bonusSvc.Sweep(ctx, tx, bonusUsers...); txErr != nil {
+		return fmt.Errorf("resolution_service.RefundAll: %w", txErr)
+	}
+
+	mmRefund := decimal.Zero
+	for _, pos := range openPositions {
+		mmRefund = mmRefund.Add(pos.Amount)
+		if txErr = s.betRepo.UpdateMMPositionStatus(ctx, tx, pos.ID, "refunded", decimal.Zero); txErr != nil {
+			return fmt.Errorf("resolution_service.RefundAll: close position %s: %w", pos.ID, txErr)
+		}
+	}
+	if mmRefund.IsPositive() {
+		entry := ledger.NewEntry(domain.TxRefund, marketID, fmt.Sprintf("MM refund: market %s cancelled", marketID)).
+			Transfer(escrow, ledger.HouseMM, mmRefund)
+		if txErr = s.ledger.Post(ctx, tx, entry); txErr != nil {
+			return fmt.Errorf("resolution_service.RefundAll: post mm refund: %w", txErr)
+		}
+	}
+
 	if txErr = tx.Commit(); txErr != nil {
 		return fmt.Errorf("resolution_service.RefundAll: commit: %w", txErr)
 	}
 
-	log.Printf("[resolution] RefundAll: refunded %d bets for cancelled market %s", len(activeBets), marketID)
+	log.Printf("[resolution] RefundAll: refunded %d bets and %s TRY of MM positions for cancelled market %s",
+		len(activeBets), mmRefund.StringFixed(4), marketID)
 	return nil
 }
@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// ResponsibleService keeps the player-protection tools: deposit, loss and
+// stake limits per day, week and month, and cooling-off and self-exclusion
+// periods.  Limits are checked by the deposit and bet flows; a limit can be
+// lowered at once but raising or removing it waits
+// cfg.Responsible.LimitIncreaseDelay.  An exclusion cannot be lifted
+// before it ends; a cooling-off blocks betting and deposits, a
+// self-exclusion also blocks logging in.
+type ResponsibleService struct {
+	db        *sqlx.DB
+	repo      *repository.ResponsibleRepository
+	auditRepo *repository.AuditRepository
+	cfg       *config.Config
+}
+
+// NewResponsibleService creates a ResponsibleService.
+func NewResponsibleService(
+	db *sqlx.DB,
+	repo *repository.ResponsibleRepository,
+	auditRepo *repository.AuditRepository,
+	cfg *config.Config,
+) *ResponsibleService {
+	return &ResponsibleService{
+		db:        db,
+		repo:      repo,
+		auditRepo: auditRepo,
+		cfg:       cfg,
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Overview
+// ──────────────────────────────────────────────────────────────────────────────
+
+// Overview returns the user's limits with the current period's usage and
+// the exclusion in force, if any.
+func (s *ResponsibleService) Overview(ctx context.Context, userID uuid.UUID) (*domain.ResponsibleOverview, error) {
+	now := time.Now().UTC()
+	limits, err := s.repo.Limits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	usages := make([]*domain.LimitUsage, 0, len(limits))
+	for _, l := range limits {
+		u := &domain.LimitUsage{GamblingLimit: *l, PeriodStart: periodStart(l.Period, now.In(s.cfg.Location()))}
+		if amt, ok := l.Effective(now); ok {
+			u.InForce = &amt
+		}
+		if u.Used, err = s.used(ctx, s.db, userID, l.Kind, u.PeriodStart); err != nil {
+			return nil, err
+		}
+		usages = append(usages, u)
+	}
+	excl, err := s.repo.ActiveExclusion(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ResponsibleOverview{Limits: usages, Exclusion: excl}, nil
+}
+
+// Exclusions returns the user's exclusions, newest first.
+func (s *ResponsibleService) Exclusions(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.SelfExclusion, error) {
+	return s.repo.Exclusions(ctx, userID, limit, offset)
+}
+
+// ActiveExclusions returns the exclusions in force, optionally of one kind.
+func (s *ResponsibleService) ActiveExclusions(ctx context.Context, kind string, limit, offset int) ([]*domain.SelfExclusionItem, int, error) {
+	return s.repo.ListActiveExclusions(ctx, kind, time.Now().UTC(), limit, offset)
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Limits
+// ──────────────────────────────────────────────────────────────────────────────
+
+// SetLimit sets the user's kind/period limit to amount, or removes it when
+// amount is nil.  A stricter limit applies at once; a looser one or a
+// removal is queued (see domain.GamblingLimit.Change).  Returns the limit
+// as stored, or nil when the user no longer has one.  Returns
+// domain.ErrInvalidLimit.
+func (s *ResponsibleService) SetLimit(ctx context.Context, userID uuid.UUID, kind domain.LimitKind, period domain.LimitPeriod, amount *decimal.Decimal) (*domain.GamblingLimit, error) {
+	if !kind.IsValid() || !period.IsValid() || (amount != nil && !amount.IsPositive()) {
+		return nil, domain.ErrInvalidLimit
+	}
+	now := time.Now().UTC()
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("responsible_service.SetLimit: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	l, txErr := s.repo.GetLimitForUpdate(ctx, tx, userID, kind, period)
+	if txErr != nil {
+		return nil, txErr
+	}
+	existed := l != nil
+	if !existed {
+		l = &domain.GamblingLimit{UserID: userID, Kind: kind, Period: period, CreatedAt: now}
+	}
+	l.UpdatedAt = now
+	keep := l.Change(amount, now, s.cfg.Responsible.LimitIncreaseDelay)
+	switch {
+	case keep:
+		txErr = s.repo.SaveLimit(ctx, tx, l)
+	case existed:
+		txErr = s.repo.DeleteLimit(ctx, tx, userID, kind, period)
+	}
+	if txErr != nil {
+		return nil, txErr
+	}
+
+	entry, txErr := repository.NewAuditEntry(userID, "rg.limit_set", "user", userID.String(), map[string]any{
+		"kind":           kind,
+		"period":         period,
+		"requested":      amount,
+		"amount":         l.Amount,
+		"pending_amount": l.PendingAmount,
+		"pending_from":   l.PendingFrom,
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.auditRepo.Log(ctx, tx, entry); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("responsible_service.SetLimit: commit: %w", txErr)
+	}
+	if !keep {
+		return nil, nil
+	}
+	return l, nil
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Exclusions
+// ──────────────────────────────────────────────────────────────────────────────
+
+// Exclude starts a cooling-off or self-exclusion period for the user from
+// now.  A cooling-off lasts 1 to cfg.Responsible.CoolingOffMaxDays days; a
+// self-exclusion lasts at least cfg.Responsible.SelfExclusionMinDays days
+// or, with indefinite, until support lifts it.  Returns
+// domain.ErrInvalidExclusion or domain.ErrExclusionActive when an
+// exclusion at least as strict is already in force.
+func (s *ResponsibleService) Exclude(ctx context.Context, userID uuid.UUID, kind domain.ExclusionKind, days int, indefinite bool) (*domain.SelfExclusion, error) {
+	rc := s.cfg.Responsible
+	switch {
+	case kind == domain.ExclusionCoolingOff:
+		if indefinite || days < 1 || days > rc.CoolingOffMaxDays {
+			return nil, domain.ErrInvalidExclusion
+		}
+	case kind == domain.ExclusionSelf:
+		if !indefinite && days < rc.SelfExclusionMinDays {
+			return nil, domain.ErrInvalidExclusion
+		}
+	default:
+		return nil, domain.ErrInvalidExclusion
+	}
+
+	now := time.Now().UTC()
+	e := &domain.SelfExclusion{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Kind:      kind,
+		StartsAt:  now,
+		CreatedAt: now,
+	}
+	if !indefinite {
+		end := now.AddDate(0, 0, days)
+		e.EndsAt = &end
+	}
+
+	cur, err := s.repo.ActiveExclusion(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil && cur.Covers(kind, e.EndsAt) {
+		return nil, domain.ErrExclusionActive
+	}
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("responsible_service.Exclude: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if txErr = s.repo.CreateExclusion(ctx, tx, e); txErr != nil {
+		return nil, txErr
+	}
+	entry, txErr := repository.NewAuditEntry(userID, "rg.exclude", "user", userID.String(), map[string]any{
+		"kind":    kind,
+		"ends_at": e.EndsAt,
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	if txErr = s.auditRepo.Log(ctx, tx, entry); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("responsible_service.Exclude: commit: %w", txErr)
+	}
+	log.Printf("[rg] user %s started %s until %v", userID, kind, e.EndsAt)
+	return e, nil
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Checks
+// ──────────────────────────────────────────────────────────────────────────────
+
+// CheckLogin returns an error wrapping domain.ErrSelfExcluded when the
+// user is self-excluded.  A cooling-off does not block logging in so that
+// the user can still withdraw.
+func (s *ResponsibleService) CheckLogin(ctx context.Context, userID uuid.UUID) error {
+	e, err := s.repo.ActiveExclusion(ctx, userID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if e != nil && e.Kind == domain.ExclusionSelf {
+		return e.Err()
+	}
+	return nil
+}
+
+// CheckPlay returns an error wrapping domain.ErrCoolingOff or
+// domain.ErrSelfExcluded when the user is excluded from betting and
+// depositing.
+func (s *ResponsibleService) CheckPlay(ctx context.Context, userID uuid.UUID) error {
+	e, err := s.repo.ActiveExclusion(ctx, userID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if e != nil {
+		return e.Err()
+	}
+	return nil
+}
+
+// CheckStake checks the user's stake and loss limits inside tx after the
+// bet's stake has been posted, so that the totals include it and
+// concurrent bets, serialised by the wallet row lock, see each other.
+// Returns an error wrapping domain.ErrLimitExceeded.
+func (s *ResponsibleService) CheckStake(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) error {
+	return s.checkLimits(ctx, tx, userID, decimal.Zero, domain.LimitStake, domain.LimitLoss)
+}
+
+// CheckDeposit checks that the user may deposit amount: they are not
+// excluded and the deposit keeps them within their deposit limits.  It
+// takes a per-user lock held until tx ends; the caller stores the deposit
+// in tx so that concurrent intents count each other.  Returns an error
+// wrapping domain.ErrCoolingOff, domain.ErrSelfExcluded or
+// domain.ErrLimitExceeded.
+func (s *ResponsibleService) CheckDeposit(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount decimal.Decimal) error {
+	if err := s.CheckPlay(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.repo.LockDeposits(ctx, tx, userID); err != nil {
+		return err
+	}
+	return s.checkLimits(ctx, tx, userID, amount, domain.LimitDeposit)
+}
+
+// checkLimits fails when the usage of any of the user's limits of kinds,
+// plus extra, is over the limit in force.
+func (s *ResponsibleService) checkLimits(ctx context.Context, q sqlx.QueryerContext, userID uuid.UUID, extra decimal.Decimal, kinds ...domain.LimitKind) error {
+	limits, err := s.repo.Limits(ctx, userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, l := range limits {
+		if !containsKind(kinds, l.Kind) {
+			continue
+		}
+		amt, ok := l.Effective(now)
+		if !ok {
+			continue
+		}
+		used, err := s.used(ctx, q, userID, l.Kind, periodStart(l.Period, now.In(s.cfg.Location())))
+		if err != nil {
+			return err
+		}
+		if used.Add(extra).GreaterThan(amt) {
+			return fmt.Errorf("%w: %s %s limit of %s", domain.ErrLimitExceeded, l.Period, l.Kind, amt.StringFixed(2))
+		}
+	}
+	return nil
+}
+
+// used returns how much of a kind of limit the user has used since since.
+func (s *ResponsibleService) used(ctx context.Context, q sqlx.QueryerContext, userID uuid.UUID, kind domain.LimitKind, since time.Time) (decimal.Decimal, error) {
+	if kind == domain.LimitDeposit {
+		return s.repo.DepositTotal(ctx, q, userID, since)
+	}
+	staked, loss, err := s.repo.PlayTotals(ctx, q, userID, since)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if kind == domain.LimitStake {
+		return staked, nil
+	}
+	return decimal.Max(loss, decimal.Zero), nil
+}
+
+func containsKind(kinds []domain.LimitKind, k domain.LimitKind) bool {
+	for _, kk := range kinds {
+		if kk == k {
+			return true
+		}
+	}
+	return false
+}
+
+// periodStart returns the start of the limit period containing now; now
+// is in the business timezone.
+func periodStart(p domain.LimitPeriod, now time.Time) time.Time {
+	switch p {
+	case domain.PeriodWeekly:
+		return domain.StartOfWeek(now)
+	case domain.PeriodMonthly:
+		return domain.StartOfMonth(now)
+	default:
+		return domain.StartOfDay(now)
+	}
+}
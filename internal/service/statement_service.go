@@ -0,0 +1,328 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/evetabi/prediction/internal/statement"
+	"github.com/evetabi/prediction/internal/storage"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// MaxExportRows caps the transactions in one statement export.
+const MaxExportRows = 10000
+
+// StatementService serves wallet transaction history to users and support:
+// filtered listings, paged by offset or by cursor, exports of a period and
+// monthly account statements.  Statements are generated once a business
+// month is over and kept, rendered as PDF and CSV, in file storage.
+type StatementService struct {
+	db            *sqlx.DB
+	walletRepo    *repository.WalletRepository
+	statementRepo *repository.StatementRepository
+	store         storage.Store
+	auditRepo     *repository.AuditRepository
+	cfg           *config.Config
+}
+
+// NewStatementService creates a StatementService.
+func NewStatementService(
+	db *sqlx.DB,
+	walletRepo *repository.WalletRepository,
+	statementRepo *repository.StatementRepository,
+	store storage.Store,
+	auditRepo *repository.AuditRepository,
+	cfg *config.Config,
+) *StatementService {
+	return &StatementService{
+		db:            db,
+		walletRepo:    walletRepo,
+		statementRepo: statementRepo,
+		store:         store,
+		auditRepo:     auditRepo,
+		cfg:           cfg,
+	}
+}
+
+// TxPage is one page of transaction history.
+type TxPage struct {
+	Transactions []*domain.Transaction
+	Total        int    // matches across all pages
+	NextCursor   string // token for the following page; "" on the last one
+}
+
+// History returns a page of the user's transactions matching f, newest
+// first.  A cursor from an earlier page takes precedence over offset, so
+// pages stay stable while new transactions arrive.
+func (s *StatementService) History(ctx context.Context, userID uuid.UUID, f domain.TxFilter, cursor string, limit, offset int) (*TxPage, error) {
+	var after *domain.TxCursor
+	if cursor != "" {
+		c, err := domain.ParseTxCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		after, offset = &c, 0
+	}
+
+	txns, err := s.walletRepo.ListTransactions(ctx, userID, f, after, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.walletRepo.CountTransactions(ctx, userID, f)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &TxPage{Transactions: txns, Total: total}
+	if len(txns) == limit {
+		page.NextCursor = domain.CursorAfter(txns[len(txns)-1]).String()
+	}
+	return page, nil
+}
+
+// Export renders the user's transactions matching f in format
+// (statement.FormatCSV or statement.FormatJSON), newest first.  Returns
+// domain.ErrExportTooLarge when more than MaxExportRows match.
+func (s *StatementService) Export(ctx context.Context, userID uuid.UUID, f domain.TxFilter, format string) ([]byte, error) {
+	if format != statement.FormatCSV && format != statement.FormatJSON {
+		return nil, fmt.Errorf("statement_service.Export: %w: %q", statement.ErrUnknownFormat, format)
+	}
+	n, err := s.walletRepo.CountTransactions(ctx, userID, f)
+	if err != nil {
+		return nil, err
+	}
+	if n > MaxExportRows {
+		return nil, fmt.Errorf("%w: %d matching, at most %d", domain.ErrExportTooLarge, n, MaxExportRows)
+	}
+	txns, err := s.walletRepo.ListTransactions(ctx, userID, f, nil, MaxExportRows, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := statement.Write(&buf, format, txns, s.cfg.Location()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Monthly statements
+// ──────────────────────────────────────────────────────────────────────────────
+
+// ListMonthly returns a page of the user's monthly statements, newest
+// first, and how many there are.
+func (s *StatementService) ListMonthly(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.AccountStatement, int, error) {
+	return s.statementRepo.List(ctx, userID, limit, offset)
+}
+
+// DownloadMonthly returns the user's statement for month rendered in
+// format (statement.FormatPDF or statement.FormatCSV).  Files are shared
+// by the server and the backoffice through the store; a statement whose
+// files have gone missing from it is rebuilt from the ledger.
+func (s *StatementService) DownloadMonthly(ctx context.Context, userID uuid.UUID, month, format string) ([]byte, error) {
+	if format != statement.FormatPDF && format != statement.FormatCSV {
+		return nil, fmt.Errorf("statement_service.DownloadMonthly: %w: %q", statement.ErrUnknownFormat, format)
+	}
+	st, err := s.statementRepo.Get(ctx, userID, month)
+	if err != nil {
+		return nil, err
+	}
+	key := st.PDFKey
+	if format == statement.FormatCSV {
+		key = st.CSVKey
+	}
+	data, err := s.read(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		log.Printf("[statement] %s statement of user %s missing from %s storage, rebuilding", month, userID, s.store.Name())
+		users, cerr := s.statementRepo.Candidates(ctx, month, st.PeriodStart, st.PeriodEnd, []uuid.UUID{userID}, false)
+		if cerr != nil {
+			return nil, cerr
+		}
+		if len(users) == 0 {
+			return nil, domain.ErrStatementNotFound
+		}
+		if err = s.generate(ctx, users[0], month, st.PeriodStart, st.PeriodEnd); err != nil {
+			return nil, err
+		}
+		data, err = s.read(ctx, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("statement_service.DownloadMonthly: %w", err)
+	}
+	return data, nil
+}
+
+// read returns the stored object under key.
+func (s *StatementService) read(ctx context.Context, key string) ([]byte, error) {
+	f, err := s.store.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// RunMonthly generates the statements due for the last business month
+// every cfg.Statement.Interval until ctx is cancelled, so they appear soon
+// after the month ends and users missed by a failed run are picked up by
+// the next.  A zero interval disables it.  Run it in its own goroutine.
+func (s *StatementService) RunMonthly(ctx context.Context) {
+	interval := s.cfg.Statement.Interval
+	if interval <= 0 {
+		log.Printf("[statement] monthly statements disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.GenerateDue(ctx, time.Now()); err != nil && ctx.Err() == nil {
+				log.Printf("[statement] monthly run: %v", err)
+			}
+		}
+	}
+}
+
+// GenerateDue generates the last business month's statement for every
+// user due one who does not have it yet, and returns how many it made.
+func (s *StatementService) GenerateDue(ctx context.Context, now time.Time) (int, error) {
+	month := statement.PreviousMonth(now, s.cfg.Location())
+	start, end, err := s.period(month, now)
+	if err != nil {
+		return 0, err
+	}
+	users, err := s.statementRepo.Candidates(ctx, month, start, end, nil, true)
+	if err != nil {
+		return 0, err
+	}
+	return s.generateAll(ctx, month, start, end, users), nil
+}
+
+// Regenerate rebuilds month's statements in the background, replacing
+// those already made: for userIDs, or for every user due one when userIDs
+// is nil.  It returns how many statements are queued; users without
+// activity or balance in the month are skipped.  Returns
+// domain.ErrInvalidStatementMonth for a month that is not over.
+func (s *StatementService) Regenerate(ctx context.Context, adminID uuid.UUID, month string, userIDs []uuid.UUID) (int, error) {
+	start, end, err := s.period(month, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	users, err := s.statementRepo.Candidates(ctx, month, start, end, userIDs, false)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return 0, fmt.Errorf("statement_service.Regenerate: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	entry, txErr := repository.NewAuditEntry(adminID, "statement.regenerate", "statement", month, map[string]any{
+		"user_ids": userIDs,
+		"queued":   len(users),
+	})
+	if txErr != nil {
+		return 0, txErr
+	}
+	if txErr = s.auditRepo.Log(ctx, tx, entry); txErr != nil {
+		return 0, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return 0, fmt.Errorf("statement_service.Regenerate: commit: %w", txErr)
+	}
+
+	log.Printf("[statement] %s queued %d %s statements for regeneration", adminID, len(users), month)
+	go s.generateAll(context.Background(), month, start, end, users)
+	return len(users), nil
+}
+
+// period returns the bounds of month, which must be over by now.
+func (s *StatementService) period(month string, now time.Time) (time.Time, time.Time, error) {
+	start, end, err := statement.MonthBounds(month, s.cfg.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if end.After(now) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: %s is not over", domain.ErrInvalidStatementMonth, month)
+	}
+	return start, end, nil
+}
+
+// generateAll generates month's statement for each of users and returns
+// how many succeeded.  A failure is logged and does not stop the others.
+func (s *StatementService) generateAll(ctx context.Context, month string, start, end time.Time, users []repository.StatementUser) int {
+	made := 0
+	for _, u := range users {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := s.generate(ctx, u, month, start, end); err != nil {
+			log.Printf("[statement] %s statement of user %s: %v", month, u.UserID, err)
+			continue
+		}
+		made++
+	}
+	if len(users) > 0 {
+		log.Printf("[statement] generated %d of %d %s statements", made, len(users), month)
+	}
+	return made
+}
+
+// generate builds the user's statement for [start, end), stores both
+// renderings and then the statement row, so that a stored statement always
+// has its files.
+func (s *StatementService) generate(ctx context.Context, u repository.StatementUser, month string, start, end time.Time) error {
+	opening, err := s.statementRepo.BalanceAt(ctx, u.UserID, start)
+	if err != nil {
+		return err
+	}
+	txns, err := s.statementRepo.Transactions(ctx, u.UserID, start, end)
+	if err != nil {
+		return err
+	}
+	st := domain.NewAccountStatement(u.UserID, month, start, end, opening)
+	for _, t := range txns {
+		st.Add(t)
+	}
+	if st.Fees, err = s.statementRepo.CashoutFees(ctx, u.UserID, start, end); err != nil {
+		return err
+	}
+	st.GeneratedAt = time.Now().UTC()
+
+	m := &statement.Monthly{Statement: st, Username: u.Username, Transactions: txns}
+	for _, format := range []string{statement.FormatPDF, statement.FormatCSV} {
+		var buf bytes.Buffer
+		if err := statement.WriteMonthly(&buf, format, m, s.cfg.Location()); err != nil {
+			return err
+		}
+		key := fmt.Sprintf("statements/%s/%s.%s", u.UserID, month, format)
+		if _, err := s.store.Put(ctx, key, &buf); err != nil {
+			return fmt.Errorf("statement_service.generate: store %s: %w", format, err)
+		}
+		if format == statement.FormatPDF {
+			st.PDFKey = key
+		} else {
+			st.CSVKey = key
+		}
+	}
+	return s.statementRepo.Upsert(ctx, st)
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// UserAdminService makes audited back-office changes to user accounts.
+type UserAdminService struct {
+	db        *sqlx.DB
+	userRepo  *repository.UserRepository
+	auditRepo *repository.AuditRepository
+}
+
+// NewUserAdminService creates a UserAdminService.
+func NewUserAdminService(db *sqlx.DB, userRepo *repository.UserRepository, auditRepo *repository.AuditRepository) *UserAdminService {
+	return &UserAdminService{db: db, userRepo: userRepo, auditRepo: auditRepo}
+}
+
+// SetRole gives the user role and audits the change.  Roles decide who
+// may approve money movements, so nobody may change their own.  Returns
+// domain.ErrSelfRoleChange or domain.ErrUserNotFound.
+func (s *UserAdminService) SetRole(ctx context.Context, adminID, userID uuid.UUID, role domain.UserRole) error {
+	if adminID == userID {
+		return domain.ErrSelfRoleChange
+	}
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return fmt.Errorf("user_admin_service.SetRole: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	old, txErr := s.userRepo.UpdateRole(ctx, tx, userID, role)
+	if txErr != nil {
+		return txErr
+	}
+	entry, txErr := repository.NewAuditEntry(adminID, "user.set_role", "user", userID.String(), map[string]any{
+		"from": old,
+		"to":   role,
+	})
+	if txErr != nil {
+		return txErr
+	}
+	if txErr = s.auditRepo.Log(ctx, tx, entry); txErr != nil {
+		return txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return fmt.Errorf("user_admin_service.SetRole: commit: %w", txErr)
+	}
+	log.Printf("[user-admin] %s changed role of user %s from %s to %s", adminID, userID, old, role)
+	return nil
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/evetabi/prediction/internal/aml"
+	"github.com/evetabi/prediction/internal/config"
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/ledger"
+	"github.com/evetabi/prediction/internal/payout"
+	"github.com/evetabi/prediction/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// WithdrawalService runs a withdrawal from request to payout.  Requesting
+// holds the amount in wallets.locked; approval releases the hold and debits
+// the wallet into liability:pending_withdrawals; the payout provider then
+// either pays it out (liability cleared against external:payments) or fails
+// it (debit refunded).  Rejection and user cancellation just release the
+// hold.
+type WithdrawalService struct {
+	db         *sqlx.DB
+	walletRepo *repository.WalletRepository
+	batchRepo  *repository.PayoutBatchRepository
+	bankRepo   *repository.BankAccountRepository
+	bonusSvc   *BonusService
+	kycSvc     *KYCService
+	amlSvc     *AMLService
+	ledger     *ledger.Ledger
+	auditRepo  *repository.AuditRepository
+	provider   payout.Provider
+	cfg        *config.Config
+}
+
+// NewWithdrawalService creates a WithdrawalService.
+func NewWithdrawalService(
+	db *sqlx.DB,
+	walletRepo *repository.WalletRepository,
+	batchRepo *repository.PayoutBatchRepository,
+	bankRepo *repository.BankAccountRepository,
+	bonusSvc *BonusService,
+	kycSvc *KYCService,
+	amlSvc *AMLService,
+	ledger *ledger.Ledger,
+	auditRepo *repository.AuditRepository,
+	provider payout.Provider,
+	cfg *config.Config,
+) *WithdrawalService {
+	return &WithdrawalService{
+		db:         db,
+		walletRepo: walletRepo,
+		batchRepo:  batchRepo,
+		bankRepo:   bankRepo,
+		bonusSvc:   bonusSvc,
+		kycSvc:     kycSvc,
+		amlSvc:     amlSvc,
+		ledger:     ledger,
+		auditRepo:  auditRepo,
+		provider:   provider,
+		cfg:        cfg,
+	}
+}
+
+// Request holds amount in the user's wallet and creates a pending request
+// paying out to one of the user's saved bank accounts (the default one when
+// bankAccountID is nil).  The request is screened for AML and stored on
+// hold when the user's case holds withdrawals.  When withdrawing forfeits
+// bonuses, a user with an active bonus must pass forfeitBonus to give it up
+// in the same transaction.
+// Returns domain.ErrBelowMinWithdraw, domain.ErrBonusActive,
+// domain.ErrBankAccountNotFound, domain.ErrBankAccountNotVerified,
+// domain.ErrBankAccountCoolingOff, domain.ErrWithdrawLimitExceeded,
+// domain.ErrKYCRequired, domain.ErrInsufficientBalance,
+// domain.ErrWalletFrozen or domain.ErrWalletNotFound unwrapped.
+func (s *WithdrawalService) Request(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, bankAccountID *uuid.UUID, forfeitBonus bool) (*domain.WithdrawRequest, error) {
+	if amount.LessThan(decimal.NewFromFloat(s.cfg.Wallet.MinWithdraw)) {
+		return nil, domain.ErrBelowMinWithdraw
+	}
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("withdrawal_service.Request: begin tx: %w", txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var acct *domain.BankAccount
+	if bankAccountID == nil {
+		acct, txErr = s.bankRepo.GetDefault(ctx, tx, userID)
+	} else {
+		acct, txErr = s.bankRepo.GetForUpdate(ctx, tx, *bankAccountID)
+		if txErr == nil && acct.UserID != userID {
+			txErr = domain.ErrBankAccountNotFound
+		}
+	}
+	if txErr != nil {
+		return nil, txErr
+	}
+	if txErr = acct.CheckUsable(time.Now()); txErr != nil {
+		return nil, txErr
+	}
+
+	if s.cfg.Bonus.ForfeitOnWithdraw {
+		if forfeitBonus {
+			if txErr = s.bonusSvc.ForfeitActive(ctx, tx, userID); errors.Is(txErr, domain.ErrBonusNotFound) {
+				txErr = nil
+			}
+		} else {
+			var active bool
+			if active, txErr = s.bonusSvc.HasActive(ctx, userID); txErr == nil && active {
+				txErr = domain.ErrBonusActive
+			}
+		}
+		if txErr != nil {
+			return nil, txErr
+		}
+	}
+
+	// The hold locks the wallet row, so the daily total below cannot race
+	// with another request from the same user.
+	if txErr = s.walletRepo.LockBalance(ctx, tx, userID, amount); txErr != nil {
+		return nil, txErr
+	}
+	today, txErr := s.walletRepo.GetDailyWithdrawTotal(ctx, tx, userID, domain.StartOfDay(time.Now().In(s.cfg.Location())))
+	if txErr != nil {
+		return nil, fmt.Errorf("withdrawal_service.Request: %w", txErr)
+	}
+	if today.Add(amount).GreaterThan(decimal.NewFromFloat(s.cfg.Wallet.MaxDailyWithdraw)) {
+		txErr = domain.ErrWithdrawLimitExceeded
+		return nil, txErr
+	}
+	if txErr = s.kycSvc.CheckWithdraw(ctx, tx, userID, amount); txErr != nil {
+		return nil, txErr
+	}
+
+	req := &domain.WithdrawRequest{
+		ID:            uuid.New(),
+		UserID:        userID,
+		Amount:        amount,
+		Status:        domain.WithdrawPending,
+		IBAN:          acct.IBAN,
+		BankAccountID: &acct.ID,
+		RequestedAt:   time.Now().UTC(),
+	}
+	req.AMLHold, txErr = s.amlSvc.Screen(ctx, tx, aml.Event{
+		Subject:   domain.AMLSubjectWithdrawal,
+		SubjectID: req.ID,
+		UserID:    userID,
+		Amount:    amount,
+		At:        req.RequestedAt,
+		IBAN:      req.IBAN,
+	})
+	if txErr != nil {
+		return nil, fmt.Errorf("withdrawal_service.Request: %w", txErr)
+	}
+	if txErr = s.walletRepo.CreateWithdrawRequest(ctx, tx, req); txErr != nil {
+		return nil, fmt.Errorf("withdrawal_service.Request: %w", txErr)
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("withdrawal_service.Request: commit: %w", txErr)
+	}
+	return req, nil
+}
+
+// Cancel lets a user withdraw their own pending request and releases the
+// hold.  Returns domain.ErrWithdrawalNotFound for other users' requests and
+// domain.ErrWithdrawalState once it is no longer pending.
+func (s *WithdrawalService) Cancel(ctx context.Context, userID, id uuid.UUID) (*domain.WithdrawRequest, error) {
+	return s.transition(ctx, id, "cancel", func(tx *sqlx.Tx, req *domain.WithdrawRequest) error {
+		if req.UserID != userID {
+			return domain.ErrWithdrawalNotFound
+		}
+		if req.Status != domain.WithdrawPending {
+			return domain.ErrWithdrawalState
+		}
+		if err := s.walletRepo.UnlockBalance(ctx, tx, req.UserID, req.Amount); err != nil {
+			return err
+		}
+		return s.walletRepo.UpdateWithdrawStatus(ctx, tx, id, domain.WithdrawCancelled, "", uuid.Nil)
+	})
+}
+
+// Approve releases the hold on a pending request and debits the wallet into
+// liability:pending_withdrawals.  Returns domain.ErrWithdrawalHeld while an
+// AML case holds the request.
+func (s *WithdrawalService) Approve(ctx context.Context, id, adminID uuid.UUID, note string) (*domain.WithdrawRequest, error) {
+	return s.transition(ctx, id, "approve", func(tx *sqlx.Tx, req *domain.WithdrawRequest) error {
+		if req.Status != domain.WithdrawPending {
+			return domain.ErrWithdrawalState
+		}
+		if req.AMLHold {
+			return domain.ErrWithdrawalHeld
+		}
+		if err := s.walletRepo.UnlockBalance(ctx, tx, req.UserID, req.Amount); err != nil {
+			return err
+		}
+		entry := ledger.NewEntry(domain.TxWithdraw, req.ID, "Withdrawal to "+maskIBAN(req.IBAN)).
+			Transfer(ledger.User(req.UserID), ledger.PendingWithdrawals, req.Amount)
+		entry.CreatedBy = &adminID
+		if err := s.ledger.Post(ctx, tx, entry); err != nil {
+			return err
+		}
+		if note == "" {
+			note = "Approved by admin"
+		}
+		if err := s.walletRepo.UpdateWithdrawStatus(ctx, tx, id, domain.WithdrawApproved, note, adminID); err != nil {
+			return err
+		}
+		return s.audit(ctx, tx, adminID, "withdrawal.approve", req, map[string]any{"note": note})
+	})
+}
+
+// Reject releases the hold on a pending request.
+func (s *WithdrawalService) Reject(ctx context.Context, id, adminID uuid.UUID, note string) (*domain.WithdrawRequest, error) {
+	return s.transition(ctx, id, "reject", func(tx *sqlx.Tx, req *domain.WithdrawRequest) error {
+		if req.Status != domain.WithdrawPending {
+			return domain.ErrWithdrawalState
+		}
+		if err := s.walletRepo.UnlockBalance(ctx, tx, req.UserID, req.Amount); err != nil {
+			return err
+		}
+		if err := s.walletRepo.UpdateWithdrawStatus(ctx, tx, id, domain.WithdrawRejected, note, adminID); err != nil {
+			return err
+		}
+		return s.audit(ctx, tx, adminID, "withdrawal.reject", req, map[string]any{"note": note})
+	})
+}
+
+// Execute sends an approved withdrawal to the payout provider and settles it
+// when the provider answers with a final result.  A provider error leaves
+// the request in processing: the money may or may not have left, so it must
+// be checked at the provider and settled with Settle.  Returns
+// domain.ErrWithdrawalHeld while an AML case holds the user's withdrawals.
+func (s *WithdrawalService) Execute(ctx context.Context, id, adminID uuid.UUID) (*domain.WithdrawRequest, error) {
+	req, err := s.transition(ctx, id, "execute", func(tx *sqlx.Tx, req *domain.WithdrawRequest) error {
+		if req.Status != domain.WithdrawApproved {
+			return domain.ErrWithdrawalState
+		}
+		held, err := s.amlSvc.HoldsWithdrawals(ctx, tx, req.UserID)
+		if err != nil {
+			return err
+		}
+		if held || req.AMLHold {
+			return domain.ErrWithdrawalHeld
+		}
+		if err := s.walletRepo.MarkWithdrawProcessing(ctx, tx, id, s.provider.Name()); err != nil {
+			return err
+		}
+		return s.audit(ctx, tx, adminID, "withdrawal.execute", req, map[string]any{"provider": s.provider.Name()})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.provider.Send(ctx, payout.Payout{
+		WithdrawalID: req.ID,
+		UserID:       req.UserID,
+		Amount:       req.Amount,
+		IBAN:         req.IBAN,
+	})
+	if err != nil {
+		log.Printf("[withdraw] ALARM: payout %s via %s outcome unknown: %v", req.ID, s.provider.Name(), err)
+		return nil, fmt.Errorf("withdrawal_service.Execute: send: %w", err)
+	}
+	switch res.Status {
+	case payout.StatusCompleted:
+		return s.Settle(ctx, id, true, res.Ref, "", uuid.Nil)
+	case payout.StatusFailed:
+		return s.Settle(ctx, id, false, res.Ref, res.Reason, uuid.Nil)
+	default:
+		return s.walletRepo.GetWithdrawRequest(ctx, id)
+	}
+}
+
+// Settle records the payout outcome of a processing withdrawal.  On success
+// the liability is cleared against external:payments; on failure the debit
+// is refunded to the wallet.  adminID is uuid.Nil when the provider's own
+// answer is being applied.  A withdrawal paid through a payout batch also
+// updates the batch's progress.
+func (s *WithdrawalService) Settle(ctx context.Context, id uuid.UUID, success bool, payoutRef, reason string, adminID uuid.UUID) (*domain.WithdrawRequest, error) {
+	return s.transition(ctx, id, "settle", func(tx *sqlx.Tx, req *domain.WithdrawRequest) error {
+		if req.Status != domain.WithdrawProcessing {
+			return domain.ErrWithdrawalState
+		}
+		var (
+			entry  *ledger.Entry
+			status domain.WithdrawStatus
+		)
+		if success {
+			status = domain.WithdrawCompleted
+			entry = ledger.NewEntry(domain.TxWithdraw, req.ID, "Withdrawal paid out").
+				Transfer(ledger.PendingWithdrawals, ledger.ExternalPayments, req.Amount)
+		} else {
+			status = domain.WithdrawFailed
+			entry = ledger.NewEntry(domain.TxRefund, req.ID, "Withdrawal failed: "+reason).
+				Transfer(ledger.PendingWithdrawals, ledger.User(req.UserID), req.Amount)
+		}
+		if adminID != uuid.Nil {
+			entry.CreatedBy = &adminID
+		}
+		if err := s.ledger.Post(ctx, tx, entry); err != nil {
+			return err
+		}
+		if err := s.walletRepo.FinishWithdrawRequest(ctx, tx, id, status, payoutRef, reason); err != nil {
+			return err
+		}
+		if req.BatchID != nil {
+			if _, err := s.batchRepo.RefreshProgress(ctx, tx, *req.BatchID); err != nil {
+				return err
+			}
+		}
+		return s.audit(ctx, tx, adminID, "withdrawal."+string(status), req, map[string]any{
+			"payout_ref": payoutRef, "reason": reason,
+		})
+	})
+}
+
+// GetUserRequests returns the user's withdrawal requests, newest first.
+func (s *WithdrawalService) GetUserRequests(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.WithdrawRequest, int, error) {
+	return s.walletRepo.GetUserWithdrawRequests(ctx, userID, limit, offset)
+}
+
+// transition locks request id, lets apply change it inside a transaction and
+// returns the updated request.  Domain errors from apply are returned as-is.
+func (s *WithdrawalService) transition(ctx context.Context, id uuid.UUID, op string, apply func(tx *sqlx.Tx, req *domain.WithdrawRequest) error) (*domain.WithdrawRequest, error) {
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return nil, fmt.Errorf("withdrawal_service.%s: begin tx: %w", op, txErr)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	req, txErr := s.walletRepo.GetWithdrawRequestForUpdate(ctx, tx, id)
+	if txErr != nil {
+		return nil, txErr
+	}
+	if txErr = apply(tx, req); txErr != nil {
+		return nil, txErr
+	}
+	if txErr = tx.Commit(); txErr != nil {
+		return nil, fmt.Errorf("withdrawal_service.%s: commit: %w", op, txErr)
+	}
+	return s.walletRepo.GetWithdrawRequest(ctx, id)
+}
+
+// audit logs a withdrawal action against the request.
+func (s *WithdrawalService) audit(ctx context.Context, tx *sqlx.Tx, actorID uuid.UUID, action string, req *domain.WithdrawRequest, details map[string]any) error {
+	details["user_id"] = req.UserID
+	details["amount"] = req.Amount
+	entry, err := repository.NewAuditEntry(actorID, action, "withdrawal", req.ID.String(), details)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.Log(ctx, tx, entry)
+}
+
+// maskIBAN keeps the country code and last four digits of an IBAN for
+// transaction descriptions.
+func maskIBAN(iban string) string {
+	if len(iban) <= 6 {
+		return iban
+	}
+	return iban[:2] + "…" + iban[len(iban)-4:]
+}
@@ -0,0 +1,98 @@
+package statement
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// FormatPDF is the PDF format of monthly statements; WriteMonthly also
+// accepts FormatCSV.
+const FormatPDF = "pdf"
+
+// monthLayout is the layout of a statement month.
+const monthLayout = "2006-01"
+
+// MonthBounds returns the first instant of month ("YYYY-MM") in loc and
+// the first instant of the month after.
+func MonthBounds(month string, loc *time.Location) (start, end time.Time, err error) {
+	start, err = time.ParseInLocation(monthLayout, month, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: %q", domain.ErrInvalidStatementMonth, month)
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// PreviousMonth returns the month ("YYYY-MM") before the one containing t
+// in loc: the latest month that is over.
+func PreviousMonth(t time.Time, loc *time.Location) string {
+	y, m, _ := t.In(loc).Date()
+	return time.Date(y, m-1, 1, 0, 0, 0, 0, loc).Format(monthLayout)
+}
+
+// Monthly is everything a monthly statement shows.
+type Monthly struct {
+	Statement    *domain.AccountStatement
+	Username     string
+	Transactions []*domain.Transaction // the month's transactions, oldest first
+}
+
+// summaryLine is one row of the statement summary.
+type summaryLine struct {
+	label  string
+	amount decimal.Decimal
+}
+
+// summary lists the statement totals in the order both formats show them.
+func (m *Monthly) summary() []summaryLine {
+	s := m.Statement
+	return []summaryLine{
+		{"Opening balance", s.OpeningBalance},
+		{"Deposits", s.Deposits},
+		{"Withdrawals", s.Withdrawals},
+		{"Bets staked", s.Stakes},
+		{"Payouts, cash-outs and refunds", s.Payouts},
+		{"Bonuses and referral shares", s.Bonuses},
+		{"Adjustments and other", s.Other},
+		{"Closing balance", s.ClosingBalance},
+		{"Cash-out fees (included above)", s.Fees},
+	}
+}
+
+// WriteMonthly writes m in format (FormatPDF or FormatCSV).
+func WriteMonthly(w io.Writer, format string, m *Monthly, loc *time.Location) error {
+	switch format {
+	case FormatPDF:
+		return WriteMonthlyPDF(w, m, loc)
+	case FormatCSV:
+		return WriteMonthlyCSV(w, m, loc)
+	default:
+		return fmt.Errorf("statement.WriteMonthly: %w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// WriteMonthlyCSV writes m as CSV: a block of summary rows, an empty row,
+// then the transactions in the columns of WriteCSV, oldest first.
+func WriteMonthlyCSV(w io.Writer, m *Monthly, loc *time.Location) error {
+	s := m.Statement
+	cw := csv.NewWriter(w)
+	rows := [][]string{
+		{"month", s.Month},
+		{"user", m.Username},
+		{"user_id", s.UserID.String()},
+		{"period_start", s.PeriodStart.In(loc).Format(time.RFC3339)},
+		{"period_end", s.PeriodEnd.In(loc).Format(time.RFC3339)},
+	}
+	for _, l := range m.summary() {
+		rows = append(rows, []string{l.label, l.amount.StringFixed(2)})
+	}
+	rows = append(rows, []string{})
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("statement.WriteMonthlyCSV: %w", err)
+	}
+	return WriteCSV(w, m.Transactions, loc)
+}
@@ -0,0 +1,153 @@
+package statement_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/statement"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestMonthBounds(t *testing.T) {
+	start, end, err := statement.MonthBounds("2026-12", istanbul)
+	if err != nil {
+		t.Fatalf("MonthBounds: %v", err)
+	}
+	if want := time.Date(2026, 11, 30, 21, 0, 0, 0, time.UTC); !start.Equal(want) {
+		t.Errorf("start = %s, want %s", start.UTC(), want)
+	}
+	if want := time.Date(2026, 12, 31, 21, 0, 0, 0, time.UTC); !end.Equal(want) {
+		t.Errorf("end = %s, want %s", end.UTC(), want)
+	}
+	for _, bad := range []string{"", "2026-13", "2026-3", "03-2026"} {
+		if _, _, err := statement.MonthBounds(bad, istanbul); !errors.Is(err, domain.ErrInvalidStatementMonth) {
+			t.Errorf("MonthBounds(%q) = %v, want ErrInvalidStatementMonth", bad, err)
+		}
+	}
+}
+
+func TestPreviousMonth(t *testing.T) {
+	cases := map[time.Time]string{
+		time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC): "2026-02",
+		time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC):   "2025-12",
+		// 31 March 22:00 UTC is already 1 April in Istanbul.
+		time.Date(2026, 3, 31, 22, 0, 0, 0, time.UTC): "2026-03",
+	}
+	for now, want := range cases {
+		if got := statement.PreviousMonth(now, istanbul); got != want {
+			t.Errorf("PreviousMonth(%s) = %s, want %s", now, got, want)
+		}
+	}
+}
+
+func testMonthly(n int) *statement.Monthly {
+	start, end, _ := statement.MonthBounds("2026-03", istanbul)
+	st := domain.NewAccountStatement(uuid.New(), "2026-03", start, end, decimal.NewFromInt(100))
+	st.GeneratedAt = end.Add(time.Hour)
+	var txns []*domain.Transaction
+	bal := decimal.NewFromInt(100)
+	for i := 0; i < n; i++ {
+		after := bal.Add(decimal.NewFromInt(10))
+		t := &domain.Transaction{
+			ID: uuid.New(), Type: domain.TxDeposit, Amount: decimal.NewFromInt(10),
+			BalanceBefore: bal, BalanceAfter: after, CreatedAt: start.Add(time.Duration(i) * time.Hour),
+		}
+		st.Add(t)
+		txns = append(txns, t)
+		bal = after
+	}
+	return &statement.Monthly{Statement: st, Username: "ayşe_(test)", Transactions: txns}
+}
+
+func TestWriteMonthlyCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := statement.WriteMonthly(&buf, statement.FormatCSV, testMonthly(3), istanbul); err != nil {
+		t.Fatalf("WriteMonthly: %v", err)
+	}
+	r := csv.NewReader(&buf)
+	r.FieldsPerRecord = -1
+	rows, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("read back: %v", err)
+	}
+	got := map[string]string{}
+	for _, row := range rows {
+		if len(row) == 2 {
+			got[row[0]] = row[1]
+		}
+	}
+	if got["month"] != "2026-03" || got["Opening balance"] != "100.00" || got["Deposits"] != "30.00" || got["Closing balance"] != "130.00" {
+		t.Errorf("summary = %v", got)
+	}
+	if last := rows[len(rows)-1]; last[1] != "deposit" || last[4] != "130.00" {
+		t.Errorf("last transaction row = %v", last)
+	}
+}
+
+// pdfObjects checks the cross-reference table of a PDF against the
+// objects' actual offsets and returns the number of pages.
+func pdfObjects(t *testing.T, pdf []byte) int {
+	t.Helper()
+	s := string(pdf)
+	if !strings.HasPrefix(s, "%PDF-1.4\n") || !strings.HasSuffix(s, "%%EOF\n") {
+		t.Fatal("missing PDF header or trailer")
+	}
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(s)
+	if m == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(m[1])
+	if !strings.HasPrefix(s[xref:], "xref\n") {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(s[xref:], -1)
+	for i, o := range offsets {
+		off, _ := strconv.Atoi(o[1])
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !strings.HasPrefix(s[off:], want) {
+			t.Errorf("xref entry %d points at %q", i+1, s[off:off+10])
+		}
+	}
+	n, _ := strconv.Atoi(regexp.MustCompile(`/Count (\d+)`).FindStringSubmatch(s)[1])
+	return n
+}
+
+func TestWriteMonthlyPDF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := statement.WriteMonthly(&buf, statement.FormatPDF, testMonthly(2), istanbul); err != nil {
+		t.Fatalf("WriteMonthly: %v", err)
+	}
+	if pages := pdfObjects(t, buf.Bytes()); pages != 1 {
+		t.Errorf("pages = %d, want 1", pages)
+	}
+	out := buf.String()
+	// Parentheses escaped, ş folded to s.
+	if !strings.Contains(out, `ayse_\(test\)`) {
+		t.Error("username not encoded as expected")
+	}
+	if !strings.Contains(out, "(Page 1 of 1) Tj") {
+		t.Error("missing page number")
+	}
+}
+
+func TestWriteMonthlyPDF_Paginates(t *testing.T) {
+	var buf bytes.Buffer
+	if err := statement.WriteMonthlyPDF(&buf, testMonthly(150), istanbul); err != nil {
+		t.Fatalf("WriteMonthlyPDF: %v", err)
+	}
+	pages := pdfObjects(t, buf.Bytes())
+	if pages < 3 {
+		t.Errorf("pages = %d, want at least 3 for 150 transactions", pages)
+	}
+	if !strings.Contains(buf.String(), fmt.Sprintf("(Page %d of %d) Tj", pages, pages)) {
+		t.Error("missing last page number")
+	}
+}
@@ -0,0 +1,171 @@
+package statement
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// The PDF writer is deliberately small: A4 pages of monospaced text in the
+// standard Courier fonts, which every reader has, so no font is embedded.
+// Text is encoded as WinAnsi; letters outside it (the Turkish ş, ğ, ı and
+// their capitals among them) are written without their accents.
+
+const (
+	pdfPageWidth  = 595 // A4 in points
+	pdfPageHeight = 842
+	pdfMargin     = 50
+	pdfFontSize   = 9
+	pdfLeading    = 12
+	pdfPageLines  = (pdfPageHeight - 2*pdfMargin) / pdfLeading
+)
+
+// pdfLine is one line of text; bold lines use Courier-Bold.
+type pdfLine struct {
+	text string
+	bold bool
+}
+
+// WriteMonthlyPDF writes m as a PDF: a header and the summary, then the
+// transactions oldest first, paginated with page numbers.
+func WriteMonthlyPDF(w io.Writer, m *Monthly, loc *time.Location) error {
+	s := m.Statement
+	lines := []pdfLine{
+		{text: "ACCOUNT STATEMENT " + s.Month, bold: true},
+		{},
+		{text: "Account:   " + m.Username + " (" + s.UserID.String() + ")"},
+		{text: fmt.Sprintf("Period:    %s to %s (%s)",
+			s.PeriodStart.In(loc).Format("02.01.2006"), s.PeriodEnd.In(loc).AddDate(0, 0, -1).Format("02.01.2006"), loc)},
+		{text: "Generated: " + s.GeneratedAt.In(loc).Format("02.01.2006 15:04")},
+		{},
+		{text: "Summary (TRY)", bold: true},
+	}
+	for _, l := range m.summary() {
+		lines = append(lines, pdfLine{text: fmt.Sprintf("  %-34s %16s", l.label, l.amount.StringFixed(2))})
+	}
+	lines = append(lines,
+		pdfLine{},
+		pdfLine{text: fmt.Sprintf("Transactions (%d)", len(m.Transactions)), bold: true},
+		pdfLine{text: fmt.Sprintf("%-16s  %-12s %14s %14s  %s", "Date", "Type", "Amount", "Balance", "Reference"), bold: true},
+	)
+	for _, t := range m.Transactions {
+		ref := ""
+		if t.RefID != nil {
+			ref = t.RefID.String()[:8]
+		}
+		lines = append(lines, pdfLine{text: fmt.Sprintf("%-16s  %-12s %14s %14s  %s",
+			t.CreatedAt.In(loc).Format("02.01.2006 15:04"), t.Type,
+			t.BalanceAfter.Sub(t.BalanceBefore).StringFixed(2), t.BalanceAfter.StringFixed(2), ref)})
+	}
+	if len(m.Transactions) == 0 {
+		lines = append(lines, pdfLine{text: "No transactions in this period."})
+	}
+	if err := writePDF(w, lines); err != nil {
+		return fmt.Errorf("statement.WriteMonthlyPDF: %w", err)
+	}
+	return nil
+}
+
+// writePDF lays lines out on as many pages as they need and writes the
+// document.
+func writePDF(w io.Writer, lines []pdfLine) error {
+	var pages [][]pdfLine
+	for len(lines) > pdfPageLines-2 { // two lines kept for the page number
+		pages = append(pages, lines[:pdfPageLines-2])
+		lines = lines[pdfPageLines-2:]
+	}
+	pages = append(pages, lines)
+
+	// Objects: 1 catalog, 2 page tree, 3 and 4 fonts, then a page and its
+	// content stream for each page.
+	var objs []string
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	objs = append(objs,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+	)
+	for i, page := range pages {
+		content := pageContent(page, fmt.Sprintf("Page %d of %d", i+1, len(pages)))
+		objs = append(objs,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, 6+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objs))
+	for i, obj := range objs {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// pageContent returns the content stream drawing lines from the top of the
+// page and footer at its bottom.
+func pageContent(lines []pdfLine, footer string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", pdfFontSize, pdfLeading, pdfMargin, pdfPageHeight-pdfMargin)
+	bold := false
+	for i, l := range lines {
+		if l.bold != bold {
+			font := "/F1"
+			if l.bold {
+				font = "/F2"
+			}
+			fmt.Fprintf(&b, "%s %d Tf\n", font, pdfFontSize)
+			bold = l.bold
+		}
+		if i > 0 {
+			b.WriteString("T*\n")
+		}
+		fmt.Fprintf(&b, "(%s) Tj\n", pdfString(l.text))
+	}
+	b.WriteString("ET\n")
+	fmt.Fprintf(&b, "BT\n/F1 %d Tf\n%d %d Td\n(%s) Tj\nET", pdfFontSize, pdfMargin, pdfMargin-pdfLeading, pdfString(footer))
+	return b.String()
+}
+
+// pdfFold maps letters missing from WinAnsi to their unaccented forms.
+var pdfFold = map[rune]byte{
+	'ş': 's', 'Ş': 'S', 'ğ': 'g', 'Ğ': 'G', 'ı': 'i', 'İ': 'I',
+}
+
+// pdfString encodes s as the body of a PDF literal string in WinAnsi.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff: // Latin-1, same codes in WinAnsi
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			if c, ok := pdfFold[r]; ok {
+				b.WriteByte(c)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	return b.String()
+}
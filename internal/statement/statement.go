@@ -0,0 +1,130 @@
+// Package statement turns wallet transaction history into account
+// statements: it parses the history filters shared by the user and
+// back-office endpoints, writes exports for a period and renders monthly
+// statements as CSV and PDF.  Dates are business days in the configured
+// business timezone.
+package statement
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Export formats accepted by Write.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// ErrUnknownFormat is returned by Write for an unsupported format.
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// dateLayout is the layout of the from and to query parameters.
+const dateLayout = "2006-01-02"
+
+// ParseFilter reads a history filter from query parameters:
+//
+//	type    transaction types, comma-separated or repeated
+//	from    first business day, YYYY-MM-DD
+//	to      last business day, YYYY-MM-DD, inclusive
+//	ref_id  bet or market ID
+func ParseFilter(q url.Values, loc *time.Location) (domain.TxFilter, error) {
+	var f domain.TxFilter
+	for _, v := range q["type"] {
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t == "" {
+				continue
+			}
+			if !domain.TxType(t).IsValid() {
+				return f, fmt.Errorf("unknown transaction type %q", t)
+			}
+			f.Types = append(f.Types, domain.TxType(t))
+		}
+	}
+	if s := q.Get("from"); s != "" {
+		from, err := time.ParseInLocation(dateLayout, s, loc)
+		if err != nil {
+			return f, errors.New("from must be YYYY-MM-DD")
+		}
+		f.From = &from
+	}
+	if s := q.Get("to"); s != "" {
+		to, err := time.ParseInLocation(dateLayout, s, loc)
+		if err != nil {
+			return f, errors.New("to must be YYYY-MM-DD")
+		}
+		to = to.AddDate(0, 0, 1) // inclusive
+		f.To = &to
+	}
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return f, errors.New("from must not be after to")
+	}
+	if s := q.Get("ref_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return f, errors.New("invalid ref_id")
+		}
+		f.RefID = &id
+	}
+	return f, nil
+}
+
+// Write writes txns in format (FormatCSV or FormatJSON).
+func Write(w io.Writer, format string, txns []*domain.Transaction, loc *time.Location) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, txns, loc)
+	case FormatJSON:
+		return WriteJSON(w, txns)
+	default:
+		return fmt.Errorf("statement.Write: %w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// WriteCSV writes txns as CSV with a header row.  Times are in loc and the
+// amount is signed: negative when the transaction debited the wallet.
+func WriteCSV(w io.Writer, txns []*domain.Transaction, loc *time.Location) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{{"date", "type", "amount", "balance_before", "balance_after", "ref_id", "description", "id"}}
+	for _, t := range txns {
+		ref := ""
+		if t.RefID != nil {
+			ref = t.RefID.String()
+		}
+		rows = append(rows, []string{
+			t.CreatedAt.In(loc).Format("2006-01-02 15:04:05"),
+			string(t.Type),
+			t.BalanceAfter.Sub(t.BalanceBefore).StringFixed(2),
+			t.BalanceBefore.StringFixed(2),
+			t.BalanceAfter.StringFixed(2),
+			ref,
+			t.Description,
+			t.ID.String(),
+		})
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("statement.WriteCSV: %w", err)
+	}
+	return nil
+}
+
+// WriteJSON writes txns as a JSON array, in the same shape as the history
+// endpoint.
+func WriteJSON(w io.Writer, txns []*domain.Transaction) error {
+	if txns == nil {
+		txns = []*domain.Transaction{}
+	}
+	if err := json.NewEncoder(w).Encode(txns); err != nil {
+		return fmt.Errorf("statement.WriteJSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package statement_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/evetabi/prediction/internal/domain"
+	"github.com/evetabi/prediction/internal/statement"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+var istanbul = time.FixedZone("TRT", 3*60*60)
+
+func TestParseFilter(t *testing.T) {
+	ref := uuid.New()
+	q := url.Values{
+		"type":   {"payout,refund", "deposit"},
+		"from":   {"2026-03-01"},
+		"to":     {"2026-03-31"},
+		"ref_id": {ref.String()},
+	}
+	f, err := statement.ParseFilter(q, istanbul)
+	if err != nil {
+		t.Fatalf("ParseFilter: %v", err)
+	}
+	if len(f.Types) != 3 || f.Types[0] != domain.TxPayout || f.Types[2] != domain.TxDeposit {
+		t.Errorf("types = %v", f.Types)
+	}
+	// Business days: midnight in Istanbul is 21:00 UTC the day before.
+	if want := time.Date(2026, 2, 28, 21, 0, 0, 0, time.UTC); !f.From.Equal(want) {
+		t.Errorf("from = %s, want %s", f.From.UTC(), want)
+	}
+	if want := time.Date(2026, 3, 31, 21, 0, 0, 0, time.UTC); !f.To.Equal(want) {
+		t.Errorf("to = %s, want %s (inclusive)", f.To.UTC(), want)
+	}
+	if f.RefID == nil || *f.RefID != ref {
+		t.Errorf("ref_id = %v, want %s", f.RefID, ref)
+	}
+
+	if f, err := statement.ParseFilter(url.Values{}, istanbul); err != nil || f.Types != nil || f.From != nil || f.To != nil || f.RefID != nil {
+		t.Errorf("empty query = %+v, %v; want zero filter", f, err)
+	}
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	for _, q := range []url.Values{
+		{"type": {"payout,nope"}},
+		{"from": {"01/03/2026"}},
+		{"to": {"yesterday"}},
+		{"from": {"2026-03-02"}, "to": {"2026-03-01"}},
+		{"ref_id": {"42"}},
+	} {
+		if _, err := statement.ParseFilter(q, istanbul); err == nil {
+			t.Errorf("ParseFilter(%v): expected error", q)
+		}
+	}
+}
+
+func testTxns() []*domain.Transaction {
+	ref := uuid.MustParse("6f1c2a9e-3b7d-4c1e-9f2a-1b2c3d4e5f60")
+	return []*domain.Transaction{
+		{
+			ID: uuid.New(), Type: domain.TxPayout, Amount: decimal.NewFromInt(180),
+			BalanceBefore: decimal.NewFromInt(20), BalanceAfter: decimal.NewFromInt(200),
+			RefID: &ref, Description: "bet won", CreatedAt: time.Date(2026, 3, 1, 22, 15, 0, 0, time.UTC),
+		},
+		{
+			ID: uuid.New(), Type: domain.TxBetLock, Amount: decimal.NewFromInt(80),
+			BalanceBefore: decimal.NewFromInt(100), BalanceAfter: decimal.NewFromInt(20),
+			RefID: &ref, Description: "bet placed", CreatedAt: time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := statement.Write(&buf, statement.FormatCSV, testTxns(), istanbul); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read back: %v", err)
+	}
+	if len(rows) != 3 || rows[0][0] != "date" || rows[0][2] != "amount" {
+		t.Fatalf("rows = %v", rows)
+	}
+	if rows[1][0] != "2026-03-02 01:15:00" || rows[1][2] != "180.00" {
+		t.Errorf("payout row = %v, want business-time date and a credit", rows[1])
+	}
+	if rows[2][2] != "-80.00" || rows[2][4] != "20.00" || rows[2][5] != "6f1c2a9e-3b7d-4c1e-9f2a-1b2c3d4e5f60" {
+		t.Errorf("bet_lock row = %v, want a debit", rows[2])
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := statement.Write(&buf, statement.FormatJSON, nil, istanbul); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	var got []domain.Transaction
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil || got == nil || len(got) != 0 {
+		t.Errorf("empty export = %q, want []", buf.String())
+	}
+
+	buf.Reset()
+	if err := statement.Write(&buf, statement.FormatJSON, testTxns(), istanbul); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil || len(got) != 2 || got[0].Type != domain.TxPayout {
+		t.Errorf("export = %v, %v", got, err)
+	}
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	if err := statement.Write(&bytes.Buffer{}, "xlsx", nil, istanbul); !errors.Is(err, statement.ErrUnknownFormat) {
+		t.Errorf("err = %v, want ErrUnknownFormat", err)
+	}
+}
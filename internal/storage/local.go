@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalName is the name of the local disk driver.
+const LocalName = "local"
+
+// Local stores objects as files under a root directory, one file per key.
+// Writes go to a temporary file that is renamed into place, so readers
+// never see a partial object.
+type Local struct {
+	root string
+}
+
+// NewLocal creates a Local rooted at dir, creating dir if needed.
+func NewLocal(dir string) (*Local, error) {
+	if dir == "" {
+		return nil, errors.New("storage.NewLocal: directory must be set")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("storage.NewLocal: %w", err)
+	}
+	return &Local{root: dir}, nil
+}
+
+// Name implements Store.
+func (*Local) Name() string { return LocalName }
+
+// Put implements Store.
+func (l *Local) Put(_ context.Context, key string, r io.Reader) (int64, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return 0, err
+	}
+	dir := filepath.Dir(path)
+	if err = os.MkdirAll(dir, 0o750); err != nil {
+		return 0, fmt.Errorf("storage.Local.Put: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("storage.Local.Put: %w", err)
+	}
+	n, err := io.Copy(tmp, r)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return 0, fmt.Errorf("storage.Local.Put: %w", err)
+	}
+	return n, nil
+}
+
+// Open implements Store.
+func (l *Local) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("storage.Local.Open: %w", err)
+	}
+	return f, nil
+}
+
+// Delete implements Store.
+func (l *Local) Delete(_ context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("storage.Local.Delete: %w", err)
+	}
+	return nil
+}
+
+func (l *Local) path(key string) (string, error) {
+	if !validKey(key) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
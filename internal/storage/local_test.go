@@ -0,0 +1,67 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/evetabi/prediction/internal/storage"
+)
+
+func TestLocal_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	s, err := storage.New(storage.LocalName, t.TempDir())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	n, err := s.Put(ctx, "kyc/user/doc", strings.NewReader("passport scan"))
+	if err != nil || n != 13 {
+		t.Fatalf("Put = %d, %v", n, err)
+	}
+	rc, err := s.Open(ctx, "kyc/user/doc")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(got) != "passport scan" {
+		t.Errorf("Open read %q", got)
+	}
+
+	if err = s.Delete(ctx, "kyc/user/doc"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err = s.Open(ctx, "kyc/user/doc"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Open after Delete = %v, want ErrNotFound", err)
+	}
+	if err = s.Delete(ctx, "kyc/user/doc"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("second Delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocal_RejectsUnsafeKeys(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := storage.NewLocal(dir + "/root")
+	if err != nil {
+		t.Fatalf("NewLocal: %v", err)
+	}
+	for _, key := range []string{"", "/etc/passwd", "../escape", "a/../../b", "a//b", `a\b`, "a/./b"} {
+		if _, err := s.Put(ctx, key, strings.NewReader("x")); !errors.Is(err, storage.ErrInvalidKey) {
+			t.Errorf("Put(%q) = %v, want ErrInvalidKey", key, err)
+		}
+	}
+	if _, err := os.Stat(dir + "/escape"); err == nil {
+		t.Error("file written outside the root")
+	}
+}
+
+func TestNew_UnknownDriver(t *testing.T) {
+	if _, err := storage.New("s3", t.TempDir()); err == nil {
+		t.Error("New accepted an unknown driver")
+	}
+}
@@ -0,0 +1,59 @@
+// Package storage keeps uploaded files, such as KYC documents, outside the
+// database.  Callers store only the object key; a Store maps keys to bytes
+// on the local disk or in an object store.  A Store knows nothing about
+// users or permissions.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned by Open and Delete for a key with no object.
+	ErrNotFound = errors.New("storage: object not found")
+	// ErrInvalidKey is returned for an empty key or one that is not a clean
+	// relative path.
+	ErrInvalidKey = errors.New("storage: invalid key")
+)
+
+// Store is a file store addressed by slash-separated keys.
+type Store interface {
+	// Name identifies the driver in logs.
+	Name() string
+	// Put stores r under key, replacing any object already there, and
+	// returns the number of bytes written.  A failed Put leaves no object.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Open returns the object stored under key.  The caller closes it.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key.
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the store for driver.  localDir is the root directory of the
+// local driver.
+func New(driver, localDir string) (Store, error) {
+	switch driver {
+	case LocalName:
+		return NewLocal(localDir)
+	default:
+		return nil, fmt.Errorf("storage.New: unknown driver %q", driver)
+	}
+}
+
+// validKey reports whether key is a clean relative path: no empty, "." or
+// ".." segments and no leading slash or backslashes.
+func validKey(key string) bool {
+	if key == "" || strings.ContainsRune(key, '\\') {
+		return false
+	}
+	for _, seg := range strings.Split(key, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return false
+		}
+	}
+	return true
+}
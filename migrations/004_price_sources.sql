@@ -0,0 +1,27 @@
+-- Migration 004: Runtime price-source configuration and admin audit trail
+
+-- Admin audit log: one row per back-office write action
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id    UUID REFERENCES users(id),          -- NULL = system
+    action      VARCHAR(100) NOT NULL,              -- e.g. 'price_source.update'
+    entity_type VARCHAR(50)  NOT NULL,              -- e.g. 'price_source'
+    entity_id   VARCHAR(100) NOT NULL DEFAULT '',
+    details     JSONB        NOT NULL DEFAULT '{}', -- before/after snapshot
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_entity  ON admin_audit_log(entity_type, entity_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_created ON admin_audit_log(created_at DESC);
+
+-- Price sources: per-exchange weight / symbol / enabled flag, editable at runtime.
+-- Rows are seeded from the PRICE_*_WEIGHT env defaults on first boot.
+CREATE TABLE IF NOT EXISTS price_sources (
+    name        VARCHAR(30) PRIMARY KEY,            -- 'binance' | 'bybit' | 'okx'
+    enabled     BOOLEAN     NOT NULL DEFAULT TRUE,
+    weight      INT         NOT NULL,
+    symbol      VARCHAR(30) NOT NULL,
+    updated_by  UUID REFERENCES users(id),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT price_sources_weight_range CHECK (weight BETWEEN 0 AND 100)
+);
@@ -0,0 +1,29 @@
+-- Migration 005: Scoped, versioned Market Maker configuration
+
+-- Markets belong to a series (e.g. 'btc_5m') so MM parameters can be
+-- tuned per product line rather than only globally or per round.
+ALTER TABLE markets ADD COLUMN IF NOT EXISTS series VARCHAR(50) NOT NULL DEFAULT 'btc_5m';
+CREATE INDEX IF NOT EXISTS idx_markets_series ON markets(series);
+
+-- mm_config rows are now overrides: NULL fields inherit from the next scope
+-- (market → series → global → env defaults).  Rows are never edited in place;
+-- a change deactivates the current row and inserts a new one, so the table
+-- doubles as the configuration history.
+ALTER TABLE mm_config ALTER COLUMN threshold_ratio  DROP NOT NULL;
+ALTER TABLE mm_config ALTER COLUMN max_exposure_try DROP NOT NULL;
+
+ALTER TABLE mm_config ADD COLUMN IF NOT EXISTS series         VARCHAR(50);
+ALTER TABLE mm_config ADD COLUMN IF NOT EXISTS min_bet_try    DECIMAL(18,4);
+ALTER TABLE mm_config ADD COLUMN IF NOT EXISTS seed_ratio     DECIMAL(5,4);
+ALTER TABLE mm_config ADD COLUMN IF NOT EXISTS balance_ratio  DECIMAL(5,4);
+ALTER TABLE mm_config ADD COLUMN IF NOT EXISTS created_by     UUID REFERENCES users(id);
+ALTER TABLE mm_config ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMPTZ;
+
+ALTER TABLE mm_config DROP CONSTRAINT IF EXISTS mm_config_single_scope;
+ALTER TABLE mm_config ADD CONSTRAINT mm_config_single_scope
+    CHECK (market_id IS NULL OR series IS NULL);
+
+-- At most one active row per scope
+CREATE UNIQUE INDEX IF NOT EXISTS uq_mm_config_active_scope
+    ON mm_config (COALESCE(market_id::text, ''), COALESCE(series, ''))
+    WHERE is_active;
@@ -0,0 +1,9 @@
+-- Migration 006: Pluggable MM strategies
+
+-- Strategy selection and its extra parameter are scoped like the rest of
+-- mm_config (market → series → global → env).
+ALTER TABLE mm_config ADD COLUMN IF NOT EXISTS strategy    VARCHAR(30);
+ALTER TABLE mm_config ADD COLUMN IF NOT EXISTS target_odds DECIMAL(8,4);
+
+-- Record why each injection was made (strategy reason code)
+ALTER TABLE mm_positions ADD COLUMN IF NOT EXISTS reason VARCHAR(50) NOT NULL DEFAULT '';
@@ -0,0 +1,15 @@
+-- Migration 007: Persisted Market Maker kill switch
+
+-- One row per scope: market_id NULL = global switch.  A missing row means
+-- the MM is enabled for that scope.  Changes are announced on the
+-- 'mm_switch_changed' NOTIFY channel and recorded in admin_audit_log.
+CREATE TABLE IF NOT EXISTS mm_switches (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    market_id   UUID REFERENCES markets(id) ON DELETE CASCADE,
+    enabled     BOOLEAN      NOT NULL,
+    reason      TEXT         NOT NULL DEFAULT '',
+    changed_by  UUID REFERENCES users(id),
+    changed_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_mm_switches_scope ON mm_switches ((COALESCE(market_id::text, '')));
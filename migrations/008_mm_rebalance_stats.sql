@@ -0,0 +1,11 @@
+-- Migration 008: Market Maker rebalance queue metrics
+
+-- Each API server process upserts its in-memory queue and decision counters
+-- here periodically so the back-office (a separate process) can show them.
+-- Counters are cumulative since the process started.
+CREATE TABLE IF NOT EXISTS mm_rebalance_stats (
+    instance    TEXT PRIMARY KEY,                 -- hostname:pid
+    stats       JSONB        NOT NULL,
+    started_at  TIMESTAMPTZ  NOT NULL,
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
@@ -0,0 +1,36 @@
+-- Migration 009: One house_treasury row per market
+
+-- Fold duplicate rows (recordCommission used ON CONFLICT DO NOTHING without a
+-- unique key) into the first row per market by (created_at, id), then
+-- enforce uniqueness.
+WITH ranked AS (
+    SELECT id, market_id,
+           ROW_NUMBER() OVER (PARTITION BY market_id ORDER BY created_at, id) AS n
+    FROM house_treasury
+)
+UPDATE house_treasury h
+SET commission_earned   = agg.commission_earned,
+    mm_pnl              = agg.mm_pnl,
+    cashout_fees_earned = agg.cashout_fees_earned
+FROM (
+    SELECT market_id,
+           SUM(commission_earned)   AS commission_earned,
+           SUM(mm_pnl)              AS mm_pnl,
+           SUM(cashout_fees_earned) AS cashout_fees_earned
+    FROM house_treasury
+    GROUP BY market_id
+    HAVING COUNT(*) > 1
+) agg, ranked r
+WHERE r.market_id = agg.market_id AND r.n = 1 AND h.id = r.id;
+
+DELETE FROM house_treasury h
+USING (
+    SELECT id,
+           ROW_NUMBER() OVER (PARTITION BY market_id ORDER BY created_at, id) AS n
+    FROM house_treasury
+) r
+WHERE h.id = r.id AND r.n > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_house_treasury_market ON house_treasury(market_id);
+
+ALTER TABLE house_treasury ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
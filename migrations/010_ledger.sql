@@ -0,0 +1,137 @@
+-- Migration 010: Double-entry ledger
+
+-- Every money movement is a journal entry whose signed lines sum to zero.
+-- Positive amounts increase an account's balance, negative amounts decrease
+-- it.  Account codes:
+--   user:<user_id>                 user wallet (balance kept in wallets)
+--   house:mm                       platform MM wallet (balance kept in wallets)
+--   house:commission, house:cashout_fees, house:cashout_pnl,
+--   house:bonus, house:adjustments house income / expense
+--   escrow:market:<market_id>      stakes held in a market's pools
+--   liability:pending_withdrawals  funds reserved for withdrawals
+--   external:payments              money outside the platform
+--   equity:opening                 balances that existed before the ledger
+-- Wallet-backed accounts keep their balance in wallets; all others in
+-- ledger_accounts.
+
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    code        VARCHAR(80)   PRIMARY KEY,
+    balance     NUMERIC(18,4) NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ   NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind         VARCHAR(50)  NOT NULL,           -- wallet_transactions.type of the movement
+    ref_id       UUID,                            -- bet, market or request
+    description  TEXT         NOT NULL DEFAULT '',
+    created_by   UUID REFERENCES users(id),       -- admin for manual entries
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS ledger_lines (
+    id             BIGSERIAL     PRIMARY KEY,
+    entry_id       UUID          NOT NULL REFERENCES ledger_entries(id),
+    account        VARCHAR(80)   NOT NULL,
+    amount         NUMERIC(18,4) NOT NULL,
+    balance_after  NUMERIC(18,4) NOT NULL,
+    CONSTRAINT ledger_lines_amount_nonzero CHECK (amount <> 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_lines_entry   ON ledger_lines(entry_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_lines_account ON ledger_lines(account, id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_ref   ON ledger_entries(ref_id);
+
+ALTER TABLE wallet_transactions ADD COLUMN IF NOT EXISTS entry_id UUID REFERENCES ledger_entries(id);
+
+-- The journal is append-only.
+CREATE OR REPLACE FUNCTION ledger_reject_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION '% is append-only', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS ledger_entries_immutable ON ledger_entries;
+CREATE TRIGGER ledger_entries_immutable
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_reject_change();
+
+DROP TRIGGER IF EXISTS ledger_lines_immutable ON ledger_lines;
+CREATE TRIGGER ledger_lines_immutable
+    BEFORE UPDATE OR DELETE ON ledger_lines
+    FOR EACH ROW EXECUTE FUNCTION ledger_reject_change();
+
+DROP TRIGGER IF EXISTS ledger_entries_no_truncate ON ledger_entries;
+CREATE TRIGGER ledger_entries_no_truncate
+    BEFORE TRUNCATE ON ledger_entries
+    FOR EACH STATEMENT EXECUTE FUNCTION ledger_reject_change();
+
+DROP TRIGGER IF EXISTS ledger_lines_no_truncate ON ledger_lines;
+CREATE TRIGGER ledger_lines_no_truncate
+    BEFORE TRUNCATE ON ledger_lines
+    FOR EACH STATEMENT EXECUTE FUNCTION ledger_reject_change();
+
+-- Entries must balance; checked at commit so lines can be inserted one by one.
+CREATE OR REPLACE FUNCTION ledger_check_balanced() RETURNS trigger AS $$
+BEGIN
+    IF (SELECT SUM(amount) FROM ledger_lines WHERE entry_id = NEW.entry_id) <> 0 THEN
+        RAISE EXCEPTION 'ledger entry % does not balance', NEW.entry_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS ledger_lines_balanced ON ledger_lines;
+CREATE CONSTRAINT TRIGGER ledger_lines_balanced
+    AFTER INSERT ON ledger_lines
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION ledger_check_balanced();
+
+-- Opening entry: carry existing wallet balances, unsettled market pools and
+-- house income into the ledger once, against equity:opening.
+DO $$
+DECLARE
+    opening UUID := gen_random_uuid();
+BEGIN
+    IF EXISTS (SELECT 1 FROM ledger_entries WHERE kind = 'opening') THEN
+        RETURN;
+    END IF;
+
+    INSERT INTO ledger_entries (id, kind, description)
+    VALUES (opening, 'opening', 'Opening balances');
+
+    INSERT INTO ledger_accounts (code, balance)
+    SELECT 'escrow:market:' || id, pool_up + pool_down
+    FROM markets
+    WHERE status NOT IN ('resolved', 'cancelled') AND pool_up + pool_down <> 0
+    ON CONFLICT (code) DO NOTHING;
+
+    INSERT INTO ledger_accounts (code, balance)
+    SELECT 'house:commission', COALESCE(SUM(commission_earned), 0) FROM house_treasury
+    UNION ALL
+    SELECT 'house:cashout_fees', COALESCE(SUM(cashout_fees_earned), 0) FROM house_treasury
+    ON CONFLICT (code) DO NOTHING;
+
+    INSERT INTO ledger_lines (entry_id, account, amount, balance_after)
+    SELECT opening,
+           CASE WHEN wallet_type = 'platform_mm' THEN 'house:mm' ELSE 'user:' || user_id END,
+           balance, balance
+    FROM wallets
+    WHERE balance <> 0
+    UNION ALL
+    SELECT opening, code, balance, balance
+    FROM ledger_accounts
+    WHERE balance <> 0;
+
+    INSERT INTO ledger_accounts (code, balance)
+    SELECT 'equity:opening', -COALESCE(SUM(amount), 0)
+    FROM ledger_lines WHERE entry_id = opening
+    ON CONFLICT (code) DO NOTHING;
+
+    INSERT INTO ledger_lines (entry_id, account, amount, balance_after)
+    SELECT opening, code, balance, balance
+    FROM ledger_accounts
+    WHERE code = 'equity:opening' AND balance <> 0;
+END;
+$$;
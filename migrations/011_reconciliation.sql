@@ -0,0 +1,25 @@
+-- Migration 011: Wallet reconciliation
+
+-- A frozen wallet still receives credits (payouts, refunds) but every debit
+-- is refused by the ledger until finance unfreezes it.
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS frozen        BOOLEAN     NOT NULL DEFAULT false;
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS frozen_reason TEXT        NOT NULL DEFAULT '';
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS frozen_at     TIMESTAMPTZ;
+
+-- One row per reconciliation pass.  drifts holds only the wallets that did
+-- not reconcile.
+CREATE TABLE IF NOT EXISTS reconciliation_runs (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source           VARCHAR(20)   NOT NULL,            -- 'scheduled' | 'manual'
+    triggered_by     UUID REFERENCES users(id),
+    started_at       TIMESTAMPTZ   NOT NULL,
+    finished_at      TIMESTAMPTZ   NOT NULL,
+    wallets_checked  INT           NOT NULL,
+    mismatched       INT           NOT NULL,
+    total_drift      NUMERIC(18,4) NOT NULL,            -- signed sum of drifts
+    abs_drift        NUMERIC(18,4) NOT NULL,            -- sum of |drift|
+    frozen           INT           NOT NULL DEFAULT 0,  -- wallets frozen by this run
+    drifts           JSONB         NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX IF NOT EXISTS idx_reconciliation_runs_started ON reconciliation_runs(started_at DESC);
@@ -0,0 +1,35 @@
+-- Migration 012: Deposits through payment providers
+
+-- A deposit starts as an intent (pending) created by the user and is settled
+-- by the provider's webhook.  Only a completed deposit has a ledger entry.
+CREATE TABLE IF NOT EXISTS deposits (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id        UUID          NOT NULL REFERENCES users(id),
+    amount         DECIMAL(18,4) NOT NULL,
+    status         VARCHAR(20)   NOT NULL DEFAULT 'pending',  -- pending|completed|failed|expired
+    provider       VARCHAR(30)   NOT NULL,
+    provider_ref   VARCHAR(100)  NOT NULL,                    -- provider's payment/session id
+    checkout_url   TEXT          NOT NULL DEFAULT '',
+    failure_reason TEXT          NOT NULL DEFAULT '',
+    entry_id       UUID REFERENCES ledger_entries(id),        -- set when completed
+    created_at     TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    updated_at     TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    completed_at   TIMESTAMPTZ,
+    CONSTRAINT deposit_amount_positive CHECK (amount > 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_deposits_provider_ref ON deposits(provider, provider_ref);
+CREATE INDEX IF NOT EXISTS idx_deposits_user    ON deposits(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_deposits_pending ON deposits(created_at) WHERE status = 'pending';
+
+-- Every webhook event that passed signature verification.  The primary key
+-- makes redelivered events no-ops, so a payment is credited exactly once.
+CREATE TABLE IF NOT EXISTS payment_webhook_events (
+    provider     VARCHAR(30)  NOT NULL,
+    event_id     VARCHAR(100) NOT NULL,
+    event_type   VARCHAR(50)  NOT NULL,
+    deposit_id   UUID REFERENCES deposits(id),
+    payload      JSONB        NOT NULL,
+    received_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    PRIMARY KEY (provider, event_id)
+);
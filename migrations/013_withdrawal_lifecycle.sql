@@ -0,0 +1,68 @@
+-- Migration 013: Withdrawal holds, debits and payouts
+--
+--   pending    funds held in wallets.locked, not yet debited
+--   approved   hold released and wallet debited into liability:pending_withdrawals
+--   processing sent to the bank payout provider, waiting for its result
+--   completed  paid out; liability cleared against external:payments
+--   failed     payout failed; the debit was refunded to the wallet
+--   rejected   / cancelled: hold released, nothing debited
+
+-- One-time conversion of requests created before holds existed.  Runs only
+-- while the payout columns are missing.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'withdraw_requests' AND column_name = 'payout_ref'
+    ) THEN
+        RETURN;
+    END IF;
+
+    -- Approval used to only flip the status, so an approved request was never
+    -- debited.  Send it back for review under the new flow.
+    UPDATE withdraw_requests
+    SET status      = 'pending',
+        review_note = 'Re-queued: approved before withdrawal holds existed'
+    WHERE status = 'approved';
+
+    -- Reject pending requests the user can no longer cover, then hold the rest.
+    UPDATE withdraw_requests r
+    SET status      = 'rejected',
+        review_note = 'Insufficient balance when withdrawal holds were introduced',
+        reviewed_at = now()
+    FROM (
+        SELECT p.user_id
+        FROM withdraw_requests p
+        JOIN wallets w ON w.user_id = p.user_id
+        WHERE p.status = 'pending'
+        GROUP BY p.user_id, w.balance, w.locked
+        HAVING SUM(p.amount) > w.balance - w.locked
+    ) short
+    WHERE r.user_id = short.user_id AND r.status = 'pending';
+
+    UPDATE wallets w
+    SET locked = w.locked + h.total, updated_at = now()
+    FROM (
+        SELECT user_id, SUM(amount) AS total
+        FROM withdraw_requests
+        WHERE status = 'pending'
+        GROUP BY user_id
+    ) h
+    WHERE w.user_id = h.user_id;
+
+    ALTER TABLE withdraw_requests
+        ADD COLUMN payout_provider VARCHAR(30),
+        ADD COLUMN payout_ref      VARCHAR(100),
+        ADD COLUMN failure_reason  TEXT NOT NULL DEFAULT '',
+        ADD COLUMN processed_at    TIMESTAMPTZ,
+        ADD COLUMN completed_at    TIMESTAMPTZ;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_withdraw_requests_user ON withdraw_requests(user_id, requested_at DESC);
+CREATE INDEX IF NOT EXISTS idx_withdraw_requests_status ON withdraw_requests(status, requested_at);
+
+-- review_note was left NULL until review, which the API cannot scan.
+UPDATE withdraw_requests SET review_note = '' WHERE review_note IS NULL;
+UPDATE withdraw_requests SET note = '' WHERE note IS NULL;
+ALTER TABLE withdraw_requests ALTER COLUMN review_note SET DEFAULT '';
+ALTER TABLE withdraw_requests ALTER COLUMN note SET DEFAULT '';
@@ -0,0 +1,23 @@
+-- Migration 014: Bank payout batches
+--
+-- Finance pays approved withdrawals from the bank portal.  A batch claims
+-- approved withdrawals (they move to processing), is exported as a bank file
+-- and is closed by importing the bank's result file.
+
+CREATE TABLE IF NOT EXISTS payout_batches (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    status        VARCHAR(20)   NOT NULL DEFAULT 'open',  -- open|exported|settled
+    item_count    INT           NOT NULL,
+    total_amount  DECIMAL(18,4) NOT NULL,
+    completed     INT           NOT NULL DEFAULT 0,
+    failed        INT           NOT NULL DEFAULT 0,
+    created_by    UUID REFERENCES users(id),
+    created_at    TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    exported_at   TIMESTAMPTZ,
+    settled_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_payout_batches_created ON payout_batches(created_at DESC);
+
+ALTER TABLE withdraw_requests ADD COLUMN IF NOT EXISTS batch_id UUID REFERENCES payout_batches(id);
+CREATE INDEX IF NOT EXISTS idx_withdraw_requests_batch ON withdraw_requests(batch_id) WHERE batch_id IS NOT NULL;
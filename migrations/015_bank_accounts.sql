@@ -0,0 +1,37 @@
+-- Migration 015: Saved bank accounts
+--
+-- Users save IBANs once instead of typing one per withdrawal.  An account is
+-- verified automatically when the holder matches users.full_name, otherwise
+-- by finance, and can receive withdrawals only after usable_from (the
+-- cooling-off period).  Removed accounts are soft-deleted because
+-- withdraw_requests keep referring to them.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS full_name VARCHAR(100) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS bank_accounts (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      UUID         NOT NULL REFERENCES users(id),
+    iban         VARCHAR(34)  NOT NULL,
+    holder_name  VARCHAR(100) NOT NULL,
+    status       VARCHAR(20)  NOT NULL DEFAULT 'pending',  -- pending|verified|rejected
+    name_match   VARCHAR(20)  NOT NULL,                    -- match|mismatch|unknown
+    is_default   BOOLEAN      NOT NULL DEFAULT false,
+    usable_from  TIMESTAMPTZ  NOT NULL,
+    reviewed_by  UUID REFERENCES users(id),
+    review_note  TEXT         NOT NULL DEFAULT '',
+    reviewed_at  TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    deleted_at   TIMESTAMPTZ
+);
+
+-- An IBAN belongs to one live account across all users.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bank_accounts_iban
+    ON bank_accounts(iban) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bank_accounts_default
+    ON bank_accounts(user_id) WHERE is_default AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_bank_accounts_user
+    ON bank_accounts(user_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_bank_accounts_pending
+    ON bank_accounts(created_at) WHERE status = 'pending' AND deleted_at IS NULL;
+
+ALTER TABLE withdraw_requests ADD COLUMN IF NOT EXISTS bank_account_id UUID REFERENCES bank_accounts(id);
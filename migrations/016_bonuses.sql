@@ -0,0 +1,32 @@
+-- Migration 016: Bonus balance with wagering requirements
+--
+-- Bonus money is held in the ledger account bonus:user:<id> instead of the
+-- wallet, so it can be staked but not withdrawn.  Each grant carries a
+-- turnover target (wagering_required); qualifying settled stakes add to
+-- wagered and once it is reached the remaining bonus balance is moved to the
+-- wallet.  bets.bonus_amount is the part of a stake paid from the bonus
+-- balance; payouts, refunds and exits return the same share to it.
+
+ALTER TABLE bets ADD COLUMN IF NOT EXISTS bonus_amount DECIMAL(18,4) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS bonus_grants (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id            UUID          NOT NULL REFERENCES users(id),
+    amount             DECIMAL(18,4) NOT NULL CHECK (amount > 0),
+    wagering_required  DECIMAL(18,4) NOT NULL CHECK (wagering_required >= 0),
+    wagered            DECIMAL(18,4) NOT NULL DEFAULT 0,
+    status             VARCHAR(20)   NOT NULL DEFAULT 'active', -- active|converted|expired|forfeited
+    reason             VARCHAR(50)   NOT NULL,
+    created_by         UUID REFERENCES users(id),
+    created_at         TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    expires_at         TIMESTAMPTZ   NOT NULL,
+    closed_at          TIMESTAMPTZ
+);
+
+-- A user works through one bonus at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bonus_grants_active
+    ON bonus_grants(user_id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_bonus_grants_user
+    ON bonus_grants(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_bonus_grants_expiry
+    ON bonus_grants(expires_at) WHERE status = 'active';
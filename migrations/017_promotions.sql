@@ -0,0 +1,62 @@
+-- Migration 017: Promotion campaigns, promo codes and free bets
+--
+-- A promotion is redeemed with its code and rewards either a bonus grant
+-- (bonus_grants, migration 016) or a free-bet token.  redeemed_count is kept
+-- on the promotion row, which is locked while redeeming, so the global cap
+-- holds under concurrency.  A free bet is a stake paid by house:free_bets:
+-- when it wins the user keeps only the winnings.
+
+CREATE TABLE IF NOT EXISTS promotions (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name               VARCHAR(100)  NOT NULL,
+    code               VARCHAR(32)   NOT NULL,                -- stored upper-case
+    reward_type        VARCHAR(20)   NOT NULL,                -- bonus|free_bet
+    reward_amount      DECIMAL(18,4) NOT NULL CHECK (reward_amount > 0),
+    free_bet_days      INT           NOT NULL DEFAULT 7,      -- free bets expire after this many days
+    starts_at          TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    ends_at            TIMESTAMPTZ,
+    max_redemptions    INT           NOT NULL DEFAULT 0,      -- 0 = unlimited
+    per_user_limit     INT           NOT NULL DEFAULT 1,
+    redeemed_count     INT           NOT NULL DEFAULT 0,
+    new_users_days     INT           NOT NULL DEFAULT 0,      -- only users registered this recently; 0 = everyone
+    requires_deposit   BOOLEAN       NOT NULL DEFAULT false,  -- only users with a completed deposit
+    is_active          BOOLEAN       NOT NULL DEFAULT true,
+    created_by         UUID REFERENCES users(id),
+    created_at         TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    updated_at         TIMESTAMPTZ   NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_promotions_code ON promotions(code);
+
+CREATE TABLE IF NOT EXISTS free_bets (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id       UUID          NOT NULL REFERENCES users(id),
+    promotion_id  UUID          NOT NULL REFERENCES promotions(id),
+    amount        DECIMAL(18,4) NOT NULL CHECK (amount > 0),
+    status        VARCHAR(20)   NOT NULL DEFAULT 'available', -- available|used|expired
+    bet_id        UUID,
+    created_at    TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    expires_at    TIMESTAMPTZ   NOT NULL,
+    used_at       TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_free_bets_user ON free_bets(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_free_bets_expiry ON free_bets(expires_at) WHERE status = 'available';
+
+CREATE TABLE IF NOT EXISTS promotion_redemptions (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    promotion_id    UUID          NOT NULL REFERENCES promotions(id),
+    user_id         UUID          NOT NULL REFERENCES users(id),
+    reward_type     VARCHAR(20)   NOT NULL,
+    amount          DECIMAL(18,4) NOT NULL,
+    bonus_grant_id  UUID REFERENCES bonus_grants(id),
+    free_bet_id     UUID REFERENCES free_bets(id),
+    created_at      TIMESTAMPTZ   NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_promo
+    ON promotion_redemptions(promotion_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_user
+    ON promotion_redemptions(user_id, promotion_id);
+
+ALTER TABLE bets ADD COLUMN IF NOT EXISTS free_bet_id UUID REFERENCES free_bets(id);
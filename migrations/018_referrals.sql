@@ -0,0 +1,51 @@
+-- Migration 018: Referral program
+--
+-- Every user has an invite code (users.referral_code; generated at signup,
+-- or on first use for accounts that predate this migration).  A user who
+-- registers with a code is attributed to its owner in referrals, and the
+-- referrer earns a share of the commission the house makes on the referred
+-- user's real-money bets.  Commission is accrued per referral and day in
+-- referral_commissions; the unique key makes a day's accrual idempotent.
+--
+-- Referrals that look fraudulent (self-referral, shared signup IP) are
+-- stored as 'flagged' and earn nothing until risk approves them.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS referral_code VARCHAR(16);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS signup_ip     VARCHAR(45);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_referral_code ON users(referral_code);
+
+CREATE TABLE IF NOT EXISTS referrals (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    referrer_id  UUID         NOT NULL REFERENCES users(id),
+    referred_id  UUID         NOT NULL REFERENCES users(id),
+    status       VARCHAR(20)  NOT NULL DEFAULT 'active', -- active|flagged|blocked
+    flag_reason  VARCHAR(50),                            -- self_referral|same_ip
+    signup_ip    VARCHAR(45),
+    reviewed_by  UUID REFERENCES users(id),
+    reviewed_at  TIMESTAMPTZ,
+    review_note  TEXT,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    CHECK (referrer_id <> referred_id)
+);
+
+-- A user is referred at most once.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_referrals_referred ON referrals(referred_id);
+CREATE INDEX IF NOT EXISTS idx_referrals_referrer ON referrals(referrer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_referrals_status   ON referrals(status, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS referral_commissions (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    referral_id      UUID          NOT NULL REFERENCES referrals(id),
+    referrer_id      UUID          NOT NULL REFERENCES users(id),
+    period_start     DATE          NOT NULL,      -- the day whose settled markets are counted
+    commission_base  DECIMAL(18,4) NOT NULL,      -- house commission attributed to the referred user
+    share_rate       DECIMAL(6,4)  NOT NULL,
+    amount           DECIMAL(18,4) NOT NULL CHECK (amount > 0),
+    created_at       TIMESTAMPTZ   NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_referral_commissions_period
+    ON referral_commissions(referral_id, period_start);
+CREATE INDEX IF NOT EXISTS idx_referral_commissions_referrer
+    ON referral_commissions(referrer_id, period_start DESC);
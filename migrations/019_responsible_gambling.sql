@@ -0,0 +1,37 @@
+-- Migration 019: Responsible gambling limits and self-exclusion
+--
+-- A user may cap their deposits, losses and stakes per day, week or month.
+-- Lowering a limit applies at once; raising or removing one is stored in
+-- pending_amount / pending_from and only applies after a waiting period
+-- (pending_from set with a NULL pending_amount is a queued removal).
+--
+-- self_exclusions keeps every break a user asked for: a cooling-off blocks
+-- betting and deposits, a self-exclusion also blocks logging in.  Neither
+-- can be ended early; ends_at NULL means indefinite.
+
+CREATE TABLE IF NOT EXISTS gambling_limits (
+    user_id         UUID          NOT NULL REFERENCES users(id),
+    kind            VARCHAR(20)   NOT NULL,                -- deposit|loss|stake
+    period          VARCHAR(20)   NOT NULL,                -- daily|weekly|monthly
+    amount          DECIMAL(18,4) NOT NULL CHECK (amount > 0),
+    pending_amount  DECIMAL(18,4) CHECK (pending_amount > 0),
+    pending_from    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, kind, period)
+);
+
+CREATE TABLE IF NOT EXISTS self_exclusions (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID         NOT NULL REFERENCES users(id),
+    kind        VARCHAR(20)  NOT NULL,                     -- cooling_off|self_exclusion
+    starts_at   TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    ends_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_self_exclusions_user ON self_exclusions(user_id, starts_at DESC);
+CREATE INDEX IF NOT EXISTS idx_self_exclusions_ends ON self_exclusions(ends_at);
+
+-- Usage of stake and loss limits is summed from the wallet journal.
+CREATE INDEX IF NOT EXISTS idx_wallet_txns_wallet_created ON wallet_transactions(wallet_id, created_at);
@@ -0,0 +1,43 @@
+-- Migration 020: KYC verification
+--
+-- users.kyc_status is the user's current verification state.  A user
+-- uploads identity documents (kept in file storage; only the key is stored
+-- here), then submits them for review; risk approves or rejects the
+-- submission.  A rejected user may upload new documents and submit again.
+-- Unverified users can only withdraw up to the configured thresholds.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS kyc_status VARCHAR(20) NOT NULL DEFAULT 'unverified';
+-- unverified|pending|verified|rejected
+
+CREATE TABLE IF NOT EXISTS kyc_submissions (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id       UUID         NOT NULL REFERENCES users(id),
+    status        VARCHAR(20)  NOT NULL DEFAULT 'pending',  -- pending|verified|rejected
+    submitted_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    reviewed_by   UUID         REFERENCES users(id),
+    reviewed_at   TIMESTAMPTZ,
+    review_note   TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_status ON kyc_submissions(status, submitted_at);
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_user   ON kyc_submissions(user_id, submitted_at DESC);
+
+-- At most one submission per user waits for review.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_kyc_submissions_user_pending
+    ON kyc_submissions(user_id) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS kyc_documents (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id        UUID          NOT NULL REFERENCES users(id),
+    submission_id  UUID          REFERENCES kyc_submissions(id),  -- NULL until submitted
+    kind           VARCHAR(30)   NOT NULL,  -- id_card|passport|driving_license|proof_of_address|selfie
+    storage_key    VARCHAR(255)  NOT NULL UNIQUE,
+    file_name      VARCHAR(255)  NOT NULL,
+    content_type   VARCHAR(100)  NOT NULL,
+    size_bytes     BIGINT        NOT NULL,
+    sha256         CHAR(64)      NOT NULL,
+    created_at     TIMESTAMPTZ   NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_documents_user       ON kyc_documents(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_kyc_documents_submission ON kyc_documents(submission_id);
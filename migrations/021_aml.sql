@@ -0,0 +1,62 @@
+-- Migration 021: AML transaction monitoring
+--
+-- Deposits, bets and withdrawal requests are screened by the rules in
+-- internal/aml.  Each hit is an alert; a user's alerts collect in their
+-- one case that is not closed, which finance reviews from a queue.  A case
+-- may hold the user's pending withdrawals (withdraw_requests.aml_hold)
+-- until it is cleared.  aml_case_events is the case's append-only history.
+
+CREATE TABLE IF NOT EXISTS aml_cases (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      UUID         NOT NULL REFERENCES users(id),
+    status       VARCHAR(20)  NOT NULL DEFAULT 'open',  -- open|escalated|cleared|reported
+    severity     VARCHAR(10)  NOT NULL,                 -- low|medium|high, highest of the alerts
+    hold         BOOLEAN      NOT NULL DEFAULT false,   -- user's pending withdrawals are held
+    assigned_to  UUID         REFERENCES users(id),
+    resolution   TEXT         NOT NULL DEFAULT '',
+    opened_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    closed_by    UUID         REFERENCES users(id),
+    closed_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_aml_cases_status ON aml_cases(status, opened_at);
+CREATE INDEX IF NOT EXISTS idx_aml_cases_user   ON aml_cases(user_id, opened_at DESC);
+
+-- At most one case per user is not closed; new alerts join it.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_aml_cases_user_live
+    ON aml_cases(user_id) WHERE status IN ('open', 'escalated');
+
+CREATE TABLE IF NOT EXISTS aml_alerts (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    case_id     UUID           NOT NULL REFERENCES aml_cases(id),
+    user_id     UUID           NOT NULL REFERENCES users(id),
+    rule        VARCHAR(50)    NOT NULL,
+    severity    VARCHAR(10)    NOT NULL,
+    subject     VARCHAR(20)    NOT NULL,  -- deposit|bet|withdrawal
+    subject_id  UUID           NOT NULL,
+    amount      DECIMAL(18,4)  NOT NULL,
+    reason      TEXT           NOT NULL,
+    created_at  TIMESTAMPTZ    NOT NULL DEFAULT now(),
+    -- Rescreening the same activity does not raise the alert twice.
+    CONSTRAINT aml_alerts_once UNIQUE (rule, subject, subject_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_aml_alerts_case ON aml_alerts(case_id, created_at);
+
+CREATE TABLE IF NOT EXISTS aml_case_events (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    case_id     UUID         NOT NULL REFERENCES aml_cases(id),
+    actor_id    UUID         REFERENCES users(id),  -- NULL for the rules engine
+    action      VARCHAR(20)  NOT NULL,  -- opened|alert|hold|release|assign|note|escalate|clear|report
+    note        TEXT         NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_aml_case_events_case ON aml_case_events(case_id, created_at);
+
+ALTER TABLE withdraw_requests ADD COLUMN IF NOT EXISTS aml_hold BOOLEAN NOT NULL DEFAULT false;
+
+-- Shared-IBAN lookups go across users.
+CREATE INDEX IF NOT EXISTS idx_withdraw_requests_iban ON withdraw_requests(iban);
+CREATE INDEX IF NOT EXISTS idx_bank_accounts_iban_all ON bank_accounts(iban);
@@ -0,0 +1,31 @@
+-- Migration 022: Monthly account statements
+--
+-- At the start of each business month a statement is generated for every
+-- active user who had wallet activity or a balance in the month before.
+-- The totals are kept here; the rendered PDF and CSV live in file storage
+-- and only their keys are stored.  Regenerating a month replaces the row
+-- and the files.
+
+CREATE TABLE IF NOT EXISTS account_statements (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id          UUID          NOT NULL REFERENCES users(id),
+    month            CHAR(7)       NOT NULL,  -- YYYY-MM in the business timezone
+    period_start     TIMESTAMPTZ   NOT NULL,
+    period_end       TIMESTAMPTZ   NOT NULL,  -- exclusive
+    opening_balance  DECIMAL(18,4) NOT NULL,
+    closing_balance  DECIMAL(18,4) NOT NULL,
+    deposits         DECIMAL(18,4) NOT NULL DEFAULT 0,
+    withdrawals      DECIMAL(18,4) NOT NULL DEFAULT 0,  -- negative: money out
+    stakes           DECIMAL(18,4) NOT NULL DEFAULT 0,  -- negative: money out
+    payouts          DECIMAL(18,4) NOT NULL DEFAULT 0,  -- payouts, cash-outs and refunds
+    bonuses          DECIMAL(18,4) NOT NULL DEFAULT 0,  -- bonus conversions and referral shares
+    other            DECIMAL(18,4) NOT NULL DEFAULT 0,  -- back-office adjustments and anything else
+    fees             DECIMAL(18,4) NOT NULL DEFAULT 0,  -- cash-out fees, already netted from payouts
+    tx_count         INT           NOT NULL DEFAULT 0,
+    pdf_key          VARCHAR(255)  NOT NULL,
+    csv_key          VARCHAR(255)  NOT NULL,
+    generated_at     TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    CONSTRAINT account_statements_user_month UNIQUE (user_id, month)
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_statements_month ON account_statements(month);
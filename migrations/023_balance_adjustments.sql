@@ -0,0 +1,26 @@
+-- Migration 023: Maker-checker balance adjustments
+--
+-- A back-office balance change is first recorded here as pending.  A
+-- different finance or admin user approves it, which posts a ledger entry
+-- of kind 'adjustment' (ref_id = the adjustment) in the same transaction,
+-- or rejects it.
+
+CREATE TABLE IF NOT EXISTS balance_adjustments (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id       UUID          NOT NULL REFERENCES users(id),
+    amount        DECIMAL(18,4) NOT NULL,  -- signed: negative debits the wallet
+    reason        VARCHAR(20)   NOT NULL,  -- correction|goodwill|promotion|chargeback|fraud|other
+    note          TEXT          NOT NULL,
+    status        VARCHAR(20)   NOT NULL DEFAULT 'pending',  -- pending|approved|rejected
+    requested_by  UUID          NOT NULL REFERENCES users(id),
+    requested_at  TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    reviewed_by   UUID          REFERENCES users(id),
+    reviewed_at   TIMESTAMPTZ,
+    review_note   TEXT,
+    entry_id      UUID          REFERENCES ledger_entries(id),
+    CONSTRAINT balance_adjustments_amount_non_zero CHECK (amount <> 0),
+    CONSTRAINT balance_adjustments_four_eyes CHECK (reviewed_by IS NULL OR reviewed_by <> requested_by OR status = 'rejected')
+);
+
+CREATE INDEX IF NOT EXISTS idx_balance_adjustments_status ON balance_adjustments(status, requested_at);
+CREATE INDEX IF NOT EXISTS idx_balance_adjustments_user   ON balance_adjustments(user_id, requested_at DESC);
@@ -0,0 +1,17 @@
+-- Migration 024: Refresh-token rotation
+--
+-- Refresh tokens are opaque and stored only as a keyed hash.  Every refresh
+-- revokes the presented token and issues its successor in the same family
+-- (one family per login).  Presenting a token that was already rotated is
+-- treated as theft and revokes the whole family.
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS family_id   UUID;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS replaced_by UUID REFERENCES refresh_tokens(id) ON DELETE SET NULL;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS revoked_at  TIMESTAMPTZ;
+
+UPDATE refresh_tokens SET family_id = id WHERE family_id IS NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family  ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user    ON refresh_tokens(user_id) WHERE NOT revoked;
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_expires ON refresh_tokens(expires_at);
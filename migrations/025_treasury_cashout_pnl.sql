@@ -0,0 +1,31 @@
+-- Migration 025: Cash-out PnL in house_treasury
+--
+-- An early exit pays the house its fee plus the stake minus the gross exit
+-- value, posted to house:cashout_pnl; negative when the exit pays more than
+-- the stake.  Book it per market next to the fee so that the finance report
+-- counts it.  Existing rows are filled once from the ledger.
+
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'house_treasury' AND column_name = 'cashout_pnl'
+    ) THEN
+        RETURN;
+    END IF;
+
+    ALTER TABLE house_treasury ADD COLUMN cashout_pnl DECIMAL(18,4) NOT NULL DEFAULT 0;
+
+    UPDATE house_treasury h
+    SET cashout_pnl = agg.pnl
+    FROM (
+        SELECT b.market_id, SUM(l.amount) AS pnl
+        FROM ledger_lines l
+        JOIN ledger_entries e ON e.id = l.entry_id
+        JOIN bets b           ON b.id = e.ref_id
+        WHERE e.kind = 'cashout' AND l.account = 'house:cashout_pnl'
+        GROUP BY b.market_id
+    ) agg
+    WHERE h.market_id = agg.market_id;
+END;
+$$;
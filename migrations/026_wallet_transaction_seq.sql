@@ -0,0 +1,39 @@
+-- Migration 026: Posting order of wallet_transactions
+--
+-- created_at is the start time of the DB transaction that posted a row and
+-- id is random, so the rows one DB transaction posts to a wallet (several
+-- payouts at settlement, a payout and a bonus conversion) tie on both.
+-- seq records the order rows were written; the wallet row lock taken by
+-- every posting makes it follow the balance chain of each wallet.
+-- Existing rows are numbered by created_at and then physical order, which
+-- is write order as the table is append-only.
+
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'wallet_transactions' AND column_name = 'seq'
+    ) THEN
+        RETURN;
+    END IF;
+
+    ALTER TABLE wallet_transactions ADD COLUMN seq BIGINT;
+
+    UPDATE wallet_transactions t
+    SET seq = o.n
+    FROM (
+        SELECT ctid AS row_ctid, ROW_NUMBER() OVER (ORDER BY created_at, ctid) AS n
+        FROM wallet_transactions
+    ) o
+    WHERE t.ctid = o.row_ctid;
+
+    CREATE SEQUENCE wallet_transactions_seq_seq OWNED BY wallet_transactions.seq;
+    PERFORM setval('wallet_transactions_seq_seq', COALESCE((SELECT MAX(seq) FROM wallet_transactions), 0) + 1, false);
+
+    ALTER TABLE wallet_transactions
+        ALTER COLUMN seq SET DEFAULT nextval('wallet_transactions_seq_seq'),
+        ALTER COLUMN seq SET NOT NULL;
+END;
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_wallet_txns_wallet_seq ON wallet_transactions(wallet_id, seq);